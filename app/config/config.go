@@ -17,9 +17,20 @@ var (
 	REDIS_DATABASE        int
 	MIDTRANS_SERVERKEY    string
 	MIDTRANS_MERCHANT_ID  string
+	PAYMENT_PROVIDER      string
 	EMAIL_SENDER_NAME     string
 	EMAIL_SENDER_ADDRESS  string
 	EMAIL_SENDER_PASSWORD string
+	SLOT_HOLD_TTL         int
+	EXPIRY_JOB_INTERVAL   int
+	EXPIRY_GRACE_PERIOD   int
+	RECONCILE_INTERVAL    int
+	RECONCILE_AFTER       int
+	WAITLIST_OFFER_TTL    int
+	PLATFORM_FEE_PERCENT  float64
+	BOOKING_FEE           int64
+	TAX_PERCENT           float64
+	COMMISSION_PERCENT    float64
 )
 
 type AppConfig struct {
@@ -119,6 +130,11 @@ func readEnv() *AppConfig {
 		isRead = false
 	}
 
+	if val, found := os.LookupEnv("PAYMENT_PROVIDER"); found {
+		PAYMENT_PROVIDER = val
+		isRead = false
+	}
+
 	if val, found := os.LookupEnv("EMAIL_SENDER_NAME"); found {
 		EMAIL_SENDER_NAME = val
 		isRead = false
@@ -134,6 +150,86 @@ func readEnv() *AppConfig {
 		isRead = false
 	}
 
+	if val, found := os.LookupEnv("SLOT_HOLD_TTL"); found {
+		SLOT_HOLD_TTL, err = strconv.Atoi(val)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("EXPIRY_JOB_INTERVAL"); found {
+		EXPIRY_JOB_INTERVAL, err = strconv.Atoi(val)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("EXPIRY_GRACE_PERIOD"); found {
+		EXPIRY_GRACE_PERIOD, err = strconv.Atoi(val)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("RECONCILE_INTERVAL"); found {
+		RECONCILE_INTERVAL, err = strconv.Atoi(val)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("RECONCILE_AFTER"); found {
+		RECONCILE_AFTER, err = strconv.Atoi(val)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("WAITLIST_OFFER_TTL"); found {
+		WAITLIST_OFFER_TTL, err = strconv.Atoi(val)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("PLATFORM_FEE_PERCENT"); found {
+		PLATFORM_FEE_PERCENT, err = strconv.ParseFloat(val, 64)
+		if err != nil {
+			log.Println("can't convert string to float")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("BOOKING_FEE"); found {
+		BOOKING_FEE, err = strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			log.Println("can't convert string to int")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("TAX_PERCENT"); found {
+		TAX_PERCENT, err = strconv.ParseFloat(val, 64)
+		if err != nil {
+			log.Println("can't convert string to float")
+		}
+		isRead = false
+	}
+
+	if val, found := os.LookupEnv("COMMISSION_PERCENT"); found {
+		COMMISSION_PERCENT, err = strconv.ParseFloat(val, 64)
+		if err != nil {
+			log.Println("can't convert string to float")
+		}
+		isRead = false
+	}
+
 	if isRead {
 		viper.AddConfigPath(".")
 		viper.SetConfigName("local")
@@ -160,9 +256,20 @@ func readEnv() *AppConfig {
 		REDIS_DATABASE = viper.GetInt("REDIS_DATABASE")
 		MIDTRANS_SERVERKEY = viper.GetString("MIDTRANS_SERVERKEY")
 		MIDTRANS_MERCHANT_ID = viper.GetString("MIDTRANS_MERCHANT_ID")
+		PAYMENT_PROVIDER = viper.GetString("PAYMENT_PROVIDER")
 		EMAIL_SENDER_ADDRESS = viper.GetString("EMAIL_SENDER_ADDRESS")
 		EMAIL_SENDER_NAME = viper.GetString("EMAIL_SENDER_NAME")
 		EMAIL_SENDER_PASSWORD = viper.GetString("EMAIL_SENDER_PASSWORD")
+		SLOT_HOLD_TTL = viper.GetInt("SLOT_HOLD_TTL")
+		EXPIRY_JOB_INTERVAL = viper.GetInt("EXPIRY_JOB_INTERVAL")
+		EXPIRY_GRACE_PERIOD = viper.GetInt("EXPIRY_GRACE_PERIOD")
+		RECONCILE_INTERVAL = viper.GetInt("RECONCILE_INTERVAL")
+		RECONCILE_AFTER = viper.GetInt("RECONCILE_AFTER")
+		WAITLIST_OFFER_TTL = viper.GetInt("WAITLIST_OFFER_TTL")
+		PLATFORM_FEE_PERCENT = viper.GetFloat64("PLATFORM_FEE_PERCENT")
+		BOOKING_FEE = viper.GetInt64("BOOKING_FEE")
+		TAX_PERCENT = viper.GetFloat64("TAX_PERCENT")
+		COMMISSION_PERCENT = viper.GetFloat64("COMMISSION_PERCENT")
 	}
 
 	return &app
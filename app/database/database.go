@@ -3,10 +3,14 @@ package database
 import (
 	"fmt"
 
+	category "github.com/playground-pro-project/playground-pro-api/features/category/data"
+	credit "github.com/playground-pro-project/playground-pro-api/features/credit/data"
+	payout "github.com/playground-pro-project/playground-pro-api/features/payout/data"
 	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation/data"
 	review "github.com/playground-pro-project/playground-pro-api/features/review/data"
 	user "github.com/playground-pro-project/playground-pro-api/features/user/data"
 	venue "github.com/playground-pro-project/playground-pro-api/features/venue/data"
+	voucher "github.com/playground-pro-project/playground-pro-api/features/voucher/data"
 
 	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
@@ -28,11 +32,31 @@ func InitDatabase(c *config.AppConfig) *gorm.DB {
 
 	err = db.AutoMigrate(
 		&user.User{},
+		&category.Category{},
 		&venue.Venue{},
+		&venue.Amenity{},
 		&venue.VenuePicture{},
+		&venue.VenueHour{},
+		&venue.VenueClosure{},
+		&venue.CancellationPolicy{},
+		&venue.PricingRule{},
 		&reservation.Payment{},
+		&reservation.PaymentItem{},
+		&reservation.PaymentShare{},
 		&reservation.Reservation{},
+		&reservation.Waitlist{},
+		&reservation.Refund{},
+		&reservation.PaymentDiscrepancy{},
 		&review.Review{},
+		&voucher.Voucher{},
+		&voucher.VoucherRedemption{},
+		&credit.CreditPackage{},
+		&credit.CreditPurchase{},
+		&credit.CreditEntry{},
+		&payout.LedgerEntry{},
+		&payout.PayoutAccount{},
+		&payout.PayoutBatch{},
+		&payout.PayoutLine{},
 	)
 
 	if err != nil {
@@ -40,6 +64,12 @@ func InitDatabase(c *config.AppConfig) *gorm.DB {
 	}
 
 	initSuperAdmin(c, db)
+	if err := initCategories(db); err != nil {
+		log.Fatal("failed to migrate venue categories: " + err.Error())
+	}
+	if err := initAmenities(db); err != nil {
+		log.Fatal("failed to seed amenities: " + err.Error())
+	}
 
 	log.Info("success connected and migrated to database")
 	return db
@@ -3,7 +3,9 @@ package database
 import (
 	"github.com/google/uuid"
 	"github.com/playground-pro-project/playground-pro-api/app/config"
+	category "github.com/playground-pro-project/playground-pro-api/features/category/data"
 	user "github.com/playground-pro-project/playground-pro-api/features/user/data"
+	venue "github.com/playground-pro-project/playground-pro-api/features/venue/data"
 	"github.com/playground-pro-project/playground-pro-api/utils/helper"
 	"gorm.io/gorm"
 )
@@ -50,3 +52,109 @@ func initSuperAdmin(c *config.AppConfig, db *gorm.DB) error {
 	log.Info("super admin created successfully")
 	return nil
 }
+
+// initCategories creates the default categories and moves venues still listed under the
+// old category enum column, and vouchers still scoped by category name, onto them before
+// dropping those columns.
+func initCategories(db *gorm.DB) error {
+	for _, c := range category.DefaultCategories {
+		var count int64
+		if err := db.Model(&category.Category{}).Where("name = ?", c.Name).Count(&count).Error; err != nil {
+			log.Error("failed to look up category " + c.Name)
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		c.CategoryID = helper.GenerateCategoryID()
+		if err := db.Create(&c).Error; err != nil {
+			log.Error("failed to create category " + c.Name)
+			return err
+		}
+	}
+
+	if err := migrateVoucherCategories(db); err != nil {
+		return err
+	}
+
+	if !db.Migrator().HasColumn("venues", "category") {
+		return nil
+	}
+
+	result := db.Exec(`UPDATE venues
+	JOIN categories ON categories.name = venues.category
+	SET venues.category_id = categories.category_id
+	WHERE venues.category_id IS NULL OR venues.category_id = ''`)
+	if result.Error != nil {
+		log.Error("failed to move venues onto categories")
+		return result.Error
+	}
+
+	if err := db.Migrator().DropColumn("venues", "category"); err != nil {
+		log.Error("failed to drop venues category column")
+		return err
+	}
+
+	log.Sugar().Infof("%d venues moved onto categories", result.RowsAffected)
+	return nil
+}
+
+// migrateVoucherCategories points vouchers scoped by category name at the category itself.
+// Vouchers naming a category that no longer exists could not be redeemed anywhere, they are
+// deleted rather than left unscoped.
+func migrateVoucherCategories(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("vouchers", "category") {
+		return nil
+	}
+
+	result := db.Exec(`UPDATE vouchers
+	JOIN categories ON categories.name = vouchers.category
+	SET vouchers.category_id = categories.category_id
+	WHERE vouchers.category_id IS NULL`)
+	if result.Error != nil {
+		log.Error("failed to move vouchers onto categories")
+		return result.Error
+	}
+	moved := result.RowsAffected
+
+	result = db.Exec(`UPDATE vouchers SET deleted_at = NOW()
+	WHERE vouchers.category <> '' AND vouchers.category_id IS NULL AND vouchers.deleted_at IS NULL`)
+	if result.Error != nil {
+		log.Error("failed to delete vouchers of missing categories")
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Sugar().Warnf("%d vouchers of missing categories deleted", result.RowsAffected)
+	}
+
+	if err := db.Migrator().DropColumn("vouchers", "category"); err != nil {
+		log.Error("failed to drop vouchers category column")
+		return err
+	}
+
+	log.Sugar().Infof("%d vouchers moved onto categories", moved)
+	return nil
+}
+
+// initAmenities creates the amenities of the default catalog that are missing.
+func initAmenities(db *gorm.DB) error {
+	for _, a := range venue.DefaultAmenities {
+		var count int64
+		if err := db.Model(&venue.Amenity{}).Where("code = ?", a.Code).Count(&count).Error; err != nil {
+			log.Error("failed to look up amenity " + a.Code)
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		a.AmenityID = helper.GenerateAmenityID()
+		if err := db.Create(&a).Error; err != nil {
+			log.Error("failed to create amenity " + a.Code)
+			return err
+		}
+	}
+
+	return nil
+}
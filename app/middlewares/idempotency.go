@@ -0,0 +1,207 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+var log = Log()
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyTTL            = 24 * time.Hour
+	idempotencyClaimTTL       = time.Minute
+	idempotencyStatusPending  = "processing"
+	idempotencyStatusComplete = "completed"
+)
+
+// idempotencyClaimRefresh is how often the claim of a request in flight is renewed, well
+// within idempotencyClaimTTL.
+var idempotencyClaimRefresh = idempotencyClaimTTL / 3
+
+// IdempotencyStore keeps idempotency records; utils/redis.RedisClient implements it.
+type IdempotencyStore interface {
+	SetIfAbsent(key string, value string, expiration time.Duration) (bool, error)
+	Set(key string, value string, expiration time.Duration) error
+	Get(key string) (string, error)
+	Delete(key string) error
+}
+
+type idempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Status      string `json:"status"`
+	Code        int    `json:"code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency lets clients safely retry a request by sending an Idempotency-Key header.
+// The first response for a key is stored and replayed for later requests with the same
+// key and body, while reusing the key with a different body is rejected. Requests
+// without the header are passed through untouched. Keys are scoped per user, so the
+// middleware should be placed after JWTMiddleware. A request in flight keeps renewing its
+// claim on the key, which expires within idempotencyClaimTTL should the server go down
+// before the request completes.
+func Idempotency(store IdempotencyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idempotencyKey := c.Request().Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" {
+				return next(c)
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				log.Error("error on reading request body")
+				return helper.BadRequestError(c, "Bad request")
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.Sum256(body)
+			record := idempotencyRecord{
+				RequestHash: hex.EncodeToString(hash[:]),
+				Status:      idempotencyStatusPending,
+			}
+			key := "idempotency:" + idempotencyOwner(c) + ":" + c.Request().Method + ":" + c.Path() + ":" + idempotencyKey
+
+			value, _ := json.Marshal(record)
+			claimed, existing, err := claim(store, key, string(value))
+			if err != nil {
+				log.Sugar().Error("error on claiming idempotency key:", err)
+				return helper.InternalServerError(c, "Internal server error")
+			}
+
+			if !claimed {
+				return replay(c, existing, record.RequestHash)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			release := keepClaim(store, key, string(value))
+			errNext := next(c)
+			release()
+
+			// Server errors are not remembered so the client can retry with the same key.
+			if errNext != nil || c.Response().Status >= http.StatusInternalServerError {
+				if err := store.Delete(key); err != nil {
+					log.Sugar().Warn("error on releasing idempotency key:", err)
+				}
+				return errNext
+			}
+
+			record.Status = idempotencyStatusComplete
+			record.Code = c.Response().Status
+			record.ContentType = c.Response().Header().Get(echo.HeaderContentType)
+			record.Body = recorder.body.Bytes()
+			value, _ = json.Marshal(record)
+			if err := store.Set(key, string(value), idempotencyTTL); err != nil {
+				log.Sugar().Warn("error on saving idempotent response:", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// claim takes the key for this request, or returns the record of the request that took it
+// first. A record expiring between the two reads leaves the key free to claim again.
+func claim(store IdempotencyStore, key string, value string) (bool, string, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := store.SetIfAbsent(key, value, idempotencyClaimTTL)
+		if err != nil || claimed {
+			return claimed, "", err
+		}
+
+		existing, err := store.Get(key)
+		if err == nil {
+			return false, existing, nil
+		}
+		if !strings.Contains(err.Error(), "key not found") {
+			return false, "", err
+		}
+	}
+
+	return false, "", errors.New("idempotency key expired while claiming it")
+}
+
+// keepClaim renews the claim of a request in flight until the returned function is called.
+func keepClaim(store IdempotencyStore, key string, value string) func() {
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(idempotencyClaimRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.Set(key, value, idempotencyClaimTTL); err != nil {
+					log.Sugar().Warn("error on renewing idempotency key:", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func replay(c echo.Context, value string, requestHash string) error {
+	record := idempotencyRecord{}
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		log.Sugar().Error("error on decoding idempotency record:", err)
+		return helper.InternalServerError(c, "Internal server error")
+	}
+
+	if record.RequestHash != requestHash {
+		log.Warn("idempotency key reused with a different request body")
+		return c.JSON(http.StatusUnprocessableEntity, helper.ResponseFormat(http.StatusUnprocessableEntity, "Idempotency-Key has already been used with a different request body", nil, nil))
+	}
+
+	if record.Status != idempotencyStatusComplete {
+		log.Warn("request with the same idempotency key is still being processed")
+		return c.JSON(http.StatusConflict, helper.ResponseFormat(http.StatusConflict, "A request with this Idempotency-Key is still being processed", nil, nil))
+	}
+
+	c.Response().Header().Set(IdempotentReplayedHeader, "true")
+	return c.Blob(record.Code, record.ContentType, record.Body)
+}
+
+// idempotencyOwner returns the authenticated user, so two users cannot collide on a key.
+func idempotencyOwner(c echo.Context) string {
+	if token, ok := c.Get("user").(*jwt.Token); ok {
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if userID, ok := claims["userID"].(string); ok {
+				return userID
+			}
+		}
+	}
+
+	return "anonymous"
+}
+
+// responseRecorder copies everything written to the client so it can be replayed.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
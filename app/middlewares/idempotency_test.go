@@ -0,0 +1,252 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is an IdempotencyStore kept in memory, recording the expiration of every key.
+type memoryStore struct {
+	mu          sync.Mutex
+	values      map[string]string
+	expirations map[string]time.Duration
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: map[string]string{}, expirations: map[string]time.Duration{}}
+}
+
+func (m *memoryStore) SetIfAbsent(key string, value string, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; ok {
+		return false, nil
+	}
+	m.values[key] = value
+	m.expirations[key] = expiration
+	return true, nil
+}
+
+func (m *memoryStore) Set(key string, value string, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	m.expirations[key] = expiration
+	return nil
+}
+
+func (m *memoryStore) Get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.New("key not found")
+	}
+	return value, nil
+}
+
+func (m *memoryStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	delete(m.expirations, key)
+	return nil
+}
+
+// vanishingStore loses the first record it reports as taken, as when the claim of another
+// request expires between SetIfAbsent and Get.
+type vanishingStore struct {
+	*memoryStore
+	vanished bool
+}
+
+func (v *vanishingStore) SetIfAbsent(key string, value string, expiration time.Duration) (bool, error) {
+	if !v.vanished {
+		v.vanished = true
+		return false, nil
+	}
+	return v.memoryStore.SetIfAbsent(key, value, expiration)
+}
+
+const idempotencyTestKey = "idempotency:anonymous:POST:/reservations:key-1"
+
+func idempotentServer(store IdempotencyStore, handler echo.HandlerFunc) *echo.Echo {
+	e := echo.New()
+	e.POST("/reservations", handler, Idempotency(store))
+	return e
+}
+
+func idempotentRequest(e *echo.Echo, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("first call is stored for a day", func(t *testing.T) {
+		store := newMemoryStore()
+		calls := 0
+		e := idempotentServer(store, func(c echo.Context) error {
+			calls++
+			return c.JSON(http.StatusCreated, map[string]string{"reservation_id": "reservation_id_1"})
+		})
+
+		rec := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+
+		record := idempotencyRecord{}
+		assert.Nil(t, json.Unmarshal([]byte(store.values[idempotencyTestKey]), &record))
+		assert.Equal(t, idempotencyStatusComplete, record.Status)
+		assert.Equal(t, http.StatusCreated, record.Code)
+		assert.Equal(t, idempotencyTTL, store.expirations[idempotencyTestKey])
+	})
+
+	t.Run("replay returns the stored response", func(t *testing.T) {
+		store := newMemoryStore()
+		calls := 0
+		e := idempotentServer(store, func(c echo.Context) error {
+			calls++
+			return c.JSON(http.StatusCreated, map[string]string{"reservation_id": "reservation_id_1"})
+		})
+
+		first := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		second := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("key reused with a different body", func(t *testing.T) {
+		store := newMemoryStore()
+		calls := 0
+		e := idempotentServer(store, func(c echo.Context) error {
+			calls++
+			return c.JSON(http.StatusCreated, nil)
+		})
+
+		idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		rec := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_2"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("request still in flight", func(t *testing.T) {
+		store := newMemoryStore()
+		entered, release := make(chan struct{}), make(chan struct{})
+		e := idempotentServer(store, func(c echo.Context) error {
+			close(entered)
+			<-release
+			return c.JSON(http.StatusCreated, nil)
+		})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		}()
+		<-entered
+
+		// The claim of a request in flight expires long before a stored response
+		assert.Equal(t, idempotencyClaimTTL, store.expirations[idempotencyTestKey])
+		rec := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		close(release)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("claim is renewed while the request is in flight", func(t *testing.T) {
+		refresh := idempotencyClaimRefresh
+		idempotencyClaimRefresh = 5 * time.Millisecond
+		defer func() { idempotencyClaimRefresh = refresh }()
+
+		store := newMemoryStore()
+		e := idempotentServer(store, func(c echo.Context) error {
+			// The claim expires while the gateway is still charging
+			store.Delete(idempotencyTestKey)
+			time.Sleep(50 * time.Millisecond)
+
+			store.mu.Lock()
+			value := store.values[idempotencyTestKey]
+			store.mu.Unlock()
+			record := idempotencyRecord{}
+			assert.Nil(t, json.Unmarshal([]byte(value), &record))
+			assert.Equal(t, idempotencyStatusPending, record.Status)
+			return c.JSON(http.StatusCreated, nil)
+		})
+
+		rec := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		// Renewals stop with the request, the stored response is left as it is
+		time.Sleep(20 * time.Millisecond)
+		record := idempotencyRecord{}
+		assert.Nil(t, json.Unmarshal([]byte(store.values[idempotencyTestKey]), &record))
+		assert.Equal(t, idempotencyStatusComplete, record.Status)
+		assert.Equal(t, idempotencyTTL, store.expirations[idempotencyTestKey])
+	})
+
+	t.Run("record expiring while read is claimed afresh", func(t *testing.T) {
+		store := &vanishingStore{memoryStore: newMemoryStore()}
+		calls := 0
+		e := idempotentServer(store, func(c echo.Context) error {
+			calls++
+			return c.JSON(http.StatusCreated, nil)
+		})
+
+		rec := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("server error releases the key", func(t *testing.T) {
+		store := newMemoryStore()
+		calls := 0
+		e := idempotentServer(store, func(c echo.Context) error {
+			calls++
+			if calls == 1 {
+				return c.JSON(http.StatusInternalServerError, nil)
+			}
+			return c.JSON(http.StatusCreated, nil)
+		})
+
+		first := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, http.StatusInternalServerError, first.Code)
+		_, kept := store.values[idempotencyTestKey]
+		assert.False(t, kept)
+
+		retry := idempotentRequest(e, "key-1", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("request without a key is passed through", func(t *testing.T) {
+		store := newMemoryStore()
+		calls := 0
+		e := idempotentServer(store, func(c echo.Context) error {
+			calls++
+			return c.JSON(http.StatusCreated, nil)
+		})
+
+		idempotentRequest(e, "", `{"venue_id":"venue_id_1"}`)
+		idempotentRequest(e, "", `{"venue_id":"venue_id_1"}`)
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, store.values)
+	})
+}
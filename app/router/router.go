@@ -4,7 +4,17 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	ctd "github.com/playground-pro-project/playground-pro-api/features/category/data"
+	cth "github.com/playground-pro-project/playground-pro-api/features/category/handler"
+	cts "github.com/playground-pro-project/playground-pro-api/features/category/service"
+	crd "github.com/playground-pro-project/playground-pro-api/features/credit/data"
+	crh "github.com/playground-pro-project/playground-pro-api/features/credit/handler"
+	crs "github.com/playground-pro-project/playground-pro-api/features/credit/service"
+	pyd "github.com/playground-pro-project/playground-pro-api/features/payout/data"
+	pyh "github.com/playground-pro-project/playground-pro-api/features/payout/handler"
+	pys "github.com/playground-pro-project/playground-pro-api/features/payout/service"
 	rsd "github.com/playground-pro-project/playground-pro-api/features/reservation/data"
 	rsh "github.com/playground-pro-project/playground-pro-api/features/reservation/handler"
 	rss "github.com/playground-pro-project/playground-pro-api/features/reservation/service"
@@ -17,7 +27,12 @@ import (
 	vd "github.com/playground-pro-project/playground-pro-api/features/venue/data"
 	vh "github.com/playground-pro-project/playground-pro-api/features/venue/handler"
 	vs "github.com/playground-pro-project/playground-pro-api/features/venue/service"
+	vcd "github.com/playground-pro-project/playground-pro-api/features/voucher/data"
+	vch "github.com/playground-pro-project/playground-pro-api/features/voucher/handler"
+	vcs "github.com/playground-pro-project/playground-pro-api/features/voucher/service"
+	mail "github.com/playground-pro-project/playground-pro-api/utils/email"
 	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
+	"github.com/playground-pro-project/playground-pro-api/utils/redis"
 	"gorm.io/gorm"
 )
 
@@ -31,6 +46,10 @@ func InitRouter(db *gorm.DB, e *echo.Echo) {
 	initUserRouter(db, e)
 	initVenueRouter(db, e)
 	initReservationRouter(db, e)
+	initVoucherRouter(db, e)
+	initCreditRouter(db, e)
+	initPayoutRouter(db, e)
+	initCategoryRouter(db, e)
 }
 
 func initUserRouter(db *gorm.DB, e *echo.Echo) {
@@ -44,8 +63,9 @@ func initUserRouter(db *gorm.DB, e *echo.Echo) {
 	venueHandler := vh.New(venueService)
 
 	reservationData := rsd.New(db)
-	refund := &paymentgateway.MyRefund{}
-	reservationService := rss.New(reservationData, refund)
+	payments := paymentgateway.NewProvider()
+	sender := mail.NewGmailSender(config.EMAIL_SENDER_NAME, config.EMAIL_SENDER_ADDRESS, config.EMAIL_SENDER_PASSWORD)
+	reservationService := rss.New(reservationData, payments, sender)
 	reservationHandler := rsh.New(reservationService)
 
 	e.POST("/register", userHandler.Register())
@@ -61,6 +81,8 @@ func initUserRouter(db *gorm.DB, e *echo.Echo) {
 	e.DELETE("/users/profile-picture", userHandler.RemoveProfilePicture(), middlewares.JWTMiddleware())
 	e.GET("/users/venues", venueHandler.MyVenues(), middlewares.JWTMiddleware())
 	e.GET("/users/reservations", reservationHandler.MyReservation(), middlewares.JWTMiddleware())
+	e.GET("/users/waitlist", reservationHandler.MyWaitlist(), middlewares.JWTMiddleware())
+	e.GET("/users/shares", reservationHandler.MyShares(), middlewares.JWTMiddleware())
 	e.GET("/users/venues/charts", reservationHandler.MyVenueCharts(), middlewares.JWTMiddleware())
 }
 
@@ -74,12 +96,16 @@ func initVenueRouter(db *gorm.DB, e *echo.Echo) {
 	reviewHandler := rh.New(reviewService)
 
 	reservationData := rsd.New(db)
-	refund := &paymentgateway.MyRefund{}
-	reservationService := rss.New(reservationData, refund)
+	payments := paymentgateway.NewProvider()
+	sender := mail.NewGmailSender(config.EMAIL_SENDER_NAME, config.EMAIL_SENDER_ADDRESS, config.EMAIL_SENDER_PASSWORD)
+	reservationService := rss.New(reservationData, payments, sender)
 	reservationHandler := rsh.New(reservationService)
 
 	e.POST("/venues", venueHandler.CreateVenue(), middlewares.JWTMiddleware())
 	e.GET("/venues", venueHandler.SearchVenues())
+	e.GET("/venues/map", venueHandler.VenueMap())
+	e.GET("/venues/suggest", venueHandler.SuggestVenues())
+	e.GET("/amenities", venueHandler.GetAmenities())
 	e.GET("/venues/:venue_id", venueHandler.SelectVenue(), middlewares.JWTMiddleware())
 	e.PUT("/venues/:venue_id", venueHandler.EditVenue(), middlewares.JWTMiddleware())
 	e.DELETE("/venues/:venue_id", venueHandler.UnregisterVenue(), middlewares.JWTMiddleware())
@@ -90,15 +116,84 @@ func initVenueRouter(db *gorm.DB, e *echo.Echo) {
 	e.POST("/venues/:venue_id/images", venueHandler.CreateVenueImage(), middlewares.JWTMiddleware())
 	e.GET("/venues/:venue_id/images", venueHandler.GetAllVenueImage(), middlewares.JWTMiddleware())
 	e.GET("/venues/:venue_id/availability", reservationHandler.CheckAvailability(), middlewares.JWTMiddleware())
+	e.GET("/venues/:venue_id/slots", reservationHandler.AvailabilitySlots(), middlewares.JWTMiddleware())
+	e.POST("/venues/:venue_id/holds", reservationHandler.CreateHold(), middlewares.JWTMiddleware())
+	e.POST("/venues/:venue_id/waitlist", reservationHandler.JoinWaitlist(), middlewares.JWTMiddleware())
+	e.POST("/venues/:venue_id/quote", reservationHandler.QuoteReservation())
+	e.GET("/venues/:venue_id/hours", venueHandler.GetOpeningHours())
+	e.PUT("/venues/:venue_id/hours", venueHandler.SetOpeningHours(), middlewares.JWTMiddleware())
+	e.GET("/venues/:venue_id/cancellation-policy", venueHandler.GetCancellationPolicy())
+	e.PUT("/venues/:venue_id/cancellation-policy", venueHandler.SetCancellationPolicy(), middlewares.JWTMiddleware())
+	e.GET("/venues/:venue_id/pricing-rules", venueHandler.GetPricingRules())
+	e.PUT("/venues/:venue_id/pricing-rules", venueHandler.SetPricingRules(), middlewares.JWTMiddleware())
+	e.GET("/venues/:venue_id/closures", venueHandler.GetClosures())
+	e.POST("/venues/:venue_id/closures", venueHandler.CreateClosure(), middlewares.JWTMiddleware())
+	e.DELETE("/venues/:venue_id/closures/:closure_id", venueHandler.DeleteClosure(), middlewares.JWTMiddleware())
 }
 
 func initReservationRouter(db *gorm.DB, e *echo.Echo) {
 	reservationData := rsd.New(db)
-	refund := &paymentgateway.MyRefund{}
-	reservationService := rss.New(reservationData, refund)
+	payments := paymentgateway.NewProvider()
+	sender := mail.NewGmailSender(config.EMAIL_SENDER_NAME, config.EMAIL_SENDER_ADDRESS, config.EMAIL_SENDER_PASSWORD)
+	reservationService := rss.New(reservationData, payments, sender)
 	reservationHandler := rsh.New(reservationService)
+	idempotencyStore := redis.NewRedisClient()
 
-	e.POST("/reservations", reservationHandler.MakeReservation(), middlewares.JWTMiddleware())
+	e.POST("/reservations", reservationHandler.MakeReservation(), middlewares.JWTMiddleware(), middlewares.Idempotency(idempotencyStore))
+	e.POST("/reservations/recurring", reservationHandler.MakeRecurringReservation(), middlewares.JWTMiddleware(), middlewares.Idempotency(idempotencyStore))
 	e.POST("/reservations/status", reservationHandler.ReservationStatus())
+	e.POST("/reservations/:reservation_id/cancel", reservationHandler.CancelReservation(), middlewares.JWTMiddleware())
+	e.PUT("/reservations/:reservation_id/schedule", reservationHandler.RescheduleReservation(), middlewares.JWTMiddleware())
 	e.GET("/reservations/:payment_id", reservationHandler.DetailTransaction(), middlewares.JWTMiddleware())
+	e.GET("/reservations/:payment_id/invoice.pdf", reservationHandler.DownloadInvoice(), middlewares.JWTMiddleware())
+	e.DELETE("/waitlist/:waitlist_id", reservationHandler.LeaveWaitlist(), middlewares.JWTMiddleware())
+	e.GET("/admin/payments/discrepancies", reservationHandler.PaymentDiscrepancies(), middlewares.JWTMiddleware())
+}
+
+func initVoucherRouter(db *gorm.DB, e *echo.Echo) {
+	voucherData := vcd.New(db)
+	voucherService := vcs.New(voucherData)
+	voucherHandler := vch.New(voucherService)
+
+	e.POST("/vouchers", voucherHandler.CreateVoucher(), middlewares.JWTMiddleware())
+	e.GET("/vouchers", voucherHandler.MyVouchers(), middlewares.JWTMiddleware())
+	e.DELETE("/vouchers/:voucher_id", voucherHandler.DeleteVoucher(), middlewares.JWTMiddleware())
+}
+
+func initCreditRouter(db *gorm.DB, e *echo.Echo) {
+	creditData := crd.New(db)
+	payments := paymentgateway.NewProvider()
+	creditService := crs.New(creditData, payments)
+	creditHandler := crh.New(creditService)
+
+	e.GET("/venues/:venue_id/packages", creditHandler.GetPackages())
+	e.POST("/venues/:venue_id/packages", creditHandler.CreatePackage(), middlewares.JWTMiddleware())
+	e.DELETE("/venues/:venue_id/packages/:package_id", creditHandler.DeletePackage(), middlewares.JWTMiddleware())
+	e.POST("/packages/:package_id/purchase", creditHandler.BuyPackage(), middlewares.JWTMiddleware())
+	e.GET("/credits", creditHandler.MyCredits(), middlewares.JWTMiddleware())
+	e.GET("/credits/history", creditHandler.MyCreditHistory(), middlewares.JWTMiddleware())
+}
+
+func initPayoutRouter(db *gorm.DB, e *echo.Echo) {
+	payoutData := pyd.New(db)
+	payoutService := pys.New(payoutData)
+	payoutHandler := pyh.New(payoutService)
+
+	e.GET("/users/payouts", payoutHandler.MyPayouts(), middlewares.JWTMiddleware())
+	e.PUT("/users/payouts/account", payoutHandler.SetPayoutAccount(), middlewares.JWTMiddleware())
+	e.POST("/admin/payouts/batches", payoutHandler.CreateBatch(), middlewares.JWTMiddleware())
+	e.GET("/admin/payouts/batches", payoutHandler.GetBatches(), middlewares.JWTMiddleware())
+	e.PUT("/admin/payouts/batches/:batch_id/paid", payoutHandler.MarkBatchPaid(), middlewares.JWTMiddleware())
+	e.GET("/admin/payouts/batches/:batch_id/export", payoutHandler.ExportBatch(), middlewares.JWTMiddleware())
+}
+
+func initCategoryRouter(db *gorm.DB, e *echo.Echo) {
+	categoryData := ctd.New(db)
+	categoryService := cts.New(categoryData)
+	categoryHandler := cth.New(categoryService)
+
+	e.GET("/categories", categoryHandler.GetCategories())
+	e.POST("/admin/categories", categoryHandler.CreateCategory(), middlewares.JWTMiddleware())
+	e.PUT("/admin/categories/:category_id", categoryHandler.UpdateCategory(), middlewares.JWTMiddleware())
+	e.DELETE("/admin/categories/:category_id", categoryHandler.DeleteCategory(), middlewares.JWTMiddleware())
 }
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/app/config"
+	rsd "github.com/playground-pro-project/playground-pro-api/features/reservation/data"
+	rss "github.com/playground-pro-project/playground-pro-api/features/reservation/service"
+	mail "github.com/playground-pro-project/playground-pro-api/utils/email"
+	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultExpiryJobInterval    = 5 * time.Minute
+	defaultReconcileJobInterval = 15 * time.Minute
+)
+
+func InitScheduler(db *gorm.DB) *Scheduler {
+	s := New()
+	initExpiryJob(db, s)
+	initReconcileJob(db, s)
+	return s
+}
+
+func initExpiryJob(db *gorm.DB, s *Scheduler) {
+	reservationData := rsd.New(db)
+	payments := paymentgateway.NewProvider()
+	sender := mail.NewGmailSender(config.EMAIL_SENDER_NAME, config.EMAIL_SENDER_ADDRESS, config.EMAIL_SENDER_PASSWORD)
+	reservationService := rss.New(reservationData, payments, sender)
+
+	interval := defaultExpiryJobInterval
+	if config.EXPIRY_JOB_INTERVAL > 0 {
+		interval = time.Duration(config.EXPIRY_JOB_INTERVAL) * time.Minute
+	}
+
+	s.Register(Job{
+		Name:     "expire-pending-payments",
+		Interval: interval,
+		Run: func() error {
+			_, err := reservationService.ExpirePendingPayments()
+			return err
+		},
+	})
+
+	s.Register(Job{
+		Name:     "expire-waitlist-offers",
+		Interval: interval,
+		Run: func() error {
+			_, err := reservationService.ExpireWaitlistOffers()
+			return err
+		},
+	})
+}
+
+func initReconcileJob(db *gorm.DB, s *Scheduler) {
+	reservationData := rsd.New(db)
+	payments := paymentgateway.NewProvider()
+	sender := mail.NewGmailSender(config.EMAIL_SENDER_NAME, config.EMAIL_SENDER_ADDRESS, config.EMAIL_SENDER_PASSWORD)
+	reservationService := rss.New(reservationData, payments, sender)
+
+	interval := defaultReconcileJobInterval
+	if config.RECONCILE_INTERVAL > 0 {
+		interval = time.Duration(config.RECONCILE_INTERVAL) * time.Minute
+	}
+
+	s.Register(Job{
+		Name:     "reconcile-pending-payments",
+		Interval: interval,
+		Run: func() error {
+			_, err := reservationService.ReconcilePayments()
+			return err
+		},
+	})
+}
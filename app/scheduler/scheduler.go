@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+)
+
+var log = middlewares.Log()
+
+// Job is a task run periodically in the background.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+type Scheduler struct {
+	jobs []Job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{
+		stop: make(chan struct{}),
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every job once right away and then on its interval, each in its own goroutine.
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+	log.Sugar().Infof("scheduler started with %d job(s)", len(s.jobs))
+}
+
+// Stop signals all jobs to finish and waits for running ones to return.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job Job) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(job)
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Sugar().Errorf("job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(); err != nil {
+		log.Sugar().Errorf("job %s failed: %s", job.Name, err.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/category"
+)
+
+type Category struct {
+	CategoryID  string    `gorm:"primaryKey;type:varchar(45)"`
+	Name        string    `gorm:"type:varchar(30);not null;uniqueIndex"`
+	Icon        string    `gorm:"type:varchar(225)"`
+	SlotMinutes int       `gorm:"type:int;default:60"`
+	PlayerCount int       `gorm:"type:int"`
+	CreatedAt   time.Time `gorm:"type:datetime"`
+	UpdatedAt   time.Time `gorm:"type:datetime"`
+}
+
+// Struct helper to read a category together with how many live venues use it
+type CategoryUsage struct {
+	Category
+	VenueCount int64
+}
+
+// DefaultCategories are the sports venues were limited to before categories were managed
+// by admins. They are created on the first migration and existing venues are moved onto
+// them.
+var DefaultCategories = []Category{
+	{Name: "basketball", SlotMinutes: 60, PlayerCount: 10},
+	{Name: "football", SlotMinutes: 90, PlayerCount: 22},
+	{Name: "futsal", SlotMinutes: 60, PlayerCount: 10},
+	{Name: "badminton", SlotMinutes: 60, PlayerCount: 4},
+}
+
+func categoryModels(c Category) category.CategoryCore {
+	return category.CategoryCore{
+		CategoryID:  c.CategoryID,
+		Name:        c.Name,
+		Icon:        c.Icon,
+		SlotMinutes: c.SlotMinutes,
+		PlayerCount: c.PlayerCount,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+func categoryEntities(c category.CategoryCore) Category {
+	return Category{
+		CategoryID:  c.CategoryID,
+		Name:        c.Name,
+		Icon:        c.Icon,
+		SlotMinutes: c.SlotMinutes,
+		PlayerCount: c.PlayerCount,
+	}
+}
+
+func modelToCategoryCore(usages []CategoryUsage) []category.CategoryCore {
+	result := make([]category.CategoryCore, len(usages))
+	for i, u := range usages {
+		result[i] = categoryModels(u.Category)
+		result[i].VenueCount = u.VenueCount
+	}
+
+	return result
+}
@@ -0,0 +1,174 @@
+package data
+
+import (
+	"errors"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/category"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	"gorm.io/gorm"
+)
+
+var log = middlewares.Log()
+
+type categoryQuery struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) category.CategoryData {
+	return &categoryQuery{
+		db: db,
+	}
+}
+
+// GetUserRole implements category.CategoryData.
+func (cq *categoryQuery) GetUserRole(userId string) (string, error) {
+	var role string
+	query := cq.db.Table("users").
+		Select("role").
+		Where("user_id = ? AND deleted_at IS NULL", userId).
+		Scan(&role)
+	if query.Error != nil {
+		log.Sugar().Error("error executing user query:", query.Error)
+		return "", query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("user not found")
+		return "", errors.New("user not found")
+	}
+
+	return role, nil
+}
+
+// SelectCategories implements category.CategoryData.
+func (cq *categoryQuery) SelectCategories() ([]category.CategoryCore, error) {
+	usages := []CategoryUsage{}
+	query := cq.db.Table("categories").
+		Select("categories.*, COUNT(venues.venue_id) AS venue_count").
+		Joins("LEFT JOIN venues ON venues.category_id = categories.category_id AND venues.deleted_at IS NULL").
+		Group("categories.category_id").
+		Order("categories.name ASC").
+		Scan(&usages)
+	if query.Error != nil {
+		log.Sugar().Error("error executing categories query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToCategoryCore(usages), nil
+}
+
+// InsertCategory implements category.CategoryData.
+func (cq *categoryQuery) InsertCategory(request category.CategoryCore) (category.CategoryCore, error) {
+	var count int64
+	query := cq.db.Model(&Category{}).Where("name = ?", request.Name).Count(&count)
+	if query.Error != nil {
+		log.Sugar().Error("error executing category query:", query.Error)
+		return category.CategoryCore{}, errors.New("internal server error while checking category name")
+	}
+	if count > 0 {
+		log.Warn("category already exists")
+		return category.CategoryCore{}, errors.New("category already exists")
+	}
+
+	request.CategoryID = helper.GenerateCategoryID()
+	req := categoryEntities(request)
+	query = cq.db.Create(&req)
+	if query.Error != nil {
+		log.Sugar().Error("error while creating category:", query.Error)
+		return category.CategoryCore{}, errors.New("internal server error while creating category")
+	}
+
+	log.Sugar().Infof("new category has been created: %s", req.CategoryID)
+	return categoryModels(req), nil
+}
+
+// UpdateCategory implements category.CategoryData.
+func (cq *categoryQuery) UpdateCategory(categoryId string, request category.CategoryCore) (category.CategoryCore, error) {
+	result := Category{}
+	err := cq.db.Transaction(func(tx *gorm.DB) error {
+		current := Category{}
+		query := tx.Where("category_id = ?", categoryId).First(&current)
+		if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+			log.Warn("category not found")
+			return errors.New("category not found")
+		} else if query.Error != nil {
+			log.Sugar().Error("error executing category query:", query.Error)
+			return query.Error
+		}
+
+		var count int64
+		query = tx.Model(&Category{}).Where("name = ? AND category_id <> ?", request.Name, categoryId).Count(&count)
+		if query.Error != nil {
+			log.Sugar().Error("error executing category query:", query.Error)
+			return query.Error
+		}
+		if count > 0 {
+			log.Warn("category already exists")
+			return errors.New("category already exists")
+		}
+
+		query = tx.Model(&current).Updates(map[string]interface{}{
+			"name":         request.Name,
+			"icon":         request.Icon,
+			"slot_minutes": request.SlotMinutes,
+			"player_count": request.PlayerCount,
+		})
+		if query.Error != nil {
+			log.Sugar().Error("error while updating category:", query.Error)
+			return query.Error
+		}
+
+		result = current
+		return nil
+	})
+	if err != nil {
+		return category.CategoryCore{}, err
+	}
+
+	result.Name = request.Name
+	result.Icon = request.Icon
+	result.SlotMinutes = request.SlotMinutes
+	result.PlayerCount = request.PlayerCount
+	return categoryModels(result), nil
+}
+
+// DeleteCategory implements category.CategoryData. Categories still used by a venue or a
+// voucher are kept.
+func (cq *categoryQuery) DeleteCategory(categoryId string) error {
+	var count int64
+	query := cq.db.Table("venues").
+		Where("category_id = ? AND deleted_at IS NULL", categoryId).
+		Count(&count)
+	if query.Error != nil {
+		log.Sugar().Error("error executing venues query:", query.Error)
+		return query.Error
+	}
+	if count > 0 {
+		log.Warn("category is still used by venues")
+		return errors.New("category is still used by venues")
+	}
+
+	query = cq.db.Table("vouchers").
+		Where("category_id = ? AND deleted_at IS NULL", categoryId).
+		Count(&count)
+	if query.Error != nil {
+		log.Sugar().Error("error executing vouchers query:", query.Error)
+		return query.Error
+	}
+	if count > 0 {
+		log.Warn("category is still used by vouchers")
+		return errors.New("category is still used by vouchers")
+	}
+
+	query = cq.db.Where("category_id = ?", categoryId).Delete(&Category{})
+	if query.Error != nil {
+		log.Sugar().Error("error while deleting category:", query.Error)
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("category not found")
+		return errors.New("category not found")
+	}
+
+	return nil
+}
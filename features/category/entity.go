@@ -0,0 +1,42 @@
+package category
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CategoryCore is a sport venues are listed under. SlotMinutes is the default booking
+// length and PlayerCount how many players a booking usually takes.
+type CategoryCore struct {
+	CategoryID  string
+	Name        string
+	Icon        string
+	SlotMinutes int
+	PlayerCount int
+	VenueCount  int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CategoryHandler interface {
+	GetCategories() echo.HandlerFunc
+	CreateCategory() echo.HandlerFunc
+	UpdateCategory() echo.HandlerFunc
+	DeleteCategory() echo.HandlerFunc
+}
+
+type CategoryService interface {
+	GetCategories() ([]CategoryCore, error)
+	CreateCategory(userId string, request CategoryCore) (CategoryCore, error)
+	UpdateCategory(userId string, categoryId string, request CategoryCore) (CategoryCore, error)
+	DeleteCategory(userId string, categoryId string) error
+}
+
+type CategoryData interface {
+	GetUserRole(userId string) (string, error)
+	SelectCategories() ([]CategoryCore, error)
+	InsertCategory(request CategoryCore) (CategoryCore, error)
+	UpdateCategory(categoryId string, request CategoryCore) (CategoryCore, error)
+	DeleteCategory(categoryId string) error
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/category"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+var log = middlewares.Log()
+
+type categoryHandler struct {
+	service category.CategoryService
+}
+
+func New(cs category.CategoryService) category.CategoryHandler {
+	return &categoryHandler{
+		service: cs,
+	}
+}
+
+// GetCategories implements category.CategoryHandler.
+func (ch *categoryHandler) GetCategories() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		categories, err := ch.service.GetCategories()
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]categoryResponse, len(categories))
+		for i, ctg := range categories {
+			result[i] = categoryResp(ctg)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// CreateCategory implements category.CategoryHandler.
+func (ch *categoryHandler) CreateCategory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := categoryRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := ch.service.CreateCategory(userId, requestCategory(req))
+		if err != nil {
+			return categoryError(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully created category", categoryResp(result), nil))
+	}
+}
+
+// UpdateCategory implements category.CategoryHandler.
+func (ch *categoryHandler) UpdateCategory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := categoryRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := ch.service.UpdateCategory(userId, c.Param("category_id"), requestCategory(req))
+		if err != nil {
+			return categoryError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully updated category", categoryResp(result), nil))
+	}
+}
+
+// DeleteCategory implements category.CategoryHandler.
+func (ch *categoryHandler) DeleteCategory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		err := ch.service.DeleteCategory(userId, c.Param("category_id"))
+		if err != nil {
+			return categoryError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully deleted category", nil, nil))
+	}
+}
+
+func categoryError(c echo.Context, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "only admins"):
+		log.Error(err.Error())
+		return helper.UnauthorizedError(c, "Only admins can manage categories")
+	case strings.Contains(err.Error(), "not found"):
+		log.Error(err.Error())
+		return helper.NotFoundError(c, "The requested resource was not found")
+	case strings.Contains(err.Error(), "invalid"),
+		strings.Contains(err.Error(), "empty"),
+		strings.Contains(err.Error(), "already exists"),
+		strings.Contains(err.Error(), "still used"):
+		log.Error("bad request, " + err.Error())
+		return helper.BadRequestError(c, "Bad request, "+err.Error())
+	default:
+		log.Error("internal server error")
+		return helper.InternalServerError(c, "Internal server error")
+	}
+}
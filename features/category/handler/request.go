@@ -0,0 +1,19 @@
+package handler
+
+import "github.com/playground-pro-project/playground-pro-api/features/category"
+
+type categoryRequest struct {
+	Name        string `json:"name" form:"name"`
+	Icon        string `json:"icon" form:"icon"`
+	SlotMinutes int    `json:"slot_minutes" form:"slot_minutes"`
+	PlayerCount int    `json:"player_count" form:"player_count"`
+}
+
+func requestCategory(r categoryRequest) category.CategoryCore {
+	return category.CategoryCore{
+		Name:        r.Name,
+		Icon:        r.Icon,
+		SlotMinutes: r.SlotMinutes,
+		PlayerCount: r.PlayerCount,
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"github.com/playground-pro-project/playground-pro-api/features/category"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+type categoryResponse struct {
+	CategoryID  string           `json:"category_id"`
+	Name        string           `json:"name"`
+	Icon        string           `json:"icon,omitempty"`
+	SlotMinutes int              `json:"slot_minutes"`
+	PlayerCount int              `json:"player_count"`
+	VenueCount  int64            `json:"venue_count"`
+	CreatedAt   helper.LocalTime `json:"created_at"`
+	UpdatedAt   helper.LocalTime `json:"updated_at"`
+}
+
+func categoryResp(c category.CategoryCore) categoryResponse {
+	return categoryResponse{
+		CategoryID:  c.CategoryID,
+		Name:        c.Name,
+		Icon:        c.Icon,
+		SlotMinutes: c.SlotMinutes,
+		PlayerCount: c.PlayerCount,
+		VenueCount:  c.VenueCount,
+		CreatedAt:   helper.LocalTime(c.CreatedAt),
+		UpdatedAt:   helper.LocalTime(c.UpdatedAt),
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/category"
+)
+
+var log = middlewares.Log()
+
+var namePattern = regexp.MustCompile(`^[a-z0-9-]{2,30}$`)
+
+type categoryService struct {
+	query category.CategoryData
+}
+
+func New(cd category.CategoryData) category.CategoryService {
+	return &categoryService{
+		query: cd,
+	}
+}
+
+// GetCategories implements category.CategoryService.
+func (cs *categoryService) GetCategories() ([]category.CategoryCore, error) {
+	categories, err := cs.query.SelectCategories()
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return categories, nil
+}
+
+// CreateCategory implements category.CategoryService.
+func (cs *categoryService) CreateCategory(userId string, request category.CategoryCore) (category.CategoryCore, error) {
+	request, err := validateCategory(request)
+	if err != nil {
+		return category.CategoryCore{}, err
+	}
+
+	if err := cs.requireAdmin(userId); err != nil {
+		return category.CategoryCore{}, err
+	}
+
+	result, err := cs.query.InsertCategory(request)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return category.CategoryCore{}, errors.New("category already exists")
+		}
+		log.Error("internal server error")
+		return category.CategoryCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// UpdateCategory implements category.CategoryService.
+func (cs *categoryService) UpdateCategory(userId string, categoryId string, request category.CategoryCore) (category.CategoryCore, error) {
+	request, err := validateCategory(request)
+	if err != nil {
+		return category.CategoryCore{}, err
+	}
+
+	if err := cs.requireAdmin(userId); err != nil {
+		return category.CategoryCore{}, err
+	}
+
+	result, err := cs.query.UpdateCategory(categoryId, request)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return category.CategoryCore{}, errors.New("category not found")
+		case strings.Contains(err.Error(), "already exists"):
+			return category.CategoryCore{}, errors.New("category already exists")
+		}
+		log.Error("internal server error")
+		return category.CategoryCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// DeleteCategory implements category.CategoryService.
+func (cs *categoryService) DeleteCategory(userId string, categoryId string) error {
+	if err := cs.requireAdmin(userId); err != nil {
+		return err
+	}
+
+	err := cs.query.DeleteCategory(categoryId)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return errors.New("category not found")
+		case strings.Contains(err.Error(), "still used by venues"):
+			return errors.New("category is still used by venues")
+		case strings.Contains(err.Error(), "still used by vouchers"):
+			return errors.New("category is still used by vouchers")
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+func (cs *categoryService) requireAdmin(userId string) error {
+	role, err := cs.query.GetUserRole(userId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("user not found")
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+	if role != "admin" {
+		log.Warn("user is not allowed to manage categories")
+		return errors.New("only admins can manage categories")
+	}
+
+	return nil
+}
+
+func validateCategory(request category.CategoryCore) (category.CategoryCore, error) {
+	request.Name = strings.ToLower(strings.TrimSpace(request.Name))
+	request.Icon = strings.TrimSpace(request.Icon)
+
+	var message string
+	switch {
+	case request.Name == "":
+		message = "name cannot be empty"
+	case !namePattern.MatchString(request.Name):
+		message = "invalid name, expected 2 to 30 letters, digits or dashes"
+	case request.SlotMinutes <= 0:
+		message = "invalid slot_minutes, must be greater than 0"
+	case request.PlayerCount <= 0:
+		message = "invalid player_count, must be greater than 0"
+	}
+	if message != "" {
+		log.Warn(message)
+		return category.CategoryCore{}, errors.New(message)
+	}
+
+	return request, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/playground-pro-project/playground-pro-api/features/category"
+	"github.com/playground-pro-project/playground-pro-api/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCategories(t *testing.T) {
+	data := mocks.NewCategoryData(t)
+	service := New(data)
+
+	t.Run("success", func(t *testing.T) {
+		categories := []category.CategoryCore{
+			{CategoryID: "CTG-1", Name: "badminton", SlotMinutes: 60, PlayerCount: 4, VenueCount: 2},
+			{CategoryID: "CTG-2", Name: "padel", SlotMinutes: 90, PlayerCount: 4},
+		}
+		data.On("SelectCategories").Return(categories, nil).Once()
+
+		result, err := service.GetCategories()
+		assert.Nil(t, err)
+		assert.Equal(t, categories, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		data.On("SelectCategories").Return(nil, errors.New("connection refused")).Once()
+
+		_, err := service.GetCategories()
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestCreateCategory(t *testing.T) {
+	data := mocks.NewCategoryData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+	request := category.CategoryCore{Name: " Padel ", Icon: "https://icons/padel.svg", SlotMinutes: 90, PlayerCount: 4}
+
+	t.Run("success", func(t *testing.T) {
+		expected := request
+		expected.Name = "padel"
+		created := expected
+		created.CategoryID = "CTG-1"
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("InsertCategory", expected).Return(created, nil).Once()
+
+		result, err := service.CreateCategory(adminId, request)
+		assert.Nil(t, err)
+		assert.Equal(t, created, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not an admin", func(t *testing.T) {
+		data.On("GetUserRole", "owner_id_1").Return("owner", nil).Once()
+
+		_, err := service.CreateCategory("owner_id_1", request)
+		assert.EqualError(t, err, "only admins can manage categories")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - duplicate name", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("InsertCategory", category.CategoryCore{Name: "padel", Icon: request.Icon, SlotMinutes: 90, PlayerCount: 4}).
+			Return(category.CategoryCore{}, errors.New("category already exists")).Once()
+
+		_, err := service.CreateCategory(adminId, request)
+		assert.EqualError(t, err, "category already exists")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			mutate  func(c *category.CategoryCore)
+			message string
+		}{
+			{"empty name", func(c *category.CategoryCore) { c.Name = "  " }, "name cannot be empty"},
+			{"name with spaces", func(c *category.CategoryCore) { c.Name = "table tennis" }, "invalid name"},
+			{"slot length", func(c *category.CategoryCore) { c.SlotMinutes = 0 }, "invalid slot_minutes"},
+			{"player count", func(c *category.CategoryCore) { c.PlayerCount = -2 }, "invalid player_count"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				invalid := request
+				tc.mutate(&invalid)
+				_, err := service.CreateCategory(adminId, invalid)
+				assert.NotNil(t, err)
+				assert.ErrorContains(t, err, tc.message)
+			})
+		}
+		data.AssertExpectations(t)
+	})
+}
+
+func TestUpdateCategory(t *testing.T) {
+	data := mocks.NewCategoryData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+	request := category.CategoryCore{Name: "table-tennis", SlotMinutes: 60, PlayerCount: 2}
+
+	t.Run("success", func(t *testing.T) {
+		updated := request
+		updated.CategoryID = "CTG-1"
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("UpdateCategory", "CTG-1", request).Return(updated, nil).Once()
+
+		result, err := service.UpdateCategory(adminId, "CTG-1", request)
+		assert.Nil(t, err)
+		assert.Equal(t, updated, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - category not found", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("UpdateCategory", "CTG-2", request).Return(category.CategoryCore{}, errors.New("category not found")).Once()
+
+		_, err := service.UpdateCategory(adminId, "CTG-2", request)
+		assert.EqualError(t, err, "category not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - user not found", func(t *testing.T) {
+		data.On("GetUserRole", "user_id_9").Return("", errors.New("user not found")).Once()
+
+		_, err := service.UpdateCategory("user_id_9", "CTG-1", request)
+		assert.EqualError(t, err, "user not found")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestDeleteCategory(t *testing.T) {
+	data := mocks.NewCategoryData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("DeleteCategory", "CTG-1").Return(nil).Once()
+
+		err := service.DeleteCategory(adminId, "CTG-1")
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - still used by venues", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("DeleteCategory", "CTG-2").Return(errors.New("category is still used by venues")).Once()
+
+		err := service.DeleteCategory(adminId, "CTG-2")
+		assert.EqualError(t, err, "category is still used by venues")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - still used by vouchers", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("DeleteCategory", "CTG-3").Return(errors.New("category is still used by vouchers")).Once()
+
+		err := service.DeleteCategory(adminId, "CTG-3")
+		assert.EqualError(t, err, "category is still used by vouchers")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not an admin", func(t *testing.T) {
+		data.On("GetUserRole", "user_id_1").Return("user", nil).Once()
+
+		err := service.DeleteCategory("user_id_1", "CTG-1")
+		assert.EqualError(t, err, "only admins can manage categories")
+		data.AssertExpectations(t)
+	})
+}
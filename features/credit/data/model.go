@@ -0,0 +1,155 @@
+package data
+
+import (
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/credit"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"gorm.io/gorm"
+)
+
+type CreditPackage struct {
+	PackageID string         `gorm:"primaryKey;type:varchar(45)"`
+	VenueID   string         `gorm:"type:varchar(45);index"`
+	Name      string         `gorm:"type:varchar(100);not null"`
+	Hours     float64        `gorm:"type:double"`
+	Price     float64        `gorm:"type:double"`
+	CreatedAt time.Time      `gorm:"type:datetime"`
+	UpdatedAt time.Time      `gorm:"type:datetime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// CreditPurchase keeps the hours and price of a package as they were when it was bought
+type CreditPurchase struct {
+	PurchaseID string    `gorm:"primaryKey;type:varchar(45)"`
+	UserID     string    `gorm:"type:varchar(45);index"`
+	PackageID  string    `gorm:"type:varchar(45);index"`
+	VenueID    string    `gorm:"type:varchar(45)"`
+	Hours      float64   `gorm:"type:double"`
+	Price      float64   `gorm:"type:double"`
+	PaymentID  string    `gorm:"type:varchar(45);index"`
+	CreatedAt  time.Time `gorm:"type:datetime"`
+}
+
+// CreditEntry is a line of the credits ledger, the balance of a user at a venue is the sum of its hours
+type CreditEntry struct {
+	EntryID     string    `gorm:"primaryKey;type:varchar(45)"`
+	UserID      string    `gorm:"type:varchar(45);index:idx_credit_entries_owner"`
+	VenueID     string    `gorm:"type:varchar(45);index:idx_credit_entries_owner"`
+	Hours       float64   `gorm:"type:double"`
+	Kind        string    `gorm:"type:enum('purchase','booking','refund')"`
+	ReferenceID string    `gorm:"type:varchar(45);index"`
+	CreatedAt   time.Time `gorm:"type:datetime"`
+}
+
+// Struct helper to save the Midtrans charge of a purchase, see the reservation feature for the full model
+type Payment struct {
+	PaymentID     string `gorm:"primaryKey;type:varchar(45)"`
+	PaymentMethod string
+	PaymentType   string
+	PaymentCode   string
+	GrandTotal    string
+	Status        string
+	Purpose       string
+	ReferenceID   string
+	ExpiredAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Struct helper to read the balance of a user per venue
+type Balance struct {
+	VenueID   string
+	VenueName string
+	Hours     float64
+}
+
+func packageModels(p CreditPackage) credit.PackageCore {
+	return credit.PackageCore{
+		PackageID: p.PackageID,
+		VenueID:   p.VenueID,
+		Name:      p.Name,
+		Hours:     p.Hours,
+		Price:     p.Price,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+func packageEntities(p credit.PackageCore) CreditPackage {
+	return CreditPackage{
+		PackageID: p.PackageID,
+		VenueID:   p.VenueID,
+		Name:      p.Name,
+		Hours:     p.Hours,
+		Price:     p.Price,
+	}
+}
+
+func modelToPackageCore(packages []CreditPackage) []credit.PackageCore {
+	result := make([]credit.PackageCore, len(packages))
+	for i, p := range packages {
+		result[i] = packageModels(p)
+	}
+
+	return result
+}
+
+func purchaseEntities(p credit.PurchaseCore) CreditPurchase {
+	return CreditPurchase{
+		PurchaseID: p.PurchaseID,
+		UserID:     p.UserID,
+		PackageID:  p.PackageID,
+		VenueID:    p.VenueID,
+		Hours:      p.Hours,
+		Price:      p.Price,
+		PaymentID:  p.Payment.PaymentID,
+	}
+}
+
+func paymentEntities(p reservation.PaymentCore) Payment {
+	result := Payment{
+		PaymentID:     p.PaymentID,
+		PaymentMethod: p.PaymentMethod,
+		PaymentType:   p.PaymentType,
+		PaymentCode:   p.PaymentCode,
+		GrandTotal:    p.GrandTotal,
+		Status:        p.Status,
+		Purpose:       p.Purpose,
+		ReferenceID:   p.ReferenceID,
+	}
+	if !p.ExpiredAt.IsZero() {
+		result.ExpiredAt = &p.ExpiredAt
+	}
+
+	return result
+}
+
+func modelToBalanceCore(balances []Balance) []credit.BalanceCore {
+	result := make([]credit.BalanceCore, len(balances))
+	for i, b := range balances {
+		result[i] = credit.BalanceCore{
+			VenueID:   b.VenueID,
+			VenueName: b.VenueName,
+			Hours:     b.Hours,
+		}
+	}
+
+	return result
+}
+
+func modelToEntryCore(entries []CreditEntry) []credit.EntryCore {
+	result := make([]credit.EntryCore, len(entries))
+	for i, e := range entries {
+		result[i] = credit.EntryCore{
+			EntryID:     e.EntryID,
+			UserID:      e.UserID,
+			VenueID:     e.VenueID,
+			Hours:       e.Hours,
+			Kind:        e.Kind,
+			ReferenceID: e.ReferenceID,
+			CreatedAt:   e.CreatedAt,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"errors"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/credit"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	"gorm.io/gorm"
+)
+
+var log = middlewares.Log()
+
+type creditQuery struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) credit.CreditData {
+	return &creditQuery{
+		db: db,
+	}
+}
+
+// GetVenueOwner implements credit.CreditData.
+func (cq *creditQuery) GetVenueOwner(venueId string) (string, error) {
+	var ownerId string
+	query := cq.db.Table("venues").
+		Select("owner_id").
+		Where("venue_id = ? AND deleted_at IS NULL", venueId).
+		Scan(&ownerId)
+	if query.Error != nil {
+		log.Sugar().Error("error executing venue query:", query.Error)
+		return "", query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("venue record not found")
+		return "", errors.New("venue record not found")
+	}
+
+	return ownerId, nil
+}
+
+// InsertPackage implements credit.CreditData.
+func (cq *creditQuery) InsertPackage(request credit.PackageCore) (credit.PackageCore, error) {
+	request.PackageID = helper.GeneratePackageID()
+	req := packageEntities(request)
+	query := cq.db.Create(&req)
+	if query.Error != nil {
+		log.Sugar().Error("error while creating package:", query.Error)
+		return credit.PackageCore{}, errors.New("internal server error while creating package")
+	}
+
+	log.Sugar().Infof("new package has been created: %s", req.PackageID)
+	return packageModels(req), nil
+}
+
+// GetPackages implements credit.CreditData.
+func (cq *creditQuery) GetPackages(venueId string) ([]credit.PackageCore, error) {
+	packages := []CreditPackage{}
+	query := cq.db.Where("venue_id = ?", venueId).Order("hours ASC").Find(&packages)
+	if query.Error != nil {
+		log.Sugar().Error("error executing packages query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToPackageCore(packages), nil
+}
+
+// GetPackage implements credit.CreditData.
+func (cq *creditQuery) GetPackage(packageId string) (credit.PackageCore, error) {
+	p := CreditPackage{}
+	query := cq.db.Where("package_id = ?", packageId).First(&p)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Warn("package not found")
+		return credit.PackageCore{}, errors.New("package not found")
+	} else if query.Error != nil {
+		log.Sugar().Error("error executing package query:", query.Error)
+		return credit.PackageCore{}, query.Error
+	}
+
+	return packageModels(p), nil
+}
+
+// DeletePackage implements credit.CreditData.
+func (cq *creditQuery) DeletePackage(venueId string, packageId string) error {
+	query := cq.db.Where("package_id = ? AND venue_id = ?", packageId, venueId).Delete(&CreditPackage{})
+	if query.Error != nil {
+		log.Sugar().Error("error while deleting package:", query.Error)
+		return errors.New("internal server error while deleting package")
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("package not found")
+		return errors.New("package not found")
+	}
+
+	return nil
+}
+
+// InsertPurchase implements credit.CreditData.
+func (cq *creditQuery) InsertPurchase(request credit.PurchaseCore) (credit.PurchaseCore, error) {
+	tx := cq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return credit.PurchaseCore{}, errors.New("internal server error on beginning database transaction")
+	}
+
+	// TODO 1 : Save the payment charged by the service, its hours are credited when it settles
+	request.Payment.Purpose = reservation.PaymentForCredits
+	request.Payment.ReferenceID = request.PurchaseID
+	payment := paymentEntities(request.Payment)
+	if err := tx.Create(&payment).Error; err != nil {
+		tx.Rollback()
+		log.Error("error while saving payment")
+		return credit.PurchaseCore{}, errors.New("internal server error while saving payment")
+	}
+
+	// TODO 2 : Save the purchase
+	purchase := purchaseEntities(request)
+	if err := tx.Create(&purchase).Error; err != nil {
+		tx.Rollback()
+		log.Error("error while saving purchase")
+		return credit.PurchaseCore{}, errors.New("internal server error while saving purchase")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error("error on committing database transaction")
+		return credit.PurchaseCore{}, errors.New("internal server error on committing database transaction")
+	}
+
+	request.CreatedAt = purchase.CreatedAt
+	log.Sugar().Infof("new package purchase has been created: %s", request.PurchaseID)
+	return request, nil
+}
+
+// MyCredits implements credit.CreditData.
+func (cq *creditQuery) MyCredits(userId string) ([]credit.BalanceCore, error) {
+	balances := []Balance{}
+	query := cq.db.Table("credit_entries").
+		Select("credit_entries.venue_id, venues.name AS venue_name, SUM(credit_entries.hours) AS hours").
+		Joins("INNER JOIN venues ON venues.venue_id = credit_entries.venue_id").
+		Where("credit_entries.user_id = ?", userId).
+		Group("credit_entries.venue_id, venues.name").
+		Order("venues.name ASC").
+		Scan(&balances)
+	if query.Error != nil {
+		log.Sugar().Error("error executing credits query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToBalanceCore(balances), nil
+}
+
+// MyCreditHistory implements credit.CreditData.
+func (cq *creditQuery) MyCreditHistory(userId string) ([]credit.EntryCore, error) {
+	entries := []CreditEntry{}
+	query := cq.db.Where("user_id = ?", userId).Order("created_at DESC").Find(&entries)
+	if query.Error != nil {
+		log.Sugar().Error("error executing credit entries query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToEntryCore(entries), nil
+}
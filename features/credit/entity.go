@@ -0,0 +1,83 @@
+package credit
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+)
+
+const (
+	EntryPurchase = "purchase"
+	EntryBooking  = "booking"
+	EntryRefund   = "refund"
+)
+
+// PackageCore is a bundle of prepaid hours sold for a single venue.
+type PackageCore struct {
+	PackageID string
+	VenueID   string
+	Name      string
+	Hours     float64
+	Price     float64
+	CreatedAt time.Time
+}
+
+// PurchaseCore is a package bought by a user, its hours are credited once the payment settles.
+type PurchaseCore struct {
+	PurchaseID string
+	UserID     string
+	PackageID  string
+	VenueID    string
+	Hours      float64
+	Price      float64
+	Payment    reservation.PaymentCore
+	CreatedAt  time.Time
+}
+
+// BalanceCore is how many prepaid hours a user has left at a venue.
+type BalanceCore struct {
+	VenueID   string
+	VenueName string
+	Hours     float64
+}
+
+// EntryCore is a line of the credits ledger, hours are positive when credited and negative when spent.
+type EntryCore struct {
+	EntryID     string
+	UserID      string
+	VenueID     string
+	Hours       float64
+	Kind        string
+	ReferenceID string
+	CreatedAt   time.Time
+}
+
+type CreditHandler interface {
+	CreatePackage() echo.HandlerFunc
+	GetPackages() echo.HandlerFunc
+	DeletePackage() echo.HandlerFunc
+	BuyPackage() echo.HandlerFunc
+	MyCredits() echo.HandlerFunc
+	MyCreditHistory() echo.HandlerFunc
+}
+
+type CreditService interface {
+	CreatePackage(userId string, request PackageCore) (PackageCore, error)
+	GetPackages(venueId string) ([]PackageCore, error)
+	DeletePackage(userId string, venueId string, packageId string) error
+	BuyPackage(userId string, packageId string, paymentType string) (PurchaseCore, error)
+	MyCredits(userId string) ([]BalanceCore, error)
+	MyCreditHistory(userId string) ([]EntryCore, error)
+}
+
+type CreditData interface {
+	GetVenueOwner(venueId string) (string, error)
+	InsertPackage(request PackageCore) (PackageCore, error)
+	GetPackages(venueId string) ([]PackageCore, error)
+	GetPackage(packageId string) (PackageCore, error)
+	DeletePackage(venueId string, packageId string) error
+	InsertPurchase(request PurchaseCore) (PurchaseCore, error)
+	MyCredits(userId string) ([]BalanceCore, error)
+	MyCreditHistory(userId string) ([]EntryCore, error)
+}
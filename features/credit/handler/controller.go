@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/credit"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+var log = middlewares.Log()
+
+type creditHandler struct {
+	service credit.CreditService
+}
+
+func New(cs credit.CreditService) credit.CreditHandler {
+	return &creditHandler{
+		service: cs,
+	}
+}
+
+// CreatePackage implements credit.CreditHandler.
+func (ch *creditHandler) CreatePackage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := createPackageRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		request := requestPackage(req)
+		request.VenueID = c.Param("venue_id")
+		result, err := ch.service.CreatePackage(userId, request)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"),
+				strings.Contains(err.Error(), "empty"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully created package", packageResp(result), nil))
+	}
+}
+
+// GetPackages implements credit.CreditHandler.
+func (ch *creditHandler) GetPackages() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		packages, err := ch.service.GetPackages(c.Param("venue_id"))
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				log.Error("venue not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			}
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]packageResponse, len(packages))
+		for i, p := range packages {
+			result[i] = packageResp(p)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// DeletePackage implements credit.CreditHandler.
+func (ch *creditHandler) DeletePackage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		err := ch.service.DeletePackage(userId, c.Param("venue_id"), c.Param("package_id"))
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			}
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully deleted package", nil, nil))
+	}
+}
+
+// BuyPackage implements credit.CreditHandler.
+func (ch *creditHandler) BuyPackage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := buyPackageRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := ch.service.BuyPackage(userId, c.Param("package_id"), req.PaymentType)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error("package not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"),
+				strings.Contains(err.Error(), "empty"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully operation", purchaseResp(result), nil))
+	}
+}
+
+// MyCredits implements credit.CreditHandler.
+func (ch *creditHandler) MyCredits() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		result, err := ch.service.MyCredits(userId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", balances(result), nil))
+	}
+}
+
+// MyCreditHistory implements credit.CreditHandler.
+func (ch *creditHandler) MyCreditHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		result, err := ch.service.MyCreditHistory(userId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", entries(result), nil))
+	}
+}
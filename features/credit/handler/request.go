@@ -0,0 +1,21 @@
+package handler
+
+import "github.com/playground-pro-project/playground-pro-api/features/credit"
+
+type createPackageRequest struct {
+	Name  string  `json:"name" form:"name"`
+	Hours float64 `json:"hours" form:"hours"`
+	Price float64 `json:"price" form:"price"`
+}
+
+type buyPackageRequest struct {
+	PaymentType string `json:"payment_type" form:"payment_type"`
+}
+
+func requestPackage(r createPackageRequest) credit.PackageCore {
+	return credit.PackageCore{
+		Name:  r.Name,
+		Hours: r.Hours,
+		Price: r.Price,
+	}
+}
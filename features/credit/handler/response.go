@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"github.com/playground-pro-project/playground-pro-api/features/credit"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+type packageResponse struct {
+	PackageID string  `json:"package_id"`
+	VenueID   string  `json:"venue_id"`
+	Name      string  `json:"name"`
+	Hours     float64 `json:"hours"`
+	Price     float64 `json:"price"`
+}
+
+type purchaseResponse struct {
+	PurchaseID    string           `json:"purchase_id"`
+	PackageID     string           `json:"package_id"`
+	VenueID       string           `json:"venue_id"`
+	Hours         float64          `json:"hours"`
+	PaymentID     string           `json:"payment_id"`
+	PaymentMethod string           `json:"payment_method"`
+	PaymentType   string           `json:"payment_type"`
+	PaymentCode   string           `json:"payment_code"`
+	GrandTotal    string           `json:"grand_total"`
+	ExpiredAt     helper.LocalTime `json:"expired_at"`
+}
+
+type balanceResponse struct {
+	VenueID   string  `json:"venue_id"`
+	VenueName string  `json:"venue_name"`
+	Hours     float64 `json:"hours"`
+}
+
+type entryResponse struct {
+	EntryID     string           `json:"entry_id"`
+	VenueID     string           `json:"venue_id"`
+	Hours       float64          `json:"hours"`
+	Kind        string           `json:"kind"`
+	ReferenceID string           `json:"reference_id"`
+	CreatedAt   helper.LocalTime `json:"created_at"`
+}
+
+func packageResp(p credit.PackageCore) packageResponse {
+	return packageResponse{
+		PackageID: p.PackageID,
+		VenueID:   p.VenueID,
+		Name:      p.Name,
+		Hours:     p.Hours,
+		Price:     p.Price,
+	}
+}
+
+func purchaseResp(p credit.PurchaseCore) purchaseResponse {
+	return purchaseResponse{
+		PurchaseID:    p.PurchaseID,
+		PackageID:     p.PackageID,
+		VenueID:       p.VenueID,
+		Hours:         p.Hours,
+		PaymentID:     p.Payment.PaymentID,
+		PaymentMethod: p.Payment.PaymentMethod,
+		PaymentType:   p.Payment.PaymentType,
+		PaymentCode:   p.Payment.PaymentCode,
+		GrandTotal:    p.Payment.GrandTotal,
+		ExpiredAt:     helper.LocalTime(p.Payment.ExpiredAt),
+	}
+}
+
+func balances(bs []credit.BalanceCore) []balanceResponse {
+	result := make([]balanceResponse, len(bs))
+	for i, b := range bs {
+		result[i] = balanceResponse{
+			VenueID:   b.VenueID,
+			VenueName: b.VenueName,
+			Hours:     b.Hours,
+		}
+	}
+
+	return result
+}
+
+func entries(es []credit.EntryCore) []entryResponse {
+	result := make([]entryResponse, len(es))
+	for i, e := range es {
+		result[i] = entryResponse{
+			EntryID:     e.EntryID,
+			VenueID:     e.VenueID,
+			Hours:       e.Hours,
+			Kind:        e.Kind,
+			ReferenceID: e.ReferenceID,
+			CreatedAt:   helper.LocalTime(e.CreatedAt),
+		}
+	}
+
+	return result
+}
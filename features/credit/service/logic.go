@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/credit"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
+)
+
+var log = middlewares.Log()
+
+type creditService struct {
+	query    credit.CreditData
+	payments paymentgateway.PaymentProvider
+}
+
+func New(cd credit.CreditData, payments paymentgateway.PaymentProvider) credit.CreditService {
+	return &creditService{
+		query:    cd,
+		payments: payments,
+	}
+}
+
+// CreatePackage implements credit.CreditService.
+func (cs *creditService) CreatePackage(userId string, request credit.PackageCore) (credit.PackageCore, error) {
+	request.Name = strings.TrimSpace(request.Name)
+
+	// TODO 1 : Validate the package
+	var message string
+	switch {
+	case request.Name == "":
+		message = "name cannot be empty"
+	case request.Hours <= 0:
+		message = "invalid hours, must be greater than 0"
+	case request.Price <= 0:
+		message = "invalid price, must be greater than 0"
+	}
+	if message != "" {
+		log.Warn(message)
+		return credit.PackageCore{}, errors.New(message)
+	}
+
+	// TODO 2 : Only the owner of the venue sells its packages
+	if err := cs.ownedVenue(userId, request.VenueID); err != nil {
+		return credit.PackageCore{}, err
+	}
+
+	// TODO 3 : Save the package
+	result, err := cs.query.InsertPackage(request)
+	if err != nil {
+		log.Error("internal server error")
+		return credit.PackageCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// GetPackages implements credit.CreditService.
+func (cs *creditService) GetPackages(venueId string) ([]credit.PackageCore, error) {
+	if _, err := cs.query.GetVenueOwner(venueId); err != nil {
+		return nil, venueError(err)
+	}
+
+	packages, err := cs.query.GetPackages(venueId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return packages, nil
+}
+
+// DeletePackage implements credit.CreditService.
+func (cs *creditService) DeletePackage(userId string, venueId string, packageId string) error {
+	if err := cs.ownedVenue(userId, venueId); err != nil {
+		return err
+	}
+
+	// Hours already bought stay on the balances, only new purchases stop
+	err := cs.query.DeletePackage(venueId, packageId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("package not found")
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// BuyPackage implements credit.CreditService.
+func (cs *creditService) BuyPackage(userId string, packageId string, paymentType string) (credit.PurchaseCore, error) {
+	if paymentType == "" {
+		log.Warn("payment_type cannot be empty")
+		return credit.PurchaseCore{}, errors.New("payment_type cannot be empty")
+	}
+	if paymentType == reservation.PaymentTypeCredits {
+		log.Warn("credits cannot pay for credits")
+		return credit.PurchaseCore{}, errors.New("invalid payment_type, packages cannot be paid with credits")
+	}
+
+	// TODO 1 : Read the package, its hours and price are fixed at the time of purchase
+	p, err := cs.query.GetPackage(packageId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return credit.PurchaseCore{}, errors.New("package not found")
+		}
+		log.Error("internal server error")
+		return credit.PurchaseCore{}, errors.New("internal server error")
+	}
+
+	// TODO 2 : Charge the package, the hours are credited once the payment settles
+	purchase := credit.PurchaseCore{
+		UserID:    userId,
+		PackageID: p.PackageID,
+		VenueID:   p.VenueID,
+		Hours:     p.Hours,
+		Price:     p.Price,
+		Payment: reservation.PaymentCore{
+			PaymentType: paymentType,
+			GrandTotal:  strconv.FormatFloat(p.Price, 'f', 2, 64),
+		},
+	}
+	purchase.PurchaseID = helper.GeneratePurchaseID()
+	charged, err := cs.payments.Charge(purchase.PurchaseID, purchase.Payment)
+	if err != nil {
+		log.Sugar().Errorf("failed to charge purchase %s: %v", purchase.PurchaseID, err)
+		if strings.Contains(err.Error(), "invalid payment_type") {
+			return credit.PurchaseCore{}, errors.New("invalid payment_type")
+		}
+		return credit.PurchaseCore{}, errors.New("internal server error")
+	}
+	purchase.Payment = charged
+
+	// TODO 3 : Save the purchase, voiding the charge if it cannot be kept
+	result, err := cs.query.InsertPurchase(purchase)
+	if err != nil {
+		if err := cs.payments.Cancel(purchase.PurchaseID); err != nil {
+			log.Sugar().Errorf("failed to void the charge of purchase %s, it is left to expire unpaid: %v", purchase.PurchaseID, err)
+		}
+		log.Error("internal server error")
+		return credit.PurchaseCore{}, errors.New("internal server error")
+	}
+
+	log.Sugar().Infof("package %s has been purchased: %s", p.PackageID, result.PurchaseID)
+	return result, nil
+}
+
+// MyCredits implements credit.CreditService.
+func (cs *creditService) MyCredits(userId string) ([]credit.BalanceCore, error) {
+	balances, err := cs.query.MyCredits(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return balances, nil
+}
+
+// MyCreditHistory implements credit.CreditService.
+func (cs *creditService) MyCreditHistory(userId string) ([]credit.EntryCore, error) {
+	entries, err := cs.query.MyCreditHistory(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return entries, nil
+}
+
+func (cs *creditService) ownedVenue(userId string, venueId string) error {
+	ownerId, err := cs.query.GetVenueOwner(venueId)
+	if err != nil {
+		return venueError(err)
+	}
+	if ownerId != userId {
+		log.Warn("venue is owned by someone else")
+		return errors.New("venue record not found")
+	}
+
+	return nil
+}
+
+func venueError(err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return errors.New("venue record not found")
+	}
+	log.Error("internal server error")
+	return errors.New("internal server error")
+}
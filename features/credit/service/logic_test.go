@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/playground-pro-project/playground-pro-api/features/credit"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"github.com/playground-pro-project/playground-pro-api/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePackage(t *testing.T) {
+	data := mocks.NewCreditData(t)
+	payments := mocks.NewPaymentProvider(t)
+	service := New(data, payments)
+	ownerId := "owner_id_1"
+	request := credit.PackageCore{VenueID: "venue_id_1", Name: " 10 hours ", Hours: 10, Price: 900000}
+
+	t.Run("success", func(t *testing.T) {
+		expected := request
+		expected.Name = "10 hours"
+		created := expected
+		created.PackageID = "PKG-1"
+		data.On("GetVenueOwner", "venue_id_1").Return(ownerId, nil).Once()
+		data.On("InsertPackage", expected).Return(created, nil).Once()
+
+		result, err := service.CreatePackage(ownerId, request)
+		assert.Nil(t, err)
+		assert.Equal(t, created, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - venue of another owner", func(t *testing.T) {
+		data.On("GetVenueOwner", "venue_id_1").Return("owner_id_2", nil).Once()
+
+		_, err := service.CreatePackage(ownerId, request)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - venue not found", func(t *testing.T) {
+		data.On("GetVenueOwner", "venue_id_1").Return("", errors.New("venue record not found")).Once()
+
+		_, err := service.CreatePackage(ownerId, request)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+
+	invalid := []struct {
+		name    string
+		request credit.PackageCore
+		message string
+	}{
+		{"error - empty name", credit.PackageCore{VenueID: "venue_id_1", Name: " ", Hours: 10, Price: 900000}, "name cannot be empty"},
+		{"error - no hours", credit.PackageCore{VenueID: "venue_id_1", Name: "10 hours", Price: 900000}, "invalid hours, must be greater than 0"},
+		{"error - free package", credit.PackageCore{VenueID: "venue_id_1", Name: "10 hours", Hours: 10}, "invalid price, must be greater than 0"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.CreatePackage(ownerId, tc.request)
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+}
+
+func TestBuyPackage(t *testing.T) {
+	data := mocks.NewCreditData(t)
+	payments := mocks.NewPaymentProvider(t)
+	service := New(data, payments)
+	userId := "user_id_1"
+	pkg := credit.PackageCore{PackageID: "PKG-1", VenueID: "venue_id_1", Name: "10 hours", Hours: 10, Price: 900000}
+
+	t.Run("success", func(t *testing.T) {
+		payment := reservation.PaymentCore{PaymentType: "bca", GrandTotal: "900000.00"}
+		charged := payment
+		charged.PaymentID = "payment_id_1"
+		charged.PaymentMethod = "bank_transfer"
+		charged.PaymentCode = "88080000000001"
+		charged.Status = "pending"
+		var purchaseId string
+		data.On("GetPackage", "PKG-1").Return(pkg, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), payment).Run(func(args mock.Arguments) {
+			purchaseId = args.String(0)
+		}).Return(charged, nil).Once()
+		data.On("InsertPurchase", mock.MatchedBy(func(p credit.PurchaseCore) bool {
+			return p.PurchaseID == purchaseId && p.UserID == userId && p.PackageID == "PKG-1" &&
+				p.Hours == 10 && p.Price == 900000 && assert.ObjectsAreEqual(charged, p.Payment)
+		})).Return(func(p credit.PurchaseCore) (credit.PurchaseCore, error) {
+			return p, nil
+		}).Once()
+
+		result, err := service.BuyPackage(userId, "PKG-1", "bca")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(result.PurchaseID, "PUR-"))
+		assert.Equal(t, charged, result.Payment)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - payment_type is empty", func(t *testing.T) {
+		_, err := service.BuyPackage(userId, "PKG-1", "")
+		assert.EqualError(t, err, "payment_type cannot be empty")
+	})
+
+	t.Run("error - paid with credits", func(t *testing.T) {
+		_, err := service.BuyPackage(userId, "PKG-1", reservation.PaymentTypeCredits)
+		assert.EqualError(t, err, "invalid payment_type, packages cannot be paid with credits")
+	})
+
+	t.Run("error - package not found", func(t *testing.T) {
+		data.On("GetPackage", "PKG-2").Return(credit.PackageCore{}, errors.New("package not found")).Once()
+
+		_, err := service.BuyPackage(userId, "PKG-2", "bca")
+		assert.EqualError(t, err, "package not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - charge failed", func(t *testing.T) {
+		data.On("GetPackage", "PKG-1").Return(pkg, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), mock.Anything).Return(reservation.PaymentCore{}, errors.New("connection reset")).Once()
+
+		_, err := service.BuyPackage(userId, "PKG-1", "bca")
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - charge is voided when the purchase cannot be saved", func(t *testing.T) {
+		var purchaseId string
+		data.On("GetPackage", "PKG-1").Return(pkg, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), mock.Anything).Run(func(args mock.Arguments) {
+			purchaseId = args.String(0)
+		}).Return(reservation.PaymentCore{PaymentID: "payment_id_1", Status: "pending"}, nil).Once()
+		data.On("InsertPurchase", mock.Anything).Return(credit.PurchaseCore{}, errors.New("failed to record the purchase")).Once()
+		payments.On("Cancel", mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, err := service.BuyPackage(userId, "PKG-1", "bca")
+		assert.EqualError(t, err, "internal server error")
+		payments.AssertCalled(t, "Cancel", purchaseId)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+}
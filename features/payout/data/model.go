@@ -0,0 +1,197 @@
+package data
+
+import (
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/payout"
+)
+
+// LedgerEntry is one leg of a ledger transaction, the legs sharing a transaction ID sum to zero.
+// The transaction ID is the payment, refund or payout line the transaction posts.
+type LedgerEntry struct {
+	EntryID       string    `gorm:"primaryKey;type:varchar(45)"`
+	TransactionID string    `gorm:"type:varchar(45);index"`
+	Account       string    `gorm:"type:enum('cash','owner','commission','fees','tax');index:idx_ledger_entries_account"`
+	OwnerID       string    `gorm:"type:varchar(45);index:idx_ledger_entries_account"`
+	Amount        int64     `gorm:"type:bigint"`
+	Kind          string    `gorm:"type:enum('payment','refund','payout')"`
+	PaymentID     string    `gorm:"type:varchar(45);index"`
+	BatchID       *string   `gorm:"type:varchar(45);index"`
+	Description   string    `gorm:"type:varchar(225)"`
+	CreatedAt     time.Time `gorm:"type:datetime;index"`
+}
+
+type PayoutAccount struct {
+	OwnerID       string    `gorm:"primaryKey;type:varchar(45)"`
+	BankName      string    `gorm:"type:varchar(100);not null"`
+	AccountNumber string    `gorm:"type:varchar(45);not null"`
+	AccountHolder string    `gorm:"type:varchar(225);not null"`
+	UpdatedAt     time.Time `gorm:"type:datetime"`
+}
+
+type PayoutBatch struct {
+	BatchID   string       `gorm:"primaryKey;type:varchar(45)"`
+	Status    string       `gorm:"type:enum('pending','paid');default:'pending'"`
+	Total     int64        `gorm:"type:bigint"`
+	Reference string       `gorm:"type:varchar(100)"`
+	PaidBy    string       `gorm:"type:varchar(45)"`
+	PaidAt    *time.Time   `gorm:"type:datetime"`
+	CreatedAt time.Time    `gorm:"type:datetime"`
+	Lines     []PayoutLine `gorm:"foreignKey:BatchID;references:BatchID"`
+}
+
+// PayoutLine keeps the bank account of the owner as it was when the batch was made
+type PayoutLine struct {
+	LineID        string    `gorm:"primaryKey;type:varchar(45)"`
+	BatchID       string    `gorm:"type:varchar(45);index"`
+	OwnerID       string    `gorm:"type:varchar(45);index"`
+	Amount        int64     `gorm:"type:bigint"`
+	BankName      string    `gorm:"type:varchar(100)"`
+	AccountNumber string    `gorm:"type:varchar(45)"`
+	AccountHolder string    `gorm:"type:varchar(225)"`
+	CreatedAt     time.Time `gorm:"type:datetime"`
+}
+
+// Struct helper to read the payouts of an owner together with the status of their batch
+type Line struct {
+	PayoutLine
+	Status string
+	PaidAt *time.Time
+}
+
+// Struct helper to read a payment to be posted, see the reservation feature for the full model
+type Payment struct {
+	PaymentID     string
+	PaymentMethod string
+	GrandTotal    string
+	Purpose       string
+	ReferenceID   string
+}
+
+// Struct helper to read the invoice lines of a payment
+type PaymentItem struct {
+	Kind        string
+	Description string
+	Amount      int64
+}
+
+func entryModels(e LedgerEntry) payout.EntryCore {
+	result := payout.EntryCore{
+		EntryID:       e.EntryID,
+		TransactionID: e.TransactionID,
+		Account:       e.Account,
+		OwnerID:       e.OwnerID,
+		Amount:        e.Amount,
+		Kind:          e.Kind,
+		PaymentID:     e.PaymentID,
+		Description:   e.Description,
+		CreatedAt:     e.CreatedAt,
+	}
+	if e.BatchID != nil {
+		result.BatchID = *e.BatchID
+	}
+
+	return result
+}
+
+func entryEntities(e payout.EntryCore) LedgerEntry {
+	result := LedgerEntry{
+		EntryID:       e.EntryID,
+		TransactionID: e.TransactionID,
+		Account:       e.Account,
+		OwnerID:       e.OwnerID,
+		Amount:        e.Amount,
+		Kind:          e.Kind,
+		PaymentID:     e.PaymentID,
+		Description:   e.Description,
+		CreatedAt:     e.CreatedAt,
+	}
+	if e.BatchID != "" {
+		batchId := e.BatchID
+		result.BatchID = &batchId
+	}
+
+	return result
+}
+
+func modelToEntryCore(es []LedgerEntry) []payout.EntryCore {
+	result := make([]payout.EntryCore, len(es))
+	for i, e := range es {
+		result[i] = entryModels(e)
+	}
+
+	return result
+}
+
+func accountModels(a PayoutAccount) payout.AccountCore {
+	return payout.AccountCore{
+		OwnerID:       a.OwnerID,
+		BankName:      a.BankName,
+		AccountNumber: a.AccountNumber,
+		AccountHolder: a.AccountHolder,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
+
+func accountEntities(a payout.AccountCore) PayoutAccount {
+	return PayoutAccount{
+		OwnerID:       a.OwnerID,
+		BankName:      a.BankName,
+		AccountNumber: a.AccountNumber,
+		AccountHolder: a.AccountHolder,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
+
+func lineModels(l PayoutLine) payout.LineCore {
+	return payout.LineCore{
+		LineID:        l.LineID,
+		BatchID:       l.BatchID,
+		OwnerID:       l.OwnerID,
+		Amount:        l.Amount,
+		BankName:      l.BankName,
+		AccountNumber: l.AccountNumber,
+		AccountHolder: l.AccountHolder,
+		CreatedAt:     l.CreatedAt,
+	}
+}
+
+func modelToLineCore(ls []Line) []payout.LineCore {
+	result := make([]payout.LineCore, len(ls))
+	for i, l := range ls {
+		result[i] = lineModels(l.PayoutLine)
+		result[i].Status = l.Status
+		result[i].PaidAt = l.PaidAt
+	}
+
+	return result
+}
+
+func batchModels(b PayoutBatch) payout.BatchCore {
+	result := payout.BatchCore{
+		BatchID:   b.BatchID,
+		Status:    b.Status,
+		Total:     b.Total,
+		Reference: b.Reference,
+		PaidBy:    b.PaidBy,
+		PaidAt:    b.PaidAt,
+		Lines:     make([]payout.LineCore, len(b.Lines)),
+		CreatedAt: b.CreatedAt,
+	}
+	for i, l := range b.Lines {
+		result.Lines[i] = lineModels(l)
+		result.Lines[i].Status = b.Status
+		result.Lines[i].PaidAt = b.PaidAt
+	}
+
+	return result
+}
+
+func modelToBatchCore(bs []PayoutBatch) []payout.BatchCore {
+	result := make([]payout.BatchCore, len(bs))
+	for i, b := range bs {
+		result[i] = batchModels(b)
+	}
+
+	return result
+}
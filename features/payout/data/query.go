@@ -0,0 +1,449 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/app/config"
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/payout"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	"github.com/playground-pro-project/playground-pro-api/utils/invoice"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var log = middlewares.Log()
+
+type payoutQuery struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) payout.PayoutData {
+	return &payoutQuery{
+		db: db,
+	}
+}
+
+// PostPayment credits the owner of what a settled payment paid for, net of the platform
+// commission, once. Payments of nothing, such as bookings paid with credits, are left alone,
+// and so is each share of a split payment, which is posted as a whole once every share settled.
+// It runs inside the transaction settling the payment.
+func PostPayment(tx *gorm.DB, paymentID string) error {
+	payment := Payment{}
+	query := tx.Table("payments").Where("payment_id = ?", paymentID).Take(&payment)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payment query:", query.Error)
+		return errors.New("internal server error")
+	}
+
+	grandTotal, err := strconv.ParseFloat(payment.GrandTotal, 64)
+	if err != nil || payment.PaymentMethod == reservation.PaymentTypeCredits || payment.Purpose == reservation.PaymentForShare {
+		return nil
+	}
+	gross := invoice.Rupiah(grandTotal)
+	if gross <= 0 {
+		return nil
+	}
+
+	posted, err := posted(tx, paymentID)
+	if err != nil || posted {
+		return err
+	}
+
+	ownerId, err := paymentOwner(tx, payment)
+	if err != nil {
+		return err
+	}
+
+	items := []PaymentItem{}
+	if err := tx.Table("payment_items").Where("payment_id = ?", paymentID).Find(&items).Error; err != nil {
+		log.Sugar().Error("error executing payment items query:", err)
+		return errors.New("internal server error")
+	}
+	invoiceItems := make([]invoice.Item, len(items))
+	for i, item := range items {
+		invoiceItems[i] = invoice.Item{Kind: item.Kind, Description: item.Description, Amount: item.Amount}
+	}
+
+	legs := payout.PaymentLegs(invoice.Summarize(invoiceItems), gross, config.COMMISSION_PERCENT)
+	for i := range legs {
+		if legs[i].Account == payout.AccountOwner {
+			legs[i].OwnerID = ownerId
+		}
+	}
+
+	return post(tx, paymentID, payout.EntryPayment, paymentID, "Payment "+paymentID, legs)
+}
+
+// PostRefund takes amount refunded of a payment back from the accounts its payment credited, in
+// the same shares. Payments settled before the ledger existed have nothing to take back from.
+// It runs inside the transaction recording the refund.
+func PostRefund(tx *gorm.DB, refundID string, paymentID string, amount int64) error {
+	entries := []LedgerEntry{}
+	query := tx.Where("transaction_id = ? AND kind = ?", paymentID, payout.EntryPayment).Find(&entries)
+	if query.Error != nil {
+		log.Sugar().Error("error executing ledger entries query:", query.Error)
+		return errors.New("internal server error")
+	}
+	if len(entries) == 0 {
+		log.Sugar().Warnf("payment %s was never posted, refund %s is not taken back from anyone", paymentID, refundID)
+		return nil
+	}
+
+	legs := payout.RefundLegs(modelToEntryCore(entries), amount)
+	return post(tx, refundID, payout.EntryRefund, paymentID, "Refund of payment "+paymentID, legs)
+}
+
+// posted tells whether a payment has been posted already.
+func posted(tx *gorm.DB, paymentID string) (bool, error) {
+	var count int64
+	query := tx.Model(&LedgerEntry{}).
+		Where("transaction_id = ? AND kind = ?", paymentID, payout.EntryPayment).
+		Count(&count)
+	if query.Error != nil {
+		log.Error("error while checking posted payment")
+		return false, errors.New("internal server error")
+	}
+
+	return count > 0, nil
+}
+
+// paymentOwner finds who owns the venue a payment was made for, be it a booking, a reschedule or
+// a package purchase. Cancelled bookings and deleted venues still belong to their owner.
+func paymentOwner(tx *gorm.DB, payment Payment) (string, error) {
+	var venues *gorm.DB
+	switch payment.Purpose {
+	case reservation.PaymentForCredits:
+		venues = tx.Table("credit_purchases").Select("venue_id").Where("purchase_id = ?", payment.ReferenceID)
+	case reservation.PaymentForReschedule:
+		venues = tx.Table("reservations").Select("venue_id").Where("reservation_id = ?", payment.ReferenceID)
+	default:
+		venues = tx.Table("reservations").Select("venue_id").Where("payment_id = ?", payment.PaymentID)
+	}
+
+	var ownerId string
+	query := tx.Table("venues").
+		Select("owner_id").
+		Where("venue_id IN (?)", venues).
+		Limit(1).
+		Scan(&ownerId)
+	if query.Error != nil {
+		log.Sugar().Error("error executing venue owner query:", query.Error)
+		return "", errors.New("internal server error")
+	}
+	if query.RowsAffected == 0 || ownerId == "" {
+		log.Sugar().Errorf("owner of payment %s not found", payment.PaymentID)
+		return "", errors.New("venue owner not found")
+	}
+
+	return ownerId, nil
+}
+
+// post writes the legs of a ledger transaction, which must balance.
+func post(tx *gorm.DB, transactionID string, kind string, paymentID string, description string, legs []payout.EntryCore) error {
+	if len(legs) == 0 {
+		return nil
+	}
+
+	var sum int64
+	entries := make([]LedgerEntry, len(legs))
+	for i, leg := range legs {
+		sum += leg.Amount
+		leg.EntryID = helper.GenerateLedgerEntryID()
+		leg.TransactionID = transactionID
+		leg.Kind = kind
+		leg.PaymentID = paymentID
+		leg.Description = description
+		entries[i] = entryEntities(leg)
+	}
+	if sum != 0 {
+		log.Sugar().Errorf("ledger transaction %s is off balance by %d", transactionID, sum)
+		return errors.New("internal server error while posting to the ledger")
+	}
+
+	if err := tx.Create(&entries).Error; err != nil {
+		log.Sugar().Error("error while posting to the ledger:", err)
+		return errors.New("internal server error while posting to the ledger")
+	}
+
+	return nil
+}
+
+// GetUserRole implements payout.PayoutData.
+func (pq *payoutQuery) GetUserRole(userId string) (string, error) {
+	var role string
+	query := pq.db.Table("users").
+		Select("role").
+		Where("user_id = ? AND deleted_at IS NULL", userId).
+		Scan(&role)
+	if query.Error != nil {
+		log.Sugar().Error("error executing user query:", query.Error)
+		return "", query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("user not found")
+		return "", errors.New("user not found")
+	}
+
+	return role, nil
+}
+
+// GetOwnerEntries implements payout.PayoutData.
+func (pq *payoutQuery) GetOwnerEntries(ownerId string) ([]payout.EntryCore, error) {
+	entries := []LedgerEntry{}
+	query := pq.db.Where("account = ? AND owner_id = ?", payout.AccountOwner, ownerId).
+		Order("created_at DESC").
+		Find(&entries)
+	if query.Error != nil {
+		log.Sugar().Error("error executing ledger entries query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToEntryCore(entries), nil
+}
+
+// GetOwnerPayouts implements payout.PayoutData.
+func (pq *payoutQuery) GetOwnerPayouts(ownerId string) ([]payout.LineCore, error) {
+	lines := []Line{}
+	query := pq.db.Table("payout_lines").
+		Select("payout_lines.*, payout_batches.status, payout_batches.paid_at").
+		Joins("JOIN payout_batches ON payout_batches.batch_id = payout_lines.batch_id").
+		Where("payout_lines.owner_id = ?", ownerId).
+		Order("payout_lines.created_at DESC").
+		Scan(&lines)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payout lines query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToLineCore(lines), nil
+}
+
+// GetAccount implements payout.PayoutData.
+func (pq *payoutQuery) GetAccount(ownerId string) (payout.AccountCore, error) {
+	account := PayoutAccount{}
+	query := pq.db.Where("owner_id = ?", ownerId).First(&account)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return payout.AccountCore{}, errors.New("payout account not found")
+	}
+	if query.Error != nil {
+		log.Sugar().Error("error executing payout account query:", query.Error)
+		return payout.AccountCore{}, query.Error
+	}
+
+	return accountModels(account), nil
+}
+
+// SaveAccount implements payout.PayoutData.
+func (pq *payoutQuery) SaveAccount(request payout.AccountCore) (payout.AccountCore, error) {
+	req := accountEntities(request)
+	query := pq.db.Save(&req)
+	if query.Error != nil {
+		log.Sugar().Error("error while saving payout account:", query.Error)
+		return payout.AccountCore{}, errors.New("internal server error while saving payout account")
+	}
+
+	return accountModels(req), nil
+}
+
+// CreateBatch implements payout.PayoutData. What every owner with a payout account is owed and
+// not yet batched goes into a single batch, owners owing the platform after refunds are left out
+// until their payments cover it again.
+func (pq *payoutQuery) CreateBatch() (payout.BatchCore, error) {
+	tx := pq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return payout.BatchCore{}, errors.New("internal server error on beginning database transaction")
+	}
+
+	entries := []LedgerEntry{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account = ? AND batch_id IS NULL", payout.AccountOwner).
+		Order("created_at").
+		Find(&entries)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error executing ledger entries query:", query.Error)
+		return payout.BatchCore{}, errors.New("internal server error")
+	}
+
+	owed := map[string]int64{}
+	entryIds := map[string][]string{}
+	owners := []string{}
+	for _, e := range entries {
+		if _, found := owed[e.OwnerID]; !found {
+			owners = append(owners, e.OwnerID)
+		}
+		owed[e.OwnerID] -= e.Amount
+		entryIds[e.OwnerID] = append(entryIds[e.OwnerID], e.EntryID)
+	}
+
+	accounts := []PayoutAccount{}
+	if err := tx.Where("owner_id IN ?", owners).Find(&accounts).Error; err != nil {
+		tx.Rollback()
+		log.Sugar().Error("error executing payout accounts query:", err)
+		return payout.BatchCore{}, errors.New("internal server error")
+	}
+	bankAccounts := map[string]PayoutAccount{}
+	for _, a := range accounts {
+		bankAccounts[a.OwnerID] = a
+	}
+
+	batch := PayoutBatch{
+		BatchID: helper.GeneratePayoutBatchID(),
+		Status:  payout.BatchPending,
+	}
+	batched := []string{}
+	for _, ownerId := range owners {
+		if owed[ownerId] <= 0 {
+			continue
+		}
+		account, found := bankAccounts[ownerId]
+		if !found {
+			log.Sugar().Warnf("owner %s is owed %d but has no payout account", ownerId, owed[ownerId])
+			continue
+		}
+
+		batch.Lines = append(batch.Lines, PayoutLine{
+			LineID:        helper.GeneratePayoutLineID(),
+			OwnerID:       ownerId,
+			Amount:        owed[ownerId],
+			BankName:      account.BankName,
+			AccountNumber: account.AccountNumber,
+			AccountHolder: account.AccountHolder,
+		})
+		batch.Total += owed[ownerId]
+		batched = append(batched, entryIds[ownerId]...)
+	}
+	if len(batch.Lines) == 0 {
+		tx.Rollback()
+		log.Warn("nothing to pay out")
+		return payout.BatchCore{}, errors.New("nothing to pay out")
+	}
+
+	if err := tx.Create(&batch).Error; err != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while creating payout batch:", err)
+		return payout.BatchCore{}, errors.New("internal server error while creating payout batch")
+	}
+
+	query = tx.Model(&LedgerEntry{}).
+		Where("entry_id IN ?", batched).
+		Update("batch_id", batch.BatchID)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while batching ledger entries:", query.Error)
+		return payout.BatchCore{}, errors.New("internal server error while creating payout batch")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error("error on committing database transaction")
+		return payout.BatchCore{}, errors.New("internal server error on committing database transaction")
+	}
+
+	log.Sugar().Infof("payout batch %s of %d has been created for %d owner(s)", batch.BatchID, batch.Total, len(batch.Lines))
+	return batchModels(batch), nil
+}
+
+// GetBatches implements payout.PayoutData.
+func (pq *payoutQuery) GetBatches() ([]payout.BatchCore, error) {
+	batches := []PayoutBatch{}
+	query := pq.db.Preload("Lines").Order("created_at DESC").Find(&batches)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payout batches query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToBatchCore(batches), nil
+}
+
+// GetBatch implements payout.PayoutData.
+func (pq *payoutQuery) GetBatch(batchId string) (payout.BatchCore, error) {
+	batch := PayoutBatch{}
+	query := pq.db.Preload("Lines").Where("batch_id = ?", batchId).First(&batch)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Warn("payout batch not found")
+		return payout.BatchCore{}, errors.New("batch not found")
+	}
+	if query.Error != nil {
+		log.Sugar().Error("error executing payout batch query:", query.Error)
+		return payout.BatchCore{}, query.Error
+	}
+
+	return batchModels(batch), nil
+}
+
+// MarkBatchPaid implements payout.PayoutData. Each transfer of the batch is posted from the owner
+// out of the cash account, settling what the batch owed them.
+func (pq *payoutQuery) MarkBatchPaid(batchId string, paidBy string, reference string) (payout.BatchCore, error) {
+	tx := pq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return payout.BatchCore{}, errors.New("internal server error on beginning database transaction")
+	}
+
+	batch := PayoutBatch{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").
+		Where("batch_id = ?", batchId).
+		Take(&batch)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		log.Warn("payout batch not found")
+		return payout.BatchCore{}, errors.New("batch not found")
+	}
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error executing payout batch query:", query.Error)
+		return payout.BatchCore{}, errors.New("internal server error")
+	}
+	if batch.Status == payout.BatchPaid {
+		tx.Rollback()
+		log.Warn("payout batch has already been paid")
+		return payout.BatchCore{}, errors.New("batch has already been paid")
+	}
+
+	for _, line := range batch.Lines {
+		legs := []payout.EntryCore{
+			{Account: payout.AccountOwner, OwnerID: line.OwnerID, Amount: line.Amount, BatchID: batch.BatchID},
+			{Account: payout.AccountCash, Amount: -line.Amount, BatchID: batch.BatchID},
+		}
+		if err := post(tx, line.LineID, payout.EntryPayout, "", fmt.Sprintf("Payout %s, ref %s", batch.BatchID, reference), legs); err != nil {
+			tx.Rollback()
+			return payout.BatchCore{}, err
+		}
+	}
+
+	now := time.Now()
+	batch.Status = payout.BatchPaid
+	batch.Reference = reference
+	batch.PaidBy = paidBy
+	batch.PaidAt = &now
+	query = tx.Model(&PayoutBatch{}).
+		Where("batch_id = ?", batch.BatchID).
+		Updates(map[string]interface{}{
+			"status":    batch.Status,
+			"reference": batch.Reference,
+			"paid_by":   batch.PaidBy,
+			"paid_at":   batch.PaidAt,
+		})
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while marking payout batch paid:", query.Error)
+		return payout.BatchCore{}, errors.New("internal server error while marking payout batch paid")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error("error on committing database transaction")
+		return payout.BatchCore{}, errors.New("internal server error on committing database transaction")
+	}
+
+	log.Sugar().Infof("payout batch %s has been paid, ref %s", batch.BatchID, reference)
+	return batchModels(batch), nil
+}
@@ -0,0 +1,190 @@
+package payout
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/utils/invoice"
+)
+
+// Accounts of the settlement ledger. Cash is the money collected through the payment gateway,
+// owner is what the platform owes each venue owner, commission, fees and tax are the share of
+// every payment the platform keeps or passes on to the tax office.
+const (
+	AccountCash       = "cash"
+	AccountOwner      = "owner"
+	AccountCommission = "commission"
+	AccountFees       = "fees"
+	AccountTax        = "tax"
+)
+
+const (
+	EntryPayment = "payment"
+	EntryRefund  = "refund"
+	EntryPayout  = "payout"
+)
+
+const (
+	BatchPending = "pending"
+	BatchPaid    = "paid"
+)
+
+// EntryCore is one leg of a ledger transaction, debits are positive and credits negative so the
+// legs of a transaction always sum to zero. OwnerID is set on the legs of owner accounts only.
+type EntryCore struct {
+	EntryID       string
+	TransactionID string
+	Account       string
+	OwnerID       string
+	Amount        int64
+	Kind          string
+	PaymentID     string
+	BatchID       string
+	Description   string
+	CreatedAt     time.Time
+}
+
+// AccountCore is the bank account the payouts of an owner are transferred to.
+type AccountCore struct {
+	OwnerID       string
+	BankName      string
+	AccountNumber string
+	AccountHolder string
+	UpdatedAt     time.Time
+}
+
+// LineCore is the transfer to a single owner within a payout batch.
+type LineCore struct {
+	LineID        string
+	BatchID       string
+	OwnerID       string
+	Amount        int64
+	BankName      string
+	AccountNumber string
+	AccountHolder string
+	Status        string
+	PaidAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// BatchCore groups what is owed to owners into bank transfers made together.
+type BatchCore struct {
+	BatchID   string
+	Status    string
+	Total     int64
+	Reference string
+	PaidBy    string
+	PaidAt    *time.Time
+	Lines     []LineCore
+	CreatedAt time.Time
+}
+
+// SummaryCore is where the money of an owner stands. Available is owed but not batched yet,
+// InPayout sits in batches waiting to be transferred and PaidOut has been transferred already.
+type SummaryCore struct {
+	Balance   int64
+	Available int64
+	InPayout  int64
+	PaidOut   int64
+	Account   *AccountCore
+	Entries   []EntryCore
+	Payouts   []LineCore
+}
+
+// PaymentLegs shares a settled payment of gross out to the ledger: the cash collected is debited,
+// tax and fees are credited as invoiced, commissionPercent is taken off what the venue earned and
+// the owner is credited the rest. Payments without an invoice earn the venue their gross amount.
+func PaymentLegs(inv invoice.Invoice, gross int64, commissionPercent float64) []EntryCore {
+	fees, tax := inv.Fees, inv.Tax
+	if len(inv.Items) == 0 || fees+tax > gross {
+		fees, tax = 0, 0
+	}
+	earned := gross - fees - tax
+	commission := invoice.Rupiah(float64(earned) * commissionPercent / 100)
+
+	return legs(
+		EntryCore{Account: AccountCash, Amount: gross},
+		EntryCore{Account: AccountOwner, Amount: -(earned - commission)},
+		EntryCore{Account: AccountCommission, Amount: -commission},
+		EntryCore{Account: AccountFees, Amount: -fees},
+		EntryCore{Account: AccountTax, Amount: -tax},
+	)
+}
+
+// RefundLegs reverses amount of a payment posted with the payment legs, taking it back from every
+// account in the share it was credited. The owner takes up what rounding leaves over.
+func RefundLegs(payment []EntryCore, amount int64) []EntryCore {
+	var gross int64
+	for _, leg := range payment {
+		if leg.Account == AccountCash {
+			gross += leg.Amount
+		}
+	}
+	if gross <= 0 || amount <= 0 {
+		return nil
+	}
+
+	result := []EntryCore{{Account: AccountCash, Amount: -amount}}
+	owner := -1
+	left := amount
+	for _, leg := range payment {
+		if leg.Account == AccountCash {
+			continue
+		}
+		share := invoice.Rupiah(float64(-leg.Amount) * float64(amount) / float64(gross))
+		if leg.Account == AccountOwner {
+			owner = len(result)
+		}
+		result = append(result, EntryCore{Account: leg.Account, OwnerID: leg.OwnerID, Amount: share})
+		left -= share
+	}
+	if owner >= 0 {
+		result[owner].Amount += left
+	} else {
+		result = append(result, EntryCore{Account: AccountCommission, Amount: left})
+	}
+
+	return legs(result...)
+}
+
+// legs drops the legs that move nothing.
+func legs(entries ...EntryCore) []EntryCore {
+	result := []EntryCore{}
+	for _, e := range entries {
+		if e.Amount != 0 {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+type PayoutHandler interface {
+	MyPayouts() echo.HandlerFunc
+	SetPayoutAccount() echo.HandlerFunc
+	CreateBatch() echo.HandlerFunc
+	GetBatches() echo.HandlerFunc
+	MarkBatchPaid() echo.HandlerFunc
+	ExportBatch() echo.HandlerFunc
+}
+
+type PayoutService interface {
+	MyPayouts(userId string) (SummaryCore, error)
+	SetPayoutAccount(userId string, request AccountCore) (AccountCore, error)
+	CreateBatch(userId string) (BatchCore, error)
+	GetBatches(userId string) ([]BatchCore, error)
+	MarkBatchPaid(userId string, batchId string, reference string) (BatchCore, error)
+	ExportBatch(userId string, batchId string) ([]byte, error)
+}
+
+type PayoutData interface {
+	GetUserRole(userId string) (string, error)
+	GetOwnerEntries(ownerId string) ([]EntryCore, error)
+	GetOwnerPayouts(ownerId string) ([]LineCore, error)
+	GetAccount(ownerId string) (AccountCore, error)
+	SaveAccount(request AccountCore) (AccountCore, error)
+	CreateBatch() (BatchCore, error)
+	GetBatches() ([]BatchCore, error)
+	GetBatch(batchId string) (BatchCore, error)
+	MarkBatchPaid(batchId string, paidBy string, reference string) (BatchCore, error)
+}
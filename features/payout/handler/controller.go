@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/payout"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+var log = middlewares.Log()
+
+type payoutHandler struct {
+	service payout.PayoutService
+}
+
+func New(ps payout.PayoutService) payout.PayoutHandler {
+	return &payoutHandler{
+		service: ps,
+	}
+}
+
+// MyPayouts implements payout.PayoutHandler.
+func (ph *payoutHandler) MyPayouts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		result, err := ph.service.MyPayouts(userId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", summaryResp(result), nil))
+	}
+}
+
+// SetPayoutAccount implements payout.PayoutHandler.
+func (ph *payoutHandler) SetPayoutAccount() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := payoutAccountRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := ph.service.SetPayoutAccount(userId, requestAccount(req))
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "only venue owners"):
+				log.Error(err.Error())
+				return helper.UnauthorizedError(c, "Only venue owners can set a payout account")
+			case strings.Contains(err.Error(), "not found"):
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"),
+				strings.Contains(err.Error(), "empty"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully saved payout account", accountResp(result), nil))
+	}
+}
+
+// CreateBatch implements payout.PayoutHandler.
+func (ph *payoutHandler) CreateBatch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		result, err := ph.service.CreateBatch(userId)
+		if err != nil {
+			return batchError(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully created payout batch", batchResp(result), nil))
+	}
+}
+
+// GetBatches implements payout.PayoutHandler.
+func (ph *payoutHandler) GetBatches() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		result, err := ph.service.GetBatches(userId)
+		if err != nil {
+			return batchError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", batches(result), nil))
+	}
+}
+
+// MarkBatchPaid implements payout.PayoutHandler.
+func (ph *payoutHandler) MarkBatchPaid() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := markPaidRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := ph.service.MarkBatchPaid(userId, c.Param("batch_id"), req.Reference)
+		if err != nil {
+			return batchError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully marked payout batch paid", batchResp(result), nil))
+	}
+}
+
+// ExportBatch implements payout.PayoutHandler.
+func (ph *payoutHandler) ExportBatch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		batchId := c.Param("batch_id")
+		file, err := ph.service.ExportBatch(userId, batchId)
+		if err != nil {
+			return batchError(c, err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "payout-"+batchId+".csv"))
+		return c.Blob(http.StatusOK, "text/csv", file)
+	}
+}
+
+func batchError(c echo.Context, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "only admins"):
+		log.Error(err.Error())
+		return helper.UnauthorizedError(c, "Only admins can manage payouts")
+	case strings.Contains(err.Error(), "not found"):
+		log.Error(err.Error())
+		return helper.NotFoundError(c, "The requested resource was not found")
+	case strings.Contains(err.Error(), "empty"),
+		strings.Contains(err.Error(), "already been paid"),
+		strings.Contains(err.Error(), "nothing to pay out"):
+		log.Error("bad request, " + err.Error())
+		return helper.BadRequestError(c, "Bad request, "+err.Error())
+	default:
+		log.Error("internal server error")
+		return helper.InternalServerError(c, "Internal server error")
+	}
+}
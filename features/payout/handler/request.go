@@ -0,0 +1,21 @@
+package handler
+
+import "github.com/playground-pro-project/playground-pro-api/features/payout"
+
+type payoutAccountRequest struct {
+	BankName      string `json:"bank_name" form:"bank_name"`
+	AccountNumber string `json:"account_number" form:"account_number"`
+	AccountHolder string `json:"account_holder" form:"account_holder"`
+}
+
+type markPaidRequest struct {
+	Reference string `json:"reference" form:"reference"`
+}
+
+func requestAccount(r payoutAccountRequest) payout.AccountCore {
+	return payout.AccountCore{
+		BankName:      r.BankName,
+		AccountNumber: r.AccountNumber,
+		AccountHolder: r.AccountHolder,
+	}
+}
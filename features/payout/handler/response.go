@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"github.com/playground-pro-project/playground-pro-api/features/payout"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+type accountResponse struct {
+	BankName      string `json:"bank_name"`
+	AccountNumber string `json:"account_number"`
+	AccountHolder string `json:"account_holder"`
+}
+
+type summaryResponse struct {
+	Balance   int64            `json:"balance"`
+	Available int64            `json:"available"`
+	InPayout  int64            `json:"in_payout"`
+	PaidOut   int64            `json:"paid_out"`
+	Account   *accountResponse `json:"account"`
+	History   []entryResponse  `json:"history"`
+	Payouts   []lineResponse   `json:"payouts"`
+}
+
+// entryResponse shows a ledger line from the side of the owner, positive when they are owed more
+type entryResponse struct {
+	EntryID     string           `json:"entry_id"`
+	Kind        string           `json:"kind"`
+	Amount      int64            `json:"amount"`
+	PaymentID   string           `json:"payment_id,omitempty"`
+	BatchID     string           `json:"batch_id,omitempty"`
+	Description string           `json:"description"`
+	CreatedAt   helper.LocalTime `json:"created_at"`
+}
+
+type lineResponse struct {
+	LineID        string            `json:"line_id"`
+	BatchID       string            `json:"batch_id"`
+	OwnerID       string            `json:"owner_id"`
+	Amount        int64             `json:"amount"`
+	BankName      string            `json:"bank_name"`
+	AccountNumber string            `json:"account_number"`
+	AccountHolder string            `json:"account_holder"`
+	Status        string            `json:"status"`
+	PaidAt        *helper.LocalTime `json:"paid_at"`
+}
+
+type batchResponse struct {
+	BatchID   string            `json:"batch_id"`
+	Status    string            `json:"status"`
+	Total     int64             `json:"total"`
+	Reference string            `json:"reference"`
+	PaidAt    *helper.LocalTime `json:"paid_at"`
+	Lines     []lineResponse    `json:"lines"`
+	CreatedAt helper.LocalTime  `json:"created_at"`
+}
+
+func accountResp(a payout.AccountCore) accountResponse {
+	return accountResponse{
+		BankName:      a.BankName,
+		AccountNumber: a.AccountNumber,
+		AccountHolder: a.AccountHolder,
+	}
+}
+
+func summaryResp(s payout.SummaryCore) summaryResponse {
+	result := summaryResponse{
+		Balance:   s.Balance,
+		Available: s.Available,
+		InPayout:  s.InPayout,
+		PaidOut:   s.PaidOut,
+		History:   make([]entryResponse, len(s.Entries)),
+		Payouts:   lines(s.Payouts),
+	}
+	if s.Account != nil {
+		account := accountResp(*s.Account)
+		result.Account = &account
+	}
+	for i, e := range s.Entries {
+		result.History[i] = entryResponse{
+			EntryID:     e.EntryID,
+			Kind:        e.Kind,
+			Amount:      -e.Amount,
+			PaymentID:   e.PaymentID,
+			BatchID:     e.BatchID,
+			Description: e.Description,
+			CreatedAt:   helper.LocalTime(e.CreatedAt),
+		}
+	}
+
+	return result
+}
+
+func lines(ls []payout.LineCore) []lineResponse {
+	result := make([]lineResponse, len(ls))
+	for i, l := range ls {
+		result[i] = lineResponse{
+			LineID:        l.LineID,
+			BatchID:       l.BatchID,
+			OwnerID:       l.OwnerID,
+			Amount:        l.Amount,
+			BankName:      l.BankName,
+			AccountNumber: l.AccountNumber,
+			AccountHolder: l.AccountHolder,
+			Status:        l.Status,
+		}
+		if l.PaidAt != nil {
+			paidAt := helper.LocalTime(*l.PaidAt)
+			result[i].PaidAt = &paidAt
+		}
+	}
+
+	return result
+}
+
+func batchResp(b payout.BatchCore) batchResponse {
+	result := batchResponse{
+		BatchID:   b.BatchID,
+		Status:    b.Status,
+		Total:     b.Total,
+		Reference: b.Reference,
+		Lines:     lines(b.Lines),
+		CreatedAt: helper.LocalTime(b.CreatedAt),
+	}
+	if b.PaidAt != nil {
+		paidAt := helper.LocalTime(*b.PaidAt)
+		result.PaidAt = &paidAt
+	}
+
+	return result
+}
+
+func batches(bs []payout.BatchCore) []batchResponse {
+	result := make([]batchResponse, len(bs))
+	for i, b := range bs {
+		result[i] = batchResp(b)
+	}
+
+	return result
+}
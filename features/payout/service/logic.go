@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/payout"
+)
+
+var log = middlewares.Log()
+
+type payoutService struct {
+	query payout.PayoutData
+}
+
+func New(pd payout.PayoutData) payout.PayoutService {
+	return &payoutService{
+		query: pd,
+	}
+}
+
+// MyPayouts implements payout.PayoutService.
+func (ps *payoutService) MyPayouts(userId string) (payout.SummaryCore, error) {
+	// TODO 1 : Read the ledger of the owner and the transfers made to them
+	entries, err := ps.query.GetOwnerEntries(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return payout.SummaryCore{}, errors.New("internal server error")
+	}
+
+	payouts, err := ps.query.GetOwnerPayouts(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return payout.SummaryCore{}, errors.New("internal server error")
+	}
+
+	summary := payout.SummaryCore{
+		Entries: entries,
+		Payouts: payouts,
+	}
+
+	// TODO 2 : Add the balance up, owner accounts are credited with what the owner is owed
+	for _, e := range entries {
+		summary.Balance -= e.Amount
+		if e.BatchID == "" {
+			summary.Available -= e.Amount
+		}
+		if e.Kind == payout.EntryPayout {
+			summary.PaidOut += e.Amount
+		}
+	}
+	summary.InPayout = summary.Balance - summary.Available
+
+	// TODO 3 : Show where the money goes, if the owner has said so yet
+	account, err := ps.query.GetAccount(userId)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		log.Error("internal server error")
+		return payout.SummaryCore{}, errors.New("internal server error")
+	}
+	if err == nil {
+		summary.Account = &account
+	}
+
+	return summary, nil
+}
+
+// SetPayoutAccount implements payout.PayoutService.
+func (ps *payoutService) SetPayoutAccount(userId string, request payout.AccountCore) (payout.AccountCore, error) {
+	request.BankName = strings.TrimSpace(request.BankName)
+	request.AccountNumber = strings.TrimSpace(request.AccountNumber)
+	request.AccountHolder = strings.TrimSpace(request.AccountHolder)
+
+	// TODO 1 : Validate the bank account
+	var message string
+	switch {
+	case request.BankName == "":
+		message = "bank_name cannot be empty"
+	case request.AccountNumber == "":
+		message = "account_number cannot be empty"
+	case strings.Trim(request.AccountNumber, "0123456789") != "":
+		message = "invalid account_number, must only contain digits"
+	case request.AccountHolder == "":
+		message = "account_holder cannot be empty"
+	}
+	if message != "" {
+		log.Warn(message)
+		return payout.AccountCore{}, errors.New(message)
+	}
+
+	// TODO 2 : Only venue owners are paid out
+	role, err := ps.query.GetUserRole(userId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return payout.AccountCore{}, errors.New("user not found")
+		}
+		log.Error("internal server error")
+		return payout.AccountCore{}, errors.New("internal server error")
+	}
+	if role != "owner" {
+		log.Warn("user is not a venue owner")
+		return payout.AccountCore{}, errors.New("only venue owners can set a payout account")
+	}
+
+	// TODO 3 : Save the bank account, batches made from now on transfer to it
+	request.OwnerID = userId
+	result, err := ps.query.SaveAccount(request)
+	if err != nil {
+		log.Error("internal server error")
+		return payout.AccountCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// CreateBatch implements payout.PayoutService.
+func (ps *payoutService) CreateBatch(userId string) (payout.BatchCore, error) {
+	if err := ps.admin(userId); err != nil {
+		return payout.BatchCore{}, err
+	}
+
+	batch, err := ps.query.CreateBatch()
+	if err != nil {
+		if strings.Contains(err.Error(), "nothing to pay out") {
+			return payout.BatchCore{}, err
+		}
+		log.Error("internal server error")
+		return payout.BatchCore{}, errors.New("internal server error")
+	}
+
+	return batch, nil
+}
+
+// GetBatches implements payout.PayoutService.
+func (ps *payoutService) GetBatches(userId string) ([]payout.BatchCore, error) {
+	if err := ps.admin(userId); err != nil {
+		return nil, err
+	}
+
+	batches, err := ps.query.GetBatches()
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return batches, nil
+}
+
+// MarkBatchPaid implements payout.PayoutService.
+func (ps *payoutService) MarkBatchPaid(userId string, batchId string, reference string) (payout.BatchCore, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		log.Warn("reference cannot be empty")
+		return payout.BatchCore{}, errors.New("reference cannot be empty")
+	}
+
+	if err := ps.admin(userId); err != nil {
+		return payout.BatchCore{}, err
+	}
+
+	batch, err := ps.query.MarkBatchPaid(batchId, userId, reference)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "already been paid") {
+			return payout.BatchCore{}, err
+		}
+		log.Error("internal server error")
+		return payout.BatchCore{}, errors.New("internal server error")
+	}
+
+	return batch, nil
+}
+
+// ExportBatch implements payout.PayoutService.
+func (ps *payoutService) ExportBatch(userId string, batchId string) ([]byte, error) {
+	if err := ps.admin(userId); err != nil {
+		return nil, err
+	}
+
+	batch, err := ps.query.GetBatch(batchId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("batch not found")
+		}
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	file, err := transferFile(batch)
+	if err != nil {
+		log.Sugar().Errorf("failed to write the transfer file of batch %s: %v", batchId, err)
+		return nil, errors.New("internal server error")
+	}
+
+	return file, nil
+}
+
+func (ps *payoutService) admin(userId string) error {
+	role, err := ps.query.GetUserRole(userId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("user not found")
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+	if role != "admin" {
+		log.Warn("user is not an admin")
+		return errors.New("only admins can manage payouts")
+	}
+
+	return nil
+}
+
+// transferFile writes a batch as a bulk bank transfer CSV, one transfer per line in whole rupiah.
+func transferFile(batch payout.BatchCore) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{"reference", "bank_name", "account_number", "account_holder", "amount", "currency", "remark"}}
+	for _, line := range batch.Lines {
+		records = append(records, []string{
+			line.LineID,
+			line.BankName,
+			line.AccountNumber,
+			line.AccountHolder,
+			strconv.FormatInt(line.Amount, 10),
+			"IDR",
+			"Playground Pro payout " + batch.BatchID,
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/payout"
+	"github.com/playground-pro-project/playground-pro-api/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMyPayouts(t *testing.T) {
+	data := mocks.NewPayoutData(t)
+	service := New(data)
+	ownerId := "owner_id_1"
+	paidAt := time.Now()
+	entries := []payout.EntryCore{
+		{EntryID: "LDG-5", Account: payout.AccountOwner, OwnerID: ownerId, Amount: -45000, Kind: payout.EntryPayment, PaymentID: "payment_id_3"},
+		{EntryID: "LDG-4", Account: payout.AccountOwner, OwnerID: ownerId, Amount: 81000, Kind: payout.EntryPayout, BatchID: "PYB-1"},
+		{EntryID: "LDG-3", Account: payout.AccountOwner, OwnerID: ownerId, Amount: 9000, Kind: payout.EntryRefund, PaymentID: "payment_id_2", BatchID: "PYB-1"},
+		{EntryID: "LDG-2", Account: payout.AccountOwner, OwnerID: ownerId, Amount: -18000, Kind: payout.EntryPayment, PaymentID: "payment_id_2", BatchID: "PYB-1"},
+		{EntryID: "LDG-1", Account: payout.AccountOwner, OwnerID: ownerId, Amount: -72000, Kind: payout.EntryPayment, PaymentID: "payment_id_1", BatchID: "PYB-1"},
+	}
+	payouts := []payout.LineCore{{LineID: "PYL-1", BatchID: "PYB-1", OwnerID: ownerId, Amount: 81000, Status: payout.BatchPaid, PaidAt: &paidAt}}
+	account := payout.AccountCore{OwnerID: ownerId, BankName: "BCA", AccountNumber: "1234567890", AccountHolder: "Budi"}
+
+	t.Run("success", func(t *testing.T) {
+		data.On("GetOwnerEntries", ownerId).Return(entries, nil).Once()
+		data.On("GetOwnerPayouts", ownerId).Return(payouts, nil).Once()
+		data.On("GetAccount", ownerId).Return(account, nil).Once()
+
+		result, err := service.MyPayouts(ownerId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(45000), result.Balance)
+		assert.Equal(t, int64(45000), result.Available)
+		assert.Equal(t, int64(0), result.InPayout)
+		assert.Equal(t, int64(81000), result.PaidOut)
+		assert.Equal(t, &account, result.Account)
+		assert.Equal(t, entries, result.Entries)
+		assert.Equal(t, payouts, result.Payouts)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - batched but not paid yet", func(t *testing.T) {
+		pending := []payout.EntryCore{entries[0], entries[2], entries[3], entries[4]}
+		data.On("GetOwnerEntries", ownerId).Return(pending, nil).Once()
+		data.On("GetOwnerPayouts", ownerId).Return([]payout.LineCore{}, nil).Once()
+		data.On("GetAccount", ownerId).Return(payout.AccountCore{}, errors.New("payout account not found")).Once()
+
+		result, err := service.MyPayouts(ownerId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(126000), result.Balance)
+		assert.Equal(t, int64(45000), result.Available)
+		assert.Equal(t, int64(81000), result.InPayout)
+		assert.Equal(t, int64(0), result.PaidOut)
+		assert.Nil(t, result.Account)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - ledger", func(t *testing.T) {
+		data.On("GetOwnerEntries", ownerId).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := service.MyPayouts(ownerId)
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestSetPayoutAccount(t *testing.T) {
+	data := mocks.NewPayoutData(t)
+	service := New(data)
+	ownerId := "owner_id_1"
+	request := payout.AccountCore{BankName: " BCA ", AccountNumber: "1234567890", AccountHolder: " Budi "}
+
+	t.Run("success", func(t *testing.T) {
+		expected := payout.AccountCore{OwnerID: ownerId, BankName: "BCA", AccountNumber: "1234567890", AccountHolder: "Budi"}
+		data.On("GetUserRole", ownerId).Return("owner", nil).Once()
+		data.On("SaveAccount", expected).Return(expected, nil).Once()
+
+		result, err := service.SetPayoutAccount(ownerId, request)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not an owner", func(t *testing.T) {
+		data.On("GetUserRole", "user_id_1").Return("user", nil).Once()
+
+		_, err := service.SetPayoutAccount("user_id_1", request)
+		assert.EqualError(t, err, "only venue owners can set a payout account")
+		data.AssertExpectations(t)
+	})
+
+	invalid := []struct {
+		name    string
+		request payout.AccountCore
+		message string
+	}{
+		{"error - empty bank", payout.AccountCore{AccountNumber: "1234567890", AccountHolder: "Budi"}, "bank_name cannot be empty"},
+		{"error - empty account number", payout.AccountCore{BankName: "BCA", AccountHolder: "Budi"}, "account_number cannot be empty"},
+		{"error - account number with letters", payout.AccountCore{BankName: "BCA", AccountNumber: "12-3456", AccountHolder: "Budi"}, "invalid account_number, must only contain digits"},
+		{"error - empty holder", payout.AccountCore{BankName: "BCA", AccountNumber: "1234567890"}, "account_holder cannot be empty"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.SetPayoutAccount(ownerId, tc.request)
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	data := mocks.NewPayoutData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		batch := payout.BatchCore{BatchID: "PYB-1", Status: payout.BatchPending, Total: 81000, Lines: []payout.LineCore{{LineID: "PYL-1", OwnerID: "owner_id_1", Amount: 81000}}}
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("CreateBatch").Return(batch, nil).Once()
+
+		result, err := service.CreateBatch(adminId)
+		assert.Nil(t, err)
+		assert.Equal(t, batch, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - nothing to pay out", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("CreateBatch").Return(payout.BatchCore{}, errors.New("nothing to pay out")).Once()
+
+		_, err := service.CreateBatch(adminId)
+		assert.EqualError(t, err, "nothing to pay out")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not an admin", func(t *testing.T) {
+		data.On("GetUserRole", "owner_id_1").Return("owner", nil).Once()
+
+		_, err := service.CreateBatch("owner_id_1")
+		assert.EqualError(t, err, "only admins can manage payouts")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestMarkBatchPaid(t *testing.T) {
+	data := mocks.NewPayoutData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		paidAt := time.Now()
+		batch := payout.BatchCore{BatchID: "PYB-1", Status: payout.BatchPaid, Total: 81000, Reference: "TRF-0001", PaidBy: adminId, PaidAt: &paidAt}
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("MarkBatchPaid", "PYB-1", adminId, "TRF-0001").Return(batch, nil).Once()
+
+		result, err := service.MarkBatchPaid(adminId, "PYB-1", " TRF-0001 ")
+		assert.Nil(t, err)
+		assert.Equal(t, batch, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - already paid", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("MarkBatchPaid", "PYB-1", adminId, "TRF-0001").Return(payout.BatchCore{}, errors.New("batch has already been paid")).Once()
+
+		_, err := service.MarkBatchPaid(adminId, "PYB-1", "TRF-0001")
+		assert.EqualError(t, err, "batch has already been paid")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - batch not found", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("MarkBatchPaid", "PYB-2", adminId, "TRF-0001").Return(payout.BatchCore{}, errors.New("batch not found")).Once()
+
+		_, err := service.MarkBatchPaid(adminId, "PYB-2", "TRF-0001")
+		assert.EqualError(t, err, "batch not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - empty reference", func(t *testing.T) {
+		_, err := service.MarkBatchPaid(adminId, "PYB-1", " ")
+		assert.EqualError(t, err, "reference cannot be empty")
+	})
+
+	t.Run("error - not an admin", func(t *testing.T) {
+		data.On("GetUserRole", "owner_id_1").Return("owner", nil).Once()
+
+		_, err := service.MarkBatchPaid("owner_id_1", "PYB-1", "TRF-0001")
+		assert.EqualError(t, err, "only admins can manage payouts")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestExportBatch(t *testing.T) {
+	data := mocks.NewPayoutData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		batch := payout.BatchCore{
+			BatchID: "PYB-1",
+			Status:  payout.BatchPending,
+			Total:   126000,
+			Lines: []payout.LineCore{
+				{LineID: "PYL-1", OwnerID: "owner_id_1", Amount: 81000, BankName: "BCA", AccountNumber: "1234567890", AccountHolder: "Budi"},
+				{LineID: "PYL-2", OwnerID: "owner_id_2", Amount: 45000, BankName: "Mandiri", AccountNumber: "0987654321", AccountHolder: "Siti, S.Pd"},
+			},
+		}
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("GetBatch", "PYB-1").Return(batch, nil).Once()
+
+		result, err := service.ExportBatch(adminId, "PYB-1")
+		assert.Nil(t, err)
+		assert.Equal(t, "reference,bank_name,account_number,account_holder,amount,currency,remark\n"+
+			"PYL-1,BCA,1234567890,Budi,81000,IDR,Playground Pro payout PYB-1\n"+
+			"PYL-2,Mandiri,0987654321,\"Siti, S.Pd\",45000,IDR,Playground Pro payout PYB-1\n", string(result))
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - batch not found", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("GetBatch", "PYB-2").Return(payout.BatchCore{}, errors.New("batch not found")).Once()
+
+		_, err := service.ExportBatch(adminId, "PYB-2")
+		assert.EqualError(t, err, "batch not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not an admin", func(t *testing.T) {
+		data.On("GetUserRole", "owner_id_1").Return("owner", nil).Once()
+
+		_, err := service.ExportBatch("owner_id_1", "PYB-1")
+		assert.EqualError(t, err, "only admins can manage payouts")
+		data.AssertExpectations(t)
+	})
+}
@@ -1,10 +1,13 @@
 package data
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/playground-pro-project/playground-pro-api/features/reservation"
-	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
+	voucher "github.com/playground-pro-project/playground-pro-api/features/voucher/data"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
 	"gorm.io/gorm"
 )
 
@@ -13,9 +16,11 @@ type Reservation struct {
 	UserID        string `gorm:"foreignKey:UserID;type:varchar(45)"`
 	VenueID       string `gorm:"foreignKey:VenueID;type:varchar(45)"`
 	PaymentID     *string
+	SeriesID      *string   `gorm:"type:varchar(45);index"`
 	CheckInDate   time.Time `gorm:"type:datetime"`
 	CheckOutDate  time.Time `gorm:"type:datetime"`
 	Duration      float64
+	Subtotal      float64
 	CreatedAt     time.Time      `gorm:"type:datetime"`
 	UpdatedAt     time.Time      `gorm:"type:datetime"`
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
@@ -30,27 +35,166 @@ type Payment struct {
 	GrandTotal    string
 	ServiceFee    float64
 	Status        string         `gorm:"type:enum('pending','success','cancel','expire');default:'pending'"`
+	StatusReason  string         `gorm:"type:varchar(225)"`
+	Purpose       string         `gorm:"type:enum('reservation','reschedule','credits','share');default:'reservation'"`
+	ReferenceID   string         `gorm:"type:varchar(45);index"`
+	VoucherID     string         `gorm:"type:varchar(45);index"`
+	Discount      float64        `gorm:"type:double"`
+	Credits       float64        `gorm:"type:double"`
+	ExpiredAt     *time.Time     `gorm:"type:datetime;index"`
 	CreatedAt     time.Time      `gorm:"type:datetime"`
 	UpdatedAt     time.Time      `gorm:"type:datetime"`
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
 	Reservation   Reservation    `gorm:"foreignKey:PaymentID;references:PaymentID"`
+	Items         []PaymentItem  `gorm:"foreignKey:PaymentID;references:PaymentID"`
+}
+
+// PaymentItem is a line of the invoice of a payment
+type PaymentItem struct {
+	ItemID      string `gorm:"primaryKey;type:varchar(45)"`
+	PaymentID   string `gorm:"type:varchar(45);index"`
+	Kind        string `gorm:"type:enum('booking','discount','platform_fee','booking_fee','tax')"`
+	Description string `gorm:"type:varchar(100)"`
+	Amount      int64  `gorm:"type:bigint"`
 }
 
 type Venue struct {
-	VenueID      string         `gorm:"primaryKey;type:varchar(45)"`
-	UserID       string         `gorm:"type:varchar(45)"`
-	Category     string         `gorm:"type:enum('basketball','football','futsal','badminton');default:'basketball'"`
-	Name         string         `gorm:"type:varchar(225);not null;unique"`
-	Description  string         `gorm:"type:text"`
-	ServiceTime  string         `gorm:"type:varchar(100)"`
-	Location     string         `gorm:"type:text"`
-	Price        float64        `gorm:"type:double"`
-	Longitude    float64        `gorm:"type:double"`
-	Latitude     float64        `gorm:"type:double"`
-	CreatedAt    time.Time      `gorm:"type:datetime"`
-	UpdatedAt    time.Time      `gorm:"type:datetime"`
-	DeletedAt    gorm.DeletedAt `gorm:"index"`
-	Reservations []Reservation  `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	VenueID          string  `gorm:"primaryKey;type:varchar(45)"`
+	UserID           string  `gorm:"type:varchar(45)"`
+	CategoryID       string  `gorm:"type:varchar(45)"`
+	Category         string  `gorm:"->;-:migration"`
+	SlotMinutes      int     `gorm:"->;-:migration"`
+	Name             string  `gorm:"type:varchar(225);not null;unique"`
+	Description      string  `gorm:"type:text"`
+	ServiceTime      string  `gorm:"type:varchar(100)"`
+	Location         string  `gorm:"type:text"`
+	Price            float64 `gorm:"type:double"`
+	Longitude        float64 `gorm:"type:double"`
+	Latitude         float64 `gorm:"type:double"`
+	RescheduleCutoff uint
+	CreatedAt        time.Time      `gorm:"type:datetime"`
+	UpdatedAt        time.Time      `gorm:"type:datetime"`
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
+	Reservations     []Reservation  `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+}
+
+type Waitlist struct {
+	WaitlistID     string     `gorm:"primaryKey;type:varchar(45)"`
+	UserID         string     `gorm:"type:varchar(45);index"`
+	VenueID        string     `gorm:"type:varchar(45);index"`
+	CheckInDate    time.Time  `gorm:"type:datetime"`
+	CheckOutDate   time.Time  `gorm:"type:datetime"`
+	Status         string     `gorm:"type:enum('waiting','offered','claimed','expired','cancelled');default:'waiting'"`
+	HoldID         string     `gorm:"type:varchar(45)"`
+	OfferExpiresAt *time.Time `gorm:"type:datetime;index"`
+	CreatedAt      time.Time  `gorm:"type:datetime"`
+	UpdatedAt      time.Time  `gorm:"type:datetime"`
+}
+
+// Struct helper to read a waitlist entry together with who to notify
+type WaitlistEntry struct {
+	Waitlist
+	Fullname  string
+	Email     string
+	VenueName string
+}
+
+// Struct helper to read who a payment is billed to
+type Customer struct {
+	UserID   string
+	Fullname string
+	Email    string
+}
+
+// PaymentShare is what one player owes of a split payment, paid by a charge of its own
+type PaymentShare struct {
+	ShareID   string    `gorm:"primaryKey;type:varchar(45)"`
+	PaymentID string    `gorm:"type:varchar(45);index"`
+	ChargeID  string    `gorm:"type:varchar(45);uniqueIndex"`
+	UserID    string    `gorm:"type:varchar(45);index"`
+	Amount    int64     `gorm:"type:bigint"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+}
+
+// Struct helper to read a share together with its charge, who pays it and what it books
+type Share struct {
+	PaymentShare
+	Fullname      string
+	Email         string
+	ChargeStatus  string
+	PaymentMethod string
+	PaymentType   string
+	PaymentCode   string
+	GrandTotal    string
+	ExpiredAt     *time.Time
+	ReservationID string
+	VenueID       string
+	VenueName     string
+	CheckInDate   time.Time
+	CheckOutDate  time.Time
+}
+
+type Refund struct {
+	RefundID  string    `gorm:"primaryKey;type:varchar(45)"`
+	PaymentID string    `gorm:"type:varchar(45);index"`
+	OrderID   string    `gorm:"type:varchar(45)"`
+	Amount    float64   `gorm:"type:double"`
+	Reason    string    `gorm:"type:varchar(225)"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+}
+
+// PaymentDiscrepancy records a pending payment the gateway had moved on from
+type PaymentDiscrepancy struct {
+	DiscrepancyID string    `gorm:"primaryKey;type:varchar(45)"`
+	PaymentID     string    `gorm:"type:varchar(45);index"`
+	OrderID       string    `gorm:"type:varchar(45)"`
+	LocalStatus   string    `gorm:"type:varchar(20)"`
+	GatewayStatus string    `gorm:"type:varchar(20)"`
+	LocalAmount   string    `gorm:"type:varchar(45)"`
+	GatewayAmount string    `gorm:"type:varchar(45)"`
+	Resolved      bool      `gorm:"index"`
+	Detail        string    `gorm:"type:varchar(225)"`
+	CreatedAt     time.Time `gorm:"type:datetime;index"`
+}
+
+// Hold is kept in Redis rather than the database
+type Hold struct {
+	HoldID       string    `json:"hold_id"`
+	VenueID      string    `json:"venue_id"`
+	UserID       string    `json:"user_id"`
+	CheckInDate  time.Time `json:"check_in_date"`
+	CheckOutDate time.Time `json:"check_out_date"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Struct helpers to read the structured schedule owned by the venue feature
+type OpeningHour struct {
+	Weekday   int
+	OpenTime  string
+	CloseTime string
+}
+
+type Closure struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Reason    string
+}
+
+type PricingRule struct {
+	Name        string
+	Weekdays    string
+	StartTime   string
+	EndTime     string
+	StartDate   *time.Time
+	EndDate     *time.Time
+	MinDuration float64
+	Price       float64
+	Priority    int
+}
+
+type CancellationPolicy struct {
+	HoursBefore   int
+	RefundPercent int
 }
 
 // `gorm:"type:enum('none','card','bca','bri','bni','mandiri','qris','gopay','shopeepay');default:'none'"`
@@ -64,11 +208,14 @@ type Availability struct {
 	ReservationID string
 	CheckInDate   time.Time
 	CheckOutDate  time.Time
+	Status        string
 }
 
 type MyReservation struct {
 	VenueID       string
 	VenueName     string
+	CategoryID    string
+	Category      string
 	Location      string
 	ReservationID string
 	CheckInDate   time.Time
@@ -89,6 +236,8 @@ func modelToMyReservationCore(result []MyReservation) []reservation.MyReservatio
 		myReservation := reservation.MyReservationCore{
 			VenueID:       r.VenueID,
 			VenueName:     r.VenueName,
+			CategoryID:    r.CategoryID,
+			Category:      r.Category,
 			Location:      r.Location,
 			ReservationID: r.ReservationID,
 			CheckInDate:   r.CheckInDate,
@@ -119,6 +268,7 @@ func modelToAvailabilityCore(result []Availability) []reservation.AvailabilityCo
 			ReservationID: r.ReservationID,
 			CheckInDate:   r.CheckInDate,
 			CheckOutDate:  r.CheckOutDate,
+			Status:        r.Status,
 		}
 		availabilities = append(availabilities, availability)
 	}
@@ -128,52 +278,62 @@ func modelToAvailabilityCore(result []Availability) []reservation.AvailabilityCo
 func modelToReservationCore(models []Reservation) []reservation.ReservationCore {
 	var cores []reservation.ReservationCore
 	for _, m := range models {
-		core := reservation.ReservationCore{
-			ReservationID: m.ReservationID,
-			UserID:        m.UserID,
-			VenueID:       m.VenueID,
-			CheckInDate:   m.CheckInDate,
-			CheckOutDate:  m.CheckOutDate,
-			Duration:      m.Duration,
-		}
-		cores = append(cores, core)
+		cores = append(cores, reservationModels(m))
 	}
 	return cores
 }
 
 // Reservation-Model to reservation-core
 func reservationModels(r Reservation) reservation.ReservationCore {
-	return reservation.ReservationCore{
+	result := reservation.ReservationCore{
 		ReservationID: r.ReservationID,
 		UserID:        r.UserID,
 		VenueID:       r.VenueID,
 		CheckInDate:   r.CheckInDate,
 		CheckOutDate:  r.CheckOutDate,
 		Duration:      r.Duration,
+		Subtotal:      r.Subtotal,
 		CreatedAt:     r.CreatedAt,
 		UpdatedAt:     r.UpdatedAt,
 		DeletedAt:     r.DeletedAt.Time,
 	}
+	if r.PaymentID != nil {
+		result.PaymentID = *r.PaymentID
+	}
+	if r.SeriesID != nil {
+		result.SeriesID = *r.SeriesID
+	}
+
+	return result
 }
 
 // Reservation-core to Reservation-Model
 func reservationEntities(r reservation.ReservationCore) Reservation {
-	return Reservation{
+	result := Reservation{
 		ReservationID: r.ReservationID,
 		UserID:        r.UserID,
 		VenueID:       r.VenueID,
 		CheckInDate:   r.CheckInDate,
 		CheckOutDate:  r.CheckOutDate,
 		Duration:      r.Duration,
+		Subtotal:      r.Subtotal,
 		CreatedAt:     r.CreatedAt,
 		UpdatedAt:     r.UpdatedAt,
 		DeletedAt:     gorm.DeletedAt{Time: r.DeletedAt},
 	}
+	if r.PaymentID != "" {
+		result.PaymentID = &r.PaymentID
+	}
+	if r.SeriesID != "" {
+		result.SeriesID = &r.SeriesID
+	}
+
+	return result
 }
 
 // Payment-Model to payment-core
 func paymentModels(p Payment) reservation.PaymentCore {
-	return reservation.PaymentCore{
+	result := reservation.PaymentCore{
 		PaymentID:     p.PaymentID,
 		PaymentCode:   p.PaymentCode,
 		PaymentMethod: p.PaymentMethod,
@@ -181,14 +341,25 @@ func paymentModels(p Payment) reservation.PaymentCore {
 		GrandTotal:    p.GrandTotal,
 		ServiceFee:    p.ServiceFee,
 		Status:        p.Status,
+		StatusReason:  p.StatusReason,
+		Purpose:       p.Purpose,
+		ReferenceID:   p.ReferenceID,
+		VoucherID:     p.VoucherID,
+		Discount:      p.Discount,
+		Credits:       p.Credits,
 		CreatedAt:     p.CreatedAt,
 		UpdatedAt:     p.UpdatedAt,
 	}
+	if p.ExpiredAt != nil {
+		result.ExpiredAt = *p.ExpiredAt
+	}
+
+	return result
 }
 
 // Payment-core to payment-model
 func paymentEntities(p reservation.PaymentCore) *Payment {
-	return &Payment{
+	result := &Payment{
 		PaymentID:     p.PaymentID,
 		PaymentCode:   p.PaymentCode,
 		PaymentMethod: p.PaymentMethod,
@@ -196,25 +367,319 @@ func paymentEntities(p reservation.PaymentCore) *Payment {
 		GrandTotal:    p.GrandTotal,
 		ServiceFee:    p.ServiceFee,
 		Status:        p.Status,
+		StatusReason:  p.StatusReason,
+		Purpose:       p.Purpose,
+		ReferenceID:   p.ReferenceID,
+		VoucherID:     p.VoucherID,
+		Discount:      p.Discount,
+		Credits:       p.Credits,
 		CreatedAt:     p.CreatedAt,
 		UpdatedAt:     p.UpdatedAt,
 	}
+	if !p.ExpiredAt.IsZero() {
+		result.ExpiredAt = &p.ExpiredAt
+	}
+
+	return result
+}
+
+func paymentItemModels(items []PaymentItem) []reservation.PaymentItemCore {
+	result := make([]reservation.PaymentItemCore, len(items))
+	for i, item := range items {
+		result[i] = reservation.PaymentItemCore{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return result
+}
+
+func paymentItemEntities(paymentID string, items []reservation.PaymentItemCore) []PaymentItem {
+	result := make([]PaymentItem, len(items))
+	for i, item := range items {
+		result[i] = PaymentItem{
+			ItemID:      helper.GeneratePaymentItemID(),
+			PaymentID:   paymentID,
+			Kind:        item.Kind,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return result
+}
+
+// Venue-Model to venue-core
+func venueModels(v Venue) reservation.VenueCore {
+	return reservation.VenueCore{
+		VenueID:          v.VenueID,
+		OwnerID:          v.UserID,
+		CategoryID:       v.CategoryID,
+		Category:         v.Category,
+		SlotMinutes:      v.SlotMinutes,
+		Name:             v.Name,
+		Description:      v.Description,
+		ServiceTime:      v.ServiceTime,
+		Location:         v.Location,
+		Price:            v.Price,
+		Longitude:        v.Longitude,
+		Latitude:         v.Latitude,
+		RescheduleCutoff: v.RescheduleCutoff,
+	}
+}
+
+func holdModels(h Hold) reservation.HoldCore {
+	return reservation.HoldCore{
+		HoldID:       h.HoldID,
+		VenueID:      h.VenueID,
+		UserID:       h.UserID,
+		CheckInDate:  h.CheckInDate,
+		CheckOutDate: h.CheckOutDate,
+		ExpiresAt:    h.ExpiresAt,
+	}
+}
+
+func holdEntities(h reservation.HoldCore) Hold {
+	return Hold{
+		HoldID:       h.HoldID,
+		VenueID:      h.VenueID,
+		UserID:       h.UserID,
+		CheckInDate:  h.CheckInDate,
+		CheckOutDate: h.CheckOutDate,
+		ExpiresAt:    h.ExpiresAt,
+	}
+}
+
+func waitlistModels(w WaitlistEntry) reservation.WaitlistCore {
+	result := reservation.WaitlistCore{
+		WaitlistID:   w.WaitlistID,
+		UserID:       w.UserID,
+		VenueID:      w.VenueID,
+		CheckInDate:  w.CheckInDate,
+		CheckOutDate: w.CheckOutDate,
+		Status:       w.Status,
+		HoldID:       w.HoldID,
+		CreatedAt:    w.CreatedAt,
+		Fullname:     w.Fullname,
+		Email:        w.Email,
+		VenueName:    w.VenueName,
+	}
+	if w.OfferExpiresAt != nil {
+		result.OfferExpiresAt = *w.OfferExpiresAt
+	}
+
+	return result
+}
+
+func waitlistEntities(w reservation.WaitlistCore) Waitlist {
+	result := Waitlist{
+		WaitlistID:   w.WaitlistID,
+		UserID:       w.UserID,
+		VenueID:      w.VenueID,
+		CheckInDate:  w.CheckInDate,
+		CheckOutDate: w.CheckOutDate,
+		Status:       w.Status,
+		HoldID:       w.HoldID,
+	}
+	if !w.OfferExpiresAt.IsZero() {
+		result.OfferExpiresAt = &w.OfferExpiresAt
+	}
+
+	return result
+}
+
+func modelToWaitlistCore(entries []WaitlistEntry) []reservation.WaitlistCore {
+	result := make([]reservation.WaitlistCore, len(entries))
+	for i, w := range entries {
+		result[i] = waitlistModels(w)
+	}
+
+	return result
+}
+
+func openingHourModels(hours []OpeningHour) []reservation.OpeningHourCore {
+	result := make([]reservation.OpeningHourCore, len(hours))
+	for i, h := range hours {
+		result[i] = reservation.OpeningHourCore{
+			Weekday:   h.Weekday,
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		}
+	}
+
+	return result
+}
+
+func closureModels(closures []Closure) []reservation.ClosureCore {
+	result := make([]reservation.ClosureCore, len(closures))
+	for i, c := range closures {
+		result[i] = reservation.ClosureCore{
+			StartDate: c.StartDate,
+			EndDate:   c.EndDate,
+			Reason:    c.Reason,
+		}
+	}
+
+	return result
+}
+
+func pricingRuleModels(rules []PricingRule) []reservation.PricingRuleCore {
+	result := make([]reservation.PricingRuleCore, len(rules))
+	for i, r := range rules {
+		weekdays := []int{}
+		for _, day := range strings.Split(r.Weekdays, ",") {
+			if weekday, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+				weekdays = append(weekdays, weekday)
+			}
+		}
+
+		result[i] = reservation.PricingRuleCore{
+			Name:        r.Name,
+			Weekdays:    weekdays,
+			StartTime:   r.StartTime,
+			EndTime:     r.EndTime,
+			MinDuration: r.MinDuration,
+			Price:       r.Price,
+			Priority:    r.Priority,
+		}
+		if r.StartDate != nil {
+			result[i].StartDate = *r.StartDate
+		}
+		if r.EndDate != nil {
+			result[i].EndDate = *r.EndDate
+		}
+	}
+
+	return result
+}
+
+func voucherModels(v voucher.Voucher) reservation.VoucherCore {
+	result := reservation.VoucherCore{
+		VoucherID:     v.VoucherID,
+		Code:          v.Code,
+		DiscountType:  v.DiscountType,
+		DiscountValue: v.DiscountValue,
+		MaxDiscount:   v.MaxDiscount,
+		MinSpend:      v.MinSpend,
+		VenueID:       v.VenueID,
+	}
+	if v.CategoryID != nil {
+		result.CategoryID = *v.CategoryID
+	}
+	if v.ValidFrom != nil {
+		result.ValidFrom = *v.ValidFrom
+	}
+	if v.ValidUntil != nil {
+		result.ValidUntil = *v.ValidUntil
+	}
+
+	return result
+}
+
+func cancellationPolicyModels(tiers []CancellationPolicy) []reservation.CancellationTierCore {
+	result := make([]reservation.CancellationTierCore, len(tiers))
+	for i, t := range tiers {
+		result[i] = reservation.CancellationTierCore{
+			HoursBefore:   t.HoursBefore,
+			RefundPercent: t.RefundPercent,
+		}
+	}
+
+	return result
+}
+
+func shareModels(s Share) reservation.ShareCore {
+	result := reservation.ShareCore{
+		ShareID:   s.ShareID,
+		PaymentID: s.PaymentID,
+		UserID:    s.UserID,
+		Fullname:  s.Fullname,
+		Email:     s.Email,
+		Amount:    s.Amount,
+		Charge: reservation.PaymentCore{
+			PaymentID:     s.ChargeID,
+			PaymentMethod: s.PaymentMethod,
+			PaymentType:   s.PaymentType,
+			PaymentCode:   s.PaymentCode,
+			GrandTotal:    s.GrandTotal,
+			Status:        s.ChargeStatus,
+			Purpose:       reservation.PaymentForShare,
+			ReferenceID:   s.PaymentID,
+		},
+		Reservation: reservation.ReservationCore{
+			ReservationID: s.ReservationID,
+			VenueID:       s.VenueID,
+			CheckInDate:   s.CheckInDate,
+			CheckOutDate:  s.CheckOutDate,
+			Venue:         reservation.VenueCore{VenueID: s.VenueID, Name: s.VenueName},
+		},
+		CreatedAt: s.CreatedAt,
+	}
+	if s.ExpiredAt != nil {
+		result.Charge.ExpiredAt = *s.ExpiredAt
+	}
+
+	return result
+}
+
+func modelToShareCore(shares []Share) []reservation.ShareCore {
+	result := make([]reservation.ShareCore, len(shares))
+	for i, s := range shares {
+		result[i] = shareModels(s)
+	}
+
+	return result
+}
+
+func refundModels(r Refund) reservation.RefundCore {
+	return reservation.RefundCore{
+		RefundID:  r.RefundID,
+		PaymentID: r.PaymentID,
+		OrderID:   r.OrderID,
+		Amount:    r.Amount,
+		Reason:    r.Reason,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func refundEntities(r reservation.RefundCore) Refund {
+	return Refund{
+		RefundID:  r.RefundID,
+		PaymentID: r.PaymentID,
+		OrderID:   r.OrderID,
+		Amount:    r.Amount,
+		Reason:    r.Reason,
+	}
+}
+
+func discrepancyModels(d PaymentDiscrepancy) reservation.DiscrepancyCore {
+	return reservation.DiscrepancyCore{
+		DiscrepancyID: d.DiscrepancyID,
+		PaymentID:     d.PaymentID,
+		OrderID:       d.OrderID,
+		LocalStatus:   d.LocalStatus,
+		GatewayStatus: d.GatewayStatus,
+		LocalAmount:   d.LocalAmount,
+		GatewayAmount: d.GatewayAmount,
+		Resolved:      d.Resolved,
+		Detail:        d.Detail,
+		CreatedAt:     d.CreatedAt,
+	}
 }
 
-// Payment response Midtrans to payment-core
-func PaymentCoreFromChargeResponse(res *paymentgateway.ChargeResponse) reservation.PaymentCore {
-	return reservation.PaymentCore{
-		PaymentID:     res.TransactionID,
-		PaymentMethod: res.PaymentType,
-		PaymentType:   paymentgateway.GetBankType(res),
-		PaymentCode:   paymentgateway.GetPaymentCode(res),
-		GrandTotal:    res.GrossAmount,
-		ServiceFee:    0,
-		Status:        res.TransactionStatus,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-		ReservationID: res.OrderID,
-		Reservation:   reservation.ReservationCore{},
+func discrepancyEntities(d reservation.DiscrepancyCore) PaymentDiscrepancy {
+	return PaymentDiscrepancy{
+		DiscrepancyID: d.DiscrepancyID,
+		PaymentID:     d.PaymentID,
+		OrderID:       d.OrderID,
+		LocalStatus:   d.LocalStatus,
+		GatewayStatus: d.GatewayStatus,
+		LocalAmount:   d.LocalAmount,
+		GatewayAmount: d.GatewayAmount,
+		Resolved:      d.Resolved,
+		Detail:        d.Detail,
 	}
 }
 
@@ -226,12 +691,14 @@ func paymentToCore(p Payment) reservation.PaymentCore {
 	}
 
 	paymentCore := reservation.PaymentCore{
-		PaymentType: p.PaymentType,
-		PaymentCode: p.PaymentCode,
-		GrandTotal:  p.GrandTotal,
-		ServiceFee:  p.ServiceFee,
-		Status:      p.Status,
-		Reservation: reservationCore,
+		PaymentType:  p.PaymentType,
+		PaymentCode:  p.PaymentCode,
+		GrandTotal:   p.GrandTotal,
+		ServiceFee:   p.ServiceFee,
+		Status:       p.Status,
+		StatusReason: p.StatusReason,
+		Items:        paymentItemModels(p.Items),
+		Reservation:  reservationCore,
 	}
 
 	if p.Reservation.VenueID != "" {
@@ -1,73 +1,468 @@
 package data
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	credit "github.com/playground-pro-project/playground-pro-api/features/credit/data"
+	payout "github.com/playground-pro-project/playground-pro-api/features/payout/data"
 	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	voucher "github.com/playground-pro-project/playground-pro-api/features/voucher/data"
 	"github.com/playground-pro-project/playground-pro-api/utils/helper"
-	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
+	"github.com/playground-pro-project/playground-pro-api/utils/invoice"
+	"github.com/playground-pro-project/playground-pro-api/utils/redis"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var log = middlewares.Log()
 
 type reservationQuery struct {
-	db *gorm.DB
+	db    *gorm.DB
+	redis *redis.RedisClient
 }
 
 func New(db *gorm.DB) reservation.ReservationData {
 	return &reservationQuery{
-		db: db,
+		db:    db,
+		redis: redis.NewRedisClient(),
 	}
 }
 
 // MakeReservation implements reservation.ReservationData.
 func (rq *reservationQuery) MakeReservation(userID string, r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
+	r.UserID = userID
+	reservationModel := reservationEntities(r)
+
+	models, payment, err := rq.book(reservationModel.ReservationID, []Reservation{reservationModel}, p)
+	if err != nil {
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+	}
+
+	return reservationModels(models[0]), payment, nil
+}
+
+// MakeRecurringReservation implements reservation.ReservationData.
+func (rq *reservationQuery) MakeRecurringReservation(userID string, occurrences []reservation.ReservationCore, p reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, error) {
+	occurrenceModels := make([]Reservation, len(occurrences))
+	for i, r := range occurrences {
+		r.UserID = userID
+		occurrenceModels[i] = reservationEntities(r)
+		occurrenceModels[i].ReservationID = helper.GenerateReservationID()
+	}
+
+	// The series was charged once, under its own ID
+	models, payment, err := rq.book(occurrences[0].SeriesID, occurrenceModels, p)
+	if err != nil {
+		return nil, reservation.PaymentCore{}, err
+	}
+
+	return modelToReservationCore(models), payment, nil
+}
+
+// book saves reservations of a single venue together with the one payment for all of them,
+// all or nothing. The payment was charged under orderID before, unless it is paid with credits.
+func (rq *reservationQuery) book(orderID string, models []Reservation, p reservation.PaymentCore) ([]Reservation, reservation.PaymentCore, error) {
 	tx := rq.db.Begin()
 	if tx.Error != nil {
 		log.Error("error on beginning database transaction")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error on beginning database transaction")
+		return nil, reservation.PaymentCore{}, errors.New("internal server error on beginning database transaction")
 	}
 
-	r.UserID = userID
-	reservationModel := reservationEntities(r)
-	reservationModel.ReservationID = helper.GenerateReservationID()
+	// TODO 0 : Lock the venue so concurrent bookings of it are serialized,
+	// then re-check the time slots while holding the lock
+	if err := lockVenue(tx, models[0].VenueID); err != nil {
+		tx.Rollback()
+		return nil, reservation.PaymentCore{}, err
+	}
+
+	for _, r := range models {
+		existing, err := overlappingReservations(tx, r.VenueID, r.CheckInDate, r.CheckOutDate)
+		if err != nil {
+			tx.Rollback()
+			log.Error("error while checking existing reservations")
+			return nil, reservation.PaymentCore{}, errors.New("internal server error while checking existing reservations")
+		}
+
+		if len(existing) > 0 {
+			tx.Rollback()
+			log.Warn("reservation not available for the specified time slot")
+			return nil, reservation.PaymentCore{}, errors.New("reservation not available")
+		}
+	}
+
+	if err := rq.checkHolds(models); err != nil {
+		tx.Rollback()
+		return nil, reservation.PaymentCore{}, err
+	}
 
-	// TODO 1 : Create reservation
-	if err := tx.Create(&reservationModel).Error; err != nil {
+	// TODO 1 : Create reservations
+	if err := tx.Create(&models).Error; err != nil {
 		tx.Rollback()
 		log.Error("error while creating reservation")
 		if strings.Contains(err.Error(), "Error 1452") {
-			return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("unregistered user")
+			return nil, reservation.PaymentCore{}, errors.New("unregistered user")
 		}
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while creating reservation")
+		return nil, reservation.PaymentCore{}, errors.New("internal server error while creating reservation")
 	}
 
-	log.Sugar().Info(reservationModel)
+	log.Sugar().Info(models)
 
-	// TODO 2 : Charge payment using Midtrans
-	paymentModel, err := paymentgateway.ChargeMidtrans(reservationModel.ReservationID, p)
-	if err != nil {
-		log.Error("error while charging Midtrans payment")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while charging Midtrans payment")
+	// TODO 2 : Check the voucher caps while holding its lock, so concurrent checkouts cannot overuse it
+	if p.VoucherID != "" {
+		if err := checkVoucherUsage(tx, p.VoucherID, models[0].UserID); err != nil {
+			tx.Rollback()
+			return nil, reservation.PaymentCore{}, err
+		}
+	}
+
+	// TODO 3 : Spend prepaid hours, anything else was charged already
+	payment := p
+	if p.PaymentType == reservation.PaymentTypeCredits {
+		spent, err := spendCredits(tx, orderID, models[0].UserID, models[0].VenueID, p.Credits)
+		if err != nil {
+			tx.Rollback()
+			return nil, reservation.PaymentCore{}, err
+		}
+		payment.PaymentID = spent.PaymentID
+		payment.PaymentMethod = spent.PaymentMethod
+		payment.GrandTotal = spent.GrandTotal
+		payment.Status = spent.Status
+	} else if p.PaymentID == "" {
+		tx.Rollback()
+		log.Error("payment was not charged")
+		return nil, reservation.PaymentCore{}, errors.New("internal server error while saving payment")
 	}
+	payment.ReservationID = orderID
 
-	// TODO 3 : Create payment
-	if err := tx.Create(paymentEntities(PaymentCoreFromChargeResponse(paymentModel))).Error; err != nil {
+	// TODO 4 : Create payment, together with the voucher redemption it was discounted by
+	if err := tx.Create(paymentEntities(payment)).Error; err != nil {
 		tx.Rollback()
 		log.Error("error while saving payment")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while saving payment")
+		return nil, reservation.PaymentCore{}, errors.New("internal server error while saving payment")
+	}
+
+	if err := savePaymentItems(tx, payment.PaymentID, p.Items); err != nil {
+		tx.Rollback()
+		return nil, reservation.PaymentCore{}, err
+	}
+
+	if err := saveShares(tx, payment); err != nil {
+		tx.Rollback()
+		return nil, reservation.PaymentCore{}, err
+	}
+
+	if p.VoucherID != "" {
+		redemption := voucher.VoucherRedemption{
+			RedemptionID: helper.GenerateRedemptionID(),
+			VoucherID:    p.VoucherID,
+			UserID:       models[0].UserID,
+			PaymentID:    payment.PaymentID,
+			Amount:       p.Discount,
+		}
+		if err := tx.Create(&redemption).Error; err != nil {
+			tx.Rollback()
+			log.Error("error while saving voucher redemption")
+			return nil, reservation.PaymentCore{}, errors.New("internal server error while saving voucher redemption")
+		}
+	}
+
+	// TODO 5 : Assign payment ID to reservations
+	reservationIDs := make([]string, len(models))
+	for i := range models {
+		models[i].PaymentID = &payment.PaymentID
+		reservationIDs[i] = models[i].ReservationID
 	}
 
-	// TODO 4 : Assign payment ID to reservation
-	reservationModel.PaymentID = &paymentModel.TransactionID
-	if err := tx.Save(&reservationModel).Error; err != nil {
+	query := tx.Model(&Reservation{}).
+		Where("reservation_id IN ?", reservationIDs).
+		Update("payment_id", payment.PaymentID)
+	if query.Error != nil {
 		tx.Rollback()
 		log.Error("error while updating reservation with payment_id")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while updating reservation with payment_id")
+		return nil, reservation.PaymentCore{}, errors.New("internal server error while updating reservation with payment_id")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error("error on committing database transaction")
+		return nil, reservation.PaymentCore{}, errors.New("internal server error on committing database transaction")
+	}
+
+	return models, payment, nil
+}
+
+// checkHolds re-reads the holds on the venue once it is locked, so a slot held by another user
+// after the service checked it cannot be booked.
+func (rq *reservationQuery) checkHolds(models []Reservation) error {
+	values, err := rq.redis.GetIndexed(holdIndexKey(models[0].VenueID))
+	if err != nil {
+		log.Sugar().Error("error while checking holds:", err)
+		return errors.New("internal server error while checking holds")
+	}
+
+	for _, h := range decodeHolds(values) {
+		for _, r := range models {
+			if h.UserID != r.UserID && h.CheckInDate.Before(r.CheckOutDate) && r.CheckInDate.Before(h.CheckOutDate) {
+				log.Warn("reservation slot is held by another user")
+				return errors.New("reservation not available")
+			}
+		}
+	}
+
+	return nil
+}
+
+// unchangedStatus tells why a status update left the payment as it was: it is gone, it has
+// the status already, or it cannot move to it.
+func (rq *reservationQuery) unchangedStatus(request reservation.PaymentCore) error {
+	var status string
+	query := rq.db.Table("payments").
+		Select("status").
+		Where("payment_id = ? AND deleted_at IS NULL", request.PaymentID).
+		Scan(&status)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payment query:", query.Error)
+		return errors.New("internal server error")
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("payment not found")
+		return errors.New("payment not found")
+	}
+	if status == request.Status {
+		log.Sugar().Infof("payment %s is already %s", request.PaymentID, status)
+		return fmt.Errorf("payment is already %s", status)
+	}
+
+	log.Sugar().Warnf("payment %s cannot change from %s to %s", request.PaymentID, status, request.Status)
+	return fmt.Errorf("payment status cannot change from %s to %s", status, request.Status)
+}
+
+// savePaymentItems stores the invoice lines of a payment.
+func savePaymentItems(tx *gorm.DB, paymentID string, items []reservation.PaymentItemCore) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(paymentItemEntities(paymentID, items)).Error; err != nil {
+		log.Error("error while saving payment items")
+		return errors.New("internal server error while saving payment items")
+	}
+
+	return nil
+}
+
+// saveShares stores the charge of every player of a split payment next to the share it pays.
+func saveShares(tx *gorm.DB, split reservation.PaymentCore) error {
+	for _, s := range split.Shares {
+		charge := s.Charge
+		charge.Purpose = reservation.PaymentForShare
+		charge.ReferenceID = split.PaymentID
+		if err := tx.Create(paymentEntities(charge)).Error; err != nil {
+			log.Error("error while saving share charge")
+			return errors.New("internal server error while saving share charge")
+		}
+
+		share := PaymentShare{
+			ShareID:   s.ShareID,
+			PaymentID: split.PaymentID,
+			ChargeID:  charge.PaymentID,
+			UserID:    s.UserID,
+			Amount:    s.Amount,
+		}
+		if err := tx.Create(&share).Error; err != nil {
+			log.Error("error while saving payment share")
+			return errors.New("internal server error while saving payment share")
+		}
+	}
+
+	return nil
+}
+
+// spendCredits takes hours off the balance of the user at the venue, standing in for a charge
+// that settled at once. The caller holds the venue lock, which serializes spending from the
+// balance; purchases and refunds only ever add to it.
+func spendCredits(tx *gorm.DB, orderID string, userID string, venueID string, hours float64) (reservation.PaymentCore, error) {
+	var balance float64
+	query := tx.Model(&credit.CreditEntry{}).
+		Select("COALESCE(SUM(hours), 0)").
+		Where("user_id = ? AND venue_id = ?", userID, venueID).
+		Scan(&balance)
+	if query.Error != nil {
+		log.Error("error while reading credits balance")
+		return reservation.PaymentCore{}, errors.New("internal server error while reading credits balance")
+	}
+
+	if balance < hours {
+		log.Sugar().Warnf("insufficient credits, %.2f hour(s) left and %.2f needed", balance, hours)
+		return reservation.PaymentCore{}, errors.New("insufficient credits")
+	}
+
+	charge := reservation.PaymentCore{
+		PaymentID:     helper.GenerateCreditPaymentID(),
+		PaymentMethod: reservation.PaymentTypeCredits,
+		GrandTotal:    "0.00",
+		Status:        "success",
+		ReservationID: orderID,
+	}
+
+	entry := credit.CreditEntry{
+		EntryID:     helper.GenerateCreditEntryID(),
+		UserID:      userID,
+		VenueID:     venueID,
+		Hours:       -hours,
+		Kind:        "booking",
+		ReferenceID: charge.PaymentID,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		log.Error("error while spending credits")
+		return reservation.PaymentCore{}, errors.New("internal server error while spending credits")
+	}
+
+	return charge, nil
+}
+
+// checkVoucherUsage locks the voucher row and checks it still has uses left, overall and for
+// the user. Redemptions of cancelled or expired payments give their use back.
+func checkVoucherUsage(tx *gorm.DB, voucherID string, userID string) error {
+	lockedVoucher := voucher.Voucher{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("voucher_id = ?", voucherID).
+		Take(&lockedVoucher)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Warn("voucher not found")
+		return errors.New("voucher not found")
+	} else if query.Error != nil {
+		log.Error("error while locking voucher")
+		return errors.New("internal server error while locking voucher")
+	}
+
+	redemptions := func(userID string) (int64, error) {
+		var count int64
+		query := tx.Table("voucher_redemptions").
+			Joins("JOIN payments ON payments.payment_id = voucher_redemptions.payment_id").
+			Where("voucher_redemptions.voucher_id = ? AND payments.status NOT IN ?", voucherID, []string{"cancel", "expire"})
+		if userID != "" {
+			query = query.Where("voucher_redemptions.user_id = ?", userID)
+		}
+		err := query.Count(&count).Error
+		return count, err
+	}
+
+	if lockedVoucher.UsageLimit > 0 {
+		used, err := redemptions("")
+		if err != nil {
+			log.Error("error while counting voucher redemptions")
+			return errors.New("internal server error while counting voucher redemptions")
+		}
+		if used >= int64(lockedVoucher.UsageLimit) {
+			log.Warn("voucher usage limit reached")
+			return errors.New("voucher usage limit reached")
+		}
+	}
+
+	if lockedVoucher.PerUserLimit > 0 {
+		used, err := redemptions(userID)
+		if err != nil {
+			log.Error("error while counting voucher redemptions")
+			return errors.New("internal server error while counting voucher redemptions")
+		}
+		if used >= int64(lockedVoucher.PerUserLimit) {
+			log.Warn("voucher usage limit per user reached")
+			return errors.New("voucher usage limit per user reached")
+		}
+	}
+
+	return nil
+}
+
+// lockVenue locks the venue row so concurrent bookings of it are serialized.
+func lockVenue(tx *gorm.DB, venueID string) error {
+	lockedVenue := Venue{}
+	query := tx.Table("venues").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("venue_id").
+		Where("venue_id = ? AND deleted_at IS NULL", venueID).
+		Take(&lockedVenue)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Error("venue not found")
+		return errors.New("venue not found")
+	} else if query.Error != nil {
+		log.Error("error while locking venue")
+		return errors.New("internal server error while locking venue")
+	}
+
+	return nil
+}
+
+// RescheduleReservation moves a reservation to a new time slot of its venue. When p carries a
+// charge, it is saved as a separate reschedule payment of the price difference referencing the
+// reservation.
+func (rq *reservationQuery) RescheduleReservation(r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error on beginning database transaction")
+	}
+
+	if err := lockVenue(tx, r.VenueID); err != nil {
+		tx.Rollback()
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+	}
+
+	existing, err := overlappingReservations(tx, r.VenueID, r.CheckInDate, r.CheckOutDate)
+	if err != nil {
+		tx.Rollback()
+		log.Error("error while checking existing reservations")
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while checking existing reservations")
+	}
+
+	for _, e := range existing {
+		if e.ReservationID != r.ReservationID {
+			tx.Rollback()
+			log.Warn("reservation not available for the specified time slot")
+			return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("reservation not available")
+		}
+	}
+
+	if err := rq.checkHolds([]Reservation{reservationEntities(r)}); err != nil {
+		tx.Rollback()
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+	}
+
+	query := tx.Model(&Reservation{}).
+		Where("reservation_id = ?", r.ReservationID).
+		Updates(map[string]interface{}{
+			"check_in_date":  r.CheckInDate,
+			"check_out_date": r.CheckOutDate,
+			"duration":       r.Duration,
+			"subtotal":       r.Subtotal,
+		})
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while rescheduling reservation:", query.Error)
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while rescheduling reservation")
+	}
+
+	payment := reservation.PaymentCore{}
+	if p.PaymentID != "" {
+		payment = p
+		payment.Purpose = reservation.PaymentForReschedule
+		payment.ReferenceID = r.ReservationID
+		if err := tx.Create(paymentEntities(payment)).Error; err != nil {
+			tx.Rollback()
+			log.Error("error while saving payment")
+			return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error while saving payment")
+		}
+
+		if err := savePaymentItems(tx, payment.PaymentID, p.Items); err != nil {
+			tx.Rollback()
+			return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -76,7 +471,79 @@ func (rq *reservationQuery) MakeReservation(userID string, r reservation.Reserva
 		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error on committing database transaction")
 	}
 
-	return reservationModels(reservationModel), PaymentCoreFromChargeResponse(paymentModel), nil
+	return r, payment, nil
+}
+
+// GetReservation implements reservation.ReservationData.
+func (rq *reservationQuery) GetReservation(userId string, reservationId string) (reservation.ReservationCore, reservation.PaymentCore, error) {
+	reservationModel := Reservation{}
+	query := rq.db.Where("reservation_id = ? AND user_id = ?", reservationId, userId).First(&reservationModel)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Error("reservation not found")
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("reservation not found")
+	} else if query.Error != nil {
+		log.Sugar().Error("error executing reservation query:", query.Error)
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, query.Error
+	}
+
+	payment := Payment{}
+	if reservationModel.PaymentID != nil {
+		query = rq.db.Where("payment_id = ?", *reservationModel.PaymentID).First(&payment)
+		if query.Error != nil && !errors.Is(query.Error, gorm.ErrRecordNotFound) {
+			log.Sugar().Error("error executing payment query:", query.Error)
+			return reservation.ReservationCore{}, reservation.PaymentCore{}, query.Error
+		}
+	}
+
+	return reservationModels(reservationModel), paymentModels(payment), nil
+}
+
+// GetSeries implements reservation.ReservationData.
+func (rq *reservationQuery) GetSeries(seriesId string) ([]reservation.ReservationCore, error) {
+	occurrences := []Reservation{}
+	query := rq.db.Where("series_id = ?", seriesId).Order("check_in_date ASC").Find(&occurrences)
+	if query.Error != nil {
+		log.Sugar().Error("error executing series query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToReservationCore(occurrences), nil
+}
+
+// CancelReservations implements reservation.ReservationData.
+func (rq *reservationQuery) CancelReservations(paymentId string, reservationIds []string, cancelPayment bool) error {
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return errors.New("internal server error on beginning database transaction")
+	}
+
+	if err := tx.Where("reservation_id IN ?", reservationIds).Delete(&Reservation{}).Error; err != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while cancelling reservations:", err)
+		return errors.New("internal server error while cancelling reservations")
+	}
+
+	if cancelPayment {
+		query := tx.Model(&Payment{}).
+			Where("payment_id = ?", paymentId).
+			Updates(map[string]interface{}{
+				"status":        "cancel",
+				"status_reason": "cancelled by customer",
+			})
+		if query.Error != nil {
+			tx.Rollback()
+			log.Sugar().Error("error while cancelling payment:", query.Error)
+			return errors.New("internal server error while cancelling payment")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return errors.New("internal server error on committing database transaction")
+	}
+
+	return nil
 }
 
 // TODO 5: Callback Midtrans for updated payment status during reservation validation
@@ -84,29 +551,117 @@ func (rq *reservationQuery) MakeReservation(userID string, r reservation.Reserva
 // ReservationStatus implements reservation.ReservationData.
 func (rq *reservationQuery) ReservationStatus(request reservation.PaymentCore) (reservation.PaymentCore, error) {
 	req := paymentEntities(request)
-	query := rq.db.Table("payments").
-		Where("payment_id = ?", request.PaymentID).
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return reservation.PaymentCore{}, errors.New("internal server error on beginning database transaction")
+	}
+
+	// Only a payment still in a status it may leave is updated, a concurrent notification
+	// that got there first leaves nothing to update
+	query := tx.Table("payments").
+		Where("payment_id = ? AND status IN ?", request.PaymentID, reservation.TransitionSources(request.Status)).
 		Updates(map[string]interface{}{
 			"status": request.Status,
 		})
-	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
-		log.Error("user profile record not found")
-		return reservation.PaymentCore{}, errors.New("user profile record not found")
+	if query.Error != nil {
+		tx.Rollback()
+		log.Error("error while updating payment status")
+		return reservation.PaymentCore{}, errors.New("internal server error")
 	}
 
 	if query.RowsAffected == 0 {
-		log.Warn("no payment record has been updated")
-		return reservation.PaymentCore{}, errors.New("no row affected")
+		tx.Rollback()
+		return reservation.PaymentCore{}, rq.unchangedStatus(request)
 	}
 
-	if query.Error != nil {
-		log.Error("error while updating payment status")
-		return reservation.PaymentCore{}, errors.New("internal server error")
+	// A settled package purchase credits its hours together with the status change, and any
+	// settled payment credits the venue owner on the settlement ledger
+	if request.Status == "success" {
+		if err := creditPurchase(tx, request.PaymentID); err != nil {
+			tx.Rollback()
+			return reservation.PaymentCore{}, err
+		}
+		if err := payout.PostPayment(tx, request.PaymentID); err != nil {
+			tx.Rollback()
+			return reservation.PaymentCore{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error("error on committing database transaction")
+		return reservation.PaymentCore{}, errors.New("internal server error on committing database transaction")
 	}
 
 	return paymentModels(*req), nil
 }
 
+// creditPurchase adds the hours of the package purchase paid by the payment to the ledger, once.
+// Payments for anything else are left alone.
+func creditPurchase(tx *gorm.DB, paymentID string) error {
+	payment := Payment{}
+	if err := tx.Where("payment_id = ?", paymentID).First(&payment).Error; err != nil {
+		log.Sugar().Error("error executing payment query:", err)
+		return errors.New("internal server error")
+	}
+	if payment.Purpose != reservation.PaymentForCredits {
+		return nil
+	}
+
+	purchase := credit.CreditPurchase{}
+	if err := tx.Where("purchase_id = ?", payment.ReferenceID).First(&purchase).Error; err != nil {
+		log.Sugar().Errorf("purchase %s of payment %s not found", payment.ReferenceID, paymentID)
+		return errors.New("purchase not found")
+	}
+
+	var credited int64
+	query := tx.Model(&credit.CreditEntry{}).
+		Where("kind = ? AND reference_id = ?", "purchase", purchase.PurchaseID).
+		Count(&credited)
+	if query.Error != nil {
+		log.Error("error while checking credited purchase")
+		return errors.New("internal server error")
+	}
+	if credited > 0 {
+		return nil
+	}
+
+	entry := credit.CreditEntry{
+		EntryID:     helper.GenerateCreditEntryID(),
+		UserID:      purchase.UserID,
+		VenueID:     purchase.VenueID,
+		Hours:       purchase.Hours,
+		Kind:        "purchase",
+		ReferenceID: purchase.PurchaseID,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		log.Error("error while crediting purchase")
+		return errors.New("internal server error while crediting purchase")
+	}
+
+	log.Sugar().Infof("%.2f hour(s) of purchase %s have been credited", purchase.Hours, purchase.PurchaseID)
+	return nil
+}
+
+// RefundCredits implements reservation.ReservationData.
+func (rq *reservationQuery) RefundCredits(userId string, venueId string, paymentId string, hours float64) error {
+	entry := credit.CreditEntry{
+		EntryID:     helper.GenerateCreditEntryID(),
+		UserID:      userId,
+		VenueID:     venueId,
+		Hours:       hours,
+		Kind:        "refund",
+		ReferenceID: paymentId,
+	}
+	if err := rq.db.Create(&entry).Error; err != nil {
+		log.Sugar().Error("error while refunding credits:", err)
+		return errors.New("internal server error while refunding credits")
+	}
+
+	return nil
+}
+
 // PriceVenue retrieves the price of a venue by its ID
 func (rq *reservationQuery) PriceVenue(venue_id string) (float64, error) {
 	venue := Venue{}
@@ -125,24 +680,6 @@ func (rq *reservationQuery) PriceVenue(venue_id string) (float64, error) {
 	return venue.Price, nil
 }
 
-// ReservationCheckOutDate retrieves check out time of a reservation by its ID
-func (rq *reservationQuery) ReservationCheckOutDate(reservation_id string) (time.Time, error) {
-	reservation := Reservation{}
-	query := rq.db.Table("reservations").
-		Select("reservations.check_out_date").
-		Where("reservation_id = ?", reservation_id).
-		First(&reservation)
-	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
-		log.Error("venue not found")
-		return time.Time{}, errors.New("venue not found")
-	} else if query.Error != nil {
-		log.Sugar().Error("error executing venue query:", query.Error)
-		return time.Time{}, query.Error
-	}
-	log.Sugar().Infof("checkout date found in the database %v", reservation.CheckOutDate)
-	return reservation.CheckOutDate, nil
-}
-
 // ReservationHistory implements reservation.ReservationData.
 func (rq *reservationQuery) MyReservation(userId string) ([]reservation.MyReservationCore, error) {
 	result := []MyReservation{}
@@ -195,7 +732,7 @@ func (rq *reservationQuery) GetVenueNameAndPrice(venueID string) (string, string
 // DetailTransaction implements reservation.ReservationData.
 func (rq *reservationQuery) DetailTransaction(userId string, paymentId string) (reservation.PaymentCore, error) {
 	payment := Payment{}
-	query := rq.db.Preload("Reservation").Where("payment_id = ?", paymentId).First(&payment)
+	query := rq.db.Preload("Reservation").Preload("Items").Where("payment_id = ?", paymentId).First(&payment)
 	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
 		log.Error("payment not found")
 		return reservation.PaymentCore{}, errors.New("payment not found")
@@ -218,25 +755,77 @@ func (rq *reservationQuery) DetailTransaction(userId string, paymentId string) (
 	return paymentToCore(payment), nil
 }
 
-// CheckAvailability implements reservation.ReservationData.
-func (rq *reservationQuery) CheckAvailability(venueId string) ([]reservation.AvailabilityCore, error) {
-	var result []Availability
-	query := rq.db.Raw(`
-	SELECT venues.venue_id,
-		venues.name, 
-		venues.category,
-		payments.payment_id,  
-		reservations.reservation_id, 
-		reservations.check_in_date, 
-		reservations.check_out_date
-	FROM payments 
-	INNER JOIN reservations ON reservations.payment_id = payments.payment_id 
-	INNER JOIN venues ON venues.venue_id = reservations.venue_id
-	WHERE reservations.check_in_date BETWEEN NOW() AND DATE_ADD(NOW(), INTERVAL 3 DAY)
-		AND payments.status IN ('success', 'pending')
-		AND venues.venue_id = ?
-	GROUP BY venues.venue_id, reservations.reservation_id
-	`, venueId).
+// GetPayment implements reservation.ReservationData.
+func (rq *reservationQuery) GetPayment(paymentId string) (reservation.PaymentCore, error) {
+	payment := Payment{}
+	query := rq.db.Where("payment_id = ?", paymentId).First(&payment)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Error("payment not found")
+		return reservation.PaymentCore{}, errors.New("payment not found")
+	} else if query.Error != nil {
+		log.Sugar().Error("error executing payment query:", query.Error)
+		return reservation.PaymentCore{}, query.Error
+	}
+
+	return paymentModels(payment), nil
+}
+
+// GetPaymentCustomer implements reservation.ReservationData.
+func (rq *reservationQuery) GetPaymentCustomer(paymentId string) (reservation.CustomerCore, error) {
+	customer := Customer{}
+	query := rq.db.Table("payments").
+		Select("users.user_id, users.fullname, users.email").
+		Joins("JOIN reservations ON reservations.payment_id = payments.payment_id OR reservations.reservation_id = payments.reference_id").
+		Joins("JOIN users ON users.user_id = reservations.user_id").
+		Where("payments.payment_id = ?", paymentId).
+		Limit(1).
+		Scan(&customer)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payment customer query:", query.Error)
+		return reservation.CustomerCore{}, query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Error("payment not found")
+		return reservation.CustomerCore{}, errors.New("payment not found")
+	}
+
+	return reservation.CustomerCore{
+		UserID:   customer.UserID,
+		Fullname: customer.Fullname,
+		Email:    customer.Email,
+	}, nil
+}
+
+// CheckAvailability implements reservation.ReservationData.
+func (rq *reservationQuery) CheckAvailability(venueId string) ([]reservation.AvailabilityCore, error) {
+	now := time.Now()
+	return rq.CheckAvailabilityByTimeWindow(venueId, now, now.AddDate(0, 0, 3))
+}
+
+// CheckAvailabilityByTimeWindow lists pending and paid reservations of a venue overlapping [start, end).
+func (rq *reservationQuery) CheckAvailabilityByTimeWindow(venueId string, start time.Time, end time.Time) ([]reservation.AvailabilityCore, error) {
+	var result []Availability
+	query := rq.db.Raw(`
+	SELECT venues.venue_id,
+		venues.name, 
+		categories.name AS category,
+		payments.payment_id,  
+		payments.status,
+		reservations.reservation_id, 
+		reservations.check_in_date, 
+		reservations.check_out_date
+	FROM payments 
+	INNER JOIN reservations ON reservations.payment_id = payments.payment_id 
+	INNER JOIN venues ON venues.venue_id = reservations.venue_id
+	LEFT JOIN categories ON categories.category_id = venues.category_id
+	WHERE reservations.check_in_date < ? 
+		AND reservations.check_out_date > ?
+		AND reservations.deleted_at IS NULL
+		AND payments.status IN ('success', 'pending')
+		AND venues.venue_id = ?
+	GROUP BY venues.venue_id, reservations.reservation_id
+	ORDER BY reservations.check_in_date ASC
+	`, end, start, venueId).
 		Scan(&result)
 	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
 		log.Error("list reservations record not found")
@@ -252,40 +841,181 @@ func (rq *reservationQuery) CheckAvailability(venueId string) ([]reservation.Ava
 	return availabilities, nil
 }
 
-func (rq *reservationQuery) GetReservationsByTimeSlot(venueID string, checkInDate, checkOutDate time.Time) ([]reservation.ReservationCore, error) {
-	var reservations []Reservation
-	query := rq.db.Where("venue_id = ? AND ((check_in_date BETWEEN ? AND ?) OR (check_out_date BETWEEN ? AND ?))",
-		venueID, checkInDate, checkOutDate, checkInDate, checkOutDate).
-		Find(&reservations)
+// GetVenue retrieves the venue being booked by its ID
+func (rq *reservationQuery) GetVenue(venueId string) (reservation.VenueCore, error) {
+	venue := Venue{}
+	query := rq.db.Table("venues").
+		Select("venues.*, categories.name AS category, categories.slot_minutes").
+		Joins("LEFT JOIN categories ON categories.category_id = venues.category_id").
+		Where("venues.venue_id = ? AND venues.deleted_at IS NULL", venueId).
+		First(&venue)
 	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
-		log.Error("list reservations record not found")
-		return nil, errors.New("list reservations record not found")
+		log.Error("venue not found")
+		return reservation.VenueCore{}, errors.New("venue not found")
 	} else if query.Error != nil {
-		log.Sugar().Error("error executing list reservations query:", query.Error)
+		log.Sugar().Error("error executing venue query:", query.Error)
+		return reservation.VenueCore{}, query.Error
+	}
+
+	hours := []OpeningHour{}
+	query = rq.db.Table("venue_hours").
+		Where("venue_id = ?", venueId).
+		Order("weekday ASC, open_time ASC").
+		Find(&hours)
+	if query.Error != nil {
+		log.Sugar().Error("error executing opening hours query:", query.Error)
+		return reservation.VenueCore{}, query.Error
+	}
+
+	result := venueModels(venue)
+	result.OpeningHours = openingHourModels(hours)
+	return result, nil
+}
+
+// GetClosures lists the closures of a venue overlapping [start, end)
+func (rq *reservationQuery) GetClosures(venueId string, start time.Time, end time.Time) ([]reservation.ClosureCore, error) {
+	closures := []Closure{}
+	query := rq.db.Table("venue_closures").
+		Where("venue_id = ? AND start_date < ? AND end_date > ? AND deleted_at IS NULL", venueId, end, start).
+		Order("start_date ASC").
+		Find(&closures)
+	if query.Error != nil {
+		log.Sugar().Error("error executing closures query:", query.Error)
 		return nil, query.Error
-	} else {
-		log.Sugar().Info("list reservations data found in the database")
+	}
+
+	return closureModels(closures), nil
+}
+
+// GetVoucher implements reservation.ReservationData.
+func (rq *reservationQuery) GetVoucher(code string) (reservation.VoucherCore, error) {
+	v := voucher.Voucher{}
+	query := rq.db.Where("code = ?", code).Take(&v)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Warn("voucher not found")
+		return reservation.VoucherCore{}, errors.New("voucher not found")
+	} else if query.Error != nil {
+		log.Sugar().Error("error executing voucher query:", query.Error)
+		return reservation.VoucherCore{}, query.Error
+	}
+
+	return voucherModels(v), nil
+}
+
+// GetPricingRules implements reservation.ReservationData.
+func (rq *reservationQuery) GetPricingRules(venueId string) ([]reservation.PricingRuleCore, error) {
+	rules := []PricingRule{}
+	query := rq.db.Table("pricing_rules").
+		Where("venue_id = ?", venueId).
+		Order("priority DESC, created_at ASC").
+		Find(&rules)
+	if query.Error != nil {
+		log.Sugar().Error("error executing pricing rules query:", query.Error)
+		return nil, query.Error
+	}
+
+	return pricingRuleModels(rules), nil
+}
+
+// GetCancellationPolicy implements reservation.ReservationData.
+func (rq *reservationQuery) GetCancellationPolicy(venueId string) ([]reservation.CancellationTierCore, error) {
+	tiers := []CancellationPolicy{}
+	query := rq.db.Table("cancellation_policies").
+		Where("venue_id = ?", venueId).
+		Order("hours_before DESC").
+		Find(&tiers)
+	if query.Error != nil {
+		log.Sugar().Error("error executing cancellation policy query:", query.Error)
+		return nil, query.Error
+	}
+
+	return cancellationPolicyModels(tiers), nil
+}
+
+// InsertRefund implements reservation.ReservationData.
+func (rq *reservationQuery) InsertRefund(request reservation.RefundCore) (reservation.RefundCore, error) {
+	request.RefundID = helper.GenerateRefundID()
+	req := refundEntities(request)
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return reservation.RefundCore{}, errors.New("internal server error on beginning database transaction")
+	}
+
+	query := tx.Create(&req)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while recording refund:", query.Error)
+		return reservation.RefundCore{}, errors.New("internal server error while recording refund")
+	}
+
+	// What was refunded is taken back from the owner together with the refund
+	if err := payout.PostRefund(tx, req.RefundID, req.PaymentID, invoice.Rupiah(req.Amount)); err != nil {
+		tx.Rollback()
+		return reservation.RefundCore{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error("error on committing database transaction")
+		return reservation.RefundCore{}, errors.New("internal server error on committing database transaction")
+	}
+
+	return refundModels(req), nil
+}
+
+// GetReservationsByTimeSlot lists live reservations of a venue overlapping [checkInDate, checkOutDate)
+func (rq *reservationQuery) GetReservationsByTimeSlot(venueID string, checkInDate, checkOutDate time.Time) ([]reservation.ReservationCore, error) {
+	reservations, err := overlappingReservations(rq.db, venueID, checkInDate, checkOutDate)
+	if err != nil {
+		log.Sugar().Error("error executing list reservations query:", err)
+		return nil, err
 	}
 
 	reservationCores := modelToReservationCore(reservations)
 	return reservationCores, nil
 }
 
-// ReservationHistory implements reservation.ReservationData.
-func (rq *reservationQuery) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time) ([]reservation.MyReservationCore, error) {
+// overlappingReservations finds reservations sharing any instant with [start, end).
+// Reservations whose payment was cancelled or expired no longer hold the slot, while
+// a reservation without a payment yet is still being created and does.
+func overlappingReservations(db *gorm.DB, venueID string, start, end time.Time) ([]Reservation, error) {
+	var reservations []Reservation
+	query := db.Table("reservations").
+		Select("reservations.*").
+		Joins("LEFT JOIN payments ON payments.payment_id = reservations.payment_id").
+		Where("reservations.venue_id = ? AND reservations.check_in_date < ? AND reservations.check_out_date > ?", venueID, end, start).
+		Where("reservations.deleted_at IS NULL").
+		Where("(payments.status IS NULL OR payments.status IN ('pending', 'success'))").
+		Find(&reservations)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+
+	return reservations, nil
+}
+
+// MyVenueCharts implements reservation.ReservationData.
+func (rq *reservationQuery) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time, groupBy string) ([]reservation.MyReservationCore, error) {
 	result := []MyReservation{}
 	search := "%" + keyword + "%"
+	columns := "venues.venue_id, venues.name AS venue_name, categories.category_id, categories.name AS category"
+	group := "venues.venue_id, categories.category_id"
+	if groupBy == reservation.ChartByCategory {
+		columns = "categories.category_id, categories.name AS category"
+		group = "categories.category_id"
+	}
 	query := rq.db.Raw(`
-	SELECT venues.venue_id,
-		venues.name AS venue_name,
+	SELECT `+columns+`,
 		COUNT(payments.payment_id) AS sales_volume
 	FROM payments
 	JOIN reservations ON payments.payment_id = reservations.payment_id
 	JOIN venues ON reservations.venue_id = venues.venue_id
+	LEFT JOIN categories ON categories.category_id = venues.category_id
 	WHERE reservations.user_id = ? 
 		AND ((reservations.check_in_date BETWEEN ? AND ?) OR (reservations.check_out_date BETWEEN ? AND ?))
 		AND payments.status LIKE ?
-	GROUP BY venues.venue_id;
+	GROUP BY `+group+`;
 	`, userId, checkInDate, checkOutDate, checkInDate, checkOutDate, search).
 		Scan(&result)
 	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
@@ -301,3 +1031,629 @@ func (rq *reservationQuery) MyVenueCharts(userId string, keyword string, checkIn
 	log.Sugar().Info(modelToMyReservationCore(result))
 	return modelToMyReservationCore(result), nil
 }
+
+// InsertHold implements reservation.ReservationData.
+func (rq *reservationQuery) InsertHold(request reservation.HoldCore, ttl time.Duration) (reservation.HoldCore, error) {
+	request.HoldID = helper.GenerateHoldID()
+	request.ExpiresAt = time.Now().Add(ttl)
+	value, err := json.Marshal(holdEntities(request))
+	if err != nil {
+		log.Error("error while encoding hold")
+		return reservation.HoldCore{}, errors.New("internal server error while encoding hold")
+	}
+
+	err = rq.redis.SetIndexed(holdIndexKey(request.VenueID), holdKey(request.VenueID, request.HoldID), string(value), ttl, func(values []string) error {
+		for _, h := range decodeHolds(values) {
+			if h.UserID != request.UserID && h.CheckInDate.Before(request.CheckOutDate) && request.CheckInDate.Before(h.CheckOutDate) {
+				return errors.New("slot is held by another user")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "slot is held") {
+			log.Warn("slot is held by another user")
+			return reservation.HoldCore{}, err
+		}
+		log.Sugar().Error("error while saving hold:", err)
+		return reservation.HoldCore{}, errors.New("internal server error while saving hold")
+	}
+
+	log.Sugar().Infof("new hold has been created: %s", request.HoldID)
+	return request, nil
+}
+
+// GetHolds implements reservation.ReservationData.
+func (rq *reservationQuery) GetHolds(venueId string) ([]reservation.HoldCore, error) {
+	values, err := rq.redis.GetIndexed(holdIndexKey(venueId))
+	if err != nil {
+		log.Sugar().Error("error while retrieving holds:", err)
+		return nil, errors.New("internal server error while retrieving holds")
+	}
+
+	holds := decodeHolds(values)
+	result := make([]reservation.HoldCore, len(holds))
+	for i, h := range holds {
+		result[i] = holdModels(h)
+	}
+
+	return result, nil
+}
+
+// DeleteHold implements reservation.ReservationData.
+func (rq *reservationQuery) DeleteHold(venueId string, holdId string) error {
+	err := rq.redis.DeleteIndexed(holdIndexKey(venueId), holdKey(venueId, holdId))
+	if err != nil {
+		log.Sugar().Error("error while releasing hold:", err)
+		return errors.New("internal server error while releasing hold")
+	}
+
+	return nil
+}
+
+func holdIndexKey(venueId string) string {
+	return "holds:" + venueId
+}
+
+func holdKey(venueId string, holdId string) string {
+	return "hold:" + venueId + ":" + holdId
+}
+
+func decodeHolds(values []string) []Hold {
+	holds := []Hold{}
+	for _, v := range values {
+		h := Hold{}
+		if err := json.Unmarshal([]byte(v), &h); err != nil {
+			log.Sugar().Warn("skipping malformed hold:", err)
+			continue
+		}
+		holds = append(holds, h)
+	}
+
+	return holds
+}
+
+// GetPaymentReservations implements reservation.ReservationData.
+func (rq *reservationQuery) GetPaymentReservations(paymentId string) ([]reservation.ReservationCore, error) {
+	reservations := []Reservation{}
+	query := rq.db.Where("payment_id = ?", paymentId).Order("check_in_date ASC").Find(&reservations)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payment reservations query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToReservationCore(reservations), nil
+}
+
+// InsertWaitlist implements reservation.ReservationData.
+func (rq *reservationQuery) InsertWaitlist(request reservation.WaitlistCore) (reservation.WaitlistCore, error) {
+	var count int64
+	query := rq.db.Model(&Waitlist{}).
+		Where("user_id = ? AND venue_id = ?", request.UserID, request.VenueID).
+		Where("check_in_date = ? AND check_out_date = ?", request.CheckInDate, request.CheckOutDate).
+		Where("status IN ?", []string{reservation.WaitlistWaiting, reservation.WaitlistOffered}).
+		Count(&count)
+	if query.Error != nil {
+		log.Sugar().Error("error executing waitlist query:", query.Error)
+		return reservation.WaitlistCore{}, errors.New("internal server error while checking waitlist")
+	}
+	if count > 0 {
+		log.Warn("user is already on the waitlist")
+		return reservation.WaitlistCore{}, errors.New("already on the waitlist")
+	}
+
+	request.WaitlistID = helper.GenerateWaitlistID()
+	request.Status = reservation.WaitlistWaiting
+	req := waitlistEntities(request)
+	query = rq.db.Create(&req)
+	if query.Error != nil {
+		if strings.Contains(query.Error.Error(), "foreign key constraint") {
+			log.Error("foreign key constraint violation")
+			return reservation.WaitlistCore{}, errors.New("unregistered user")
+		}
+		log.Sugar().Error("error while joining waitlist:", query.Error)
+		return reservation.WaitlistCore{}, errors.New("internal server error while joining waitlist")
+	}
+
+	return waitlistModels(WaitlistEntry{Waitlist: req}), nil
+}
+
+// waitlistEntries selects waitlist entries along with the user and venue they refer to.
+func (rq *reservationQuery) waitlistEntries(db *gorm.DB) *gorm.DB {
+	return db.Table("waitlists").
+		Select("waitlists.*, users.fullname, users.email, venues.name AS venue_name").
+		Joins("JOIN users ON users.user_id = waitlists.user_id").
+		Joins("JOIN venues ON venues.venue_id = waitlists.venue_id")
+}
+
+// GetWaitlist returns the entries still waiting for a slot overlapping [start, end), first come first served.
+func (rq *reservationQuery) GetWaitlist(venueId string, start time.Time, end time.Time) ([]reservation.WaitlistCore, error) {
+	entries := []WaitlistEntry{}
+	query := rq.waitlistEntries(rq.db).
+		Where("waitlists.venue_id = ? AND waitlists.status = ?", venueId, reservation.WaitlistWaiting).
+		Where("waitlists.check_in_date < ? AND waitlists.check_out_date > ?", end, start).
+		Order("waitlists.created_at ASC").
+		Find(&entries)
+	if query.Error != nil {
+		log.Sugar().Error("error executing waitlist query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToWaitlistCore(entries), nil
+}
+
+// GetWaitlistEntry implements reservation.ReservationData.
+func (rq *reservationQuery) GetWaitlistEntry(userId string, waitlistId string) (reservation.WaitlistCore, error) {
+	entry := WaitlistEntry{}
+	query := rq.waitlistEntries(rq.db).
+		Where("waitlists.waitlist_id = ? AND waitlists.user_id = ?", waitlistId, userId).
+		First(&entry)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Error("waitlist entry not found")
+		return reservation.WaitlistCore{}, errors.New("waitlist entry not found")
+	} else if query.Error != nil {
+		log.Sugar().Error("error executing waitlist query:", query.Error)
+		return reservation.WaitlistCore{}, query.Error
+	}
+
+	return waitlistModels(entry), nil
+}
+
+// MyWaitlist implements reservation.ReservationData.
+func (rq *reservationQuery) MyWaitlist(userId string) ([]reservation.WaitlistCore, error) {
+	entries := []WaitlistEntry{}
+	query := rq.waitlistEntries(rq.db).
+		Where("waitlists.user_id = ?", userId).
+		Where("waitlists.status IN ?", []string{reservation.WaitlistWaiting, reservation.WaitlistOffered}).
+		Order("waitlists.check_in_date ASC").
+		Find(&entries)
+	if query.Error != nil {
+		log.Sugar().Error("error executing waitlist query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToWaitlistCore(entries), nil
+}
+
+// UpdateWaitlist implements reservation.ReservationData.
+func (rq *reservationQuery) UpdateWaitlist(request reservation.WaitlistCore) error {
+	req := waitlistEntities(request)
+	query := rq.db.Model(&Waitlist{}).
+		Where("waitlist_id = ?", request.WaitlistID).
+		Updates(map[string]interface{}{
+			"status":           req.Status,
+			"hold_id":          req.HoldID,
+			"offer_expires_at": req.OfferExpiresAt,
+		})
+	if query.Error != nil {
+		log.Sugar().Error("error while updating waitlist:", query.Error)
+		return errors.New("internal server error while updating waitlist")
+	}
+
+	return nil
+}
+
+// ClaimWaitlistOffer marks the offer made through the given hold as claimed.
+func (rq *reservationQuery) ClaimWaitlistOffer(userId string, holdId string) error {
+	query := rq.db.Model(&Waitlist{}).
+		Where("user_id = ? AND hold_id = ? AND status = ?", userId, holdId, reservation.WaitlistOffered).
+		Update("status", reservation.WaitlistClaimed)
+	if query.Error != nil {
+		log.Sugar().Error("error while claiming waitlist offer:", query.Error)
+		return errors.New("internal server error while claiming waitlist offer")
+	}
+
+	return nil
+}
+
+// ExpireWaitlistOffers marks offers that were not claimed before the given moment as expired.
+func (rq *reservationQuery) ExpireWaitlistOffers(before time.Time) ([]reservation.WaitlistCore, error) {
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return nil, errors.New("internal server error on beginning database transaction")
+	}
+
+	entries := []Waitlist{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("status = ? AND offer_expires_at < ?", reservation.WaitlistOffered, before).
+		Find(&entries)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error executing stale offers query:", query.Error)
+		return nil, errors.New("internal server error while retrieving stale offers")
+	}
+
+	if len(entries) == 0 {
+		tx.Rollback()
+		return []reservation.WaitlistCore{}, nil
+	}
+
+	waitlistIds := make([]string, len(entries))
+	for i, w := range entries {
+		waitlistIds[i] = w.WaitlistID
+	}
+
+	query = tx.Model(&Waitlist{}).
+		Where("waitlist_id IN ?", waitlistIds).
+		Update("status", reservation.WaitlistExpired)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while expiring offers:", query.Error)
+		return nil, errors.New("internal server error while expiring offers")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return nil, errors.New("internal server error on committing database transaction")
+	}
+
+	result := make([]reservation.WaitlistCore, len(entries))
+	for i, w := range entries {
+		w.Status = reservation.WaitlistExpired
+		result[i] = waitlistModels(WaitlistEntry{Waitlist: w})
+	}
+
+	return result, nil
+}
+
+// GetOverduePayments lists pending payments whose charge expired before the given moment.
+// Payments saved before the expiry was recorded are given Midtrans' default of one day.
+func (rq *reservationQuery) GetOverduePayments(before time.Time) ([]reservation.PaymentCore, error) {
+	payments := []Payment{}
+	query := rq.db.Where("status = ?", "pending").
+		Where("COALESCE(expired_at, DATE_ADD(created_at, INTERVAL 1 DAY)) < ?", before).
+		Find(&payments)
+	if query.Error != nil {
+		log.Sugar().Error("error executing overdue payments query:", query.Error)
+		return nil, errors.New("internal server error while retrieving overdue payments")
+	}
+
+	result := make([]reservation.PaymentCore, len(payments))
+	for i, p := range payments {
+		result[i] = paymentModels(p)
+	}
+
+	return result, nil
+}
+
+// ExpirePendingPayments marks the given payments as expired, which releases their slots.
+// Payments settled or cancelled in the meantime are left as they are and not returned.
+func (rq *reservationQuery) ExpirePendingPayments(paymentIds []string, reason string) ([]reservation.PaymentCore, error) {
+	if len(paymentIds) == 0 {
+		return []reservation.PaymentCore{}, nil
+	}
+
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return nil, errors.New("internal server error on beginning database transaction")
+	}
+
+	payments := []Payment{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("payment_id IN ? AND status = ?", paymentIds, "pending").
+		Find(&payments)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error executing overdue payments query:", query.Error)
+		return nil, errors.New("internal server error while retrieving overdue payments")
+	}
+
+	if len(payments) == 0 {
+		tx.Rollback()
+		return []reservation.PaymentCore{}, nil
+	}
+
+	paymentIds = make([]string, len(payments))
+	for i, p := range payments {
+		paymentIds[i] = p.PaymentID
+	}
+
+	query = tx.Model(&Payment{}).
+		Where("payment_id IN ?", paymentIds).
+		Updates(map[string]interface{}{
+			"status":        "expire",
+			"status_reason": reason,
+		})
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while expiring payments:", query.Error)
+		return nil, errors.New("internal server error while expiring payments")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return nil, errors.New("internal server error on committing database transaction")
+	}
+
+	result := make([]reservation.PaymentCore, len(payments))
+	for i, p := range payments {
+		p.Status = "expire"
+		p.StatusReason = reason
+		result[i] = paymentModels(p)
+	}
+
+	return result, nil
+}
+
+// GetStalePayments lists payments charged before the given moment that are still pending,
+// oldest first. Payments with an open discrepancy are left to the admins, and split payments
+// are never charged themselves, their shares are looked up instead.
+func (rq *reservationQuery) GetStalePayments(before time.Time, limit int) ([]reservation.PaymentCore, error) {
+	payments := []Payment{}
+	open := rq.db.Model(&PaymentDiscrepancy{}).Select("payment_id").Where("resolved = ?", false)
+	query := rq.db.Where("status = ? AND payment_type <> ? AND created_at < ?", reservation.PaymentPending, reservation.PaymentTypeCredits, before).
+		Where("COALESCE(payment_method, '') <> ?", reservation.PaymentMethodSplit).
+		Where("payment_id NOT IN (?)", open).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&payments)
+	if query.Error != nil {
+		log.Sugar().Error("error executing stale payments query:", query.Error)
+		return nil, errors.New("internal server error while retrieving stale payments")
+	}
+
+	result := make([]reservation.PaymentCore, len(payments))
+	for i, p := range payments {
+		result[i] = paymentModels(p)
+	}
+
+	return result, nil
+}
+
+// InsertDiscrepancy implements reservation.ReservationData.
+func (rq *reservationQuery) InsertDiscrepancy(request reservation.DiscrepancyCore) (reservation.DiscrepancyCore, error) {
+	request.DiscrepancyID = helper.GenerateDiscrepancyID()
+	req := discrepancyEntities(request)
+	query := rq.db.Create(&req)
+	if query.Error != nil {
+		log.Sugar().Error("error while recording payment discrepancy:", query.Error)
+		return reservation.DiscrepancyCore{}, errors.New("internal server error while recording payment discrepancy")
+	}
+
+	return discrepancyModels(req), nil
+}
+
+// GetDiscrepancies implements reservation.ReservationData.
+func (rq *reservationQuery) GetDiscrepancies(unresolvedOnly bool) ([]reservation.DiscrepancyCore, error) {
+	discrepancies := []PaymentDiscrepancy{}
+	query := rq.db.Order("created_at DESC")
+	if unresolvedOnly {
+		query = query.Where("resolved = ?", false)
+	}
+	if err := query.Find(&discrepancies).Error; err != nil {
+		log.Sugar().Error("error executing payment discrepancies query:", err)
+		return nil, errors.New("internal server error while retrieving payment discrepancies")
+	}
+
+	result := make([]reservation.DiscrepancyCore, len(discrepancies))
+	for i, d := range discrepancies {
+		result[i] = discrepancyModels(d)
+	}
+
+	return result, nil
+}
+
+// GetUserRole implements reservation.ReservationData.
+func (rq *reservationQuery) GetUserRole(userId string) (string, error) {
+	var role string
+	query := rq.db.Table("users").
+		Select("role").
+		Where("user_id = ? AND deleted_at IS NULL", userId).
+		Scan(&role)
+	if query.Error != nil {
+		log.Sugar().Error("error executing user query:", query.Error)
+		return "", query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("user not found")
+		return "", errors.New("user not found")
+	}
+
+	return role, nil
+}
+
+// GetUserByContact implements reservation.ReservationData. The email is looked up when both are given.
+func (rq *reservationQuery) GetUserByContact(email string, phone string) (reservation.CustomerCore, error) {
+	customer := Customer{}
+	query := rq.db.Table("users").
+		Select("user_id, fullname, email").
+		Where("deleted_at IS NULL")
+	if email != "" {
+		query = query.Where("email = ?", email)
+	} else {
+		query = query.Where("phone = ?", phone)
+	}
+	query = query.Limit(1).Scan(&customer)
+	if query.Error != nil {
+		log.Sugar().Error("error executing user query:", query.Error)
+		return reservation.CustomerCore{}, query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("user not found")
+		return reservation.CustomerCore{}, errors.New("user not found")
+	}
+
+	return reservation.CustomerCore{
+		UserID:   customer.UserID,
+		Fullname: customer.Fullname,
+		Email:    customer.Email,
+	}, nil
+}
+
+const shareColumns = `payment_shares.*,
+	users.fullname,
+	users.email,
+	charges.status AS charge_status,
+	charges.payment_method,
+	charges.payment_type,
+	charges.payment_code,
+	charges.grand_total,
+	charges.expired_at`
+
+// GetShares implements reservation.ReservationData.
+func (rq *reservationQuery) GetShares(paymentId string) ([]reservation.ShareCore, error) {
+	shares := []Share{}
+	query := rq.db.Table("payment_shares").
+		Select(shareColumns).
+		Joins("JOIN payments charges ON charges.payment_id = payment_shares.charge_id").
+		Joins("LEFT JOIN users ON users.user_id = payment_shares.user_id").
+		Where("payment_shares.payment_id = ?", paymentId).
+		Order("payment_shares.created_at ASC").
+		Scan(&shares)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payment shares query:", query.Error)
+		return nil, errors.New("internal server error while retrieving payment shares")
+	}
+
+	return modelToShareCore(shares), nil
+}
+
+// GetUserShares implements reservation.ReservationData.
+func (rq *reservationQuery) GetUserShares(userId string) ([]reservation.ShareCore, error) {
+	shares := []Share{}
+	query := rq.db.Table("payment_shares").
+		Select(shareColumns+`,
+			reservations.reservation_id,
+			reservations.venue_id,
+			venues.name AS venue_name,
+			reservations.check_in_date,
+			reservations.check_out_date`).
+		Joins("JOIN payments charges ON charges.payment_id = payment_shares.charge_id").
+		Joins("JOIN users ON users.user_id = payment_shares.user_id").
+		Joins("JOIN reservations ON reservations.payment_id = payment_shares.payment_id").
+		Joins("JOIN venues ON venues.venue_id = reservations.venue_id").
+		Where("payment_shares.user_id = ?", userId).
+		Order("payment_shares.created_at DESC").
+		Scan(&shares)
+	if query.Error != nil {
+		log.Sugar().Error("error executing payment shares query:", query.Error)
+		return nil, errors.New("internal server error while retrieving payment shares")
+	}
+
+	return modelToShareCore(shares), nil
+}
+
+// ConfirmSplitPayment implements reservation.ReservationData. A pending split payment is
+// marked paid once none of its shares is left unpaid, reporting true when this call did so.
+// The split payment stays locked meanwhile, so when the last two shares settle at once
+// exactly one of them confirms it.
+func (rq *reservationQuery) ConfirmSplitPayment(paymentId string) (reservation.PaymentCore, bool, error) {
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return reservation.PaymentCore{}, false, errors.New("internal server error on beginning database transaction")
+	}
+
+	split, err := lockPayment(tx, paymentId)
+	if err != nil {
+		tx.Rollback()
+		return reservation.PaymentCore{}, false, err
+	}
+	if split.Status != reservation.PaymentPending {
+		tx.Rollback()
+		return paymentModels(split), false, nil
+	}
+
+	var unpaid int64
+	query := tx.Table("payment_shares").
+		Joins("JOIN payments charges ON charges.payment_id = payment_shares.charge_id").
+		Where("payment_shares.payment_id = ? AND charges.status <> ?", paymentId, reservation.PaymentSuccess).
+		Count(&unpaid)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error executing payment shares query:", query.Error)
+		return reservation.PaymentCore{}, false, errors.New("internal server error while retrieving payment shares")
+	}
+	if unpaid > 0 {
+		tx.Rollback()
+		return paymentModels(split), false, nil
+	}
+
+	query = tx.Model(&Payment{}).
+		Where("payment_id = ?", paymentId).
+		Update("status", reservation.PaymentSuccess)
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while confirming split payment:", query.Error)
+		return reservation.PaymentCore{}, false, errors.New("internal server error while confirming split payment")
+	}
+
+	if err := payout.PostPayment(tx, paymentId); err != nil {
+		tx.Rollback()
+		return reservation.PaymentCore{}, false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return reservation.PaymentCore{}, false, errors.New("internal server error on committing database transaction")
+	}
+
+	split.Status = reservation.PaymentSuccess
+	return paymentModels(split), true, nil
+}
+
+// FailSplitPayment implements reservation.ReservationData. A pending split payment is marked
+// expired, which releases its slot, and its shares are returned so they can be voided or
+// refunded. A split payment that is no longer pending is returned as it is, without shares.
+func (rq *reservationQuery) FailSplitPayment(paymentId string, reason string) (reservation.PaymentCore, []reservation.ShareCore, error) {
+	tx := rq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return reservation.PaymentCore{}, nil, errors.New("internal server error on beginning database transaction")
+	}
+
+	split, err := lockPayment(tx, paymentId)
+	if err != nil {
+		tx.Rollback()
+		return reservation.PaymentCore{}, nil, err
+	}
+	if split.Status != reservation.PaymentPending {
+		tx.Rollback()
+		return paymentModels(split), nil, nil
+	}
+
+	query := tx.Model(&Payment{}).
+		Where("payment_id = ?", paymentId).
+		Updates(map[string]interface{}{
+			"status":        reservation.PaymentExpire,
+			"status_reason": reason,
+		})
+	if query.Error != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while expiring split payment:", query.Error)
+		return reservation.PaymentCore{}, nil, errors.New("internal server error while expiring split payment")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return reservation.PaymentCore{}, nil, errors.New("internal server error on committing database transaction")
+	}
+
+	split.Status = reservation.PaymentExpire
+	split.StatusReason = reason
+	shares, err := rq.GetShares(paymentId)
+	if err != nil {
+		return reservation.PaymentCore{}, nil, err
+	}
+
+	return paymentModels(split), shares, nil
+}
+
+// lockPayment reads a payment for update.
+func lockPayment(tx *gorm.DB, paymentID string) (Payment, error) {
+	payment := Payment{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("payment_id = ?", paymentID).
+		First(&payment)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		log.Error("payment not found")
+		return Payment{}, errors.New("payment not found")
+	} else if query.Error != nil {
+		log.Sugar().Error("error executing payment query:", query.Error)
+		return Payment{}, errors.New("internal server error")
+	}
+
+	return payment, nil
+}
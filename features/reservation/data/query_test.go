@@ -0,0 +1,228 @@
+package data
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/playground-pro-project/playground-pro-api/app/config"
+	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"github.com/playground-pro-project/playground-pro-api/utils/redis"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// newTestQuery runs the data layer against sqlmock, with holds kept in an in-memory Redis.
+func newTestQuery(t *testing.T) (*reservationQuery, sqlmock.Sqlmock) {
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}), &gorm.Config{})
+	assert.Nil(t, err)
+
+	server := miniredis.RunT(t)
+	config.REDIS_HOST, config.REDIS_PORT = server.Host(), server.Port()
+	return &reservationQuery{db: db, redis: redis.NewRedisClient()}, mock
+}
+
+// overlapQuery matches the re-check of booked slots; only live bookings of pending or paid
+// payments count, cancelled and expired ones leave their slot free.
+var overlapQuery = regexp.QuoteMeta("SELECT reservations.* FROM `reservations` LEFT JOIN payments ON payments.payment_id = reservations.payment_id " +
+	"WHERE (reservations.venue_id = ? AND reservations.check_in_date < ? AND reservations.check_out_date > ?) " +
+	"AND reservations.deleted_at IS NULL AND ((payments.status IS NULL OR payments.status IN ('pending', 'success'))) " +
+	"AND `reservations`.`deleted_at` IS NULL")
+
+var lockQuery = regexp.QuoteMeta("SELECT `venue_id` FROM `venues` WHERE (venue_id = ? AND deleted_at IS NULL) AND `venues`.`deleted_at` IS NULL LIMIT 1 FOR UPDATE")
+
+func TestBook(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.Local)
+	payment := reservation.PaymentCore{PaymentID: "payment_id_1", PaymentType: "bca", GrandTotal: "300", Status: "pending"}
+	booking := func(checkIn, checkOut time.Time) []Reservation {
+		return []Reservation{{ReservationID: "reservation_id_2", UserID: "user_id_2", VenueID: "venue_id_1", CheckInDate: checkIn, CheckOutDate: checkOut}}
+	}
+
+	t.Run("slot is locked and re-checked in the transaction saving it", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(11*time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs("venue_id_1").
+			WillReturnRows(sqlmock.NewRows([]string{"venue_id"}).AddRow("venue_id_1"))
+		mock.ExpectQuery(overlapQuery).WithArgs("venue_id_1", checkOut, checkIn).
+			WillReturnRows(sqlmock.NewRows([]string{"reservation_id"}))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `reservations`")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `payments`")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `reservations` SET `payment_id`=?")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		models, saved, err := rq.book("reservation_id_2", booking(checkIn, checkOut), payment)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, "payment_id_1", *models[0].PaymentID)
+		assert.Equal(t, "reservation_id_2", saved.ReservationID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("booking enclosing an existing one is rejected", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		// The existing booking runs 10:00 to 11:00, inside the new one
+		checkIn, checkOut := day.Add(9*time.Hour), day.Add(12*time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs("venue_id_1").
+			WillReturnRows(sqlmock.NewRows([]string{"venue_id"}).AddRow("venue_id_1"))
+		// check_in_date < 12:00 AND check_out_date > 09:00 holds for 10:00 to 11:00
+		mock.ExpectQuery(overlapQuery).WithArgs("venue_id_1", checkOut, checkIn).
+			WillReturnRows(sqlmock.NewRows([]string{"reservation_id", "venue_id", "check_in_date", "check_out_date"}).
+				AddRow("reservation_id_1", "venue_id_1", day.Add(10*time.Hour), day.Add(11*time.Hour)))
+		mock.ExpectRollback()
+
+		_, _, err := rq.book("reservation_id_2", booking(checkIn, checkOut), payment)
+		assert.EqualError(t, err, "reservation not available")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("cancelled and expired payments leave the slot free", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(11*time.Hour)
+
+		// The reservations of cancelled and expired payments are filtered out by the re-check
+		// itself, so it finds nothing and the slot is booked
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs("venue_id_1").
+			WillReturnRows(sqlmock.NewRows([]string{"venue_id"}).AddRow("venue_id_1"))
+		mock.ExpectQuery(overlapQuery).WithArgs("venue_id_1", checkOut, checkIn).
+			WillReturnRows(sqlmock.NewRows([]string{"reservation_id"}))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `reservations`")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `payments`")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `reservations` SET `payment_id`=?")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, _, err := rq.book("reservation_id_2", booking(checkIn, checkOut), payment)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("slot held by another user is rejected under the lock", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(11*time.Hour)
+		_, err := rq.InsertHold(reservation.HoldCore{VenueID: "venue_id_1", UserID: "user_id_3", CheckInDate: checkIn, CheckOutDate: checkOut}, time.Minute)
+		assert.Nil(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs("venue_id_1").
+			WillReturnRows(sqlmock.NewRows([]string{"venue_id"}).AddRow("venue_id_1"))
+		mock.ExpectQuery(overlapQuery).WithArgs("venue_id_1", checkOut, checkIn).
+			WillReturnRows(sqlmock.NewRows([]string{"reservation_id"}))
+		mock.ExpectRollback()
+
+		_, _, err = rq.book("reservation_id_2", booking(checkIn, checkOut), payment)
+		assert.EqualError(t, err, "reservation not available")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown venue is not booked", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs("venue_id_1").WillReturnRows(sqlmock.NewRows([]string{"venue_id"}))
+		mock.ExpectRollback()
+
+		_, _, err := rq.book("reservation_id_2", booking(day.Add(10*time.Hour), day.Add(11*time.Hour)), payment)
+		assert.EqualError(t, err, "venue not found")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReservationStatus(t *testing.T) {
+	update := regexp.QuoteMeta("UPDATE `payments` SET `status`=? WHERE payment_id = ? AND status IN (?)")
+	current := regexp.QuoteMeta("SELECT status FROM `payments` WHERE payment_id = ? AND deleted_at IS NULL")
+	expired := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "expire"}
+
+	t.Run("repeated update finds the status already saved", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("expire", "payment_id_1", "pending").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectQuery(current).WithArgs("payment_id_1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("expire"))
+
+		_, err := rq.ReservationStatus(expired)
+		assert.EqualError(t, err, "payment is already expire")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update cannot move the status backwards", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("expire", "payment_id_1", "pending").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectQuery(current).WithArgs("payment_id_1").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("success"))
+
+		_, err := rq.ReservationStatus(expired)
+		assert.EqualError(t, err, "payment status cannot change from success to expire")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error is not taken for a missing payment", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("expire", "payment_id_1", "pending").WillReturnError(errors.New("lock wait timeout exceeded"))
+		mock.ExpectRollback()
+
+		_, err := rq.ReservationStatus(expired)
+		assert.EqualError(t, err, "internal server error")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown payment", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("expire", "payment_id_1", "pending").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectQuery(current).WithArgs("payment_id_1").WillReturnRows(sqlmock.NewRows([]string{"status"}))
+
+		_, err := rq.ReservationStatus(expired)
+		assert.EqualError(t, err, "payment not found")
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpirePendingPayments(t *testing.T) {
+	locked := regexp.QuoteMeta("SELECT * FROM `payments` WHERE (payment_id IN (?,?) AND status = ?) AND `payments`.`deleted_at` IS NULL FOR UPDATE")
+	expire := regexp.QuoteMeta("UPDATE `payments` SET `status`=?,`status_reason`=?,`updated_at`=? WHERE payment_id IN (?) AND `payments`.`deleted_at` IS NULL")
+
+	t.Run("payments settled in the meantime are left as they are", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+		mock.ExpectBegin()
+		// payment_id_2 was settled after its charge was voided, only payment_id_1 is still pending
+		mock.ExpectQuery(locked).WithArgs("payment_id_1", "payment_id_2", "pending").
+			WillReturnRows(sqlmock.NewRows([]string{"payment_id", "status"}).AddRow("payment_id_1", "pending"))
+		mock.ExpectExec(expire).WithArgs("expire", "charge expired", sqlmock.AnyArg(), "payment_id_1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		expired, err := rq.ExpirePendingPayments([]string{"payment_id_1", "payment_id_2"}, "charge expired")
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Len(t, expired, 1)
+		assert.Equal(t, "payment_id_1", expired[0].PaymentID)
+		assert.Equal(t, "expire", expired[0].Status)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing to expire", func(t *testing.T) {
+		rq, mock := newTestQuery(t)
+
+		expired, err := rq.ExpirePendingPayments([]string{}, "charge expired")
+		assert.Nil(t, err)
+		assert.Empty(t, expired)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
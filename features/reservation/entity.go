@@ -13,6 +13,10 @@ type ReservationCore struct {
 	CheckInDate   time.Time `validate:"required"`
 	CheckOutDate  time.Time `validate:"required"`
 	Duration      float64
+	Subtotal      float64
+	SeriesID      string
+	PaymentID     string
+	HoldID        string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	DeletedAt     time.Time
@@ -27,6 +31,18 @@ type PaymentCore struct {
 	GrandTotal    string
 	ServiceFee    float64
 	Status        string
+	StatusReason  string
+	Purpose       string
+	ReferenceID   string
+	VoucherCode   string
+	VoucherID     string
+	Discount      float64
+	// Credits is how many prepaid hours paid for the booking
+	Credits       float64
+	Items         []PaymentItemCore
+	SplitMode     string
+	Shares        []ShareCore
+	ExpiredAt     time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	ReservationID string
@@ -35,19 +51,265 @@ type PaymentCore struct {
 }
 
 type VenueCore struct {
+	VenueID     string
+	OwnerID     string
+	CategoryID  string
+	Category    string
+	SlotMinutes int
+	Name        string
+	Description string
+	Username    string
+	ServiceTime string
+	Location    string
+	Distance    uint
+	Price       float64
+	Longitude   float64
+	Latitude    float64
+	// RescheduleCutoff is in hours, zero falls back to the default cutoff
+	RescheduleCutoff uint
+	OpeningHours     []OpeningHourCore
+	Reservations     []ReservationCore
+}
+
+// VoucherCore is a promo code as seen at checkout, see the voucher feature for its fields.
+type VoucherCore struct {
+	VoucherID     string
+	Code          string
+	DiscountType  string
+	DiscountValue float64
+	MaxDiscount   float64
+	MinSpend      float64
+	ValidFrom     time.Time
+	ValidUntil    time.Time
+	VenueID       string
+	CategoryID    string
+}
+
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// PricingRuleCore overrides the venue price per hour, see the venue feature for its fields.
+type PricingRuleCore struct {
+	Name        string
+	Weekdays    []int
+	StartTime   string
+	EndTime     string
+	StartDate   time.Time
+	EndDate     time.Time
+	MinDuration float64
+	Price       float64
+	Priority    int
+}
+
+type QuoteCore struct {
 	VenueID      string
-	OwnerID      string
-	Category     string
-	Name         string
-	Description  string
-	Username     string
-	ServiceTime  string
-	Location     string
-	Distance     uint
-	Price        float64
-	Longitude    float64
-	Latitude     float64
-	Reservations []ReservationCore
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+	Duration     float64
+	Items        []QuoteItemCore
+	Total        float64
+}
+
+// QuoteItemCore is a stretch of a booking charged at a single hourly rate
+type QuoteItemCore struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Rule      string
+	Rate      float64
+	Hours     float64
+	Amount    float64
+}
+
+const (
+	RecurrenceWeekly   = "weekly"
+	RecurrenceBiweekly = "biweekly"
+)
+
+const (
+	PaymentForReservation = "reservation"
+	PaymentForReschedule  = "reschedule"
+	PaymentForCredits     = "credits"
+	PaymentForShare       = "share"
+)
+
+// PaymentTypeCredits settles a booking from the prepaid hours of the user at the venue instead of Midtrans
+const PaymentTypeCredits = "credits"
+
+// PaymentMethodSplit marks a booking paid by its players share by share, it is never charged itself
+const PaymentMethodSplit = "split"
+
+const (
+	SplitEqual  = "equal"
+	SplitCustom = "custom"
+)
+
+// ShareCore is the part of a split payment one player pays with a charge of their own, made
+// under the share ID. The split payment settles once every share has, and falls through as
+// soon as any share is cancelled or expires.
+type ShareCore struct {
+	ShareID     string
+	PaymentID   string
+	UserID      string
+	Fullname    string
+	Email       string
+	Phone       string
+	Amount      int64
+	Charge      PaymentCore
+	Reservation ReservationCore
+	CreatedAt   time.Time
+}
+
+// Payment statuses we keep, a Midtrans settlement is kept as success
+const (
+	PaymentPending = "pending"
+	PaymentSuccess = "success"
+	PaymentCancel  = "cancel"
+	PaymentExpire  = "expire"
+)
+
+// paymentTransitions lists the statuses a payment may move on to, cancelled and expired
+// payments are final
+var paymentTransitions = map[string][]string{
+	PaymentPending: {PaymentSuccess, PaymentCancel, PaymentExpire},
+	PaymentSuccess: {PaymentCancel},
+}
+
+// CanTransition tells whether a payment may move from one status to the other.
+func CanTransition(from string, to string) bool {
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionSources lists the statuses a payment may move to status from.
+func TransitionSources(status string) []string {
+	sources := []string{}
+	for from := range paymentTransitions {
+		if CanTransition(from, status) {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+type RescheduleCore struct {
+	Reservation     ReservationCore
+	Payment         PaymentCore
+	PriceDifference float64
+}
+
+// CancellationTierCore is one step of a venue's cancellation policy: bookings cancelled at
+// least HoursBefore hours before check-in get RefundPercent of their subtotal back, the price
+// after any voucher discount. Fees and tax are not refunded.
+type CancellationTierCore struct {
+	HoursBefore   int
+	RefundPercent int
+}
+
+// PaymentItemCore is a line of the invoice of a payment in whole rupiah, discounts are negative.
+type PaymentItemCore struct {
+	Kind        string
+	Description string
+	Amount      int64
+}
+
+// CustomerCore is who a payment is billed to
+type CustomerCore struct {
+	UserID   string
+	Fullname string
+	Email    string
+}
+
+type RefundCore struct {
+	RefundID  string
+	PaymentID string
+	OrderID   string
+	Amount    float64
+	// Credits is how many prepaid hours went back to the balance instead of money
+	Credits   float64
+	Reason    string
+	CreatedAt time.Time
+}
+
+// DiscrepancyCore is a pending payment the gateway had moved on from, found by reconciliation.
+// Resolved tells whether the gateway status could be applied to our payment.
+type DiscrepancyCore struct {
+	DiscrepancyID string
+	PaymentID     string
+	OrderID       string
+	LocalStatus   string
+	GatewayStatus string
+	LocalAmount   string
+	GatewayAmount string
+	Resolved      bool
+	Detail        string
+	CreatedAt     time.Time
+}
+
+const (
+	CancelOccurrence = "occurrence"
+	CancelSeries     = "series"
+)
+
+type RecurrenceCore struct {
+	Frequency string
+	Count     int
+	Until     time.Time
+}
+
+type ConflictCore struct {
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+	Reason       string
+}
+
+type HoldCore struct {
+	HoldID       string
+	VenueID      string
+	UserID       string
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+	ExpiresAt    time.Time
+}
+
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistOffered   = "offered"
+	WaitlistClaimed   = "claimed"
+	WaitlistExpired   = "expired"
+	WaitlistCancelled = "cancelled"
+)
+
+type WaitlistCore struct {
+	WaitlistID     string
+	UserID         string
+	VenueID        string
+	CheckInDate    time.Time
+	CheckOutDate   time.Time
+	Status         string
+	HoldID         string
+	OfferExpiresAt time.Time
+	CreatedAt      time.Time
+	Fullname       string
+	Email          string
+	VenueName      string
+}
+
+type OpeningHourCore struct {
+	Weekday   int
+	OpenTime  string
+	CloseTime string
+}
+
+type ClosureCore struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Reason    string
 }
 
 type AvailabilityCore struct {
@@ -58,11 +320,39 @@ type AvailabilityCore struct {
 	ReservationID string
 	CheckInDate   time.Time
 	CheckOutDate  time.Time
+	Status        string
 }
 
+const (
+	SlotFree    = "free"
+	SlotPending = "pending"
+	SlotBooked  = "booked"
+	SlotClosed  = "closed"
+	SlotHeld    = "held"
+)
+
+type SlotCore struct {
+	Start  time.Time
+	End    time.Time
+	Status string
+}
+
+type DaySlotsCore struct {
+	Date  time.Time
+	Slots []SlotCore
+}
+
+// Owner charts count sales per venue or per venue category.
+const (
+	ChartByVenue    = "venue"
+	ChartByCategory = "category"
+)
+
 type MyReservationCore struct {
 	VenueID       string
 	VenueName     string
+	CategoryID    string
+	Category      string
 	Location      string
 	ReservationID string
 	CheckInDate   time.Time
@@ -79,30 +369,94 @@ type MyReservationCore struct {
 
 type ReservationHandler interface {
 	MakeReservation() echo.HandlerFunc
+	MakeRecurringReservation() echo.HandlerFunc
+	CancelReservation() echo.HandlerFunc
+	RescheduleReservation() echo.HandlerFunc
+	QuoteReservation() echo.HandlerFunc
 	ReservationStatus() echo.HandlerFunc
 	MyReservation() echo.HandlerFunc
 	DetailTransaction() echo.HandlerFunc
+	DownloadInvoice() echo.HandlerFunc
 	CheckAvailability() echo.HandlerFunc
+	AvailabilitySlots() echo.HandlerFunc
+	CreateHold() echo.HandlerFunc
+	JoinWaitlist() echo.HandlerFunc
+	MyWaitlist() echo.HandlerFunc
+	LeaveWaitlist() echo.HandlerFunc
 	MyVenueCharts() echo.HandlerFunc
+	PaymentDiscrepancies() echo.HandlerFunc
+	MyShares() echo.HandlerFunc
 }
 
 type ReservationService interface {
 	MakeReservation(userId string, r ReservationCore, p PaymentCore) (ReservationCore, PaymentCore, error)
+	MakeRecurringReservation(userId string, r ReservationCore, rule RecurrenceCore, p PaymentCore) ([]ReservationCore, PaymentCore, []ConflictCore, error)
+	CancelReservation(userId string, reservationId string, scope string) ([]ReservationCore, RefundCore, error)
+	RescheduleReservation(userId string, reservationId string, checkInDate time.Time, checkOutDate time.Time, p PaymentCore) (RescheduleCore, error)
+	QuoteReservation(venueId string, checkInDate time.Time, checkOutDate time.Time) (QuoteCore, error)
 	ReservationStatus(request PaymentCore) (PaymentCore, error)
+	PaymentNotification(body []byte) (PaymentCore, error)
 	MyReservation(userId string) ([]MyReservationCore, error)
 	DetailTransaction(userId string, paymentId string) (PaymentCore, error)
+	InvoicePDF(userId string, paymentId string) ([]byte, error)
 	CheckAvailability(venueId string) ([]AvailabilityCore, error)
-	MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time) ([]MyReservationCore, error)
+	AvailabilitySlots(venueId string, startDate time.Time, endDate time.Time, slotLength time.Duration) ([]DaySlotsCore, error)
+	CreateHold(userId string, request HoldCore) (HoldCore, error)
+	JoinWaitlist(userId string, request WaitlistCore) (WaitlistCore, error)
+	MyWaitlist(userId string) ([]WaitlistCore, error)
+	LeaveWaitlist(userId string, waitlistId string) error
+	ExpireWaitlistOffers() ([]WaitlistCore, error)
+	ExpirePendingPayments() ([]PaymentCore, error)
+	ReconcilePayments() ([]DiscrepancyCore, error)
+	PaymentDiscrepancies(userId string, unresolvedOnly bool) ([]DiscrepancyCore, error)
+	MyShares(userId string) ([]ShareCore, error)
+	MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time, groupBy string) ([]MyReservationCore, error)
 }
 
 type ReservationData interface {
 	MakeReservation(userId string, r ReservationCore, p PaymentCore) (ReservationCore, PaymentCore, error)
+	MakeRecurringReservation(userId string, occurrences []ReservationCore, p PaymentCore) ([]ReservationCore, PaymentCore, error)
+	GetReservation(userId string, reservationId string) (ReservationCore, PaymentCore, error)
+	GetSeries(seriesId string) ([]ReservationCore, error)
+	CancelReservations(paymentId string, reservationIds []string, cancelPayment bool) error
+	RescheduleReservation(r ReservationCore, p PaymentCore) (ReservationCore, PaymentCore, error)
 	ReservationStatus(request PaymentCore) (PaymentCore, error)
+	GetCancellationPolicy(venueId string) ([]CancellationTierCore, error)
+	InsertRefund(request RefundCore) (RefundCore, error)
+	RefundCredits(userId string, venueId string, paymentId string, hours float64) error
 	PriceVenue(venueID string) (float64, error)
-	ReservationCheckOutDate(reservation_id string) (time.Time, error)
+	GetPricingRules(venueId string) ([]PricingRuleCore, error)
+	GetVoucher(code string) (VoucherCore, error)
 	MyReservation(userId string) ([]MyReservationCore, error)
 	DetailTransaction(userId string, paymentId string) (PaymentCore, error)
+	GetPayment(paymentId string) (PaymentCore, error)
+	GetPaymentCustomer(paymentId string) (CustomerCore, error)
 	CheckAvailability(venueId string) ([]AvailabilityCore, error)
+	CheckAvailabilityByTimeWindow(venueId string, start time.Time, end time.Time) ([]AvailabilityCore, error)
+	GetVenue(venueId string) (VenueCore, error)
+	GetClosures(venueId string, start time.Time, end time.Time) ([]ClosureCore, error)
 	GetReservationsByTimeSlot(venueID string, checkInDate, checkOutDate time.Time) ([]ReservationCore, error)
-	MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time) ([]MyReservationCore, error)
+	InsertHold(request HoldCore, ttl time.Duration) (HoldCore, error)
+	GetHolds(venueId string) ([]HoldCore, error)
+	DeleteHold(venueId string, holdId string) error
+	GetPaymentReservations(paymentId string) ([]ReservationCore, error)
+	InsertWaitlist(request WaitlistCore) (WaitlistCore, error)
+	GetWaitlist(venueId string, start time.Time, end time.Time) ([]WaitlistCore, error)
+	GetWaitlistEntry(userId string, waitlistId string) (WaitlistCore, error)
+	MyWaitlist(userId string) ([]WaitlistCore, error)
+	UpdateWaitlist(request WaitlistCore) error
+	ClaimWaitlistOffer(userId string, holdId string) error
+	ExpireWaitlistOffers(before time.Time) ([]WaitlistCore, error)
+	GetOverduePayments(before time.Time) ([]PaymentCore, error)
+	ExpirePendingPayments(paymentIds []string, reason string) ([]PaymentCore, error)
+	GetStalePayments(before time.Time, limit int) ([]PaymentCore, error)
+	InsertDiscrepancy(request DiscrepancyCore) (DiscrepancyCore, error)
+	GetDiscrepancies(unresolvedOnly bool) ([]DiscrepancyCore, error)
+	GetUserRole(userId string) (string, error)
+	GetUserByContact(email string, phone string) (CustomerCore, error)
+	GetShares(paymentId string) ([]ShareCore, error)
+	GetUserShares(userId string) ([]ShareCore, error)
+	ConfirmSplitPayment(paymentId string) (PaymentCore, bool, error)
+	FailSplitPayment(paymentId string, reason string) (PaymentCore, []ShareCore, error)
+	MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time, groupBy string) ([]MyReservationCore, error)
 }
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -60,6 +63,307 @@ func (rh *reservationHandler) CheckAvailability() echo.HandlerFunc {
 	}
 }
 
+// AvailabilitySlots implements reservation.ReservationHandler.
+func (rh *reservationHandler) AvailabilitySlots() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		venueID := c.Param("venue_id")
+		if venueID == "" {
+			log.Error("empty venue_id parameter")
+			return helper.NotFoundError(c, "The requested resource was not found")
+		}
+
+		startDate := time.Now()
+		if startDateStr := c.QueryParam("start_date"); startDateStr != "" {
+			parsed, err := time.Parse("2006-01-02", startDateStr)
+			if err != nil {
+				log.Error("failed to parse start_date")
+				return helper.BadRequestError(c, "Invalid value for start_date")
+			}
+			startDate = parsed
+		}
+
+		endDate := startDate
+		if endDateStr := c.QueryParam("end_date"); endDateStr != "" {
+			parsed, err := time.Parse("2006-01-02", endDateStr)
+			if err != nil {
+				log.Error("failed to parse end_date")
+				return helper.BadRequestError(c, "Invalid value for end_date")
+			}
+			endDate = parsed
+		}
+
+		// Left at zero, the service falls back to the slot length of the venue category
+		var slotLength time.Duration
+		if slotLengthStr := c.QueryParam("slot_length"); slotLengthStr != "" {
+			minutes, err := strconv.Atoi(slotLengthStr)
+			if err != nil || minutes <= 0 {
+				log.Error("failed to parse slot_length")
+				return helper.BadRequestError(c, "Invalid value for slot_length")
+			}
+			slotLength = time.Duration(minutes) * time.Minute
+		}
+
+		days, err := rh.service.AvailabilitySlots(venueID, startDate, endDate, slotLength)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error("venue not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "slot length"),
+				strings.Contains(err.Error(), "date range"),
+				strings.Contains(err.Error(), "end_date"),
+				strings.Contains(err.Error(), "timewindow"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		result := make([]daySlotsResponse, len(days))
+		for i, d := range days {
+			result[i] = daySlots(d)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// CreateHold implements reservation.ReservationHandler.
+func (rh *reservationHandler) CreateHold() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := createHoldRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := rh.service.CreateHold(userId, req.requestHold(c.Param("venue_id")))
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "venue not found"):
+				log.Error("venue not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "empty"),
+				strings.Contains(err.Error(), "timewindow"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request")
+			case strings.Contains(err.Error(), "outside opening hours"),
+				strings.Contains(err.Error(), "venue is closed"),
+				strings.Contains(err.Error(), "reservation not available"),
+				strings.Contains(err.Error(), "held by another user"):
+				log.Error(err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully operation", hold(result), nil))
+	}
+}
+
+// RescheduleReservation implements reservation.ReservationHandler.
+func (rh *reservationHandler) RescheduleReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := rescheduleRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		checkInDate, errIn := time.Parse("2006-01-02 15:04:05", req.CheckInDate)
+		checkOutDate, errOut := time.Parse("2006-01-02 15:04:05", req.CheckOutDate)
+		if errIn != nil || errOut != nil {
+			log.Error("error while parsing string to datetime format")
+			return helper.BadRequestError(c, "Bad request, invalid check_in_date or check_out_date")
+		}
+
+		payment := reservation.PaymentCore{PaymentType: req.PaymentType}
+		result, err := rh.service.RescheduleReservation(userId, c.Param("reservation_id"), checkInDate, checkOutDate, payment)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "empty"),
+				strings.Contains(err.Error(), "timewindow"),
+				strings.Contains(err.Error(), "check_out_date must be after"),
+				strings.Contains(err.Error(), "no longer active"),
+				strings.Contains(err.Error(), "cannot be rescheduled"),
+				strings.Contains(err.Error(), "outside opening hours"),
+				strings.Contains(err.Error(), "venue is closed"),
+				strings.Contains(err.Error(), "reservation not available"),
+				strings.Contains(err.Error(), "held by another user"),
+				strings.Contains(err.Error(), "paid with credits"),
+				strings.Contains(err.Error(), "must be paid"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully rescheduled reservation", reschedule(result), nil))
+	}
+}
+
+// QuoteReservation implements reservation.ReservationHandler.
+func (rh *reservationHandler) QuoteReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := quoteRequest{}
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		checkInDate, errIn := time.Parse("2006-01-02 15:04:05", req.CheckInDate)
+		checkOutDate, errOut := time.Parse("2006-01-02 15:04:05", req.CheckOutDate)
+		if errIn != nil || errOut != nil {
+			log.Error("error while parsing string to datetime format")
+			return helper.BadRequestError(c, "Bad request, invalid check_in_date or check_out_date")
+		}
+
+		result, err := rh.service.QuoteReservation(c.Param("venue_id"), checkInDate, checkOutDate)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "empty"),
+				strings.Contains(err.Error(), "check_out_date must be after"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", quote(result), nil))
+	}
+}
+
+// JoinWaitlist implements reservation.ReservationHandler.
+func (rh *reservationHandler) JoinWaitlist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := joinWaitlistRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		result, err := rh.service.JoinWaitlist(userId, req.requestWaitlist(c.Param("venue_id")))
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "venue not found"):
+				log.Error("venue not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "empty"),
+				strings.Contains(err.Error(), "timewindow"),
+				strings.Contains(err.Error(), "unregistered user"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request")
+			case strings.Contains(err.Error(), "outside opening hours"),
+				strings.Contains(err.Error(), "venue is closed"),
+				strings.Contains(err.Error(), "reservation is available"),
+				strings.Contains(err.Error(), "already on the waitlist"),
+				strings.Contains(err.Error(), "hold not found"):
+				log.Error(err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully joined the waitlist", waitlist(result), nil))
+	}
+}
+
+// MyWaitlist implements reservation.ReservationHandler.
+func (rh *reservationHandler) MyWaitlist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		entries, err := rh.service.MyWaitlist(userId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]waitlistResponse, len(entries))
+		for i, w := range entries {
+			result[i] = waitlist(w)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully operation", result, nil))
+	}
+}
+
+// LeaveWaitlist implements reservation.ReservationHandler.
+func (rh *reservationHandler) LeaveWaitlist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		err := rh.service.LeaveWaitlist(userId, c.Param("waitlist_id"))
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error("waitlist entry not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "no longer active"):
+				log.Error(err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully left the waitlist", nil, nil))
+	}
+}
+
 // MakeReservation implements reservation.ReservationHandler.
 func (rh *reservationHandler) MakeReservation() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -85,6 +389,13 @@ func (rh *reservationHandler) MakeReservation() echo.HandlerFunc {
 		reservation, payment, err := rh.service.MakeReservation(userId, reservationData, paymentData)
 		if err != nil {
 			switch {
+			case strings.Contains(err.Error(), "voucher"),
+				strings.Contains(err.Error(), "credits"),
+				strings.Contains(err.Error(), "split"),
+				strings.Contains(err.Error(), "participant"),
+				strings.Contains(err.Error(), "share"):
+				log.Error(err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
 			case strings.Contains(err.Error(), "empty"):
 				log.Error("bad request, request cannot be empty")
 				return helper.BadRequestError(c, "Bad request")
@@ -100,6 +411,15 @@ func (rh *reservationHandler) MakeReservation() echo.HandlerFunc {
 			case strings.Contains(err.Error(), "reservation not available"):
 				log.Error("reservation not available for the specified venue and timewindow")
 				return helper.BadRequestError(c, "Bad request, reservation not available")
+			case strings.Contains(err.Error(), "outside opening hours"),
+				strings.Contains(err.Error(), "venue is closed"),
+				strings.Contains(err.Error(), "held by another user"),
+				strings.Contains(err.Error(), "hold not found"):
+				log.Error(err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			case strings.Contains(err.Error(), "venue not found"):
+				log.Error("venue not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
 			default:
 				log.Error("internal server error")
 				return helper.InternalServerError(c, "Internal server error")
@@ -112,39 +432,133 @@ func (rh *reservationHandler) MakeReservation() echo.HandlerFunc {
 	}
 }
 
-// ReservationStatus implements reservation.ReservationHandler.
-func (rh *reservationHandler) ReservationStatus() echo.HandlerFunc {
+// MakeRecurringReservation implements reservation.ReservationHandler.
+func (rh *reservationHandler) MakeRecurringReservation() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		if c.Request().UserAgent() != "Veritrans" {
-			log.Error("invalid client's User-Agent")
-			return helper.UnauthorizedError(c, "Invalid client's User-Agent")
-		}
+		req := struct {
+			Reservation makeReservationRequest `json:"reservation"`
+			Recurrence  recurrenceRequest      `json:"recurrence"`
+			Payment     createPaymentRequest   `json:"payment"`
+		}{}
 
-		midtransResponse := MidtransCallback{}
-		log.Sugar().Info(midtransResponse)
-		// https + verify signature key between midtrans and system, to enhance security issues
-		if !validSignatureKey(midtransResponse) {
-			log.Error("invalid signature key")
-			return helper.UnauthorizedError(c, "Invalid Signature Key")
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
 		}
 
-		errBind := c.Bind(&midtransResponse)
+		errBind := c.Bind(&req)
 		if errBind != nil {
-			log.Sugar().Errorf("error on binding notification input", errBind)
+			log.Error("error on bind request")
 			return helper.BadRequestError(c, "Bad request")
 		}
 
-		_, err := rh.service.ReservationStatus(reservationStatusRequest(midtransResponse))
+		rule, err := req.Recurrence.requestRecurrence()
 		if err != nil {
-			if strings.Contains(err.Error(), "not found") {
-				log.Error("payment not found")
+			return helper.BadRequestError(c, "Bad request, invalid until date")
+		}
+
+		reservationData := requestReservation(req.Reservation)
+		paymentData := req.Payment.requestPayment()
+		series, payment, conflictList, err := rh.service.MakeRecurringReservation(userId, reservationData, rule, paymentData)
+		if err != nil {
+			switch {
+			case len(conflictList) > 0:
+				log.Error("some occurrences are not available")
+				return c.JSON(http.StatusBadRequest, helper.ResponseFormat(http.StatusBadRequest, "Bad request, some occurrences are not available", conflicts(conflictList), nil))
+			case strings.Contains(err.Error(), "venue not found"):
+				log.Error("venue not found")
 				return helper.NotFoundError(c, "The requested resource was not found")
-			} else if strings.Contains(err.Error(), "no payment record has been updated") {
-				log.Error("no payment record has been updated")
+			case strings.Contains(err.Error(), "empty"),
+				strings.Contains(err.Error(), "unregistered user"):
+				log.Error("bad request, " + err.Error())
 				return helper.BadRequestError(c, "Bad request")
-			} else if strings.Contains(err.Error(), "refund cannot be processed at least 1 hour away") {
-				log.Error("refund cannot be processed at least 1 hour away")
+			case strings.Contains(err.Error(), "recurrence"),
+				strings.Contains(err.Error(), "frequency"),
+				strings.Contains(err.Error(), "until"),
+				strings.Contains(err.Error(), "check_out_date"),
+				strings.Contains(err.Error(), "overlap"),
+				strings.Contains(err.Error(), "voucher"),
+				strings.Contains(err.Error(), "credits"),
+				strings.Contains(err.Error(), "split"),
+				strings.Contains(err.Error(), "reservation not available"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully operation", recurringReservation(series, payment), nil))
+	}
+}
+
+// CancelReservation implements reservation.ReservationHandler.
+func (rh *reservationHandler) CancelReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := cancelReservationRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		cancelled, refund, err := rh.service.CancelReservation(userId, c.Param("reservation_id"), req.Scope)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				log.Error("reservation not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "scope"),
+				strings.Contains(err.Error(), "no longer active"),
+				strings.Contains(err.Error(), "cannot be cancelled"),
+				strings.Contains(err.Error(), "as a whole"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully cancelled reservation", cancellation(cancelled, refund), nil))
+	}
+}
+
+// ReservationStatus implements reservation.ReservationHandler.
+func (rh *reservationHandler) ReservationStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, errRead := io.ReadAll(c.Request().Body)
+		if errRead != nil {
+			log.Sugar().Errorf("error on reading notification input: %v", errRead)
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		// The payment provider verifies the notification was sent by the gateway
+		_, err := rh.service.PaymentNotification(body)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid signature key") {
+				log.Error("invalid signature key")
+				return helper.UnauthorizedError(c, "Invalid Signature Key")
+			} else if strings.Contains(err.Error(), "invalid notification") {
+				log.Error("invalid notification")
 				return helper.BadRequestError(c, "Bad request")
+			} else if strings.Contains(err.Error(), "not found") {
+				log.Error("payment not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			} else if strings.Contains(err.Error(), "gross amount does not match the payment") {
+				log.Error("gross amount does not match the payment")
+				return helper.BadRequestError(c, "Gross amount does not match the payment")
+			} else if strings.Contains(err.Error(), "payment status cannot change") {
+				log.Error(err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
 			}
 			log.Error("internal server error")
 			return helper.InternalServerError(c, "Internal server error")
@@ -213,11 +627,41 @@ func (rh *reservationHandler) DetailTransaction() echo.HandlerFunc {
 			}
 		}
 
-		result, _ := reservationHistory(payment)
+		result := reservationHistory(payment)
 		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
 	}
 }
 
+// DownloadInvoice implements reservation.ReservationHandler.
+func (rh *reservationHandler) DownloadInvoice() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		paymentId := c.Param("payment_id")
+		if paymentId == "" {
+			log.Error("empty paymentId parameter")
+			return helper.NotFoundError(c, "The requested resource was not found")
+		}
+		pdf, err := rh.service.InvoicePDF(userId, paymentId)
+		if err != nil {
+			if strings.Contains(err.Error(), "payment not found") {
+				log.Error("payment not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			} else {
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "receipt-"+paymentId+".pdf"))
+		return c.Blob(http.StatusOK, "application/pdf", pdf)
+	}
+}
+
 // MyVenueCharts implements reservation.ReservationHandler.
 func (rh *reservationHandler) MyVenueCharts() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -242,11 +686,14 @@ func (rh *reservationHandler) MyVenueCharts() echo.HandlerFunc {
 			return helper.BadRequestError(c, "Invalid value for check_out_date")
 		}
 		log.Sugar().Info(checkOutDate)
-		res, err := rh.service.MyVenueCharts(userId, keyword, checkInDate, checkOutDate)
+		res, err := rh.service.MyVenueCharts(userId, keyword, checkInDate, checkOutDate, c.QueryParam("group_by"))
 		if err != nil {
 			if strings.Contains(err.Error(), "list charts record not found") {
 				log.Error("list charts record not found")
 				return helper.NotFoundError(c, "The requested resource was not found")
+			} else if strings.Contains(err.Error(), "invalid group_by") {
+				log.Error("bad request, invalid group_by")
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
 			} else {
 				log.Error("internal server error")
 				return helper.InternalServerError(c, "Internal server error")
@@ -266,3 +713,56 @@ func (rh *reservationHandler) MyVenueCharts() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
 	}
 }
+
+// PaymentDiscrepancies implements reservation.ReservationHandler.
+func (rh *reservationHandler) PaymentDiscrepancies() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		status := c.QueryParam("status")
+		if status != "" && status != "unresolved" {
+			log.Error("invalid status filter")
+			return helper.BadRequestError(c, "Bad request, status can only be unresolved")
+		}
+
+		discrepancies, err := rh.service.PaymentDiscrepancies(userId, status == "unresolved")
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "only admins"):
+				log.Error(err.Error())
+				return helper.UnauthorizedError(c, "Only admins can view payment discrepancies")
+			case strings.Contains(err.Error(), "not found"):
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", discrepancyReport(discrepancies), nil))
+	}
+}
+
+// MyShares implements reservation.ReservationHandler.
+func (rh *reservationHandler) MyShares() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		shares, err := rh.service.MyShares(userId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", myShares(shares), nil))
+	}
+}
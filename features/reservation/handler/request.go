@@ -1,12 +1,9 @@
 package handler
 
 import (
-	"crypto/sha512"
-	"encoding/hex"
 	"time"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/features/reservation"
 )
 
@@ -14,10 +11,56 @@ type makeReservationRequest struct {
 	VenueID      string `json:"venue_id" form:"venue_id"`
 	CheckInDate  string `json:"check_in_date" form:"check_in_date" validate:"datetime"`
 	CheckOutDate string `json:"check_out_date" form:"check_out_date" validate:"datetime"`
+	HoldID       string `json:"hold_id" form:"hold_id"`
+}
+
+type recurrenceRequest struct {
+	Frequency string `json:"frequency" form:"frequency"`
+	Count     int    `json:"count" form:"count"`
+	Until     string `json:"until" form:"until"`
+}
+
+type cancelReservationRequest struct {
+	Scope string `json:"scope" form:"scope"`
+}
+
+type createHoldRequest struct {
+	CheckInDate  string `json:"check_in_date" form:"check_in_date" validate:"datetime"`
+	CheckOutDate string `json:"check_out_date" form:"check_out_date" validate:"datetime"`
+}
+
+type rescheduleRequest struct {
+	CheckInDate  string `json:"check_in_date" form:"check_in_date" validate:"datetime"`
+	CheckOutDate string `json:"check_out_date" form:"check_out_date" validate:"datetime"`
+	PaymentType  string `json:"payment_type" form:"payment_type"`
+}
+
+type quoteRequest struct {
+	CheckInDate  string `json:"check_in_date" form:"check_in_date" validate:"datetime"`
+	CheckOutDate string `json:"check_out_date" form:"check_out_date" validate:"datetime"`
+}
+
+type joinWaitlistRequest struct {
+	CheckInDate  string `json:"check_in_date" form:"check_in_date" validate:"datetime"`
+	CheckOutDate string `json:"check_out_date" form:"check_out_date" validate:"datetime"`
 }
 
 type createPaymentRequest struct {
-	PaymentType string `json:"payment_type"  form:"payment_type"`
+	PaymentType string        `json:"payment_type"  form:"payment_type"`
+	VoucherCode string        `json:"voucher_code" form:"voucher_code"`
+	Split       *splitRequest `json:"split" form:"split"`
+}
+
+// splitRequest shares the payment with other players, equally or by the amount each one pays
+type splitRequest struct {
+	Mode         string               `json:"mode"`
+	Participants []participantRequest `json:"participants"`
+}
+
+type participantRequest struct {
+	Email  string `json:"email"`
+	Phone  string `json:"phone"`
+	Amount int64  `json:"amount"`
 }
 
 type editReservationRequest struct {
@@ -25,28 +68,6 @@ type editReservationRequest struct {
 	CheckOutDate *string `json:"check_out_date" form:"check_out_date" validate:"datetime"`
 }
 
-type MidtransCallback struct {
-	TransactionTime     string `json:"transaction_time"`
-	TransactionStatus   string `json:"transaction_status"`
-	TransactionID       string `json:"transaction_id"`
-	StatusMessage       string `json:"status_message"`
-	StatusCode          string `json:"status_code"`
-	SignatureKey        string `json:"signature_key"` // Hash SHA512
-	PaymentType         string `json:"payment_type"`
-	OrderID             string `json:"order_id"`
-	MerchantID          string `json:"merchant_id"`
-	MaskedCard          string `json:"masked_card"`
-	GrossAmount         string `json:"gross_amount"`
-	FraudStatus         string `json:"fraud_status"`
-	ECI                 string `json:"eci"`
-	Currency            string `json:"currency"`
-	ChannelResponseMsg  string `json:"channel_response_message"`
-	ChannelResponseCode string `json:"channel_response_code"`
-	CardType            string `json:"card_type"`
-	Bank                string `json:"bank"`
-	ApprovalCode        string `json:"approval_code"`
-}
-
 func customDateTimeFormatValidator(fl validator.FieldLevel) bool {
 	dateStr := fl.Field().String()
 	_, err := time.Parse("2006-01-02 15:04:05", dateStr)
@@ -66,6 +87,7 @@ func requestReservation(data interface{}) reservation.ReservationCore {
 	switch v := data.(type) {
 	case makeReservationRequest:
 		result.VenueID = v.VenueID
+		result.HoldID = v.HoldID
 		checkInDate, err := time.Parse("2006-01-02 15:04:05", v.CheckInDate)
 		if err != nil {
 			log.Error("error while parsing string to time format")
@@ -134,27 +156,67 @@ func reqReservation(data interface{}) reservation.MyReservationCore {
 	return result
 }
 
-func (p createPaymentRequest) requestPayment() reservation.PaymentCore {
-	return reservation.PaymentCore{
-		PaymentType: p.PaymentType,
+func (rr recurrenceRequest) requestRecurrence() (reservation.RecurrenceCore, error) {
+	result := reservation.RecurrenceCore{
+		Frequency: rr.Frequency,
+		Count:     rr.Count,
 	}
+	if rr.Until != "" {
+		until, err := time.Parse("2006-01-02", rr.Until)
+		if err != nil {
+			log.Error("error while parsing string to date format")
+			return reservation.RecurrenceCore{}, err
+		}
+		result.Until = until
+	}
+
+	return result, nil
+}
+
+func (h createHoldRequest) requestHold(venueId string) reservation.HoldCore {
+	result := reservation.HoldCore{VenueID: venueId}
+	checkInDate, err := time.Parse("2006-01-02 15:04:05", h.CheckInDate)
+	if err == nil {
+		result.CheckInDate = checkInDate
+	}
+	checkOutDate, err := time.Parse("2006-01-02 15:04:05", h.CheckOutDate)
+	if err == nil {
+		result.CheckOutDate = checkOutDate
+	}
+
+	return result
 }
 
-func reservationStatusRequest(r MidtransCallback) reservation.PaymentCore {
-	res := reservation.PaymentCore{
-		PaymentID: r.TransactionID,
-		Reservation: reservation.ReservationCore{
-			ReservationID: r.OrderID,
-		},
-		PaymentMethod: r.PaymentType,
-		Status:        r.TransactionStatus,
+func (w joinWaitlistRequest) requestWaitlist(venueId string) reservation.WaitlistCore {
+	result := reservation.WaitlistCore{VenueID: venueId}
+	checkInDate, err := time.Parse("2006-01-02 15:04:05", w.CheckInDate)
+	if err == nil {
+		result.CheckInDate = checkInDate
+	}
+	checkOutDate, err := time.Parse("2006-01-02 15:04:05", w.CheckOutDate)
+	if err == nil {
+		result.CheckOutDate = checkOutDate
 	}
 
-	return res
+	return result
 }
 
-func validSignatureKey(response MidtransCallback) bool {
-	hash := sha512.Sum512([]byte(response.OrderID + response.StatusCode + response.GrossAmount + config.MIDTRANS_SERVERKEY))
-	hashStr := hex.EncodeToString(hash[:])
-	return string(hashStr) == response.SignatureKey
+func (p createPaymentRequest) requestPayment() reservation.PaymentCore {
+	result := reservation.PaymentCore{
+		PaymentType: p.PaymentType,
+		VoucherCode: p.VoucherCode,
+	}
+	if p.Split != nil {
+		result.SplitMode = p.Split.Mode
+		result.Shares = make([]reservation.ShareCore, len(p.Split.Participants))
+		for i, participant := range p.Split.Participants {
+			result.Shares[i] = reservation.ShareCore{
+				Email:  participant.Email,
+				Phone:  participant.Phone,
+				Amount: participant.Amount,
+			}
+		}
+	}
+
+	return result
 }
@@ -1,45 +1,123 @@
 package handler
 
 import (
-	"errors"
-	"strconv"
-
 	"github.com/playground-pro-project/playground-pro-api/features/reservation"
 	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	"github.com/playground-pro-project/playground-pro-api/utils/invoice"
 )
 
 type makeReservationResponse struct {
-	PaymentID     string `json:"payment_id"`
-	ReservationID string `json:"reservation_id"`
-	PaymentMethod string `json:"payment_method"`
-	PaymentType   string `json:"payment_type"`
-	PaymentCode   string `json:"payment_code"`
+	PaymentID     string           `json:"payment_id"`
+	ReservationID string           `json:"reservation_id"`
+	PaymentMethod string           `json:"payment_method"`
+	PaymentType   string           `json:"payment_type"`
+	PaymentCode   string           `json:"payment_code"`
+	GrandTotal    string           `json:"grand_total"`
+	Discount      float64          `json:"discount,omitempty"`
+	Credits       float64          `json:"credits,omitempty"`
+	ExpiredAt     helper.LocalTime `json:"expired_at"`
+	Shares        []shareResponse  `json:"shares,omitempty"`
+}
+
+// shareResponse is what one player of a split payment pays and how
+type shareResponse struct {
+	ShareID     string           `json:"share_id"`
+	PaymentID   string           `json:"payment_id"`
+	Fullname    string           `json:"fullname,omitempty"`
+	Email       string           `json:"email,omitempty"`
+	Amount      int64            `json:"amount"`
+	PaymentType string           `json:"payment_type"`
+	PaymentCode string           `json:"payment_code"`
+	Status      string           `json:"status"`
+	ExpiredAt   helper.LocalTime `json:"expired_at"`
+}
+
+// myShareResponse is a share the user was asked to pay, with the booking it pays for
+type myShareResponse struct {
+	shareResponse
+	ReservationID string           `json:"reservation_id"`
+	VenueID       string           `json:"venue_id"`
+	VenueName     string           `json:"venue_name"`
+	CheckInDate   helper.LocalTime `json:"check_in_date"`
+	CheckOutDate  helper.LocalTime `json:"check_out_date"`
+}
+
+func share(s reservation.ShareCore) shareResponse {
+	return shareResponse{
+		ShareID:     s.ShareID,
+		PaymentID:   s.PaymentID,
+		Fullname:    s.Fullname,
+		Email:       s.Email,
+		Amount:      s.Amount,
+		PaymentType: s.Charge.PaymentType,
+		PaymentCode: s.Charge.PaymentCode,
+		Status:      s.Charge.Status,
+		ExpiredAt:   helper.LocalTime(s.Charge.ExpiredAt),
+	}
+}
+
+func myShares(shares []reservation.ShareCore) []myShareResponse {
+	result := make([]myShareResponse, len(shares))
+	for i, s := range shares {
+		result[i] = myShareResponse{
+			shareResponse: share(s),
+			ReservationID: s.Reservation.ReservationID,
+			VenueID:       s.Reservation.VenueID,
+			VenueName:     s.Reservation.Venue.Name,
+			CheckInDate:   helper.LocalTime(s.Reservation.CheckInDate),
+			CheckOutDate:  helper.LocalTime(s.Reservation.CheckOutDate),
+		}
+	}
+
+	return result
 }
 
 func makeReservation(p reservation.PaymentCore) makeReservationResponse {
+	shares := []shareResponse{}
+	for _, s := range p.Shares {
+		shares = append(shares, share(s))
+	}
+
 	return makeReservationResponse{
 		PaymentID:     p.PaymentID,
 		ReservationID: p.Reservation.ReservationID,
 		PaymentMethod: p.PaymentMethod,
 		PaymentType:   p.PaymentType,
 		PaymentCode:   p.PaymentCode,
+		GrandTotal:    p.GrandTotal,
+		Discount:      p.Discount,
+		Credits:       p.Credits,
+		ExpiredAt:     helper.LocalTime(p.ExpiredAt),
+		Shares:        shares,
 	}
 }
 
 type reservationHistoryResponse struct {
-	Name          string           `json:"venue_name,omitempty"`
-	Location      string           `json:"location,omitempty"`
-	CheckInDate   helper.LocalTime `json:"check_in_date,omitempty"`
-	CheckOutDate  helper.LocalTime `json:"check_out_date,omitempty"`
-	Duration      float64          `json:"duration,omitempty"`
-	Price         float64          `json:"price,omitempty"`
-	PaymentID     string           `json:"payment_id,omitempty"`
-	GrandTotal    float64          `json:"total_price,omitempty"`
-	PaymentType   string           `json:"payment_type,omitempty"`
-	PaymentCode   string           `json:"payment_code,omitempty"`
-	Status        string           `json:"status,omitempty"`
-	ReservationID string           `json:"reservation_id,omitempty"`
-	VenueID       string           `json:"venue_id,omitempty"`
+	Name          string                `json:"venue_name,omitempty"`
+	Location      string                `json:"location,omitempty"`
+	CheckInDate   helper.LocalTime      `json:"check_in_date,omitempty"`
+	CheckOutDate  helper.LocalTime      `json:"check_out_date,omitempty"`
+	Duration      float64               `json:"duration,omitempty"`
+	Price         float64               `json:"price,omitempty"`
+	PaymentID     string                `json:"payment_id,omitempty"`
+	PaymentType   string                `json:"payment_type,omitempty"`
+	PaymentCode   string                `json:"payment_code,omitempty"`
+	Status        string                `json:"status,omitempty"`
+	StatusReason  string                `json:"status_reason,omitempty"`
+	ReservationID string                `json:"reservation_id,omitempty"`
+	VenueID       string                `json:"venue_id,omitempty"`
+	Subtotal      int64                 `json:"subtotal"`
+	Discount      int64                 `json:"discount"`
+	Fees          int64                 `json:"fees"`
+	Tax           int64                 `json:"tax"`
+	Total         int64                 `json:"total_price"`
+	Items         []invoiceItemResponse `json:"items"`
+}
+
+type invoiceItemResponse struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
 }
 
 type myReservationResponse struct {
@@ -73,8 +151,10 @@ func myReservation(r reservation.MyReservationCore) myReservationResponse {
 }
 
 type chartResponse struct {
-	VenueID     string `json:"venue_id"`
-	VenueName   string `json:"venue_name"`
+	VenueID     string `json:"venue_id,omitempty"`
+	VenueName   string `json:"venue_name,omitempty"`
+	CategoryID  string `json:"category_id,omitempty"`
+	Category    string `json:"category,omitempty"`
 	SalesVolume uint   `json:"sales_volume"`
 }
 
@@ -82,19 +162,20 @@ func charts(r reservation.MyReservationCore) chartResponse {
 	response := chartResponse{
 		VenueID:     r.VenueID,
 		VenueName:   r.VenueName,
+		CategoryID:  r.CategoryID,
+		Category:    r.Category,
 		SalesVolume: r.SalesVolume,
 	}
 
 	return response
 }
 
-func reservationHistory(payment reservation.PaymentCore) (reservationHistoryResponse, error) {
-	grandTotalStr := payment.GrandTotal
-	grandTotal, err := strconv.ParseFloat(grandTotalStr, 64)
-	if err != nil {
-		log.Sugar().Error("error parsing GrandTotal:", err)
-		return reservationHistoryResponse{}, errors.New("error on parsing string to float64")
+func reservationHistory(payment reservation.PaymentCore) reservationHistoryResponse {
+	items := make([]invoice.Item, len(payment.Items))
+	for i, item := range payment.Items {
+		items[i] = invoice.Item{Kind: item.Kind, Description: item.Description, Amount: item.Amount}
 	}
+	bill := invoice.Summarize(items)
 
 	response := reservationHistoryResponse{
 		Name:         payment.Reservation.Venue.Name,
@@ -103,13 +184,26 @@ func reservationHistory(payment reservation.PaymentCore) (reservationHistoryResp
 		CheckOutDate: helper.LocalTime(payment.Reservation.CheckOutDate),
 		Duration:     payment.Reservation.Duration,
 		Price:        payment.Reservation.Venue.Price,
-		GrandTotal:   grandTotal,
 		PaymentType:  payment.PaymentType,
 		PaymentCode:  payment.PaymentCode,
 		Status:       payment.Status,
+		StatusReason: payment.StatusReason,
+		Subtotal:     bill.Subtotal,
+		Discount:     bill.Discount,
+		Fees:         bill.Fees,
+		Tax:          bill.Tax,
+		Total:        bill.Total,
+		Items:        make([]invoiceItemResponse, len(items)),
+	}
+	for i, item := range items {
+		response.Items[i] = invoiceItemResponse{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
 	}
 
-	return response, nil
+	return response
 }
 
 type availability struct {
@@ -156,3 +250,284 @@ func Availability(reservations []reservation.AvailabilityCore) []venue {
 
 	return venues
 }
+
+type slotResponse struct {
+	Start  helper.LocalTime `json:"start"`
+	End    helper.LocalTime `json:"end"`
+	Status string           `json:"status"`
+}
+
+type daySlotsResponse struct {
+	Date  string         `json:"date"`
+	Slots []slotResponse `json:"slots"`
+}
+
+func daySlots(d reservation.DaySlotsCore) daySlotsResponse {
+	slots := make([]slotResponse, len(d.Slots))
+	for i, s := range d.Slots {
+		slots[i] = slotResponse{
+			Start:  helper.LocalTime(s.Start),
+			End:    helper.LocalTime(s.End),
+			Status: s.Status,
+		}
+	}
+
+	return daySlotsResponse{
+		Date:  d.Date.Format("2006-01-02"),
+		Slots: slots,
+	}
+}
+
+type holdResponse struct {
+	HoldID       string           `json:"hold_id"`
+	VenueID      string           `json:"venue_id"`
+	CheckInDate  helper.LocalTime `json:"check_in_date"`
+	CheckOutDate helper.LocalTime `json:"check_out_date"`
+	ExpiresAt    helper.LocalTime `json:"expires_at"`
+}
+
+func hold(h reservation.HoldCore) holdResponse {
+	return holdResponse{
+		HoldID:       h.HoldID,
+		VenueID:      h.VenueID,
+		CheckInDate:  helper.LocalTime(h.CheckInDate),
+		CheckOutDate: helper.LocalTime(h.CheckOutDate),
+		ExpiresAt:    helper.LocalTime(h.ExpiresAt),
+	}
+}
+
+type rescheduleResponse struct {
+	ReservationID   string           `json:"reservation_id"`
+	CheckInDate     helper.LocalTime `json:"check_in_date"`
+	CheckOutDate    helper.LocalTime `json:"check_out_date"`
+	Duration        float64          `json:"duration"`
+	PriceDifference float64          `json:"price_difference"`
+	PaymentID       string           `json:"payment_id,omitempty"`
+	PaymentMethod   string           `json:"payment_method,omitempty"`
+	PaymentType     string           `json:"payment_type,omitempty"`
+	PaymentCode     string           `json:"payment_code,omitempty"`
+	ExpiredAt       helper.LocalTime `json:"expired_at"`
+}
+
+func reschedule(r reservation.RescheduleCore) rescheduleResponse {
+	return rescheduleResponse{
+		ReservationID:   r.Reservation.ReservationID,
+		CheckInDate:     helper.LocalTime(r.Reservation.CheckInDate),
+		CheckOutDate:    helper.LocalTime(r.Reservation.CheckOutDate),
+		Duration:        r.Reservation.Duration,
+		PriceDifference: r.PriceDifference,
+		PaymentID:       r.Payment.PaymentID,
+		PaymentMethod:   r.Payment.PaymentMethod,
+		PaymentType:     r.Payment.PaymentType,
+		PaymentCode:     r.Payment.PaymentCode,
+		ExpiredAt:       helper.LocalTime(r.Payment.ExpiredAt),
+	}
+}
+
+type quoteResponse struct {
+	VenueID      string              `json:"venue_id"`
+	CheckInDate  helper.LocalTime    `json:"check_in_date"`
+	CheckOutDate helper.LocalTime    `json:"check_out_date"`
+	Duration     float64             `json:"duration"`
+	Items        []quoteItemResponse `json:"items"`
+	Total        float64             `json:"total_price"`
+}
+
+type quoteItemResponse struct {
+	StartDate helper.LocalTime `json:"start_date"`
+	EndDate   helper.LocalTime `json:"end_date"`
+	Rule      string           `json:"rule,omitempty"`
+	Rate      float64          `json:"rate"`
+	Hours     float64          `json:"hours"`
+	Amount    float64          `json:"amount"`
+}
+
+func quote(q reservation.QuoteCore) quoteResponse {
+	items := make([]quoteItemResponse, len(q.Items))
+	for i, item := range q.Items {
+		items[i] = quoteItemResponse{
+			StartDate: helper.LocalTime(item.StartDate),
+			EndDate:   helper.LocalTime(item.EndDate),
+			Rule:      item.Rule,
+			Rate:      item.Rate,
+			Hours:     item.Hours,
+			Amount:    item.Amount,
+		}
+	}
+
+	return quoteResponse{
+		VenueID:      q.VenueID,
+		CheckInDate:  helper.LocalTime(q.CheckInDate),
+		CheckOutDate: helper.LocalTime(q.CheckOutDate),
+		Duration:     q.Duration,
+		Items:        items,
+		Total:        q.Total,
+	}
+}
+
+type waitlistResponse struct {
+	WaitlistID     string           `json:"waitlist_id"`
+	VenueID        string           `json:"venue_id"`
+	VenueName      string           `json:"venue_name,omitempty"`
+	CheckInDate    helper.LocalTime `json:"check_in_date"`
+	CheckOutDate   helper.LocalTime `json:"check_out_date"`
+	Status         string           `json:"status"`
+	HoldID         string           `json:"hold_id,omitempty"`
+	OfferExpiresAt helper.LocalTime `json:"offer_expires_at"`
+}
+
+func waitlist(w reservation.WaitlistCore) waitlistResponse {
+	return waitlistResponse{
+		WaitlistID:     w.WaitlistID,
+		VenueID:        w.VenueID,
+		VenueName:      w.VenueName,
+		CheckInDate:    helper.LocalTime(w.CheckInDate),
+		CheckOutDate:   helper.LocalTime(w.CheckOutDate),
+		Status:         w.Status,
+		HoldID:         w.HoldID,
+		OfferExpiresAt: helper.LocalTime(w.OfferExpiresAt),
+	}
+}
+
+type occurrenceResponse struct {
+	ReservationID string           `json:"reservation_id"`
+	CheckInDate   helper.LocalTime `json:"check_in_date"`
+	CheckOutDate  helper.LocalTime `json:"check_out_date"`
+}
+
+type recurringReservationResponse struct {
+	SeriesID      string               `json:"series_id"`
+	PaymentID     string               `json:"payment_id"`
+	PaymentMethod string               `json:"payment_method"`
+	PaymentType   string               `json:"payment_type"`
+	PaymentCode   string               `json:"payment_code"`
+	GrandTotal    string               `json:"total_price"`
+	Credits       float64              `json:"credits,omitempty"`
+	ExpiredAt     helper.LocalTime     `json:"expired_at"`
+	Occurrences   []occurrenceResponse `json:"occurrences"`
+}
+
+type cancellationResponse struct {
+	Reservations []occurrenceResponse `json:"reservations"`
+	Refund       *refundResponse      `json:"refund,omitempty"`
+}
+
+type refundResponse struct {
+	RefundID string  `json:"refund_id,omitempty"`
+	Amount   float64 `json:"amount"`
+	Credits  float64 `json:"credits,omitempty"`
+	Reason   string  `json:"reason"`
+}
+
+type conflictResponse struct {
+	CheckInDate  helper.LocalTime `json:"check_in_date"`
+	CheckOutDate helper.LocalTime `json:"check_out_date"`
+	Reason       string           `json:"reason"`
+}
+
+func occurrences(rs []reservation.ReservationCore) []occurrenceResponse {
+	result := make([]occurrenceResponse, len(rs))
+	for i, r := range rs {
+		result[i] = occurrenceResponse{
+			ReservationID: r.ReservationID,
+			CheckInDate:   helper.LocalTime(r.CheckInDate),
+			CheckOutDate:  helper.LocalTime(r.CheckOutDate),
+		}
+	}
+
+	return result
+}
+
+func recurringReservation(rs []reservation.ReservationCore, p reservation.PaymentCore) recurringReservationResponse {
+	response := recurringReservationResponse{
+		PaymentID:     p.PaymentID,
+		PaymentMethod: p.PaymentMethod,
+		PaymentType:   p.PaymentType,
+		PaymentCode:   p.PaymentCode,
+		GrandTotal:    p.GrandTotal,
+		Credits:       p.Credits,
+		ExpiredAt:     helper.LocalTime(p.ExpiredAt),
+		Occurrences:   occurrences(rs),
+	}
+	if len(rs) > 0 {
+		response.SeriesID = rs[0].SeriesID
+	}
+
+	return response
+}
+
+func cancellation(rs []reservation.ReservationCore, refund reservation.RefundCore) cancellationResponse {
+	response := cancellationResponse{Reservations: occurrences(rs)}
+	if refund.Reason != "" {
+		response.Refund = &refundResponse{
+			RefundID: refund.RefundID,
+			Amount:   refund.Amount,
+			Credits:  refund.Credits,
+			Reason:   refund.Reason,
+		}
+	}
+
+	return response
+}
+
+func conflicts(cs []reservation.ConflictCore) []conflictResponse {
+	result := make([]conflictResponse, len(cs))
+	for i, c := range cs {
+		result[i] = conflictResponse{
+			CheckInDate:  helper.LocalTime(c.CheckInDate),
+			CheckOutDate: helper.LocalTime(c.CheckOutDate),
+			Reason:       c.Reason,
+		}
+	}
+
+	return result
+}
+
+type discrepancyResponse struct {
+	DiscrepancyID string           `json:"discrepancy_id"`
+	PaymentID     string           `json:"payment_id"`
+	OrderID       string           `json:"order_id,omitempty"`
+	LocalStatus   string           `json:"local_status"`
+	GatewayStatus string           `json:"gateway_status,omitempty"`
+	LocalAmount   string           `json:"local_amount"`
+	GatewayAmount string           `json:"gateway_amount,omitempty"`
+	Resolved      bool             `json:"resolved"`
+	Detail        string           `json:"detail"`
+	FoundAt       helper.LocalTime `json:"found_at"`
+}
+
+// discrepancyReportResponse counts the discrepancies reconciliation found besides listing them
+type discrepancyReportResponse struct {
+	Total         int                   `json:"total"`
+	Resolved      int                   `json:"resolved"`
+	Unresolved    int                   `json:"unresolved"`
+	Discrepancies []discrepancyResponse `json:"discrepancies"`
+}
+
+func discrepancyReport(ds []reservation.DiscrepancyCore) discrepancyReportResponse {
+	report := discrepancyReportResponse{
+		Total:         len(ds),
+		Discrepancies: make([]discrepancyResponse, len(ds)),
+	}
+	for i, d := range ds {
+		if d.Resolved {
+			report.Resolved++
+		} else {
+			report.Unresolved++
+		}
+		report.Discrepancies[i] = discrepancyResponse{
+			DiscrepancyID: d.DiscrepancyID,
+			PaymentID:     d.PaymentID,
+			OrderID:       d.OrderID,
+			LocalStatus:   d.LocalStatus,
+			GatewayStatus: d.GatewayStatus,
+			LocalAmount:   d.LocalAmount,
+			GatewayAmount: d.GatewayAmount,
+			Resolved:      d.Resolved,
+			Detail:        d.Detail,
+			FoundAt:       helper.LocalTime(d.CreatedAt),
+		}
+	}
+
+	return report
+}
@@ -1,31 +1,67 @@
 package service
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
 	"github.com/playground-pro-project/playground-pro-api/features/reservation"
+	mail "github.com/playground-pro-project/playground-pro-api/utils/email"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	"github.com/playground-pro-project/playground-pro-api/utils/invoice"
 	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
+	"github.com/playground-pro-project/playground-pro-api/utils/pricing"
+	"github.com/playground-pro-project/playground-pro-api/utils/schedule"
 )
 
 var log = middlewares.Log()
 
+var (
+	waitlistTemplate = "./utils/email/waitlist_template.html"
+	receiptTemplate  = "./utils/email/receipt_template.html"
+	shareTemplate    = "./utils/email/share_template.html"
+)
+
+const (
+	bookingWindowMonths     = 3
+	minSlotLength           = 15 * time.Minute
+	defaultSlotLength       = time.Hour
+	maxSlotDays             = 31
+	maxOccurrences          = 52
+	defaultHoldTTL          = 10 * time.Minute
+	defaultExpiryGrace      = 5 * time.Minute
+	defaultReconcileAfter   = 30 * time.Minute
+	reconcileBatchSize      = 100
+	defaultOfferTTL         = 30 * time.Minute
+	defaultRescheduleCutoff = time.Hour
+	defaultRefundNotice     = 1 // hours
+	maxSplitParticipants    = 21
+)
+
 type reservationService struct {
 	query    reservation.ReservationData
 	validate *validator.Validate
-	refund   paymentgateway.Refund
+	payments paymentgateway.PaymentProvider
+	email    mail.EmailSender
 }
 
-func New(rd reservation.ReservationData, refund paymentgateway.Refund) reservation.ReservationService {
+func New(rd reservation.ReservationData, payments paymentgateway.PaymentProvider, sender mail.EmailSender) reservation.ReservationService {
 	return &reservationService{
 		query:    rd,
 		validate: validator.New(),
-		refund:   refund,
+		payments: payments,
+		email:    sender,
 	}
 }
 
@@ -47,59 +83,71 @@ func (rs *reservationService) MakeReservation(userId string, r reservation.Reser
 		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New(message)
 	}
 
-	// TODO 1 : Validate reservation timewindow and check availability
-	minTime := time.Now().Local()
-	maxTime := minTime.AddDate(0, 3, 0)
-	if r.CheckInDate.Before(minTime) || r.CheckInDate.After(maxTime) {
-		log.Warn("reservation date not within the allowed timewindow")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("reservation date not within the allowed timewindow")
-	}
-
-	if r.CheckOutDate.Before(minTime) || r.CheckOutDate.After(maxTime) {
-		log.Warn("reservation date not within the allowed timewindow")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("reservation date not within the allowed timewindow")
-	}
-
-	// TODO 1.5: Check if there is an existing reservation for the same time slot
-	existingReservations, err := rs.query.GetReservationsByTimeSlot(r.VenueID, r.CheckInDate, r.CheckOutDate)
+	// TODO 1 : Validate the time slot against opening hours, closures, bookings and holds
+	ownHolds, err := rs.checkSlot(userId, r)
 	if err != nil {
-		log.Sugar().Errorf("error on retrieving existing reservations: %s", err.Error())
 		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
 	}
 
-	if len(existingReservations) > 0 {
-		log.Warn("reservation not available for the specified time slot")
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("reservation not available")
-	}
-
-	// TODO 2 : Get price of spesific venue
-	res1, err := rs.query.PriceVenue(r.VenueID)
+	// TODO 2 : Get price and pricing rules of spesific venue
+	price, rules, err := rs.venuePricing(r.VenueID)
 	if err != nil {
 		log.Sugar().Errorf("failed to get venue price %s", r.VenueID)
 		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
 	}
 
-	price, err := strconv.ParseFloat(fmt.Sprintf("%.2f", res1), 64)
-	if err != nil {
-		log.Sugar().Errorf("failed to parse grand_total: %s", err.Error())
-		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
-	}
-
-	log.Sugar().Infof("%.2f", price)
+	// TODO 3: Price the booking across the pricing rules it touches
+	q := quote(r, price, rules)
+	r.Duration = q.Duration
+	r.Subtotal = q.Total
 
-	// TODO 3: Get accumulative duration of specific venue
-	duration := r.CheckOutDate.Sub(r.CheckInDate).Hours()
-	r.Duration = duration
-	log.Sugar().Infof("%.2f", r.Duration)
+	// TODO 4: Apply the voucher, the discounted subtotal is what refunds and reschedules work from
+	if p.PaymentType == reservation.PaymentTypeCredits && p.VoucherCode != "" {
+		log.Warn("vouchers cannot be combined with credits")
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("vouchers cannot be combined with credits")
+	}
+	if p.VoucherCode != "" {
+		v, discount, err := rs.applyVoucher(p.VoucherCode, r)
+		if err != nil {
+			return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+		}
+		p.VoucherID = v.VoucherID
+		p.Discount = discount
+	}
 
-	// TODO 4: Multiply duration and price
-	p.GrandTotal = strconv.FormatFloat(duration*price, 'f', 2, 64)
+	// TODO 5: Itemize the charge with fees and tax, prepaid hours are burnt one for one instead
+	if p.PaymentType == reservation.PaymentTypeCredits {
+		p.Credits = r.Duration
+		p.GrandTotal = "0"
+	} else {
+		bill := invoice.Build(invoiceRates(), r.Subtotal, p.Discount, "Venue booking")
+		r.Subtotal = float64(bill.Subtotal - bill.Discount)
+		p.Discount = float64(bill.Discount)
+		p.ServiceFee = float64(bill.Fees)
+		p.Items = paymentItems(bill.Items)
+		p.GrandTotal = strconv.FormatInt(bill.Total, 10)
+	}
 
 	log.Sugar().Infof(p.GrandTotal)
 
-	// TODO 5: Save data
+	// TODO 6: Charge before saving, so no database transaction waits on the payment gateway.
+	// A split payment is charged share by share, to every player
+	if p.SplitMode != "" {
+		p.Shares, err = rs.splitShares(userId, p)
+		if err != nil {
+			return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+		}
+	}
+	r.ReservationID = helper.GenerateReservationID()
+	p, err = rs.charge(r.ReservationID, p)
+	if err != nil {
+		return reservation.ReservationCore{}, reservation.PaymentCore{}, err
+	}
+
+	// TODO 7: Save data, the charge is voided when the booking cannot be kept
 	result, paymentResult, err := rs.query.MakeReservation(userId, r, p)
 	if err != nil {
+		rs.voidCharge(r.ReservationID, p)
 		var message string
 		switch {
 		case strings.Contains(err.Error(), "user does not exist"):
@@ -108,6 +156,19 @@ func (rs *reservationService) MakeReservation(userId string, r reservation.Reser
 		case strings.Contains(err.Error(), "unregistered user"):
 			log.Error("foreign key constraint violation")
 			message = "unregistered user"
+		case strings.Contains(err.Error(), "reservation not available"):
+			log.Warn("time slot was taken by a concurrent reservation")
+			message = "reservation not available"
+		case strings.Contains(err.Error(), "venue not found"):
+			log.Error("venue not found")
+			message = "venue not found"
+		case strings.Contains(err.Error(), "voucher usage limit"),
+			strings.Contains(err.Error(), "insufficient credits"):
+			log.Warn(err.Error())
+			message = err.Error()
+		case strings.Contains(err.Error(), "voucher not found"):
+			log.Warn("voucher was removed before checkout")
+			message = "invalid voucher code"
 		default:
 			log.Error("internal server error")
 			message = "internal server error"
@@ -115,117 +176,2217 @@ func (rs *reservationService) MakeReservation(userId string, r reservation.Reser
 		return reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New(message)
 	}
 
+	// TODO 8: Release the holds this reservation was made from
+	for _, h := range ownHolds {
+		if err := rs.query.DeleteHold(h.VenueID, h.HoldID); err != nil {
+			log.Sugar().Warnf("failed to release hold %s, it will expire on its own", h.HoldID)
+		}
+	}
+	if r.HoldID != "" {
+		if err := rs.query.ClaimWaitlistOffer(userId, r.HoldID); err != nil {
+			log.Sugar().Warnf("failed to mark waitlist offer %s as claimed", r.HoldID)
+		}
+	}
+	rs.inviteShares(result, paymentResult)
+
 	log.Sugar().Infof("new reservation has been created: %s", result.ReservationID)
 	return result, paymentResult, nil
 }
 
-// ReservationStatus implements reservation.ReservationService.
-func (rs *reservationService) ReservationStatus(request reservation.PaymentCore) (reservation.PaymentCore, error) {
-	switch request.Status {
-	case "settlement":
-		request.Status = "success"
-		res, err := rs.query.ReservationStatus(request)
-		if err != nil {
-			log.Error("failed to update reservation status")
-			return res, errors.New("failed to update reservation status: " + err.Error())
-		}
+// MakeRecurringReservation implements reservation.ReservationService.
+func (rs *reservationService) MakeRecurringReservation(userId string, r reservation.ReservationCore, rule reservation.RecurrenceCore, p reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, []reservation.ConflictCore, error) {
+	var message string
+	if r.VenueID == "" {
+		message = "venue_id cannot be empty"
+	} else if r.CheckInDate.IsZero() {
+		message = "check_in_date cannot be empty"
+	} else if r.CheckOutDate.IsZero() {
+		message = "check_out_date cannot be empty"
+	}
+	if p.VoucherCode != "" {
+		message = "vouchers cannot be applied to recurring reservations"
+	}
+	if p.SplitMode != "" {
+		message = "split payments are not available for recurring reservations"
+	}
+	if message != "" {
+		log.Warn(message)
+		return nil, reservation.PaymentCore{}, nil, errors.New(message)
+	}
 
-	case "cancel":
-		request.Status = "cancel"
-		if !paymentgateway.IsRefundable(request.PaymentMethod) {
-			grandTotal, errConv := strconv.ParseFloat(request.GrandTotal, 64)
-			if errConv != nil {
-				log.Error("failed to parse grand total")
-				return request, errors.New("failed to parse grand total: " + errConv.Error())
-			}
+	occurrences, err := expandRecurrence(r, rule)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, reservation.PaymentCore{}, nil, err
+	}
 
-			checkInTime, errQuery := rs.query.ReservationCheckOutDate(request.Reservation.ReservationID)
-			if errQuery != nil {
-				return reservation.PaymentCore{}, errors.New("failed to get checkout date: " + errQuery.Error())
+	// TODO 1 : Validate every occurrence and report all the dates that conflict at once
+	conflicts := []reservation.ConflictCore{}
+	for _, occurrence := range occurrences {
+		if _, err := rs.checkSlot(userId, occurrence); err != nil {
+			if !isSlotConflict(err) {
+				return nil, reservation.PaymentCore{}, nil, err
 			}
+			conflicts = append(conflicts, reservation.ConflictCore{
+				CheckInDate:  occurrence.CheckInDate,
+				CheckOutDate: occurrence.CheckOutDate,
+				Reason:       err.Error(),
+			})
+		}
+	}
 
-			timeDiff := time.Until(checkInTime)
-			if timeDiff < time.Hour {
-				log.Error("refund cannot be processed at least 1 hour away")
-				return request, errors.New("refund cannot be processed at least 1 hour away")
+	if len(conflicts) > 0 {
+		log.Sugar().Warnf("%d of %d occurrences are not available", len(conflicts), len(occurrences))
+		return nil, reservation.PaymentCore{}, conflicts, errors.New("some occurrences are not available")
+	}
+
+	// TODO 2 : Price every occurrence, the series is paid at once
+	price, rules, err := rs.venuePricing(r.VenueID)
+	if err != nil {
+		log.Sugar().Errorf("failed to get venue price %s", r.VenueID)
+		return nil, reservation.PaymentCore{}, nil, err
+	}
+
+	subtotal, hours := 0.0, 0.0
+	for i := range occurrences {
+		q := quote(occurrences[i], price, rules)
+		occurrences[i].Duration = q.Duration
+		occurrences[i].Subtotal = q.Total
+		subtotal += occurrences[i].Subtotal
+		hours += occurrences[i].Duration
+	}
+
+	// The series is a single booking, its fees are charged once
+	if p.PaymentType == reservation.PaymentTypeCredits {
+		p.Credits = hours
+		p.GrandTotal = "0"
+	} else {
+		bill := invoice.Build(invoiceRates(), subtotal, 0, fmt.Sprintf("Venue booking, %d sessions", len(occurrences)))
+		p.ServiceFee = float64(bill.Fees)
+		p.Items = paymentItems(bill.Items)
+		p.GrandTotal = strconv.FormatInt(bill.Total, 10)
+	}
+
+	// TODO 3 : Charge the series once, under its own ID
+	seriesId := helper.GenerateSeriesID()
+	for i := range occurrences {
+		occurrences[i].SeriesID = seriesId
+	}
+	p, err = rs.charge(seriesId, p)
+	if err != nil {
+		return nil, reservation.PaymentCore{}, nil, err
+	}
+
+	// TODO 4 : Save all occurrences atomically
+	result, paymentResult, err := rs.query.MakeRecurringReservation(userId, occurrences, p)
+	if err != nil {
+		rs.voidCharge(seriesId, p)
+		switch {
+		case strings.Contains(err.Error(), "reservation not available"):
+			log.Warn("an occurrence was taken by a concurrent reservation")
+			return nil, reservation.PaymentCore{}, nil, errors.New("reservation not available")
+		case strings.Contains(err.Error(), "insufficient credits"):
+			log.Warn("insufficient credits for the series")
+			return nil, reservation.PaymentCore{}, nil, errors.New("insufficient credits")
+		case strings.Contains(err.Error(), "unregistered user"):
+			log.Error("foreign key constraint violation")
+			return nil, reservation.PaymentCore{}, nil, errors.New("unregistered user")
+		default:
+			log.Error("internal server error")
+			return nil, reservation.PaymentCore{}, nil, errors.New("internal server error")
+		}
+	}
+
+	log.Sugar().Infof("new series of %d reservations has been created", len(result))
+	return result, paymentResult, nil, nil
+}
+
+// charge asks the payment provider to pay for an order. Prepaid hours are not charged here,
+// they are spent together with saving the booking. A split payment charges its shares instead.
+func (rs *reservationService) charge(orderId string, p reservation.PaymentCore) (reservation.PaymentCore, error) {
+	if p.PaymentType == reservation.PaymentTypeCredits {
+		return p, nil
+	}
+	if len(p.Shares) > 0 {
+		return rs.chargeShares(p)
+	}
+
+	charged, err := rs.payments.Charge(orderId, p)
+	if err != nil {
+		log.Sugar().Errorf("failed to charge order %s: %v", orderId, err)
+		if strings.Contains(err.Error(), "invalid payment_type") {
+			return reservation.PaymentCore{}, errors.New("invalid payment_type")
+		}
+		return reservation.PaymentCore{}, errors.New("internal server error while charging payment")
+	}
+
+	// The booking keeps its invoice, the provider tells how and until when to pay
+	p.PaymentID = charged.PaymentID
+	p.PaymentMethod = charged.PaymentMethod
+	p.PaymentType = charged.PaymentType
+	p.PaymentCode = charged.PaymentCode
+	p.GrandTotal = charged.GrandTotal
+	p.Status = charged.Status
+	p.ExpiredAt = charged.ExpiredAt
+	return p, nil
+}
+
+// voidCharge cancels the charge of an order that could not be booked after all.
+func (rs *reservationService) voidCharge(orderId string, p reservation.PaymentCore) {
+	if len(p.Shares) > 0 {
+		for _, share := range p.Shares {
+			rs.voidCharge(share.ShareID, share.Charge)
+		}
+		return
+	}
+	if p.PaymentID == "" {
+		return
+	}
+
+	if err := rs.payments.Cancel(orderId); err != nil {
+		log.Sugar().Errorf("failed to void the charge of order %s, it is left to expire unpaid: %v", orderId, err)
+	}
+}
+
+// splitShares works out what every player pays of a split payment. Invited players are looked
+// up by email or phone, the booker pays whatever they leave, and equal shares leave the booker
+// the rupiah that do not divide evenly.
+func (rs *reservationService) splitShares(userId string, p reservation.PaymentCore) ([]reservation.ShareCore, error) {
+	var message string
+	switch {
+	case p.SplitMode != reservation.SplitEqual && p.SplitMode != reservation.SplitCustom:
+		message = "invalid split mode, expected equal or custom"
+	case p.PaymentType == reservation.PaymentTypeCredits:
+		message = "split payments cannot be paid with credits"
+	case len(p.Shares) == 0:
+		message = "split payments need at least one participant"
+	case len(p.Shares) > maxSplitParticipants:
+		message = fmt.Sprintf("split payments take at most %d participants", maxSplitParticipants)
+	}
+	if message != "" {
+		log.Warn(message)
+		return nil, errors.New(message)
+	}
+
+	total, _ := strconv.ParseInt(p.GrandTotal, 10, 64)
+	shares := []reservation.ShareCore{}
+	seen := map[string]bool{userId: true}
+	invited := int64(0)
+	for _, share := range p.Shares {
+		email, phone := strings.TrimSpace(share.Email), strings.TrimSpace(share.Phone)
+		if email == "" && phone == "" {
+			log.Warn("participant email or phone cannot be empty")
+			return nil, errors.New("participant email or phone cannot be empty")
+		}
+
+		user, err := rs.query.GetUserByContact(email, phone)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				log.Sugar().Warnf("participant %s%s is not registered", email, phone)
+				return nil, errors.New("participant not found")
 			}
+			log.Error("internal server error")
+			return nil, errors.New("internal server error")
+		}
+		if seen[user.UserID] {
+			log.Warn("participant was invited twice")
+			return nil, errors.New("invalid participants, each must be a different user other than the booker")
+		}
+		seen[user.UserID] = true
 
-			err := rs.refund.RefundTransaction(request.Reservation.ReservationID, int64(grandTotal), "reason")
-			if err != nil {
-				log.Error("failed to refund transaction")
-				return request, errors.New("failed to refund transaction: " + err.Error())
+		if p.SplitMode == reservation.SplitCustom {
+			if share.Amount <= 0 {
+				log.Warn("share amount must be greater than 0")
+				return nil, errors.New("invalid share amount, must be greater than 0")
 			}
+			invited += share.Amount
 		}
+		shares = append(shares, reservation.ShareCore{
+			UserID:   user.UserID,
+			Fullname: user.Fullname,
+			Email:    user.Email,
+			Amount:   share.Amount,
+		})
+	}
 
-		res, err := rs.query.ReservationStatus(request)
-		if err != nil {
-			log.Error("failed to update status to cancel")
-			return res, errors.New("failed to update status to cancel: " + err.Error())
+	if p.SplitMode == reservation.SplitEqual {
+		each := total / int64(len(shares)+1)
+		if each <= 0 {
+			log.Warn("total is too small to split")
+			return nil, errors.New("invalid share amount, must be greater than 0")
 		}
+		for i := range shares {
+			shares[i].Amount = each
+		}
+		invited = each * int64(len(shares))
+	}
+	if invited > total {
+		log.Warn("shares add up to more than the total")
+		return nil, errors.New("invalid share amount, shares add up to more than the total")
+	}
 
-	case "expire":
-		res, err := rs.query.ReservationStatus(request)
+	if rest := total - invited; rest > 0 {
+		shares = append([]reservation.ShareCore{{UserID: userId, Amount: rest}}, shares...)
+	}
+
+	return shares, nil
+}
+
+// chargeShares charges every player of a split payment their share, each under a share ID of
+// its own. The split payment is never charged itself, it lapses with the first share to lapse.
+// Should any charge fail, the ones made before it are voided.
+func (rs *reservationService) chargeShares(p reservation.PaymentCore) (reservation.PaymentCore, error) {
+	p.PaymentID = helper.GenerateSplitPaymentID()
+	p.PaymentMethod = reservation.PaymentMethodSplit
+	p.Status = reservation.PaymentPending
+	p.ExpiredAt = time.Time{}
+	for i := range p.Shares {
+		share := &p.Shares[i]
+		share.ShareID = helper.GenerateShareID()
+		share.PaymentID = p.PaymentID
+		charged, err := rs.charge(share.ShareID, reservation.PaymentCore{
+			PaymentType: p.PaymentType,
+			GrandTotal:  strconv.FormatInt(share.Amount, 10),
+		})
 		if err != nil {
-			log.Error("error on updating status to expire")
-			return res, errors.New("error on updating status to expire: " + err.Error())
+			rs.voidCharge("", reservation.PaymentCore{Shares: p.Shares[:i]})
+			return reservation.PaymentCore{}, err
+		}
+
+		share.Charge = charged
+		if p.ExpiredAt.IsZero() || (!charged.ExpiredAt.IsZero() && charged.ExpiredAt.Before(p.ExpiredAt)) {
+			p.ExpiredAt = charged.ExpiredAt
 		}
 	}
 
-	return request, nil
+	return p, nil
 }
 
-// ReservationHistory implements reservation.ReservationService.
-func (rs *reservationService) MyReservation(userId string) ([]reservation.MyReservationCore, error) {
-	payments, err := rs.query.MyReservation(userId)
+// inviteShares emails every invited player of a split payment how to pay their share.
+func (rs *reservationService) inviteShares(r reservation.ReservationCore, p reservation.PaymentCore) {
+	if len(p.Shares) == 0 {
+		return
+	}
+
+	tmpl, err := template.ParseFiles(shareTemplate)
 	if err != nil {
-		if strings.Contains(err.Error(), "list reservations record not found") {
-			log.Error("list reservations record not found")
-			return []reservation.MyReservationCore{}, errors.New("list reservations record not found")
-		} else {
-			log.Error("internal server error")
-			return []reservation.MyReservationCore{}, errors.New("internal server error")
+		log.Sugar().Errorf("failed to parse email template: %v", err)
+		return
+	}
+
+	venue, err := rs.query.GetVenue(r.VenueID)
+	if err != nil {
+		log.Sugar().Warnf("failed to get venue %s for the share invitations", r.VenueID)
+	}
+
+	for _, share := range p.Shares {
+		if share.Email == "" {
+			// The booker pays their own share at checkout
+			continue
+		}
+
+		data := struct {
+			Name         string
+			VenueName    string
+			CheckInDate  string
+			CheckOutDate string
+			Amount       string
+			PaymentType  string
+			PaymentCode  string
+			ExpiresAt    string
+		}{
+			Name:         share.Fullname,
+			VenueName:    venue.Name,
+			CheckInDate:  r.CheckInDate.Format("2006-01-02 15:04"),
+			CheckOutDate: r.CheckOutDate.Format("2006-01-02 15:04"),
+			Amount:       invoice.FormatRupiah(share.Amount),
+			PaymentType:  strings.ToUpper(share.Charge.PaymentType),
+			PaymentCode:  share.Charge.PaymentCode,
+			ExpiresAt:    share.Charge.ExpiredAt.Format("2006-01-02 15:04"),
+		}
+
+		var emailContent bytes.Buffer
+		if err := tmpl.Execute(&emailContent, data); err != nil {
+			log.Sugar().Errorf("failed to render email template: %v", err)
+			return
+		}
+
+		subject := "You Have Been Invited to Split a Booking at " + venue.Name
+		to := []string{share.Email}
+		if err := rs.email.SendEmail(subject, emailContent.String(), to, nil, nil, nil); err != nil {
+			log.Sugar().Errorf("failed to invite the player of share %s: %v", share.ShareID, err)
 		}
 	}
+}
 
-	return payments, err
+// expandRecurrence lists the occurrences of r following rule, starting with r itself.
+func expandRecurrence(r reservation.ReservationCore, rule reservation.RecurrenceCore) ([]reservation.ReservationCore, error) {
+	var weeks int
+	switch rule.Frequency {
+	case reservation.RecurrenceWeekly:
+		weeks = 1
+	case reservation.RecurrenceBiweekly:
+		weeks = 2
+	default:
+		return nil, errors.New("invalid frequency, expected weekly or biweekly")
+	}
+
+	switch {
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return nil, errors.New("recurrence takes either count or until, not both")
+	case rule.Count <= 0 && rule.Until.IsZero():
+		return nil, errors.New("recurrence count or until cannot be empty")
+	case rule.Count > maxOccurrences:
+		return nil, fmt.Errorf("recurrence cannot exceed %d occurrences", maxOccurrences)
+	case !r.CheckOutDate.After(r.CheckInDate):
+		return nil, errors.New("check_out_date must be after check_in_date")
+	case r.CheckOutDate.Sub(r.CheckInDate) > time.Duration(weeks)*7*24*time.Hour:
+		return nil, errors.New("occurrences cannot overlap each other")
+	}
+
+	// until is a date, so its whole day is included
+	until := schedule.StartOfDay(rule.Until).AddDate(0, 0, 1)
+	occurrences := []reservation.ReservationCore{}
+	for i := 0; rule.Count == 0 || i < rule.Count; i++ {
+		occurrence := r
+		if i > 0 {
+			// A hold only ever covers the first occurrence
+			occurrence.HoldID = ""
+		}
+		occurrence.CheckInDate = r.CheckInDate.AddDate(0, 0, 7*weeks*i)
+		occurrence.CheckOutDate = r.CheckOutDate.AddDate(0, 0, 7*weeks*i)
+		if rule.Count == 0 && !occurrence.CheckInDate.Before(until) {
+			break
+		}
+		if len(occurrences) == maxOccurrences {
+			return nil, fmt.Errorf("recurrence cannot exceed %d occurrences", maxOccurrences)
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	if len(occurrences) == 0 {
+		return nil, errors.New("until cannot be before check_in_date")
+	}
+
+	return occurrences, nil
 }
 
-func (rs *reservationService) DetailTransaction(userId string, paymentId string) (reservation.PaymentCore, error) {
-	payment, err := rs.query.DetailTransaction(userId, paymentId)
-	if err != nil {
-		if strings.Contains(err.Error(), "payment not found") {
-			log.Error("payment not found")
-			return reservation.PaymentCore{}, errors.New("payment not found")
-		} else {
-			log.Error("internal server error")
-			return reservation.PaymentCore{}, err
+// isSlotConflict reports whether checkSlot rejected a slot for reasons specific to its date.
+func isSlotConflict(err error) bool {
+	for _, reason := range []string{"timewindow", "outside opening hours", "venue is closed", "reservation not available", "held by another user"} {
+		if strings.Contains(err.Error(), reason) {
+			return true
 		}
 	}
 
-	return payment, nil
+	return false
 }
 
-// CheckAvailability implements reservation.ReservationService.
-func (rs *reservationService) CheckAvailability(venueId string) ([]reservation.AvailabilityCore, error) {
-	result, err := rs.query.CheckAvailability(venueId)
+// CancelReservation implements reservation.ReservationService.
+func (rs *reservationService) CancelReservation(userId string, reservationId string, scope string) ([]reservation.ReservationCore, reservation.RefundCore, error) {
+	if scope == "" {
+		scope = reservation.CancelOccurrence
+	}
+	if scope != reservation.CancelOccurrence && scope != reservation.CancelSeries {
+		log.Warn("invalid cancellation scope")
+		return nil, reservation.RefundCore{}, errors.New("invalid scope, expected occurrence or series")
+	}
+
+	target, payment, err := rs.query.GetReservation(userId, reservationId)
 	if err != nil {
-		if strings.Contains(err.Error(), "list venues record not found") {
-			log.Error("list venues record not found")
-			return []reservation.AvailabilityCore{}, errors.New("list venues record not found")
-		} else {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, reservation.RefundCore{}, errors.New("reservation not found")
+		}
+		log.Error("internal server error")
+		return nil, reservation.RefundCore{}, errors.New("internal server error")
+	}
+
+	if payment.Status != "pending" && payment.Status != "success" {
+		log.Warn("reservation is no longer active")
+		return nil, reservation.RefundCore{}, errors.New("reservation is no longer active")
+	}
+
+	// Every live reservation paid by the same payment
+	paid := []reservation.ReservationCore{target}
+	if target.SeriesID != "" {
+		paid, err = rs.query.GetSeries(target.SeriesID)
+		if err != nil {
 			log.Error("internal server error")
-			return []reservation.AvailabilityCore{}, errors.New("internal server error")
+			return nil, reservation.RefundCore{}, errors.New("internal server error")
 		}
 	}
 
-	log.Sugar().Info(result)
+	cancelled := []reservation.ReservationCore{target}
+	if scope == reservation.CancelSeries {
+		// Occurrences that already started stay as they are
+		cancelled = []reservation.ReservationCore{}
+		for _, r := range paid {
+			if time.Now().Before(r.CheckInDate) {
+				cancelled = append(cancelled, r)
+			}
+		}
+	} else if !time.Now().Before(target.CheckInDate) {
+		cancelled = []reservation.ReservationCore{}
+	}
 
-	return result, nil
+	if len(cancelled) == 0 {
+		log.Warn("cancellation is closed after check-in")
+		return nil, reservation.RefundCore{}, errors.New("reservation cannot be cancelled after check-in")
+	}
+
+	cancelPayment := len(cancelled) == len(paid)
+	if payment.Status == "pending" && !cancelPayment {
+		// A pending charge cannot be reduced, only dropped
+		log.Warn("unpaid series can only be cancelled as a whole")
+		return nil, reservation.RefundCore{}, errors.New("unpaid series can only be cancelled as a whole")
+	}
+
+	refund := reservation.RefundCore{PaymentID: payment.PaymentID}
+	split := payment.PaymentMethod == reservation.PaymentMethodSplit
+	if split {
+		// Every player is refunded their own part, each refund is recorded as it goes through
+		refund.Amount, err = rs.cancelSplit(payment, cancelled)
+		if err != nil {
+			log.Error("internal server error")
+			return nil, reservation.RefundCore{}, errors.New("internal server error")
+		}
+		refund.Reason = "cancelled by customer, refunded to every player of the split payment"
+	} else if payment.Status == "success" && payment.PaymentMethod == reservation.PaymentTypeCredits {
+		refund.Credits, err = rs.refundCredits(cancelled)
+		if err != nil {
+			log.Error("internal server error")
+			return nil, reservation.RefundCore{}, errors.New("internal server error")
+		}
+		refund.Reason = "cancelled by customer, credits returned under the venue cancellation policy"
+	} else if payment.Status == "success" {
+		paymentSubtotal := 0.0
+		if cancelPayment {
+			// Reservations made before subtotals were recorded fall back to the payment subtotal
+			paymentSubtotal = bookingSubtotal(payment)
+		}
+
+		refund.Amount, err = rs.refundAmount(cancelled, paymentSubtotal)
+		if err != nil {
+			log.Error("internal server error")
+			return nil, reservation.RefundCore{}, errors.New("internal server error")
+		}
+
+		refund.OrderID = orderID(target)
+		refund.Reason = "cancelled by customer, refunded under the venue cancellation policy"
+		refund, err = rs.refundOrder(refund)
+		if err != nil {
+			return nil, reservation.RefundCore{}, err
+		}
+	} else if payment.Status == "pending" && payment.PaymentMethod != reservation.PaymentTypeCredits {
+		// The open charge is voided first, once cancelled here its settlement would be rejected
+		if err := rs.payments.Cancel(orderID(target)); err != nil {
+			log.Sugar().Errorf("failed to cancel the charge of payment %s: %v", payment.PaymentID, err)
+			return nil, reservation.RefundCore{}, errors.New("failed to cancel payment")
+		}
+	}
+
+	ids := make([]string, len(cancelled))
+	for i, r := range cancelled {
+		ids[i] = r.ReservationID
+	}
+
+	if err := rs.query.CancelReservations(payment.PaymentID, ids, cancelPayment); err != nil {
+		log.Error("internal server error")
+		return nil, reservation.RefundCore{}, errors.New("internal server error")
+	}
+
+	if refund.Credits > 0 {
+		// The reservations are gone already, a failed refund is left for support to settle
+		if err := rs.query.RefundCredits(userId, target.VenueID, payment.PaymentID, refund.Credits); err != nil {
+			log.Sugar().Errorf("failed to refund %.2f credit hour(s) for payment %s", refund.Credits, payment.PaymentID)
+		}
+	}
+
+	log.Sugar().Infof("%d reservation(s) have been cancelled", len(cancelled))
+	rs.releaseSlots(cancelled)
+	return cancelled, refund, nil
 }
 
-// MyVenueCharts implements reservation.ReservationService.
-func (rs *reservationService) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time) ([]reservation.MyReservationCore, error) {
-	result, err := rs.query.MyVenueCharts(userId, keyword, checkInDate, checkOutDate)
+// orderID is the order a reservation was charged under, the series for a recurring booking.
+func orderID(r reservation.ReservationCore) string {
+	if r.SeriesID != "" {
+		return r.SeriesID
+	}
+	return r.ReservationID
+}
+
+// refundOrder sends refund.Amount back on the charge of refund.OrderID and records it, which
+// takes it back from the venue owner's payouts as well.
+func (rs *reservationService) refundOrder(refund reservation.RefundCore) (reservation.RefundCore, error) {
+	if refund.Amount <= 0 {
+		return refund, nil
+	}
+
+	if err := rs.payments.RefundTransaction(refund.OrderID, int64(refund.Amount), refund.Reason); err != nil {
+		log.Sugar().Errorf("failed to refund transaction: %s", err.Error())
+		return refund, errors.New("failed to refund transaction")
+	}
+
+	return rs.recordRefund(refund), nil
+}
+
+// refundAmount sums what each cancelled reservation gets back under its venue's cancellation
+// policy. Refunds are a percent of the booking subtotal, after any voucher discount; fees and
+// tax are never refunded. When none of them recorded a subtotal, the subtotal of the payment
+// is refunded at the rate of the first.
+func (rs *reservationService) refundAmount(cancelled []reservation.ReservationCore, paymentSubtotal float64) (float64, error) {
+	percents, err := rs.refundPercents(cancelled)
+	if err != nil {
+		return 0, err
+	}
+
+	amount, subtotal := 0.0, 0.0
+	for i, r := range cancelled {
+		amount += r.Subtotal * float64(percents[i]) / 100
+		subtotal += r.Subtotal
+	}
+
+	if subtotal == 0 && len(cancelled) > 0 {
+		amount = paymentSubtotal * float64(percents[0]) / 100
+	}
+
+	return amount, nil
+}
+
+// bookingSubtotal is what a payment charged for its bookings, its grand total less fees.
+// Payments made before subtotals were recorded carried no tax, so it is their subtotal.
+func bookingSubtotal(p reservation.PaymentCore) float64 {
+	grandTotal, _ := strconv.ParseFloat(p.GrandTotal, 64)
+	return grandTotal - p.ServiceFee
+}
+
+// refundCredits sums the prepaid hours each cancelled reservation gets back under its venue's
+// cancellation policy.
+func (rs *reservationService) refundCredits(cancelled []reservation.ReservationCore) (float64, error) {
+	percents, err := rs.refundPercents(cancelled)
+	if err != nil {
+		return 0, err
+	}
+
+	hours := 0.0
+	for i, r := range cancelled {
+		hours += r.Duration * float64(percents[i]) / 100
+	}
+
+	return math.Round(hours*100) / 100, nil
+}
+
+// refundPercents looks up the refund percent of each reservation, reading every venue's policy once.
+func (rs *reservationService) refundPercents(cancelled []reservation.ReservationCore) ([]int, error) {
+	policies := map[string][]reservation.CancellationTierCore{}
+	percents := make([]int, len(cancelled))
+	for i, r := range cancelled {
+		tiers, ok := policies[r.VenueID]
+		if !ok {
+			var err error
+			tiers, err = rs.query.GetCancellationPolicy(r.VenueID)
+			if err != nil {
+				return nil, err
+			}
+			policies[r.VenueID] = tiers
+		}
+
+		percents[i] = refundPercent(tiers, r.CheckInDate)
+	}
+
+	return percents, nil
+}
+
+// refundPercent picks the first tier, most generous first, whose notice period has not passed
+// yet. Venues without a policy refund in full up to an hour before check-in.
+func refundPercent(tiers []reservation.CancellationTierCore, checkInDate time.Time) int {
+	if len(tiers) == 0 {
+		tiers = []reservation.CancellationTierCore{{HoursBefore: defaultRefundNotice, RefundPercent: 100}}
+	}
+
+	notice := time.Until(checkInDate)
+	for _, t := range tiers {
+		if notice >= time.Duration(t.HoursBefore)*time.Hour {
+			return t.RefundPercent
+		}
+	}
+
+	return 0
+}
+
+// cancelSplit refunds the players of a cancelled split payment, returning how much went back.
+// Before every share is paid, the shares paid so far are refunded in full and the others voided.
+// Afterwards the refund the cancellation policy allows is shared out in proportion to what
+// each player paid.
+func (rs *reservationService) cancelSplit(payment reservation.PaymentCore, cancelled []reservation.ReservationCore) (float64, error) {
+	shares, err := rs.query.GetShares(payment.PaymentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if payment.Status == reservation.PaymentPending {
+		return rs.unwindShares(payment.PaymentID, shares, "cancelled by customer before every share was paid"), nil
+	}
+
+	amount, err := rs.refundAmount(cancelled, bookingSubtotal(payment))
+	if err != nil {
+		return 0, err
+	}
+
+	refunded, total := rs.refundShares(payment.PaymentID, shares, invoice.Rupiah(amount), "cancelled by customer, refunded under the venue cancellation policy")
+	rs.closeShares(refunded)
+	return total, nil
+}
+
+// unwindShares undoes the shares of a split payment that fell through: unpaid shares are
+// voided and paid ones refunded in full. It returns how much went back to the players.
+func (rs *reservationService) unwindShares(paymentId string, shares []reservation.ShareCore, reason string) float64 {
+	voided, paid := []reservation.ShareCore{}, []reservation.ShareCore{}
+	var amount int64
+	for _, share := range shares {
+		switch share.Charge.Status {
+		case reservation.PaymentPending:
+			if err := rs.payments.Cancel(share.ShareID); err != nil {
+				// Should the player still pay it, the share is refunded when its settlement comes in
+				log.Sugar().Warnf("failed to void share %s, it is left to expire unpaid: %v", share.ShareID, err)
+				continue
+			}
+			voided = append(voided, share)
+		case reservation.PaymentSuccess:
+			paid = append(paid, share)
+			amount += share.Amount
+		}
+	}
+
+	refunded, total := rs.refundShares(paymentId, paid, amount, reason)
+	rs.closeShares(append(voided, refunded...))
+	return total
+}
+
+// refundShares sends amount back to the players of a split payment in proportion to what each
+// paid, recording every refund against the split payment. It returns the shares refunded and
+// the total sent back; a refund the gateway refused is logged and left for support to settle.
+func (rs *reservationService) refundShares(paymentId string, shares []reservation.ShareCore, amount int64, reason string) ([]reservation.ShareCore, float64) {
+	var paid int64
+	for _, share := range shares {
+		paid += share.Amount
+	}
+
+	refunded := []reservation.ShareCore{}
+	total, left := 0.0, amount
+	for i, share := range shares {
+		part := left
+		if i < len(shares)-1 && paid > 0 {
+			part = amount * share.Amount / paid
+		}
+		left -= part
+
+		if part > 0 {
+			if err := rs.payments.RefundTransaction(share.ShareID, part, reason); err != nil {
+				log.Sugar().Errorf("failed to refund %d of share %s: %v", part, share.ShareID, err)
+				continue
+			}
+			rs.recordRefund(reservation.RefundCore{
+				PaymentID: paymentId,
+				OrderID:   share.ShareID,
+				Amount:    float64(part),
+				Reason:    reason,
+			})
+			total += float64(part)
+		}
+		refunded = append(refunded, share)
+	}
+
+	return refunded, total
+}
+
+// closeShares marks the charges of shares that were voided or refunded as cancelled.
+func (rs *reservationService) closeShares(shares []reservation.ShareCore) {
+	for _, share := range shares {
+		_, err := rs.query.ReservationStatus(reservation.PaymentCore{
+			PaymentID: share.Charge.PaymentID,
+			Status:    reservation.PaymentCancel,
+		})
+		if err != nil {
+			log.Sugar().Warnf("failed to mark the charge of share %s cancelled", share.ShareID)
+		}
+	}
+}
+
+// recordRefund keeps a record of money sent back to a customer. The refund itself has already
+// gone through at this point, so failing to record it is logged instead of reported.
+func (rs *reservationService) recordRefund(refund reservation.RefundCore) reservation.RefundCore {
+	if refund.Amount <= 0 {
+		return refund
+	}
+
+	recorded, err := rs.query.InsertRefund(refund)
+	if err != nil {
+		log.Sugar().Errorf("failed to record refund of %.2f for payment %s", refund.Amount, refund.PaymentID)
+		return refund
+	}
+
+	return recorded
+}
+
+// RescheduleReservation implements reservation.ReservationService.
+func (rs *reservationService) RescheduleReservation(userId string, reservationId string, checkInDate time.Time, checkOutDate time.Time, p reservation.PaymentCore) (reservation.RescheduleCore, error) {
+	var message string
+	if checkInDate.IsZero() {
+		message = "check_in_date cannot be empty"
+	} else if checkOutDate.IsZero() {
+		message = "check_out_date cannot be empty"
+	} else if !checkOutDate.After(checkInDate) {
+		message = "check_out_date must be after check_in_date"
+	}
+	if message != "" {
+		log.Warn(message)
+		return reservation.RescheduleCore{}, errors.New(message)
+	}
+
+	target, payment, err := rs.query.GetReservation(userId, reservationId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return reservation.RescheduleCore{}, errors.New("reservation not found")
+		}
+		log.Error("internal server error")
+		return reservation.RescheduleCore{}, errors.New("internal server error")
+	}
+
+	if payment.Status != "pending" && payment.Status != "success" {
+		log.Warn("reservation is no longer active")
+		return reservation.RescheduleCore{}, errors.New("reservation is no longer active")
+	}
+
+	// TODO 1 : Honour the venue's reschedule cutoff
+	venue, err := rs.query.GetVenue(target.VenueID)
+	if err != nil {
+		log.Sugar().Errorf("failed to get venue %s", target.VenueID)
+		return reservation.RescheduleCore{}, err
+	}
+
+	cutoff := rescheduleCutoff(venue)
+	if time.Until(target.CheckInDate) < cutoff {
+		log.Warn("reschedule cutoff has passed")
+		return reservation.RescheduleCore{}, fmt.Errorf("reservation cannot be rescheduled less than %.0f hour(s) before check-in", cutoff.Hours())
+	}
+
+	// TODO 2 : Validate the new slot with the same rules as a new booking
+	moved := target
+	moved.CheckInDate = checkInDate
+	moved.CheckOutDate = checkOutDate
+	if _, err := rs.checkSlot(userId, moved); err != nil {
+		return reservation.RescheduleCore{}, err
+	}
+
+	// TODO 3 : Reprice the reservation
+	price, rules, err := rs.venuePricing(target.VenueID)
+	if err != nil {
+		log.Sugar().Errorf("failed to get venue price %s", target.VenueID)
+		return reservation.RescheduleCore{}, err
+	}
+
+	q := quote(moved, price, rules)
+	moved.Duration = q.Duration
+	moved.Subtotal = q.Total
+	previous := target.Subtotal
+	if previous == 0 {
+		// Reservations made before subtotals were recorded are priced at today's rates
+		previous = quote(target, price, rules).Total
+	}
+	difference := moved.Subtotal - previous
+	if payment.PaymentMethod == reservation.PaymentTypeCredits {
+		// Prepaid hours do not follow the rates, only the length of the booking
+		if moved.Duration != target.Duration {
+			log.Warn("reservation paid with credits cannot change length")
+			return reservation.RescheduleCore{}, errors.New("reservations paid with credits can only be moved to a slot of the same length")
+		}
+		difference = 0
+	}
+
+	if difference != 0 && payment.Status != "success" {
+		log.Warn("unpaid reservation can only be moved to a slot of the same price")
+		return reservation.RescheduleCore{}, errors.New("reservation must be paid before its price can change")
+	}
+
+	charge := reservation.PaymentCore{}
+	if difference > 0 {
+		if p.PaymentType == "" {
+			log.Warn("payment_type cannot be empty")
+			return reservation.RescheduleCore{}, errors.New("payment_type cannot be empty")
+		}
+		// No booking fee, the reservation paid it already
+		rates := invoiceRates()
+		rates.BookingFee = 0
+		bill := invoice.Build(rates, difference, 0, "Reschedule price difference")
+		charge = p
+		charge.ServiceFee = float64(bill.Fees)
+		charge.Items = paymentItems(bill.Items)
+		charge.GrandTotal = strconv.FormatInt(bill.Total, 10)
+	}
+
+	// TODO 4 : Move the reservation, charging any extra cost as a payment of its own
+	rescheduleId := ""
+	if charge.GrandTotal != "" {
+		rescheduleId = helper.GenerateRescheduleID()
+		charge, err = rs.charge(rescheduleId, charge)
+		if err != nil {
+			return reservation.RescheduleCore{}, err
+		}
+	}
+
+	result, paymentResult, err := rs.query.RescheduleReservation(moved, charge)
+	if err != nil {
+		rs.voidCharge(rescheduleId, charge)
+		switch {
+		case strings.Contains(err.Error(), "reservation not available"):
+			log.Warn("new slot was taken by a concurrent reservation")
+			return reservation.RescheduleCore{}, errors.New("reservation not available")
+		case strings.Contains(err.Error(), "venue not found"):
+			return reservation.RescheduleCore{}, errors.New("venue not found")
+		default:
+			log.Error("internal server error")
+			return reservation.RescheduleCore{}, errors.New("internal server error")
+		}
+	}
+
+	// TODO 5 : Refund what a cheaper slot saves
+	if difference < 0 {
+		rs.refundDifference(target, payment, -difference)
+	}
+
+	log.Sugar().Infof("reservation %s has been rescheduled", result.ReservationID)
+	rs.releaseSlots([]reservation.ReservationCore{target})
+	return reservation.RescheduleCore{
+		Reservation:     result,
+		Payment:         paymentResult,
+		PriceDifference: difference,
+	}, nil
+}
+
+// refundDifference gives back what moving a paid reservation to a cheaper slot saves, shared out
+// among the players of a split payment. The reservation has moved already, so a refund that does
+// not go through is logged and left for support to settle.
+func (rs *reservationService) refundDifference(target reservation.ReservationCore, payment reservation.PaymentCore, amount float64) {
+	reason := "rescheduled by customer"
+	if payment.PaymentMethod == reservation.PaymentMethodSplit {
+		shares, err := rs.query.GetShares(payment.PaymentID)
+		if err != nil {
+			log.Sugar().Errorf("failed to get the shares of payment %s, %.2f was not refunded", payment.PaymentID, amount)
+			return
+		}
+		rs.refundShares(payment.PaymentID, shares, invoice.Rupiah(amount), reason)
+		return
+	}
+
+	_, err := rs.refundOrder(reservation.RefundCore{
+		PaymentID: payment.PaymentID,
+		OrderID:   orderID(target),
+		Amount:    amount,
+		Reason:    reason,
+	})
+	if err != nil {
+		log.Sugar().Errorf("reservation %s was rescheduled but %.2f was not refunded", target.ReservationID, amount)
+	}
+}
+
+func rescheduleCutoff(venue reservation.VenueCore) time.Duration {
+	if venue.RescheduleCutoff > 0 {
+		return time.Duration(venue.RescheduleCutoff) * time.Hour
+	}
+	return defaultRescheduleCutoff
+}
+
+// CreateHold implements reservation.ReservationService.
+func (rs *reservationService) CreateHold(userId string, request reservation.HoldCore) (reservation.HoldCore, error) {
+	var message string
+	if request.VenueID == "" {
+		message = "venue_id cannot be empty"
+	} else if request.CheckInDate.IsZero() {
+		message = "check_in_date cannot be empty"
+	} else if request.CheckOutDate.IsZero() {
+		message = "check_out_date cannot be empty"
+	}
+	if message != "" {
+		log.Warn(message)
+		return reservation.HoldCore{}, errors.New(message)
+	}
+
+	_, err := rs.checkSlot(userId, reservation.ReservationCore{
+		VenueID:      request.VenueID,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+	})
+	if err != nil {
+		return reservation.HoldCore{}, err
+	}
+
+	request.UserID = userId
+	result, err := rs.query.InsertHold(request, holdTTL())
+	if err != nil {
+		if strings.Contains(err.Error(), "slot is held by another user") {
+			return reservation.HoldCore{}, err
+		}
+		log.Error("internal server error")
+		return reservation.HoldCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// JoinWaitlist implements reservation.ReservationService.
+func (rs *reservationService) JoinWaitlist(userId string, request reservation.WaitlistCore) (reservation.WaitlistCore, error) {
+	var message string
+	if request.VenueID == "" {
+		message = "venue_id cannot be empty"
+	} else if request.CheckInDate.IsZero() {
+		message = "check_in_date cannot be empty"
+	} else if request.CheckOutDate.IsZero() {
+		message = "check_out_date cannot be empty"
+	}
+	if message != "" {
+		log.Warn(message)
+		return reservation.WaitlistCore{}, errors.New(message)
+	}
+
+	// Only a slot that is taken can be waited for
+	_, err := rs.checkSlot(userId, reservation.ReservationCore{
+		VenueID:      request.VenueID,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+	})
+	if err == nil {
+		log.Warn("requested slot is available")
+		return reservation.WaitlistCore{}, errors.New("reservation is available, no need to join the waitlist")
+	}
+	if !strings.Contains(err.Error(), "reservation not available") && !strings.Contains(err.Error(), "held by another user") {
+		return reservation.WaitlistCore{}, err
+	}
+
+	request.UserID = userId
+	result, err := rs.query.InsertWaitlist(request)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already on the waitlist"),
+			strings.Contains(err.Error(), "unregistered user"):
+			return reservation.WaitlistCore{}, err
+		default:
+			log.Error("internal server error")
+			return reservation.WaitlistCore{}, errors.New("internal server error")
+		}
+	}
+
+	log.Sugar().Infof("user %s joined the waitlist of venue %s", userId, request.VenueID)
+	return result, nil
+}
+
+// MyWaitlist implements reservation.ReservationService.
+func (rs *reservationService) MyWaitlist(userId string) ([]reservation.WaitlistCore, error) {
+	result, err := rs.query.MyWaitlist(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// LeaveWaitlist implements reservation.ReservationService.
+func (rs *reservationService) LeaveWaitlist(userId string, waitlistId string) error {
+	entry, err := rs.query.GetWaitlistEntry(userId, waitlistId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("waitlist entry not found")
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+
+	if entry.Status != reservation.WaitlistWaiting && entry.Status != reservation.WaitlistOffered {
+		log.Warn("waitlist entry is no longer active")
+		return errors.New("waitlist entry is no longer active")
+	}
+
+	offered := entry.Status == reservation.WaitlistOffered
+	entry.Status = reservation.WaitlistCancelled
+	if err := rs.query.UpdateWaitlist(entry); err != nil {
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+
+	// A declined offer moves on to the next in line
+	if offered {
+		if err := rs.query.DeleteHold(entry.VenueID, entry.HoldID); err != nil {
+			log.Sugar().Warnf("failed to release hold %s, it will expire on its own", entry.HoldID)
+			return nil
+		}
+		rs.offerWaitlist(entry.VenueID, entry.CheckInDate, entry.CheckOutDate)
+	}
+
+	return nil
+}
+
+// ExpireWaitlistOffers implements reservation.ReservationService.
+func (rs *reservationService) ExpireWaitlistOffers() ([]reservation.WaitlistCore, error) {
+	expired, err := rs.query.ExpireWaitlistOffers(time.Now())
+	if err != nil {
+		log.Sugar().Errorf("failed to expire waitlist offers: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	for _, w := range expired {
+		log.Sugar().Infof("waitlist offer %s was not claimed in time", w.WaitlistID)
+		rs.offerWaitlist(w.VenueID, w.CheckInDate, w.CheckOutDate)
+	}
+
+	return expired, nil
+}
+
+// releasePayment offers the slots of a payment that no longer stands to the waitlist.
+func (rs *reservationService) releasePayment(paymentId string) {
+	freed, err := rs.query.GetPaymentReservations(paymentId)
+	if err != nil {
+		log.Sugar().Warnf("failed to get reservations of payment %s, waitlist is not notified", paymentId)
+		return
+	}
+
+	rs.releaseSlots(freed)
+}
+
+// releaseSlots offers the slots of cancelled or expired reservations to the waitlist.
+func (rs *reservationService) releaseSlots(freed []reservation.ReservationCore) {
+	for _, r := range freed {
+		rs.offerWaitlist(r.VenueID, r.CheckInDate, r.CheckOutDate)
+	}
+}
+
+// offerWaitlist gives the users waiting for [start, end) a first claim on their slot, oldest
+// entry first. The claim is a hold in the user's name that lapses after the offer TTL. Entries
+// whose slot is still partly booked or already offered to someone else keep waiting.
+func (rs *reservationService) offerWaitlist(venueId string, start time.Time, end time.Time) []reservation.WaitlistCore {
+	entries, err := rs.query.GetWaitlist(venueId, start, end)
+	if err != nil {
+		log.Sugar().Warnf("failed to get waitlist of venue %s", venueId)
+		return nil
+	}
+
+	offered := []reservation.WaitlistCore{}
+	for _, entry := range entries {
+		taken, err := rs.query.GetReservationsByTimeSlot(venueId, entry.CheckInDate, entry.CheckOutDate)
+		if err != nil || len(taken) > 0 {
+			continue
+		}
+
+		hold, err := rs.query.InsertHold(reservation.HoldCore{
+			VenueID:      venueId,
+			UserID:       entry.UserID,
+			CheckInDate:  entry.CheckInDate,
+			CheckOutDate: entry.CheckOutDate,
+		}, waitlistOfferTTL())
+		if err != nil {
+			continue
+		}
+
+		entry.Status = reservation.WaitlistOffered
+		entry.HoldID = hold.HoldID
+		entry.OfferExpiresAt = hold.ExpiresAt
+		if err := rs.query.UpdateWaitlist(entry); err != nil {
+			if err := rs.query.DeleteHold(venueId, hold.HoldID); err != nil {
+				log.Sugar().Warnf("failed to release hold %s, it will expire on its own", hold.HoldID)
+			}
+			continue
+		}
+
+		rs.notifyWaitlist(entry)
+		offered = append(offered, entry)
+	}
+
+	return offered
+}
+
+// notifyWaitlist emails a waitlisted user that their slot is being held for them.
+func (rs *reservationService) notifyWaitlist(entry reservation.WaitlistCore) {
+	tmpl, err := template.ParseFiles(waitlistTemplate)
+	if err != nil {
+		log.Sugar().Errorf("failed to parse email template: %v", err)
+		return
+	}
+
+	data := struct {
+		Name         string
+		VenueName    string
+		CheckInDate  string
+		CheckOutDate string
+		ExpiresAt    string
+		HoldID       string
+	}{
+		Name:         entry.Fullname,
+		VenueName:    entry.VenueName,
+		CheckInDate:  entry.CheckInDate.Format("2006-01-02 15:04"),
+		CheckOutDate: entry.CheckOutDate.Format("2006-01-02 15:04"),
+		ExpiresAt:    entry.OfferExpiresAt.Format("2006-01-02 15:04"),
+		HoldID:       entry.HoldID,
+	}
+
+	var emailContent bytes.Buffer
+	if err := tmpl.Execute(&emailContent, data); err != nil {
+		log.Sugar().Errorf("failed to render email template: %v", err)
+		return
+	}
+
+	subject := "Your Waitlisted Slot at " + entry.VenueName + " Is Available"
+	to := []string{entry.Email}
+	if err := rs.email.SendEmail(subject, emailContent.String(), to, nil, nil, nil); err != nil {
+		log.Sugar().Errorf("failed to notify waitlist entry %s: %v", entry.WaitlistID, err)
+	}
+}
+
+func waitlistOfferTTL() time.Duration {
+	if config.WAITLIST_OFFER_TTL > 0 {
+		return time.Duration(config.WAITLIST_OFFER_TTL) * time.Minute
+	}
+	return defaultOfferTTL
+}
+
+// checkSlot verifies a venue can take a booking for [CheckInDate, CheckOutDate): within the
+// booking window, open, not closed, not booked and not held by another user. When r names a
+// hold it must be a live hold of the user covering the slot. It returns the user's own holds
+// overlapping the slot so they can be released once the booking is made.
+func (rs *reservationService) checkSlot(userId string, r reservation.ReservationCore) ([]reservation.HoldCore, error) {
+	minTime, maxTime := bookingWindow()
+	if r.CheckInDate.Before(minTime) || r.CheckInDate.After(maxTime) {
+		log.Warn("reservation date not within the allowed timewindow")
+		return nil, errors.New("reservation date not within the allowed timewindow")
+	}
+
+	if r.CheckOutDate.Before(minTime) || r.CheckOutDate.After(maxTime) {
+		log.Warn("reservation date not within the allowed timewindow")
+		return nil, errors.New("reservation date not within the allowed timewindow")
+	}
+
+	venue, err := rs.query.GetVenue(r.VenueID)
+	if err != nil {
+		log.Sugar().Errorf("failed to get venue %s", r.VenueID)
+		return nil, err
+	}
+
+	requested := schedule.Interval{Start: r.CheckInDate, End: r.CheckOutDate}
+	hours, err := weeklyHours(venue)
+	if err != nil {
+		// Legacy venues without parseable hours stay bookable around the clock.
+		log.Sugar().Warnf("skipping opening hours check of venue %s: %s", r.VenueID, err.Error())
+	} else if !hours.Covers(requested) {
+		log.Warn("reservation outside opening hours")
+		return nil, errors.New("reservation outside opening hours")
+	}
+
+	closures, err := rs.query.GetClosures(r.VenueID, r.CheckInDate, r.CheckOutDate)
+	if err != nil {
+		log.Sugar().Errorf("error on retrieving venue closures: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	if len(closures) > 0 {
+		log.Warn("venue is closed on the requested date")
+		return nil, errors.New("venue is closed on the requested date")
+	}
+
+	existingReservations, err := rs.query.GetReservationsByTimeSlot(r.VenueID, r.CheckInDate, r.CheckOutDate)
+	if err != nil {
+		log.Sugar().Errorf("error on retrieving existing reservations: %s", err.Error())
+		return nil, err
+	}
+
+	for _, e := range existingReservations {
+		// A reservation being rescheduled does not block its own new slot
+		if r.ReservationID == "" || e.ReservationID != r.ReservationID {
+			log.Warn("reservation not available for the specified time slot")
+			return nil, errors.New("reservation not available")
+		}
+	}
+
+	holds, err := rs.query.GetHolds(r.VenueID)
+	if err != nil {
+		log.Sugar().Errorf("error on retrieving holds: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	ownHolds := []reservation.HoldCore{}
+	holdFound := r.HoldID == ""
+	for _, h := range holds {
+		holdRange := schedule.Interval{Start: h.CheckInDate, End: h.CheckOutDate}
+		if h.HoldID == r.HoldID && h.UserID == userId && holdRange.Contains(requested) {
+			holdFound = true
+		}
+		if !holdRange.Overlaps(requested) {
+			continue
+		}
+		if h.UserID != userId {
+			log.Warn("slot is held by another user")
+			return nil, errors.New("slot is held by another user")
+		}
+		ownHolds = append(ownHolds, h)
+	}
+
+	if !holdFound {
+		log.Warn("hold not found or expired")
+		return nil, errors.New("hold not found or expired")
+	}
+
+	return ownHolds, nil
+}
+
+// QuoteReservation implements reservation.ReservationService.
+func (rs *reservationService) QuoteReservation(venueId string, checkInDate time.Time, checkOutDate time.Time) (reservation.QuoteCore, error) {
+	var message string
+	if checkInDate.IsZero() {
+		message = "check_in_date cannot be empty"
+	} else if checkOutDate.IsZero() {
+		message = "check_out_date cannot be empty"
+	} else if !checkOutDate.After(checkInDate) {
+		message = "check_out_date must be after check_in_date"
+	}
+	if message != "" {
+		log.Warn(message)
+		return reservation.QuoteCore{}, errors.New(message)
+	}
+
+	price, rules, err := rs.venuePricing(venueId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Error("venue not found")
+			return reservation.QuoteCore{}, errors.New("venue not found")
+		}
+		log.Error("internal server error")
+		return reservation.QuoteCore{}, errors.New("internal server error")
+	}
+
+	r := reservation.ReservationCore{VenueID: venueId, CheckInDate: checkInDate, CheckOutDate: checkOutDate}
+	return quote(r, price, rules), nil
+}
+
+// venuePricing reads the base hourly price of a venue along with its pricing rules.
+func (rs *reservationService) venuePricing(venueId string) (float64, []pricing.Rule, error) {
+	price, err := rs.query.PriceVenue(venueId)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	cores, err := rs.query.GetPricingRules(venueId)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rules := make([]pricing.Rule, 0, len(cores))
+	for _, c := range cores {
+		rule := pricing.Rule{
+			Name:        c.Name,
+			From:        c.StartDate,
+			Until:       c.EndDate,
+			MinDuration: time.Duration(c.MinDuration * float64(time.Hour)),
+			Rate:        c.Price,
+			Priority:    c.Priority,
+		}
+		for _, weekday := range c.Weekdays {
+			rule.Weekdays = append(rule.Weekdays, time.Weekday(weekday))
+		}
+		if c.StartTime != "" {
+			band, err := schedule.NewShift(c.StartTime, c.EndTime)
+			if err != nil {
+				log.Sugar().Warnf("skipping pricing rule %q of venue %s, invalid time band", c.Name, venueId)
+				continue
+			}
+			rule.Band = &band
+		}
+		rules = append(rules, rule)
+	}
+
+	return math.Round(price*100) / 100, rules, nil
+}
+
+// applyVoucher checks a voucher code against the reservation and works out its discount.
+// Usage caps are checked when the reservation is saved, under the voucher lock.
+func (rs *reservationService) applyVoucher(code string, r reservation.ReservationCore) (reservation.VoucherCore, float64, error) {
+	v, err := rs.query.GetVoucher(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if strings.Contains(err.Error(), "voucher not found") {
+			log.Warn("invalid voucher code")
+			return reservation.VoucherCore{}, 0, errors.New("invalid voucher code")
+		}
+		log.Error("failed to get voucher")
+		return reservation.VoucherCore{}, 0, errors.New("internal server error")
+	}
+
+	now := time.Now()
+	switch {
+	case !v.ValidFrom.IsZero() && now.Before(v.ValidFrom):
+		return reservation.VoucherCore{}, 0, errors.New("voucher is not valid yet")
+	case !v.ValidUntil.IsZero() && now.After(v.ValidUntil):
+		return reservation.VoucherCore{}, 0, errors.New("voucher has expired")
+	case v.VenueID != "" && v.VenueID != r.VenueID:
+		return reservation.VoucherCore{}, 0, errors.New("voucher does not apply to this venue")
+	}
+
+	if v.CategoryID != "" {
+		venue, err := rs.query.GetVenue(r.VenueID)
+		if err != nil {
+			log.Error("failed to get venue")
+			return reservation.VoucherCore{}, 0, errors.New("venue not found")
+		}
+		if venue.CategoryID != v.CategoryID {
+			return reservation.VoucherCore{}, 0, errors.New("voucher does not apply to this venue")
+		}
+	}
+
+	if r.Subtotal < v.MinSpend {
+		return reservation.VoucherCore{}, 0, fmt.Errorf("voucher requires a minimum spend of %.2f", v.MinSpend)
+	}
+
+	discount := v.DiscountValue
+	if v.DiscountType == reservation.DiscountPercentage {
+		discount = r.Subtotal * v.DiscountValue / 100
+		if v.MaxDiscount > 0 && discount > v.MaxDiscount {
+			discount = v.MaxDiscount
+		}
+	}
+	if discount > r.Subtotal {
+		discount = r.Subtotal
+	}
+
+	return v, math.Round(discount*100) / 100, nil
+}
+
+// quote prices a reservation by splitting it across the pricing rules it touches.
+func quote(r reservation.ReservationCore, price float64, rules []pricing.Rule) reservation.QuoteCore {
+	items := pricing.Quote(price, rules, schedule.Interval{Start: r.CheckInDate, End: r.CheckOutDate})
+	result := reservation.QuoteCore{
+		VenueID:      r.VenueID,
+		CheckInDate:  r.CheckInDate,
+		CheckOutDate: r.CheckOutDate,
+		Duration:     r.CheckOutDate.Sub(r.CheckInDate).Hours(),
+		Items:        make([]reservation.QuoteItemCore, len(items)),
+		Total:        pricing.Total(items),
+	}
+	for i, item := range items {
+		result.Items[i] = reservation.QuoteItemCore{
+			StartDate: item.Start,
+			EndDate:   item.End,
+			Rule:      item.Rule,
+			Rate:      item.Rate,
+			Hours:     item.Hours,
+			Amount:    item.Amount,
+		}
+	}
+
+	return result
+}
+
+// PaymentNotification implements reservation.ReservationService.
+func (rs *reservationService) PaymentNotification(body []byte) (reservation.PaymentCore, error) {
+	request, err := rs.payments.ParseWebhook(body)
+	if err != nil {
+		log.Sugar().Warnf("rejected payment notification: %v", err)
+		return reservation.PaymentCore{}, err
+	}
+
+	return rs.ReservationStatus(request)
+}
+
+// ReservationStatus implements reservation.ReservationService.
+func (rs *reservationService) ReservationStatus(request reservation.PaymentCore) (reservation.PaymentCore, error) {
+	// TODO 1 : Check the update against the payment we keep, it must be for the amount we charged
+	grandTotal, errConv := strconv.ParseFloat(request.GrandTotal, 64)
+	if errConv != nil {
+		log.Error("failed to parse grand total")
+		return request, errors.New("failed to parse grand total: " + errConv.Error())
+	}
+
+	stored, err := rs.query.GetPayment(request.PaymentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "payment not found") {
+			log.Warn("payment not found")
+			return reservation.PaymentCore{}, errors.New("payment not found")
+		}
+		log.Error("failed to get payment")
+		return reservation.PaymentCore{}, errors.New("internal server error")
+	}
+
+	storedTotal, errConv := strconv.ParseFloat(stored.GrandTotal, 64)
+	if errConv != nil || math.Abs(grandTotal-storedTotal) >= 0.01 {
+		log.Sugar().Warnf("gross amount %s does not match payment %s of %s", request.GrandTotal, stored.PaymentID, stored.GrandTotal)
+		return reservation.PaymentCore{}, errors.New("gross amount does not match the payment")
+	}
+
+	// TODO 2 : Read the status as ours, repeats are acknowledged and nothing moves backwards
+	status, known := paymentStatus(request.Status)
+	if !known {
+		log.Sugar().Infof("ignoring status %s of payment %s", request.Status, request.PaymentID)
+		return request, nil
+	}
+
+	if stored.Purpose == reservation.PaymentForShare {
+		return rs.shareStatus(stored, request, status)
+	}
+
+	if stored.Status == status {
+		log.Sugar().Infof("payment %s is already %s", request.PaymentID, status)
+		request.Status = status
+		return request, nil
+	}
+	if !reservation.CanTransition(stored.Status, status) {
+		log.Sugar().Warnf("payment %s cannot change from %s to %s", request.PaymentID, stored.Status, status)
+		return reservation.PaymentCore{}, fmt.Errorf("payment status cannot change from %s to %s", stored.Status, status)
+	}
+	request.Status = status
+
+	// TODO 3 : Apply the new status
+	switch status {
+	case reservation.PaymentSuccess:
+		res, err := rs.query.ReservationStatus(request)
+		if statusApplied(err) {
+			return request, nil
+		}
+		if err != nil {
+			log.Error("failed to update reservation status")
+			return res, errors.New("failed to update reservation status: " + err.Error())
+		}
+		rs.sendReceipt(request.PaymentID)
+
+	case reservation.PaymentCancel:
+		// Only a captured payment has money to give back, a pending one was never taken
+		if stored.Status == reservation.PaymentSuccess && !paymentgateway.IsRefundable(request.PaymentMethod) {
+			reservations, errQuery := rs.query.GetPaymentReservations(request.PaymentID)
+			if errQuery != nil {
+				return reservation.PaymentCore{}, errors.New("failed to get reservations: " + errQuery.Error())
+			}
+
+			amount, errPolicy := rs.refundAmount(reservations, bookingSubtotal(stored))
+			if errPolicy != nil {
+				return reservation.PaymentCore{}, errors.New("failed to get cancellation policy: " + errPolicy.Error())
+			}
+
+			refund := reservation.RefundCore{
+				PaymentID: request.PaymentID,
+				OrderID:   request.Reservation.ReservationID,
+				Amount:    amount,
+				Reason:    "payment cancelled, refunded under the venue cancellation policy",
+			}
+			if refund.Amount > 0 {
+				err := rs.payments.RefundTransaction(refund.OrderID, int64(refund.Amount), refund.Reason)
+				if err != nil {
+					log.Error("failed to refund transaction")
+					return request, errors.New("failed to refund transaction: " + err.Error())
+				}
+				rs.recordRefund(refund)
+			}
+		}
+
+		res, err := rs.query.ReservationStatus(request)
+		if statusApplied(err) {
+			return request, nil
+		}
+		if err != nil {
+			log.Error("failed to update status to cancel")
+			return res, errors.New("failed to update status to cancel: " + err.Error())
+		}
+		rs.releasePayment(request.PaymentID)
+
+	case reservation.PaymentExpire:
+		res, err := rs.query.ReservationStatus(request)
+		if statusApplied(err) {
+			return request, nil
+		}
+		if err != nil {
+			log.Error("error on updating status to expire")
+			return res, errors.New("error on updating status to expire: " + err.Error())
+		}
+		rs.releasePayment(request.PaymentID)
+	}
+
+	return request, nil
+}
+
+// statusApplied tells whether a status update found the payment there already, moved by a
+// concurrent notification for the same payment. The one that moved it applies the effects.
+func statusApplied(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "payment is already")
+}
+
+// shareStatus applies a status update to the charge of one share of a split payment, then
+// settles the split payment or lets it fall through. That second step runs on repeated updates
+// as well, so an update that failed halfway is finished when the gateway sends it again.
+func (rs *reservationService) shareStatus(stored reservation.PaymentCore, request reservation.PaymentCore, status string) (reservation.PaymentCore, error) {
+	request.Status = status
+	if stored.Status != status {
+		if !reservation.CanTransition(stored.Status, status) {
+			log.Sugar().Warnf("payment %s cannot change from %s to %s", request.PaymentID, stored.Status, status)
+			return reservation.PaymentCore{}, fmt.Errorf("payment status cannot change from %s to %s", stored.Status, status)
+		}
+		if _, err := rs.query.ReservationStatus(request); err != nil && !statusApplied(err) {
+			log.Error("failed to update share status")
+			return request, errors.New("failed to update share status: " + err.Error())
+		}
+	}
+
+	switch status {
+	case reservation.PaymentSuccess:
+		split, confirmed, err := rs.query.ConfirmSplitPayment(stored.ReferenceID)
+		if err != nil {
+			log.Error("failed to confirm split payment")
+			return request, errors.New("failed to confirm split payment: " + err.Error())
+		}
+		if confirmed {
+			log.Sugar().Infof("every share of split payment %s has been paid", split.PaymentID)
+			rs.sendReceipt(split.PaymentID)
+			return request, nil
+		}
+		if split.Status == reservation.PaymentPending || split.Status == reservation.PaymentSuccess || stored.Status == status {
+			return request, nil
+		}
+
+		// The split payment fell through before this share was paid, its player gets it back
+		shares, err := rs.query.GetShares(split.PaymentID)
+		if err != nil {
+			log.Error("failed to get payment shares")
+			return request, errors.New("failed to get payment shares: " + err.Error())
+		}
+		for _, share := range shares {
+			if share.Charge.PaymentID == stored.PaymentID {
+				refunded, _ := rs.refundShares(split.PaymentID, []reservation.ShareCore{share}, share.Amount, "share was paid after the split payment fell through")
+				rs.closeShares(refunded)
+			}
+		}
+
+	case reservation.PaymentCancel, reservation.PaymentExpire:
+		if err := rs.failSplit(stored.ReferenceID, "a share of the split payment was not paid"); err != nil {
+			return request, err
+		}
+	}
+
+	return request, nil
+}
+
+// failSplit lets a pending split payment fall through, releasing its slot and undoing its shares.
+func (rs *reservationService) failSplit(paymentId string, reason string) error {
+	split, shares, err := rs.query.FailSplitPayment(paymentId, reason)
+	if err != nil {
+		log.Error("failed to expire split payment")
+		return errors.New("failed to expire split payment: " + err.Error())
+	}
+	if shares == nil {
+		// It was settled or let go already
+		return nil
+	}
+
+	log.Sugar().Infof("split payment %s fell through, its slot is available again", split.PaymentID)
+	rs.unwindShares(split.PaymentID, shares, reason)
+	rs.releasePayment(split.PaymentID)
+	return nil
+}
+
+// paymentStatus reads a gateway transaction status as one of ours, false for those we do not act on.
+func paymentStatus(gatewayStatus string) (string, bool) {
+	switch gatewayStatus {
+	case "settlement":
+		return reservation.PaymentSuccess, true
+	case reservation.PaymentPending, reservation.PaymentSuccess, reservation.PaymentCancel, reservation.PaymentExpire:
+		return gatewayStatus, true
+	}
+	return gatewayStatus, false
+}
+
+// ReservationHistory implements reservation.ReservationService.
+func (rs *reservationService) MyReservation(userId string) ([]reservation.MyReservationCore, error) {
+	payments, err := rs.query.MyReservation(userId)
+	if err != nil {
+		if strings.Contains(err.Error(), "list reservations record not found") {
+			log.Error("list reservations record not found")
+			return []reservation.MyReservationCore{}, errors.New("list reservations record not found")
+		} else {
+			log.Error("internal server error")
+			return []reservation.MyReservationCore{}, errors.New("internal server error")
+		}
+	}
+
+	return payments, err
+}
+
+func (rs *reservationService) DetailTransaction(userId string, paymentId string) (reservation.PaymentCore, error) {
+	payment, err := rs.query.DetailTransaction(userId, paymentId)
+	if err != nil {
+		if strings.Contains(err.Error(), "payment not found") {
+			log.Error("payment not found")
+			return reservation.PaymentCore{}, errors.New("payment not found")
+		} else {
+			log.Error("internal server error")
+			return reservation.PaymentCore{}, err
+		}
+	}
+
+	// Payments made before invoices were itemized show their total as the booking
+	if len(payment.Items) == 0 {
+		grandTotal, err := strconv.ParseFloat(payment.GrandTotal, 64)
+		if err != nil {
+			log.Sugar().Warnf("payment %s has an unreadable grand total %q", paymentId, payment.GrandTotal)
+		}
+		payment.Items = paymentItems(invoice.Build(invoice.Rates{}, grandTotal, 0, "Venue booking").Items)
+	}
+
+	return payment, nil
+}
+
+// InvoicePDF implements reservation.ReservationService.
+func (rs *reservationService) InvoicePDF(userId string, paymentId string) ([]byte, error) {
+	customer, err := rs.query.GetPaymentCustomer(paymentId)
+	if err != nil {
+		if strings.Contains(err.Error(), "payment not found") {
+			log.Error("payment not found")
+			return nil, errors.New("payment not found")
+		}
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+	if customer.UserID != userId {
+		log.Sugar().Warnf("user %s requested the invoice of payment %s of another user", userId, paymentId)
+		return nil, errors.New("payment not found")
+	}
+
+	payment, err := rs.DetailTransaction(userId, paymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.renderReceipt(customer, payment)
+}
+
+// renderReceipt lays out the PDF receipt of a payment with the reservations it paid for.
+func (rs *reservationService) renderReceipt(customer reservation.CustomerCore, payment reservation.PaymentCore) ([]byte, error) {
+	reservations, err := rs.query.GetPaymentReservations(payment.PaymentID)
+	if err != nil {
+		log.Error("failed to get reservations")
+		return nil, errors.New("failed to get reservations: " + err.Error())
+	}
+	if len(reservations) == 0 && payment.Reservation.ReservationID != "" {
+		reservations = []reservation.ReservationCore{payment.Reservation}
+	}
+
+	receipt := invoice.Receipt{
+		Number:        payment.PaymentID,
+		IssuedAt:      payment.CreatedAt,
+		Customer:      customer.Fullname,
+		Status:        payment.Status,
+		PaymentMethod: payment.PaymentMethod,
+		PaymentType:   payment.PaymentType,
+		VenueName:     payment.Reservation.Venue.Name,
+		VenueLocation: payment.Reservation.Venue.Location,
+		Invoice:       paymentInvoice(payment.Items),
+	}
+	for _, r := range reservations {
+		receipt.Sessions = append(receipt.Sessions, invoice.Session{
+			CheckIn:  r.CheckInDate,
+			CheckOut: r.CheckOutDate,
+			Hours:    r.CheckOutDate.Sub(r.CheckInDate).Hours(),
+		})
+	}
+
+	pdf, err := invoice.RenderPDF(receipt)
+	if err != nil {
+		log.Sugar().Errorf("failed to render invoice of payment %s: %v", payment.PaymentID, err)
+		return nil, errors.New("failed to render invoice")
+	}
+
+	return pdf, nil
+}
+
+// sendReceipt emails the customer of a settled booking its confirmation with the PDF receipt attached.
+// Failures are only logged, the payment stays settled either way.
+func (rs *reservationService) sendReceipt(paymentId string) {
+	customer, err := rs.query.GetPaymentCustomer(paymentId)
+	if err != nil {
+		log.Sugar().Warnf("no customer to send the receipt of payment %s to: %v", paymentId, err)
+		return
+	}
+	payment, err := rs.DetailTransaction(customer.UserID, paymentId)
+	if err != nil {
+		log.Sugar().Errorf("failed to get payment %s for its receipt: %v", paymentId, err)
+		return
+	}
+	pdf, err := rs.renderReceipt(customer, payment)
+	if err != nil {
+		return
+	}
+
+	// SendEmail attaches files from disk, the receipt only lives there until it is sent
+	dir, err := os.MkdirTemp("", "receipt")
+	if err != nil {
+		log.Sugar().Errorf("failed to create receipt directory: %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	attachment := filepath.Join(dir, "receipt-"+paymentId+".pdf")
+	if err := os.WriteFile(attachment, pdf, 0o600); err != nil {
+		log.Sugar().Errorf("failed to write receipt: %v", err)
+		return
+	}
+
+	tmpl, err := template.ParseFiles(receiptTemplate)
+	if err != nil {
+		log.Sugar().Errorf("failed to parse email template: %v", err)
+		return
+	}
+
+	data := struct {
+		Name      string
+		VenueName string
+		PaymentID string
+		Total     string
+	}{
+		Name:      customer.Fullname,
+		VenueName: payment.Reservation.Venue.Name,
+		PaymentID: paymentId,
+		Total:     invoice.FormatRupiah(paymentInvoice(payment.Items).Total),
+	}
+
+	var emailContent bytes.Buffer
+	if err := tmpl.Execute(&emailContent, data); err != nil {
+		log.Sugar().Errorf("failed to render email template: %v", err)
+		return
+	}
+
+	subject := "Your Playground Pro Booking Is Confirmed"
+	to := []string{customer.Email}
+	if err := rs.email.SendEmail(subject, emailContent.String(), to, nil, nil, []string{attachment}); err != nil {
+		log.Sugar().Errorf("failed to send receipt of payment %s: %v", paymentId, err)
+	}
+}
+
+// invoiceRates reads the fees and tax charged on top of bookings.
+func invoiceRates() invoice.Rates {
+	return invoice.Rates{
+		PlatformFeePercent: config.PLATFORM_FEE_PERCENT,
+		BookingFee:         config.BOOKING_FEE,
+		TaxPercent:         config.TAX_PERCENT,
+	}
+}
+
+func paymentInvoice(items []reservation.PaymentItemCore) invoice.Invoice {
+	result := make([]invoice.Item, len(items))
+	for i, item := range items {
+		result[i] = invoice.Item{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return invoice.Summarize(result)
+}
+
+func paymentItems(items []invoice.Item) []reservation.PaymentItemCore {
+	result := make([]reservation.PaymentItemCore, len(items))
+	for i, item := range items {
+		result[i] = reservation.PaymentItemCore{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return result
+}
+
+// CheckAvailability implements reservation.ReservationService.
+func (rs *reservationService) CheckAvailability(venueId string) ([]reservation.AvailabilityCore, error) {
+	result, err := rs.query.CheckAvailability(venueId)
+	if err != nil {
+		if strings.Contains(err.Error(), "list venues record not found") {
+			log.Error("list venues record not found")
+			return []reservation.AvailabilityCore{}, errors.New("list venues record not found")
+		} else {
+			log.Error("internal server error")
+			return []reservation.AvailabilityCore{}, errors.New("internal server error")
+		}
+	}
+
+	log.Sugar().Info(result)
+
+	return result, nil
+}
+
+// AvailabilitySlots implements reservation.ReservationService. A zero slot length takes the
+// slot length of the venue category.
+func (rs *reservationService) AvailabilitySlots(venueId string, startDate time.Time, endDate time.Time, slotLength time.Duration) ([]reservation.DaySlotsCore, error) {
+	startDate = schedule.StartOfDay(startDate)
+	endDate = schedule.StartOfDay(endDate)
+	switch {
+	case slotLength != 0 && slotLength < minSlotLength:
+		log.Warn("slot length is too short")
+		return nil, errors.New("slot length must be at least 15 minutes")
+	case endDate.Before(startDate):
+		log.Warn("end date is before start date")
+		return nil, errors.New("end_date cannot be before start_date")
+	case endDate.Sub(startDate) > maxSlotDays*24*time.Hour:
+		log.Warn("requested date range is too wide")
+		return nil, errors.New("date range cannot exceed 31 days")
+	}
+
+	minTime, maxTime := bookingWindow()
+	if startDate.Before(schedule.StartOfDay(minTime)) || endDate.After(maxTime) {
+		log.Warn("reservation date not within the allowed timewindow")
+		return nil, errors.New("reservation date not within the allowed timewindow")
+	}
+
+	venue, err := rs.query.GetVenue(venueId)
+	if err != nil {
+		log.Sugar().Errorf("failed to get venue %s", venueId)
+		return nil, err
+	}
+
+	if slotLength == 0 {
+		slotLength = time.Duration(venue.SlotMinutes) * time.Minute
+		if slotLength < minSlotLength {
+			slotLength = defaultSlotLength
+		}
+	}
+
+	hours, err := weeklyHours(venue)
+	if err != nil {
+		log.Sugar().Errorf("failed to parse opening hours of venue %s: %s", venueId, err.Error())
+		return nil, errors.New("invalid venue service time")
+	}
+
+	// Overnight shifts of the last day close on the following day.
+	windowEnd := endDate.AddDate(0, 0, 2)
+	taken, err := rs.query.CheckAvailabilityByTimeWindow(venueId, startDate, windowEnd)
+	if err != nil {
+		log.Sugar().Errorf("error on retrieving existing reservations: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	closures, err := rs.query.GetClosures(venueId, startDate, windowEnd)
+	if err != nil {
+		log.Sugar().Errorf("error on retrieving venue closures: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	holds, err := rs.query.GetHolds(venueId)
+	if err != nil {
+		log.Sugar().Errorf("error on retrieving holds: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	days := []reservation.DaySlotsCore{}
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		slots := []reservation.SlotCore{}
+		for _, shift := range hours[day.Weekday()] {
+			for _, slot := range shift.On(day).Split(slotLength) {
+				if slot.Start.Before(minTime) || slot.End.After(maxTime) {
+					continue
+				}
+				status := slotStatus(slot, taken)
+				if status == reservation.SlotFree && held(slot, holds) {
+					status = reservation.SlotHeld
+				}
+				if closed(slot, closures) {
+					status = reservation.SlotClosed
+				}
+				slots = append(slots, reservation.SlotCore{
+					Start:  slot.Start,
+					End:    slot.End,
+					Status: status,
+				})
+			}
+		}
+
+		sort.Slice(slots, func(i, j int) bool {
+			return slots[i].Start.Before(slots[j].Start)
+		})
+		days = append(days, reservation.DaySlotsCore{Date: day, Slots: slots})
+	}
+
+	return days, nil
+}
+
+// bookingWindow returns the earliest and latest moment a reservation may cover.
+func bookingWindow() (time.Time, time.Time) {
+	minTime := time.Now().Local()
+	return minTime, minTime.AddDate(0, bookingWindowMonths, 0)
+}
+
+// weeklyHours returns the structured opening hours of a venue, falling back to
+// its free-form service time for venues that have not set them yet.
+func weeklyHours(venue reservation.VenueCore) (schedule.WeeklyHours, error) {
+	if len(venue.OpeningHours) == 0 {
+		shifts, err := schedule.ParseServiceTime(venue.ServiceTime)
+		if err != nil {
+			return nil, err
+		}
+		return schedule.Daily(shifts), nil
+	}
+
+	hours := schedule.WeeklyHours{}
+	for _, h := range venue.OpeningHours {
+		shift, err := schedule.NewShift(h.OpenTime, h.CloseTime)
+		if err != nil {
+			return nil, err
+		}
+		day := time.Weekday(h.Weekday)
+		hours[day] = append(hours[day], shift)
+	}
+
+	return hours, nil
+}
+
+// closed reports whether a slot overlaps any closure of the venue.
+func closed(slot schedule.Interval, closures []reservation.ClosureCore) bool {
+	for _, c := range closures {
+		if slot.Overlaps(schedule.Interval{Start: c.StartDate, End: c.EndDate}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// held reports whether a slot overlaps a live hold.
+func held(slot schedule.Interval, holds []reservation.HoldCore) bool {
+	for _, h := range holds {
+		if slot.Overlaps(schedule.Interval{Start: h.CheckInDate, End: h.CheckOutDate}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// holdTTL returns how long a hold keeps a slot, 10 minutes unless configured.
+func holdTTL() time.Duration {
+	if config.SLOT_HOLD_TTL > 0 {
+		return time.Duration(config.SLOT_HOLD_TTL) * time.Minute
+	}
+	return defaultHoldTTL
+}
+
+// slotStatus reports the strongest claim on a slot: a paid reservation wins over a pending one.
+func slotStatus(slot schedule.Interval, taken []reservation.AvailabilityCore) string {
+	status := reservation.SlotFree
+	for _, t := range taken {
+		if !slot.Overlaps(schedule.Interval{Start: t.CheckInDate, End: t.CheckOutDate}) {
+			continue
+		}
+		if t.Status == "success" {
+			return reservation.SlotBooked
+		}
+		status = reservation.SlotPending
+	}
+
+	return status
+}
+
+// ExpirePendingPayments implements reservation.ReservationService. Charges are cancelled at
+// the gateway before their payments are expired here.
+func (rs *reservationService) ExpirePendingPayments() ([]reservation.PaymentCore, error) {
+	grace := expiryGracePeriod()
+	reason := fmt.Sprintf("payment was not settled within %s after the charge expired", grace)
+	overdue, err := rs.query.GetOverduePayments(time.Now().Add(-grace))
+	if err != nil {
+		log.Sugar().Errorf("failed to get overdue payments: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	voided := []string{}
+	for _, p := range overdue {
+		if rs.voidOverdue(p) {
+			voided = append(voided, p.PaymentID)
+		}
+	}
+
+	expired, err := rs.query.ExpirePendingPayments(voided, reason)
+	if err != nil {
+		log.Sugar().Errorf("failed to expire pending payments: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	for _, p := range expired {
+		switch {
+		case p.Purpose == reservation.PaymentForShare:
+			// The split payment it is part of expired no later than its shares
+			continue
+		case p.PaymentMethod == reservation.PaymentMethodSplit:
+			shares, err := rs.query.GetShares(p.PaymentID)
+			if err != nil {
+				log.Sugar().Warnf("failed to get shares of split payment %s, they are left to expire", p.PaymentID)
+				break
+			}
+			rs.unwindShares(p.PaymentID, shares, reason)
+		}
+
+		log.Sugar().Infof("payment %s has expired, its slot is available again", p.PaymentID)
+		rs.releasePayment(p.PaymentID)
+	}
+
+	return expired, nil
+}
+
+// voidOverdue cancels the charge of an overdue payment at the gateway, so the customer can no
+// longer pay for a slot given up, and reports whether the payment may be expired. A charge the
+// gateway already closed may be; one it settled, or could not be reached about, is kept
+// pending for the reconciler to pick up.
+func (rs *reservationService) voidOverdue(p reservation.PaymentCore) bool {
+	if p.PaymentType == reservation.PaymentTypeCredits || p.PaymentMethod == reservation.PaymentMethodSplit {
+		// Nothing was charged at the gateway, the shares of a split payment are voided with it
+		return true
+	}
+
+	err := rs.payments.Cancel(p.PaymentID)
+	if err == nil {
+		return true
+	}
+
+	gateway, statusErr := rs.payments.Status(p.PaymentID)
+	if statusErr != nil {
+		if strings.Contains(statusErr.Error(), "payment not found") {
+			return true
+		}
+		log.Sugar().Warnf("failed to cancel the charge of overdue payment %s, it is kept pending: %v", p.PaymentID, err)
+		return false
+	}
+
+	switch status, _ := paymentStatus(gateway.Status); status {
+	case reservation.PaymentCancel, reservation.PaymentExpire:
+		return true
+	}
+	log.Sugar().Warnf("overdue payment %s is %s at the gateway, it is kept pending", p.PaymentID, gateway.Status)
+	return false
+}
+
+// ReconcilePayments implements reservation.ReservationService. Payments still pending a while
+// after they were charged are looked up at the gateway in case its notification got lost, and
+// moved along as the notification would have. Every payment the gateway had moved on from is
+// recorded for the discrepancy report, along with whether it could be settled here.
+func (rs *reservationService) ReconcilePayments() ([]reservation.DiscrepancyCore, error) {
+	stale, err := rs.query.GetStalePayments(time.Now().Add(-reconcileAfter()), reconcileBatchSize)
+	if err != nil {
+		log.Sugar().Errorf("failed to get stale payments: %s", err.Error())
+		return nil, errors.New("internal server error")
+	}
+
+	found := []reservation.DiscrepancyCore{}
+	for _, p := range stale {
+		gateway, err := rs.payments.Status(p.PaymentID)
+		if err != nil && !strings.Contains(err.Error(), "payment not found") {
+			// The lookup is tried again on the next run
+			log.Sugar().Warnf("failed to look up payment %s at the gateway: %v", p.PaymentID, err)
+			continue
+		}
+
+		discrepancy := reservation.DiscrepancyCore{
+			PaymentID:     p.PaymentID,
+			OrderID:       gateway.Reservation.ReservationID,
+			LocalStatus:   p.Status,
+			GatewayStatus: gateway.Status,
+			LocalAmount:   p.GrandTotal,
+			GatewayAmount: gateway.GrandTotal,
+		}
+		status, known := paymentStatus(gateway.Status)
+		switch {
+		case err != nil:
+			discrepancy.Detail = "payment not found at the gateway"
+		case status == p.Status:
+			// Still waiting for the customer at the gateway as well
+			continue
+		case !known:
+			discrepancy.Detail = "gateway status " + gateway.Status + " is not handled"
+		default:
+			gateway.PaymentID = p.PaymentID
+			if _, err := rs.ReservationStatus(gateway); err != nil {
+				discrepancy.Detail = err.Error()
+			} else {
+				discrepancy.Resolved = true
+				discrepancy.Detail = "payment moved to " + status
+			}
+		}
+
+		if discrepancy.Resolved {
+			log.Sugar().Infof("payment %s was %s at the gateway, applied", p.PaymentID, gateway.Status)
+		} else {
+			log.Sugar().Warnf("payment %s needs attention: %s", p.PaymentID, discrepancy.Detail)
+		}
+		recorded, err := rs.query.InsertDiscrepancy(discrepancy)
+		if err != nil {
+			log.Sugar().Errorf("failed to record discrepancy of payment %s: %v", p.PaymentID, err)
+			recorded = discrepancy
+		}
+		found = append(found, recorded)
+	}
+
+	return found, nil
+}
+
+// reconcileAfter returns how long a payment may stay pending before it is looked up at the gateway.
+func reconcileAfter() time.Duration {
+	if config.RECONCILE_AFTER > 0 {
+		return time.Duration(config.RECONCILE_AFTER) * time.Minute
+	}
+	return defaultReconcileAfter
+}
+
+// MyShares implements reservation.ReservationService.
+func (rs *reservationService) MyShares(userId string) ([]reservation.ShareCore, error) {
+	shares, err := rs.query.GetUserShares(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return shares, nil
+}
+
+// PaymentDiscrepancies implements reservation.ReservationService.
+func (rs *reservationService) PaymentDiscrepancies(userId string, unresolvedOnly bool) ([]reservation.DiscrepancyCore, error) {
+	role, err := rs.query.GetUserRole(userId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("user not found")
+		}
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+	if role != "admin" {
+		log.Warn("user is not allowed to view payment discrepancies")
+		return nil, errors.New("only admins can view payment discrepancies")
+	}
+
+	result, err := rs.query.GetDiscrepancies(unresolvedOnly)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// expiryGracePeriod returns how long past the charge expiry a pending payment is kept,
+// leaving room for a late Midtrans notification.
+func expiryGracePeriod() time.Duration {
+	if config.EXPIRY_GRACE_PERIOD > 0 {
+		return time.Duration(config.EXPIRY_GRACE_PERIOD) * time.Minute
+	}
+	return defaultExpiryGrace
+}
+
+// MyVenueCharts implements reservation.ReservationService.
+func (rs *reservationService) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time, groupBy string) ([]reservation.MyReservationCore, error) {
+	switch groupBy {
+	case "":
+		groupBy = reservation.ChartByVenue
+	case reservation.ChartByVenue, reservation.ChartByCategory:
+	default:
+		log.Warn("invalid group_by")
+		return []reservation.MyReservationCore{}, errors.New("invalid group_by, expected venue or category")
+	}
+
+	result, err := rs.query.MyVenueCharts(userId, keyword, checkInDate, checkOutDate, groupBy)
 	if err != nil {
 		if strings.Contains(err.Error(), "list charts record not found") {
 			log.Error("list charts record not found")
@@ -1,29 +1,33 @@
 package service
 
 import (
+	"bytes"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/features/reservation"
 	"github.com/playground-pro-project/playground-pro-api/mocks"
 	paymentgateway "github.com/playground-pro-project/playground-pro-api/utils/payment_gateway"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestMyVenueCharts(t *testing.T) {
 	data := mocks.NewReservationData(t)
-	refund := &paymentgateway.MyRefund{}
-	service := New(data, refund)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
 	userID := "user_id_1"
 	keyword := "keyword"
 	checkInDateStr := "2022-12-25 20:00:00"
 	checkOutDateStr := "2022-12-25 21:00:00"
 	checkInDate, _ := time.Parse("2006-01-02", checkInDateStr)
 	checkOutDate, _ := time.Parse("2006-01-02", checkOutDateStr)
-	request := reservation.MyReservationCore{
-		// Set the necessary fields for the request object
-	}
 
 	t.Run("success", func(t *testing.T) {
 		mockReservations := []reservation.MyReservationCore{
@@ -31,8 +35,8 @@ func TestMyVenueCharts(t *testing.T) {
 			{VenueID: "venue_id_2", VenueName: "Venue 2"},
 		}
 
-		data.On("MyVenueCharts", userID, keyword, request).Return(mockReservations, nil).Once()
-		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate)
+		data.On("MyVenueCharts", userID, keyword, checkInDate, checkOutDate, reservation.ChartByVenue).Return(mockReservations, nil).Once()
+		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate, "")
 		assert.Nil(t, err)
 		assert.Equal(t, mockReservations, result)
 		data.AssertExpectations(t)
@@ -40,8 +44,8 @@ func TestMyVenueCharts(t *testing.T) {
 
 	t.Run("list charts record not found", func(t *testing.T) {
 		mockError := errors.New("list charts record not found")
-		data.On("MyVenueCharts", userID, keyword, request).Return([]reservation.MyReservationCore{}, mockError).Once()
-		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate)
+		data.On("MyVenueCharts", userID, keyword, checkInDate, checkOutDate, reservation.ChartByVenue).Return([]reservation.MyReservationCore{}, mockError).Once()
+		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate, "")
 		assert.NotNil(t, err)
 		assert.ErrorContains(t, err, "list charts record not found")
 		assert.Equal(t, []reservation.MyReservationCore{}, result)
@@ -50,19 +54,40 @@ func TestMyVenueCharts(t *testing.T) {
 
 	t.Run("query error", func(t *testing.T) {
 		mockError := errors.New("internal server error")
-		data.On("MyVenueCharts", userID, keyword, request).Return([]reservation.MyReservationCore{}, mockError).Once()
-		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate)
+		data.On("MyVenueCharts", userID, keyword, checkInDate, checkOutDate, reservation.ChartByVenue).Return([]reservation.MyReservationCore{}, mockError).Once()
+		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate, "")
 		assert.NotNil(t, err)
 		assert.ErrorContains(t, err, "internal server error")
 		assert.Equal(t, []reservation.MyReservationCore{}, result)
 		data.AssertExpectations(t)
 	})
+
+	t.Run("success - grouped by category", func(t *testing.T) {
+		mockReservations := []reservation.MyReservationCore{
+			{Category: "futsal"},
+			{Category: "badminton"},
+		}
+
+		data.On("MyVenueCharts", userID, keyword, checkInDate, checkOutDate, reservation.ChartByCategory).Return(mockReservations, nil).Once()
+		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate, reservation.ChartByCategory)
+		assert.Nil(t, err)
+		assert.Equal(t, mockReservations, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid group_by", func(t *testing.T) {
+		result, err := service.MyVenueCharts(userID, keyword, checkInDate, checkOutDate, "month")
+		assert.EqualError(t, err, "invalid group_by, expected venue or category")
+		assert.Equal(t, []reservation.MyReservationCore{}, result)
+		data.AssertNotCalled(t, "MyVenueCharts", userID, keyword, checkInDate, checkOutDate, "month")
+	})
 }
 
 func TestCheckAvailability(t *testing.T) {
 	data := mocks.NewReservationData(t)
-	refund := &paymentgateway.MyRefund{}
-	service := New(data, refund)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
 	venueID := "venue_id_1"
 
 	t.Run("success", func(t *testing.T) {
@@ -117,8 +142,9 @@ func TestCheckAvailability(t *testing.T) {
 
 func TestDetailTransaction(t *testing.T) {
 	data := mocks.NewReservationData(t)
-	refund := &paymentgateway.MyRefund{}
-	service := New(data, refund)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
 	userID := "user_id_1"
 	paymentID := "payment_id_1"
 
@@ -138,6 +164,29 @@ func TestDetailTransaction(t *testing.T) {
 			Venue:         reservation.VenueCore{},
 		}
 
+		data.On("DetailTransaction", userID, paymentID).Return(mockPayment, nil).Once()
+		result, err := service.DetailTransaction(userID, paymentID)
+		assert.Nil(t, err)
+		// Payments without stored items show their total as the booking
+		expected := mockPayment
+		expected.Items = []reservation.PaymentItemCore{{Kind: "booking", Description: "Venue booking", Amount: 100}}
+		assert.Equal(t, expected, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - itemized invoice", func(t *testing.T) {
+		mockPayment := reservation.PaymentCore{
+			PaymentID:  "payment_id_1",
+			GrandTotal: "232.00",
+			ServiceFee: 9,
+			Items: []reservation.PaymentItemCore{
+				{Kind: "booking", Description: "Venue booking", Amount: 200},
+				{Kind: "platform_fee", Description: "Platform fee", Amount: 4},
+				{Kind: "booking_fee", Description: "Booking fee", Amount: 5},
+				{Kind: "tax", Description: "VAT", Amount: 23},
+			},
+		}
+
 		data.On("DetailTransaction", userID, paymentID).Return(mockPayment, nil).Once()
 		result, err := service.DetailTransaction(userID, paymentID)
 		assert.Nil(t, err)
@@ -166,10 +215,71 @@ func TestDetailTransaction(t *testing.T) {
 	})
 }
 
+func TestInvoicePDF(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userID := "user_id_1"
+	paymentID := "payment_id_1"
+	checkIn := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
+	booked := reservation.ReservationCore{
+		ReservationID: "reservation_id_1",
+		CheckInDate:   checkIn,
+		CheckOutDate:  checkIn.Add(2 * time.Hour),
+		Venue:         reservation.VenueCore{Name: "Lapangan Futsal Ceria", Location: "Jl. Merdeka 1, Bandung"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		payment := reservation.PaymentCore{
+			PaymentID:     paymentID,
+			PaymentMethod: "bank_transfer",
+			PaymentType:   "bca",
+			GrandTotal:    "232",
+			Status:        "success",
+			CreatedAt:     checkIn.Add(-24 * time.Hour),
+			Items: []reservation.PaymentItemCore{
+				{Kind: "booking", Description: "Venue booking", Amount: 200},
+				{Kind: "platform_fee", Description: "Platform fee", Amount: 4},
+				{Kind: "booking_fee", Description: "Booking fee", Amount: 5},
+				{Kind: "tax", Description: "VAT", Amount: 23},
+			},
+			Reservation: booked,
+		}
+		data.On("GetPaymentCustomer", paymentID).Return(reservation.CustomerCore{UserID: userID, Fullname: "Jane Doe"}, nil).Once()
+		data.On("DetailTransaction", userID, paymentID).Return(payment, nil).Once()
+		data.On("GetPaymentReservations", paymentID).Return([]reservation.ReservationCore{booked}, nil).Once()
+
+		pdf, err := service.InvoicePDF(userID, paymentID)
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - payment of another user", func(t *testing.T) {
+		data.On("GetPaymentCustomer", paymentID).Return(reservation.CustomerCore{UserID: "user_id_2"}, nil).Once()
+
+		pdf, err := service.InvoicePDF(userID, paymentID)
+		assert.EqualError(t, err, "payment not found")
+		assert.Nil(t, pdf)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - payment not found", func(t *testing.T) {
+		data.On("GetPaymentCustomer", paymentID).Return(reservation.CustomerCore{}, errors.New("payment not found")).Once()
+
+		pdf, err := service.InvoicePDF(userID, paymentID)
+		assert.EqualError(t, err, "payment not found")
+		assert.Nil(t, pdf)
+		data.AssertExpectations(t)
+	})
+}
+
 func TestMyReservation(t *testing.T) {
 	data := mocks.NewReservationData(t)
-	refund := &paymentgateway.MyRefund{}
-	service := New(data, refund)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
 	userID := "user_id_1"
 
 	t.Run("success", func(t *testing.T) {
@@ -222,8 +332,11 @@ func TestMyReservation(t *testing.T) {
 
 func TestReservationStatus(t *testing.T) {
 	data := mocks.NewReservationData(t)
-	refund := &mocks.Refund{}
-	service := New(data, refund)
+	payments := &mocks.PaymentProvider{}
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	pending := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "pending", GrandTotal: "150.00"}
+	paid := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "success", GrandTotal: "150.00"}
 
 	t.Run("success - expire", func(t *testing.T) {
 		request := reservation.PaymentCore{
@@ -236,7 +349,9 @@ func TestReservationStatus(t *testing.T) {
 			GrandTotal: "150.0",
 		}
 
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
 		data.On("ReservationStatus", request).Return(request, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return([]reservation.ReservationCore{}, nil).Once()
 
 		result, err := service.ReservationStatus(request)
 		assert.Nil(t, err)
@@ -261,7 +376,7 @@ func TestReservationStatus(t *testing.T) {
 		assert.Equal(t, request, result)
 	})
 
-	t.Run("error - failed to get checkout date", func(t *testing.T) {
+	t.Run("error - failed to get reservations", func(t *testing.T) {
 		request := reservation.PaymentCore{
 			PaymentID:     "payment_id_1",
 			PaymentMethod: "method",
@@ -272,16 +387,17 @@ func TestReservationStatus(t *testing.T) {
 			GrandTotal: "150.0",
 		}
 
-		data.On("ReservationCheckOutDate", request.Reservation.ReservationID).Return(time.Time{}, errors.New("database error")).Once()
+		data.On("GetPayment", request.PaymentID).Return(paid, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return(nil, errors.New("database error")).Once()
 
 		result, err := service.ReservationStatus(request)
 		assert.Error(t, err)
-		assert.Equal(t, "failed to get checkout date: database error", err.Error())
+		assert.Equal(t, "failed to get reservations: database error", err.Error())
 		assert.Equal(t, reservation.PaymentCore{}, result)
 		data.AssertExpectations(t)
 	})
 
-	t.Run("error - refund cannot be processed at least 1 hour away", func(t *testing.T) {
+	t.Run("success - cancel without refund in the last tier", func(t *testing.T) {
 		request := reservation.PaymentCore{
 			PaymentID:     "payment_id_1",
 			PaymentMethod: "method",
@@ -292,14 +408,75 @@ func TestReservationStatus(t *testing.T) {
 			GrandTotal: "150.0",
 		}
 
-		mockCheckOutDate := time.Now().Add(time.Minute * 30)
-		data.On("ReservationCheckOutDate", request.Reservation.ReservationID).Return(mockCheckOutDate, nil).Once()
+		reservations := []reservation.ReservationCore{{ReservationID: "reservation_id_1", VenueID: "venue_id_1", CheckInDate: time.Now().Add(time.Minute * 30)}}
+		data.On("GetPayment", request.PaymentID).Return(paid, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return(reservations, nil).Twice()
+		data.On("GetCancellationPolicy", "venue_id_1").Return([]reservation.CancellationTierCore{}, nil).Once()
+		data.On("ReservationStatus", request).Return(request, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
 
 		result, err := service.ReservationStatus(request)
-		assert.Error(t, err)
-		assert.Equal(t, "refund cannot be processed at least 1 hour away", err.Error())
-		assert.Equal(t, request, result)
+		assert.Nil(t, err)
+		assert.Equal(t, "cancel", result.Status)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - pending payment is cancelled without a refund", func(t *testing.T) {
+		request := reservation.PaymentCore{
+			PaymentID:     "payment_id_1",
+			PaymentMethod: "method",
+			Status:        "cancel",
+			Reservation: reservation.ReservationCore{
+				ReservationID: "reservation_id_1",
+			},
+			GrandTotal: "150.0",
+		}
+
+		reservations := []reservation.ReservationCore{{ReservationID: "reservation_id_1", VenueID: "venue_id_1", CheckInDate: time.Now().Add(time.Hour * 48)}}
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
+		data.On("ReservationStatus", request).Return(request, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return(reservations, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "cancel", result.Status)
+		data.AssertExpectations(t)
+		payments.AssertNotCalled(t, "RefundTransaction", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("success - paid payment is cancelled with a refund", func(t *testing.T) {
+		request := reservation.PaymentCore{
+			PaymentID:     "payment_id_1",
+			PaymentMethod: "method",
+			Status:        "cancel",
+			Reservation: reservation.ReservationCore{
+				ReservationID: "reservation_id_1",
+			},
+			GrandTotal: "150.0",
+		}
+		refund := reservation.RefundCore{
+			PaymentID: "payment_id_1",
+			OrderID:   "reservation_id_1",
+			Amount:    150,
+			Reason:    "payment cancelled, refunded under the venue cancellation policy",
+		}
+
+		reservations := []reservation.ReservationCore{{ReservationID: "reservation_id_1", VenueID: "venue_id_1", CheckInDate: time.Now().Add(time.Hour * 48)}}
+		data.On("GetPayment", request.PaymentID).Return(paid, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return(reservations, nil).Twice()
+		data.On("GetCancellationPolicy", "venue_id_1").Return([]reservation.CancellationTierCore{}, nil).Once()
+		payments.On("RefundTransaction", "reservation_id_1", int64(150), refund.Reason).Return(nil).Once()
+		data.On("InsertRefund", refund).Return(refund, nil).Once()
+		data.On("ReservationStatus", request).Return(request, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "cancel", result.Status)
 		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
 	})
 
 	t.Run("error - failed to refund transaction", func(t *testing.T) {
@@ -313,16 +490,18 @@ func TestReservationStatus(t *testing.T) {
 			GrandTotal: "150.0",
 		}
 
-		mockCheckOutDate := time.Now().Add(time.Hour * 2)
-		data.On("ReservationCheckOutDate", request.Reservation.ReservationID).Return(mockCheckOutDate, nil).Once()
-		refund.On("RefundTransaction", request.Reservation.ReservationID, int64(150.0), "reason").Return(errors.New("refund error")).Once()
+		reservations := []reservation.ReservationCore{{ReservationID: "reservation_id_1", VenueID: "venue_id_1", CheckInDate: time.Now().Add(time.Hour * 2)}}
+		data.On("GetPayment", request.PaymentID).Return(paid, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return(reservations, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return([]reservation.CancellationTierCore{}, nil).Once()
+		payments.On("RefundTransaction", request.Reservation.ReservationID, int64(150.0), "payment cancelled, refunded under the venue cancellation policy").Return(errors.New("refund error")).Once()
 
 		result, err := service.ReservationStatus(request)
 		assert.Error(t, err)
 		assert.Equal(t, "failed to refund transaction: refund error", err.Error())
 		assert.Equal(t, request, result)
 		data.AssertExpectations(t)
-		refund.AssertExpectations(t)
+		payments.AssertExpectations(t)
 	})
 
 	t.Run("error - error on updating status to expire", func(t *testing.T) {
@@ -336,6 +515,7 @@ func TestReservationStatus(t *testing.T) {
 			GrandTotal: "150.0",
 		}
 
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
 		data.On("ReservationStatus", request).Return(reservation.PaymentCore{}, errors.New("database error")).Once()
 		result, err := service.ReservationStatus(request)
 		assert.Error(t, err)
@@ -354,58 +534,307 @@ func TestReservationStatus(t *testing.T) {
 			},
 			GrandTotal: "150.0",
 		}
-		data.On("ReservationStatus", request).Return(reservation.PaymentCore{
-			PaymentID:     "payment_id_1",
-			PaymentMethod: "method",
-			Status:        "success",
+		settled := request
+		settled.Status = "success"
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
+		data.On("ReservationStatus", settled).Return(settled, nil).Once()
+		data.On("GetPaymentCustomer", request.PaymentID).Return(reservation.CustomerCore{}, errors.New("payment not found")).Once()
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "success", result.Status)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - repeated settlement is acknowledged once", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "settlement", GrandTotal: "150.00"}
+		data.On("GetPayment", request.PaymentID).Return(paid, nil).Once()
+
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "success", result.Status)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - settlement saved by a concurrent notification", func(t *testing.T) {
+		data := mocks.NewReservationData(t)
+		service := New(data, payments, email)
+		request := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "settlement", GrandTotal: "150.00"}
+		settled := request
+		settled.Status = "success"
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
+		data.On("ReservationStatus", settled).Return(reservation.PaymentCore{}, errors.New("payment is already success")).Once()
+
+		// The receipt is left to the notification that saved the settlement
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "success", result.Status)
+		data.AssertExpectations(t)
+		data.AssertNotCalled(t, "GetPaymentCustomer", request.PaymentID)
+	})
+
+	t.Run("error - payment expired by a concurrent update", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "settlement", GrandTotal: "150.00"}
+		settled := request
+		settled.Status = "success"
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
+		data.On("ReservationStatus", settled).Return(reservation.PaymentCore{}, errors.New("payment status cannot change from expire to success")).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.ErrorContains(t, err, "payment status cannot change from expire to success")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - status cannot move backwards", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "pending", GrandTotal: "150.00"}
+		data.On("GetPayment", request.PaymentID).Return(paid, nil).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.EqualError(t, err, "payment status cannot change from success to pending")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - expired payment cannot settle", func(t *testing.T) {
+		expired := pending
+		expired.Status = "expire"
+		request := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "settlement", GrandTotal: "150.00"}
+		data.On("GetPayment", request.PaymentID).Return(expired, nil).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.EqualError(t, err, "payment status cannot change from expire to success")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - gross amount does not match", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "settlement", GrandTotal: "1.00"}
+		data.On("GetPayment", request.PaymentID).Return(pending, nil).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.EqualError(t, err, "gross amount does not match the payment")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - payment not found", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "payment_id_9", Status: "settlement", GrandTotal: "150.00"}
+		data.On("GetPayment", request.PaymentID).Return(reservation.PaymentCore{}, errors.New("payment not found")).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.EqualError(t, err, "payment not found")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestPaymentNotification(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	body := []byte(`{"order_id":"reservation_id_1","transaction_status":"expire"}`)
+
+	t.Run("success", func(t *testing.T) {
+		request := reservation.PaymentCore{
+			PaymentID: "payment_id_1",
+			Status:    "expire",
 			Reservation: reservation.ReservationCore{
 				ReservationID: "reservation_id_1",
 			},
-			GrandTotal: "150.0",
-		}, nil).Once()
-		result, err := service.ReservationStatus(request)
+			GrandTotal: "150.00",
+		}
+		payments.On("ParseWebhook", body).Return(request, nil).Once()
+		data.On("GetPayment", request.PaymentID).Return(reservation.PaymentCore{PaymentID: "payment_id_1", Status: "pending", GrandTotal: "150.00"}, nil).Once()
+		data.On("ReservationStatus", request).Return(request, nil).Once()
+		data.On("GetPaymentReservations", request.PaymentID).Return([]reservation.ReservationCore{}, nil).Once()
+
+		result, err := service.PaymentNotification(body)
 		assert.Nil(t, err)
-		assert.Equal(t, "success", result.Status)
+		assert.Equal(t, "expire", result.Status)
 		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid signature key", func(t *testing.T) {
+		payments.On("ParseWebhook", body).Return(reservation.PaymentCore{}, errors.New("invalid signature key")).Once()
+
+		_, err := service.PaymentNotification(body)
+		assert.EqualError(t, err, "invalid signature key")
+		payments.AssertExpectations(t)
+	})
+}
+
+// pendingCharge answers a charge the way the provider does, waiting for a bank transfer
+func pendingCharge(orderId string, p reservation.PaymentCore) (reservation.PaymentCore, error) {
+	p.PaymentID = "payment_id_1"
+	p.PaymentMethod = "bank_transfer"
+	p.PaymentCode = "88080000000001"
+	p.Status = "pending"
+	return p, nil
+}
+
+// charged is p once the provider took it
+func charged(p reservation.PaymentCore) reservation.PaymentCore {
+	p, _ = pendingCharge("", p)
+	return p
+}
+
+// bookedAs matches r as the service books it, under the reservation ID it generated
+func bookedAs(r reservation.ReservationCore) interface{} {
+	return mock.MatchedBy(func(got reservation.ReservationCore) bool {
+		r.ReservationID = got.ReservationID
+		return got.ReservationID != "" && assert.ObjectsAreEqual(r, got)
 	})
 }
 
 func TestMakeReservation(t *testing.T) {
 	data := mocks.NewReservationData(t)
-	refund := &paymentgateway.MyRefund{}
-	service := New(data, refund)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
 	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	checkIn := day.Add(9 * time.Hour)
+	checkOut := day.Add(11 * time.Hour)
+	venue := reservation.VenueCore{VenueID: "venue_id_1", ServiceTime: "07:00 - 23:00"}
+	reservationCore := reservation.ReservationCore{
+		VenueID:      "venue_id_1",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+	}
+	paymentCore := reservation.PaymentCore{}
+	// What the service hands to the data layer after pricing 2 hours at 100
+	pricedReservation := reservationCore
+	pricedReservation.Duration = 2
+	pricedReservation.Subtotal = 200
+	pricedPayment := reservation.PaymentCore{
+		GrandTotal: "200",
+		Items:      []reservation.PaymentItemCore{{Kind: "booking", Description: "Venue booking", Amount: 200}},
+	}
 
 	t.Run("success", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		var orderId string
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Run(func(args mock.Arguments) {
+			orderId = args.String(0)
+		}).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(func(userId string, r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
+			return r, p, nil
+		}).Once()
+
+		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Nil(t, err)
+		assert.Equal(t, orderId, result.ReservationID)
+		assert.Equal(t, charged(pricedPayment), paymentResult)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
 
-		// Mock the necessary methods
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+	t.Run("error - charge failed", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
 		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
-		data.On("MakeReservation", userId, reservationCore, paymentCore).Return(reservationCore, paymentCore, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(reservation.PaymentCore{}, errors.New("connection reset")).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.EqualError(t, err, "internal server error while charging payment")
+		assert.Equal(t, reservation.ReservationCore{}, result)
+		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - charge is voided when the booking fails", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		var orderId string
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Run(func(args mock.Arguments) {
+			orderId = args.String(0)
+		}).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("reservation not available")).Once()
+		payments.On("Cancel", mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, _, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.EqualError(t, err, "reservation not available")
+		payments.AssertCalled(t, "Cancel", orderId)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - fees and tax are itemized", func(t *testing.T) {
+		config.PLATFORM_FEE_PERCENT, config.BOOKING_FEE, config.TAX_PERCENT = 2, 5, 11
+		defer func() {
+			config.PLATFORM_FEE_PERCENT, config.BOOKING_FEE, config.TAX_PERCENT = 0, 0, 0
+		}()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		// VAT is 11% of 209, rounded to the rupiah
+		billed := reservation.PaymentCore{
+			GrandTotal: "232",
+			ServiceFee: 9,
+			Items: []reservation.PaymentItemCore{
+				{Kind: "booking", Description: "Venue booking", Amount: 200},
+				{Kind: "platform_fee", Description: "Platform fee", Amount: 4},
+				{Kind: "booking_fee", Description: "Booking fee", Amount: 5},
+				{Kind: "tax", Description: "VAT", Amount: 23},
+			},
+		}
+		payments.On("Charge", mock.AnythingOfType("string"), billed).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(billed)).Return(pricedReservation, charged(billed), nil).Once()
+
+		_, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Nil(t, err)
+		assert.Equal(t, charged(billed), paymentResult)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - paid with credits", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		creditPayment := reservation.PaymentCore{PaymentType: reservation.PaymentTypeCredits, GrandTotal: "0", Credits: 2}
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), creditPayment).Return(pricedReservation, creditPayment, nil).Once()
+
+		_, paymentResult, err := service.MakeReservation(userId, reservationCore, reservation.PaymentCore{PaymentType: reservation.PaymentTypeCredits})
 		assert.Nil(t, err)
-		assert.Equal(t, reservationCore, result)
-		assert.Equal(t, paymentCore, paymentResult)
+		assert.Equal(t, 2.0, paymentResult.Credits)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - insufficient credits", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		data.On("MakeReservation", userId, mock.Anything, mock.Anything).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("insufficient credits")).Once()
+
+		_, _, err := service.MakeReservation(userId, reservationCore, reservation.PaymentCore{PaymentType: reservation.PaymentTypeCredits})
+		assert.EqualError(t, err, "insufficient credits")
 		data.AssertExpectations(t)
 	})
 
 	t.Run("error - venue_id is empty", func(t *testing.T) {
-		userId := "user_id_1"
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
+		request := reservationCore
+		request.VenueID = ""
 
-		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		result, paymentResult, err := service.MakeReservation(userId, request, paymentCore)
 
 		assert.Error(t, err)
 		assert.Equal(t, "venue_id cannot be empty", err.Error())
@@ -414,15 +843,10 @@ func TestMakeReservation(t *testing.T) {
 	})
 
 	t.Run("error - check_in_date is empty", func(t *testing.T) {
-		userId := "user_id_1"
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Time{},
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
+		request := reservationCore
+		request.CheckInDate = time.Time{}
 
-		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		result, paymentResult, err := service.MakeReservation(userId, request, paymentCore)
 
 		assert.Error(t, err)
 		assert.Equal(t, "check_in_date cannot be empty", err.Error())
@@ -431,15 +855,10 @@ func TestMakeReservation(t *testing.T) {
 	})
 
 	t.Run("error - check_out_date is empty", func(t *testing.T) {
-		userId := "user_id_1"
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Time{},
-		}
-		paymentCore := reservation.PaymentCore{}
+		request := reservationCore
+		request.CheckOutDate = time.Time{}
 
-		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		result, paymentResult, err := service.MakeReservation(userId, request, paymentCore)
 
 		assert.Error(t, err)
 		assert.Equal(t, "check_out_date cannot be empty", err.Error())
@@ -448,31 +867,79 @@ func TestMakeReservation(t *testing.T) {
 	})
 
 	t.Run("error - reservation date not within the allowed timewindow", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, -1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
+		request := reservationCore
+		request.CheckInDate = time.Now().AddDate(0, 0, -1)
+
+		result, paymentResult, err := service.MakeReservation(userId, request, paymentCore)
+
+		assert.Error(t, err)
+		assert.Equal(t, "reservation date not within the allowed timewindow", err.Error())
+		assert.Equal(t, reservation.ReservationCore{}, result)
+		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+	})
+
+	t.Run("error - venue not found", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(reservation.VenueCore{}, errors.New("venue not found")).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
 		assert.Error(t, err)
-		assert.Equal(t, "internal server error", err.Error())
+		assert.Equal(t, "venue not found", err.Error())
 		assert.Equal(t, reservation.ReservationCore{}, result)
 		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+		data.AssertExpectations(t)
 	})
 
-	t.Run("error - reservation not available for the specified time slot", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
+	t.Run("error - reservation outside opening hours", func(t *testing.T) {
+		request := reservationCore
+		request.CheckInDate = day.Add(22 * time.Hour)
+		request.CheckOutDate = day.Add(24 * time.Hour)
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+
+		result, paymentResult, err := service.MakeReservation(userId, request, paymentCore)
+
+		assert.Error(t, err)
+		assert.Equal(t, "reservation outside opening hours", err.Error())
+		assert.Equal(t, reservation.ReservationCore{}, result)
+		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - reservation outside structured opening hours", func(t *testing.T) {
+		structured := venue
+		structured.OpeningHours = []reservation.OpeningHourCore{
+			{Weekday: int(day.Weekday()), OpenTime: "06:00", CloseTime: "10:00"},
+			{Weekday: int(day.Weekday()), OpenTime: "15:00", CloseTime: "02:00"},
 		}
-		paymentCore := reservation.PaymentCore{}
+		data.On("GetVenue", reservationCore.VenueID).Return(structured, nil).Once()
+
+		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
-		// Mock the necessary methods to simulate an existing reservation
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{{}}, nil).Once()
+		assert.Error(t, err)
+		assert.Equal(t, "reservation outside opening hours", err.Error())
+		assert.Equal(t, reservation.ReservationCore{}, result)
+		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - venue is closed on the requested date", func(t *testing.T) {
+		closures := []reservation.ClosureCore{{StartDate: day, EndDate: day.AddDate(0, 0, 1), Reason: "public holiday"}}
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return(closures, nil).Once()
+
+		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+
+		assert.Error(t, err)
+		assert.Equal(t, "venue is closed on the requested date", err.Error())
+		assert.Equal(t, reservation.ReservationCore{}, result)
+		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - reservation not available for the specified time slot", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{{}}, nil).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
@@ -483,60 +950,89 @@ func TestMakeReservation(t *testing.T) {
 		data.AssertExpectations(t)
 	})
 
-	t.Run("error - failed to get venue price", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
+	t.Run("success - consumes own hold", func(t *testing.T) {
+		request := reservationCore
+		request.HoldID = "HLD-1"
+		priced := pricedReservation
+		priced.HoldID = "HLD-1"
+		holds := []reservation.HoldCore{
+			{HoldID: "HLD-1", VenueID: "venue_id_1", UserID: userId, CheckInDate: checkIn, CheckOutDate: checkOut},
+			{HoldID: "HLD-2", VenueID: "venue_id_1", UserID: "user_id_2", CheckInDate: checkOut, CheckOutDate: checkOut.Add(time.Hour)},
 		}
-		paymentCore := reservation.PaymentCore{}
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return(holds, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(priced), charged(pricedPayment)).Return(priced, charged(pricedPayment), nil).Once()
+		data.On("DeleteHold", "venue_id_1", "HLD-1").Return(nil).Once()
+		data.On("ClaimWaitlistOffer", userId, "HLD-1").Return(nil).Once()
 
-		// Mock the necessary methods to simulate an error in getting the venue price
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
-		data.On("PriceVenue", reservationCore.VenueID).Return(0.0, errors.New("failed to get venue price")).Once()
+		result, _, err := service.MakeReservation(userId, request, paymentCore)
+		assert.Nil(t, err)
+		assert.Equal(t, priced, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - slot is held by another user", func(t *testing.T) {
+		holds := []reservation.HoldCore{
+			{HoldID: "HLD-2", VenueID: "venue_id_1", UserID: "user_id_2", CheckInDate: checkIn.Add(-time.Hour), CheckOutDate: checkIn.Add(time.Hour)},
+		}
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return(holds, nil).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
-		assert.Error(t, err)
-		assert.Equal(t, "failed to get venue price", err.Error())
+		assert.EqualError(t, err, "slot is held by another user")
 		assert.Equal(t, reservation.ReservationCore{}, result)
 		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
 		data.AssertExpectations(t)
 	})
 
-	t.Run("error - failed to parse grand_total", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
+	t.Run("error - hold not found or expired", func(t *testing.T) {
+		request := reservationCore
+		request.HoldID = "HLD-expired"
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
 
-		// Mock the necessary methods to simulate an error in parsing grand_total
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
-		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		_, _, err := service.MakeReservation(userId, request, paymentCore)
+
+		assert.EqualError(t, err, "hold not found or expired")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - failed to get venue price", func(t *testing.T) {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(0.0, errors.New("failed to get venue price")).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
 		assert.Error(t, err)
-		assert.Equal(t, "failed to parse grand_total", err.Error())
+		assert.Equal(t, "failed to get venue price", err.Error())
 		assert.Equal(t, reservation.ReservationCore{}, result)
 		assert.Equal(t, reservation.PaymentCore{}, paymentResult)
 		data.AssertExpectations(t)
 	})
 
 	t.Run("error - failed to insert data, user does not exist", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
-
-		// Mock the necessary methods to simulate a user-related error
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
 		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
-		data.On("MakeReservation", userId, reservationCore, paymentCore).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("user does not exist")).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(pendingCharge, nil).Once()
+		payments.On("Cancel", mock.AnythingOfType("string")).Return(nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("user does not exist")).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
@@ -548,17 +1044,15 @@ func TestMakeReservation(t *testing.T) {
 	})
 
 	t.Run("error - foreign key constraint violation", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
-
-		// Mock the necessary methods to simulate a foreign key constraint violation error
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
 		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
-		data.On("MakeReservation", userId, reservationCore, paymentCore).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("unregistered user")).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(pendingCharge, nil).Once()
+		payments.On("Cancel", mock.AnythingOfType("string")).Return(nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("unregistered user")).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
@@ -570,17 +1064,15 @@ func TestMakeReservation(t *testing.T) {
 	})
 
 	t.Run("error - internal server error", func(t *testing.T) {
-		reservationCore := reservation.ReservationCore{
-			VenueID:      "venue_id_1",
-			CheckInDate:  time.Now().AddDate(0, 0, 1),
-			CheckOutDate: time.Now().AddDate(0, 0, 2),
-		}
-		paymentCore := reservation.PaymentCore{}
-
-		// Mock the necessary methods to simulate an internal server error
-		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, reservationCore.CheckInDate, reservationCore.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
 		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
-		data.On("MakeReservation", userId, reservationCore, paymentCore).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error")).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(pendingCharge, nil).Once()
+		payments.On("Cancel", mock.AnythingOfType("string")).Return(nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("internal server error")).Once()
 
 		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
 
@@ -591,3 +1083,1661 @@ func TestMakeReservation(t *testing.T) {
 		data.AssertExpectations(t)
 	})
 }
+
+func TestMakeReservationWithVoucher(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	checkIn := day.Add(9 * time.Hour)
+	checkOut := day.Add(11 * time.Hour)
+	venue := reservation.VenueCore{VenueID: "venue_id_1", CategoryID: "CTG-1", Category: "futsal", ServiceTime: "07:00 - 23:00"}
+	reservationCore := reservation.ReservationCore{
+		VenueID:      "venue_id_1",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+	}
+	paymentCore := reservation.PaymentCore{VoucherCode: "promo10"}
+	voucherCore := reservation.VoucherCore{
+		VoucherID:     "voucher_id_1",
+		Code:          "PROMO10",
+		DiscountType:  reservation.DiscountPercentage,
+		DiscountValue: 10,
+		ValidFrom:     time.Now().AddDate(0, 0, -1),
+		ValidUntil:    time.Now().AddDate(0, 0, 7),
+	}
+
+	// Prices 2 hours at 100 before the voucher applies
+	expectQuote := func() {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+	}
+
+	t.Run("success - percentage discount", func(t *testing.T) {
+		expectQuote()
+		data.On("GetVoucher", "PROMO10").Return(voucherCore, nil).Once()
+		pricedReservation := reservationCore
+		pricedReservation.Duration = 2
+		pricedReservation.Subtotal = 180
+		pricedPayment := reservation.PaymentCore{
+			VoucherCode: "promo10",
+			VoucherID:   "voucher_id_1",
+			Discount:    20,
+			GrandTotal:  "180",
+			Items: []reservation.PaymentItemCore{
+				{Kind: "booking", Description: "Venue booking", Amount: 200},
+				{Kind: "discount", Description: "Voucher discount", Amount: -20},
+			},
+		}
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(pricedReservation, charged(pricedPayment), nil).Once()
+
+		result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Nil(t, err)
+		assert.Equal(t, pricedReservation, result)
+		assert.Equal(t, charged(pricedPayment), paymentResult)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - fixed discount capped at the subtotal", func(t *testing.T) {
+		expectQuote()
+		fixed := voucherCore
+		fixed.DiscountType = reservation.DiscountFixed
+		fixed.DiscountValue = 500
+		data.On("GetVoucher", "PROMO10").Return(fixed, nil).Once()
+		pricedReservation := reservationCore
+		pricedReservation.Duration = 2
+		pricedReservation.Subtotal = 0
+		pricedPayment := reservation.PaymentCore{
+			VoucherCode: "promo10",
+			VoucherID:   "voucher_id_1",
+			Discount:    200,
+			GrandTotal:  "0",
+			Items: []reservation.PaymentItemCore{
+				{Kind: "booking", Description: "Venue booking", Amount: 200},
+				{Kind: "discount", Description: "Voucher discount", Amount: -200},
+			},
+		}
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), charged(pricedPayment)).Return(pricedReservation, charged(pricedPayment), nil).Once()
+
+		_, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Nil(t, err)
+		assert.Equal(t, 200.0, paymentResult.Discount)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - category scoped voucher", func(t *testing.T) {
+		expectQuote()
+		scoped := voucherCore
+		scoped.CategoryID = "CTG-1"
+		scoped.MaxDiscount = 15
+		data.On("GetVoucher", "PROMO10").Return(scoped, nil).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), mock.Anything).Return(pendingCharge, nil).Once()
+		data.On("MakeReservation", userId, mock.Anything, mock.Anything).Return(reservationCore, reservation.PaymentCore{Discount: 15}, nil).Once()
+
+		_, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Nil(t, err)
+		assert.Equal(t, 15.0, paymentResult.Discount)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - voucher scoped to another category", func(t *testing.T) {
+		expectQuote()
+		scoped := voucherCore
+		scoped.CategoryID = "CTG-2"
+		data.On("GetVoucher", "PROMO10").Return(scoped, nil).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+
+		_, _, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.EqualError(t, err, "voucher does not apply to this venue")
+		data.AssertExpectations(t)
+	})
+
+	failures := []struct {
+		name    string
+		voucher func(v reservation.VoucherCore) reservation.VoucherCore
+		message string
+	}{
+		{"error - voucher is not valid yet", func(v reservation.VoucherCore) reservation.VoucherCore {
+			v.ValidFrom = time.Now().AddDate(0, 0, 1)
+			return v
+		}, "voucher is not valid yet"},
+		{"error - voucher has expired", func(v reservation.VoucherCore) reservation.VoucherCore {
+			v.ValidUntil = time.Now().AddDate(0, 0, -1)
+			return v
+		}, "voucher has expired"},
+		{"error - voucher of another venue", func(v reservation.VoucherCore) reservation.VoucherCore {
+			v.VenueID = "venue_id_2"
+			return v
+		}, "voucher does not apply to this venue"},
+		{"error - minimum spend not reached", func(v reservation.VoucherCore) reservation.VoucherCore {
+			v.MinSpend = 250
+			return v
+		}, "voucher requires a minimum spend of 250.00"},
+	}
+	for _, tc := range failures {
+		t.Run(tc.name, func(t *testing.T) {
+			expectQuote()
+			data.On("GetVoucher", "PROMO10").Return(tc.voucher(voucherCore), nil).Once()
+
+			result, paymentResult, err := service.MakeReservation(userId, reservationCore, paymentCore)
+			assert.Error(t, err)
+			assert.Equal(t, tc.message, err.Error())
+			assert.Equal(t, reservation.ReservationCore{}, result)
+			assert.Equal(t, reservation.PaymentCore{}, paymentResult)
+			data.AssertExpectations(t)
+		})
+	}
+
+	t.Run("error - invalid voucher code", func(t *testing.T) {
+		expectQuote()
+		data.On("GetVoucher", "PROMO10").Return(reservation.VoucherCore{}, errors.New("voucher not found")).Once()
+
+		_, _, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid voucher code", err.Error())
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - voucher usage limit reached", func(t *testing.T) {
+		expectQuote()
+		data.On("GetVoucher", "PROMO10").Return(voucherCore, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), mock.Anything).Return(pendingCharge, nil).Once()
+		payments.On("Cancel", mock.AnythingOfType("string")).Return(nil).Once()
+		data.On("MakeReservation", userId, mock.Anything, mock.Anything).Return(reservation.ReservationCore{}, reservation.PaymentCore{}, errors.New("voucher usage limit per user reached")).Once()
+
+		_, _, err := service.MakeReservation(userId, reservationCore, paymentCore)
+		assert.Error(t, err)
+		assert.Equal(t, "voucher usage limit per user reached", err.Error())
+		data.AssertExpectations(t)
+	})
+}
+
+func TestMakeSplitReservation(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	shareTemplate = "../../../utils/email/share_template.html"
+	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	checkIn := day.Add(9 * time.Hour)
+	checkOut := day.Add(11 * time.Hour)
+	venue := reservation.VenueCore{VenueID: "venue_id_1", Name: "Lapangan A", ServiceTime: "07:00 - 23:00"}
+	reservationCore := reservation.ReservationCore{
+		VenueID:      "venue_id_1",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+	}
+	pricedReservation := reservationCore
+	pricedReservation.Duration = 2
+	pricedReservation.Subtotal = 200
+	friends := []reservation.CustomerCore{
+		{UserID: "user_id_2", Fullname: "Budi", Email: "budi@mail.com"},
+		{UserID: "user_id_3", Fullname: "Siti", Email: "siti@mail.com"},
+	}
+	slotIsFree := func() {
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", reservationCore.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", reservationCore.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", reservationCore.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", reservationCore.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+	}
+	booked := func(userId string, r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
+		return r, p, nil
+	}
+	invited := func(to string) {
+		email.On("SendEmail", "You Have Been Invited to Split a Booking at Lapangan A", mock.AnythingOfType("string"), []string{to}, []string(nil), []string(nil), []string(nil)).Return(nil).Once()
+	}
+
+	t.Run("success - equal shares", func(t *testing.T) {
+		slotIsFree()
+		data.On("GetUserByContact", "budi@mail.com", "").Return(friends[0], nil).Once()
+		data.On("GetUserByContact", "", "081234567890").Return(friends[1], nil).Once()
+		for _, amount := range []string{"68", "66", "66"} {
+			payments.On("Charge", mock.AnythingOfType("string"), reservation.PaymentCore{GrandTotal: amount}).Return(shareCharge, nil).Once()
+		}
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), mock.Anything).Return(booked).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		invited("budi@mail.com")
+		invited("siti@mail.com")
+
+		request := reservation.PaymentCore{
+			SplitMode: reservation.SplitEqual,
+			Shares:    []reservation.ShareCore{{Email: " budi@mail.com "}, {Phone: "081234567890"}},
+		}
+		_, result, err := service.MakeReservation(userId, reservationCore, request)
+		assert.Nil(t, err)
+		assert.Equal(t, reservation.PaymentMethodSplit, result.PaymentMethod)
+		assert.True(t, strings.HasPrefix(result.PaymentID, "SPL-"))
+		assert.Equal(t, "200", result.GrandTotal)
+		assert.Equal(t, "pending", result.Status)
+		if assert.Len(t, result.Shares, 3) {
+			for i, expected := range []struct {
+				userId string
+				amount int64
+			}{{userId, 68}, {"user_id_2", 66}, {"user_id_3", 66}} {
+				assert.Equal(t, expected.userId, result.Shares[i].UserID)
+				assert.Equal(t, expected.amount, result.Shares[i].Amount)
+				assert.Equal(t, result.PaymentID, result.Shares[i].PaymentID)
+				assert.Equal(t, "charge_"+result.Shares[i].ShareID, result.Shares[i].Charge.PaymentID)
+			}
+		}
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+		email.AssertExpectations(t)
+	})
+
+	t.Run("success - custom shares cover the whole booking", func(t *testing.T) {
+		slotIsFree()
+		data.On("GetUserByContact", "budi@mail.com", "").Return(friends[0], nil).Once()
+		data.On("GetUserByContact", "siti@mail.com", "").Return(friends[1], nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), reservation.PaymentCore{GrandTotal: "150"}).Return(shareCharge, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), reservation.PaymentCore{GrandTotal: "50"}).Return(shareCharge, nil).Once()
+		data.On("MakeReservation", userId, bookedAs(pricedReservation), mock.Anything).Return(booked).Once()
+		data.On("GetVenue", reservationCore.VenueID).Return(venue, nil).Once()
+		invited("budi@mail.com")
+		invited("siti@mail.com")
+
+		request := reservation.PaymentCore{
+			SplitMode: reservation.SplitCustom,
+			Shares:    []reservation.ShareCore{{Email: "budi@mail.com", Amount: 150}, {Email: "siti@mail.com", Amount: 50}},
+		}
+		_, result, err := service.MakeReservation(userId, reservationCore, request)
+		assert.Nil(t, err)
+		if assert.Len(t, result.Shares, 2) {
+			assert.Equal(t, "user_id_2", result.Shares[0].UserID)
+			assert.Equal(t, "user_id_3", result.Shares[1].UserID)
+		}
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - custom shares add up to more than the total", func(t *testing.T) {
+		slotIsFree()
+		data.On("GetUserByContact", "budi@mail.com", "").Return(friends[0], nil).Once()
+		data.On("GetUserByContact", "siti@mail.com", "").Return(friends[1], nil).Once()
+
+		request := reservation.PaymentCore{
+			SplitMode: reservation.SplitCustom,
+			Shares:    []reservation.ShareCore{{Email: "budi@mail.com", Amount: 150}, {Email: "siti@mail.com", Amount: 100}},
+		}
+		_, _, err := service.MakeReservation(userId, reservationCore, request)
+		assert.EqualError(t, err, "invalid share amount, shares add up to more than the total")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - participant not found", func(t *testing.T) {
+		slotIsFree()
+		data.On("GetUserByContact", "nobody@mail.com", "").Return(reservation.CustomerCore{}, errors.New("user not found")).Once()
+
+		request := reservation.PaymentCore{SplitMode: reservation.SplitEqual, Shares: []reservation.ShareCore{{Email: "nobody@mail.com"}}}
+		_, _, err := service.MakeReservation(userId, reservationCore, request)
+		assert.EqualError(t, err, "participant not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - booker invites themselves", func(t *testing.T) {
+		slotIsFree()
+		data.On("GetUserByContact", "me@mail.com", "").Return(reservation.CustomerCore{UserID: userId}, nil).Once()
+
+		request := reservation.PaymentCore{SplitMode: reservation.SplitEqual, Shares: []reservation.ShareCore{{Email: "me@mail.com"}}}
+		_, _, err := service.MakeReservation(userId, reservationCore, request)
+		assert.EqualError(t, err, "invalid participants, each must be a different user other than the booker")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid split mode", func(t *testing.T) {
+		slotIsFree()
+
+		request := reservation.PaymentCore{SplitMode: "halves", Shares: []reservation.ShareCore{{Email: "budi@mail.com"}}}
+		_, _, err := service.MakeReservation(userId, reservationCore, request)
+		assert.EqualError(t, err, "invalid split mode, expected equal or custom")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - a failed share charge voids the shares charged before it", func(t *testing.T) {
+		slotIsFree()
+		data.On("GetUserByContact", "budi@mail.com", "").Return(friends[0], nil).Once()
+		var firstShare string
+		payments.On("Charge", mock.AnythingOfType("string"), reservation.PaymentCore{GrandTotal: "100"}).Run(func(args mock.Arguments) {
+			firstShare = args.String(0)
+		}).Return(shareCharge, nil).Once()
+		payments.On("Charge", mock.AnythingOfType("string"), reservation.PaymentCore{GrandTotal: "100"}).Return(reservation.PaymentCore{}, errors.New("connection reset")).Once()
+		payments.On("Cancel", mock.MatchedBy(func(orderId string) bool { return orderId == firstShare })).Return(nil).Once()
+
+		request := reservation.PaymentCore{SplitMode: reservation.SplitEqual, Shares: []reservation.ShareCore{{Email: "budi@mail.com"}}}
+		_, _, err := service.MakeReservation(userId, reservationCore, request)
+		assert.EqualError(t, err, "internal server error while charging payment")
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - recurring reservations cannot be split", func(t *testing.T) {
+		request := reservation.PaymentCore{SplitMode: reservation.SplitEqual, Shares: []reservation.ShareCore{{Email: "budi@mail.com"}}}
+		_, _, _, err := service.MakeRecurringReservation(userId, reservationCore, reservation.RecurrenceCore{Frequency: "weekly", Count: 2}, request)
+		assert.EqualError(t, err, "split payments are not available for recurring reservations")
+	})
+}
+
+// shareCharge answers the charge of a share under a payment ID of its own
+func shareCharge(orderId string, p reservation.PaymentCore) (reservation.PaymentCore, error) {
+	p, _ = pendingCharge(orderId, p)
+	p.PaymentID = "charge_" + orderId
+	return p, nil
+}
+
+func TestShareStatus(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	stored := reservation.PaymentCore{PaymentID: "charge_2", Status: "pending", GrandTotal: "66", Purpose: reservation.PaymentForShare, ReferenceID: "SPL-1"}
+	shares := func(second string) []reservation.ShareCore {
+		return []reservation.ShareCore{
+			{ShareID: "SHR-1", PaymentID: "SPL-1", UserID: "user_id_1", Amount: 68, Charge: reservation.PaymentCore{PaymentID: "charge_1", Status: "success"}},
+			{ShareID: "SHR-2", PaymentID: "SPL-1", UserID: "user_id_2", Amount: 66, Charge: reservation.PaymentCore{PaymentID: "charge_2", Status: second}},
+			{ShareID: "SHR-3", PaymentID: "SPL-1", UserID: "user_id_3", Amount: 66, Charge: reservation.PaymentCore{PaymentID: "charge_3", Status: "pending"}},
+		}
+	}
+	closed := func(chargeId string) {
+		data.On("ReservationStatus", reservation.PaymentCore{PaymentID: chargeId, Status: "cancel"}).Return(reservation.PaymentCore{}, nil).Once()
+	}
+	reason := "a share of the split payment was not paid"
+
+	t.Run("success - last share confirms the booking", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "charge_2", Status: "settlement", GrandTotal: "66.00"}
+		settled := request
+		settled.Status = "success"
+		data.On("GetPayment", "charge_2").Return(stored, nil).Once()
+		data.On("ReservationStatus", settled).Return(settled, nil).Once()
+		data.On("ConfirmSplitPayment", "SPL-1").Return(reservation.PaymentCore{PaymentID: "SPL-1", Status: "success"}, true, nil).Once()
+		data.On("GetPaymentCustomer", "SPL-1").Return(reservation.CustomerCore{}, errors.New("payment not found")).Once()
+
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "success", result.Status)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - other shares are still unpaid", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "charge_2", Status: "settlement", GrandTotal: "66.00"}
+		settled := request
+		settled.Status = "success"
+		data.On("GetPayment", "charge_2").Return(stored, nil).Once()
+		data.On("ReservationStatus", settled).Return(settled, nil).Once()
+		data.On("ConfirmSplitPayment", "SPL-1").Return(reservation.PaymentCore{PaymentID: "SPL-1", Status: "pending"}, false, nil).Once()
+
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "success", result.Status)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - an expired share releases the booking", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "charge_2", Status: "expire", GrandTotal: "66.00"}
+		data.On("GetPayment", "charge_2").Return(stored, nil).Once()
+		data.On("ReservationStatus", request).Return(request, nil).Once()
+		data.On("FailSplitPayment", "SPL-1", reason).Return(reservation.PaymentCore{PaymentID: "SPL-1", Status: "expire"}, shares("expire"), nil).Once()
+		payments.On("Cancel", "SHR-3").Return(nil).Once()
+		payments.On("RefundTransaction", "SHR-1", int64(68), reason).Return(nil).Once()
+		data.On("InsertRefund", reservation.RefundCore{PaymentID: "SPL-1", OrderID: "SHR-1", Amount: 68, Reason: reason}).Return(reservation.RefundCore{RefundID: "RFD-1"}, nil).Once()
+		closed("charge_3")
+		closed("charge_1")
+		data.On("GetPaymentReservations", "SPL-1").Return([]reservation.ReservationCore{}, nil).Once()
+
+		result, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		assert.Equal(t, "expire", result.Status)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - repeated expiry leaves the split payment alone", func(t *testing.T) {
+		expired := stored
+		expired.Status = "expire"
+		request := reservation.PaymentCore{PaymentID: "charge_2", Status: "expire", GrandTotal: "66.00"}
+		data.On("GetPayment", "charge_2").Return(expired, nil).Once()
+		data.On("FailSplitPayment", "SPL-1", reason).Return(reservation.PaymentCore{PaymentID: "SPL-1", Status: "expire"}, nil, nil).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - share paid after the booking fell through is refunded", func(t *testing.T) {
+		request := reservation.PaymentCore{PaymentID: "charge_2", Status: "settlement", GrandTotal: "66.00"}
+		settled := request
+		settled.Status = "success"
+		lateReason := "share was paid after the split payment fell through"
+		data.On("GetPayment", "charge_2").Return(stored, nil).Once()
+		data.On("ReservationStatus", settled).Return(settled, nil).Once()
+		data.On("ConfirmSplitPayment", "SPL-1").Return(reservation.PaymentCore{PaymentID: "SPL-1", Status: "expire"}, false, nil).Once()
+		data.On("GetShares", "SPL-1").Return(shares("success"), nil).Once()
+		payments.On("RefundTransaction", "SHR-2", int64(66), lateReason).Return(nil).Once()
+		data.On("InsertRefund", reservation.RefundCore{PaymentID: "SPL-1", OrderID: "SHR-2", Amount: 66, Reason: lateReason}).Return(reservation.RefundCore{RefundID: "RFD-2"}, nil).Once()
+		closed("charge_2")
+
+		_, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - failed to confirm split payment", func(t *testing.T) {
+		paid := stored
+		paid.Status = "success"
+		request := reservation.PaymentCore{PaymentID: "charge_2", Status: "settlement", GrandTotal: "66.00"}
+		data.On("GetPayment", "charge_2").Return(paid, nil).Once()
+		data.On("ConfirmSplitPayment", "SPL-1").Return(reservation.PaymentCore{}, false, errors.New("database error")).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.EqualError(t, err, "failed to confirm split payment: database error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestAvailabilitySlots(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	venueID := "venue_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+
+	t.Run("success", func(t *testing.T) {
+		taken := []reservation.AvailabilityCore{
+			{
+				ReservationID: "reservation_id_1",
+				CheckInDate:   day.Add(9 * time.Hour),
+				CheckOutDate:  day.Add(10 * time.Hour),
+				Status:        "success",
+			},
+			{
+				ReservationID: "reservation_id_2",
+				CheckInDate:   day.Add(10*time.Hour + 30*time.Minute),
+				CheckOutDate:  day.Add(11 * time.Hour),
+				Status:        "pending",
+			},
+		}
+
+		data.On("GetVenue", venueID).Return(reservation.VenueCore{VenueID: venueID, ServiceTime: "08:00 - 12:00"}, nil).Once()
+		data.On("CheckAvailabilityByTimeWindow", venueID, mock.Anything, mock.Anything).Return(taken, nil).Once()
+		data.On("GetClosures", venueID, mock.Anything, mock.Anything).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetHolds", venueID).Return([]reservation.HoldCore{{CheckInDate: day.Add(11 * time.Hour), CheckOutDate: day.Add(11*time.Hour + 10*time.Minute)}}, nil).Once()
+
+		result, err := service.AvailabilitySlots(venueID, day, day, time.Hour)
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Len(t, result[0].Slots, 4)
+		assert.Equal(t, reservation.SlotFree, result[0].Slots[0].Status)
+		assert.Equal(t, reservation.SlotBooked, result[0].Slots[1].Status)
+		assert.Equal(t, reservation.SlotPending, result[0].Slots[2].Status)
+		assert.Equal(t, reservation.SlotHeld, result[0].Slots[3].Status)
+		assert.Equal(t, day.Add(11*time.Hour), result[0].Slots[3].Start)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - structured hours and closures", func(t *testing.T) {
+		venue := reservation.VenueCore{
+			VenueID:     venueID,
+			ServiceTime: "08:00 - 12:00",
+			OpeningHours: []reservation.OpeningHourCore{
+				{Weekday: int(day.Weekday()), OpenTime: "18:00", CloseTime: "20:00"},
+			},
+		}
+		closures := []reservation.ClosureCore{{StartDate: day.Add(19 * time.Hour), EndDate: day.Add(21 * time.Hour)}}
+		data.On("GetVenue", venueID).Return(venue, nil).Once()
+		data.On("CheckAvailabilityByTimeWindow", venueID, mock.Anything, mock.Anything).Return([]reservation.AvailabilityCore{}, nil).Once()
+		data.On("GetClosures", venueID, mock.Anything, mock.Anything).Return(closures, nil).Once()
+		data.On("GetHolds", venueID).Return([]reservation.HoldCore{}, nil).Once()
+
+		result, err := service.AvailabilitySlots(venueID, day, day.AddDate(0, 0, 1), time.Hour)
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Len(t, result[0].Slots, 2)
+		assert.Equal(t, day.Add(18*time.Hour), result[0].Slots[0].Start)
+		assert.Equal(t, reservation.SlotFree, result[0].Slots[0].Status)
+		assert.Equal(t, reservation.SlotClosed, result[0].Slots[1].Status)
+		assert.Empty(t, result[1].Slots)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - slot length of the venue category", func(t *testing.T) {
+		venue := reservation.VenueCore{VenueID: venueID, Category: "football", SlotMinutes: 90, ServiceTime: "08:00 - 14:00"}
+		data.On("GetVenue", venueID).Return(venue, nil).Once()
+		data.On("CheckAvailabilityByTimeWindow", venueID, mock.Anything, mock.Anything).Return([]reservation.AvailabilityCore{}, nil).Once()
+		data.On("GetClosures", venueID, mock.Anything, mock.Anything).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetHolds", venueID).Return([]reservation.HoldCore{}, nil).Once()
+
+		result, err := service.AvailabilitySlots(venueID, day, day, 0)
+		assert.Nil(t, err)
+		assert.Len(t, result[0].Slots, 4)
+		assert.Equal(t, day.Add(9*time.Hour+30*time.Minute), result[0].Slots[1].Start)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - slot length too short", func(t *testing.T) {
+		result, err := service.AvailabilitySlots(venueID, day, day, 5*time.Minute)
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "slot length must be at least 15 minutes")
+	})
+
+	t.Run("error - outside booking window", func(t *testing.T) {
+		result, err := service.AvailabilitySlots(venueID, day, day.AddDate(0, 4, 0), time.Hour)
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "date range cannot exceed 31 days")
+
+		result, err = service.AvailabilitySlots(venueID, day.AddDate(0, 4, 0), day.AddDate(0, 4, 1), time.Hour)
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "reservation date not within the allowed timewindow")
+	})
+
+	t.Run("error - venue not found", func(t *testing.T) {
+		data.On("GetVenue", venueID).Return(reservation.VenueCore{}, errors.New("venue not found")).Once()
+		result, err := service.AvailabilitySlots(venueID, day, day, time.Hour)
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "venue not found")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestCreateHold(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	venue := reservation.VenueCore{VenueID: "venue_id_1", ServiceTime: "07:00 - 23:00"}
+	request := reservation.HoldCore{
+		VenueID:      "venue_id_1",
+		CheckInDate:  day.Add(9 * time.Hour),
+		CheckOutDate: day.Add(11 * time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		expected := request
+		expected.UserID = userId
+		created := expected
+		created.HoldID = "HLD-1"
+		created.ExpiresAt = time.Now().Add(defaultHoldTTL)
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("InsertHold", expected, defaultHoldTTL).Return(created, nil).Once()
+
+		result, err := service.CreateHold(userId, request)
+		assert.Nil(t, err)
+		assert.Equal(t, created, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - check_in_date is empty", func(t *testing.T) {
+		invalid := request
+		invalid.CheckInDate = time.Time{}
+		_, err := service.CreateHold(userId, invalid)
+		assert.EqualError(t, err, "check_in_date cannot be empty")
+	})
+
+	t.Run("error - reservation not available", func(t *testing.T) {
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ReservationCore{{}}, nil).Once()
+
+		_, err := service.CreateHold(userId, request)
+		assert.EqualError(t, err, "reservation not available")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - slot is held by another user", func(t *testing.T) {
+		expected := request
+		expected.UserID = userId
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("InsertHold", expected, defaultHoldTTL).Return(reservation.HoldCore{}, errors.New("slot is held by another user")).Once()
+
+		_, err := service.CreateHold(userId, request)
+		assert.EqualError(t, err, "slot is held by another user")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestExpirePendingPayments(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	beforeGrace := mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().Add(-defaultExpiryGrace)
+		return !before.After(cutoff) && before.After(cutoff.Add(-time.Minute))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		overdue := []reservation.PaymentCore{{PaymentID: "payment_id_1", Status: "pending"}}
+		expired := []reservation.PaymentCore{{PaymentID: "payment_id_1", Status: "expire"}}
+		data.On("GetOverduePayments", beforeGrace).Return(overdue, nil).Once()
+		payments.On("Cancel", "payment_id_1").Return(nil).Once()
+		data.On("ExpirePendingPayments", []string{"payment_id_1"}, mock.AnythingOfType("string")).Return(expired, nil).Once()
+		data.On("GetPaymentReservations", "payment_id_1").Return([]reservation.ReservationCore{}, nil).Once()
+
+		result, err := service.ExpirePendingPayments()
+		assert.Nil(t, err)
+		assert.Equal(t, expired, result)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - charges the gateway did not close are kept pending", func(t *testing.T) {
+		overdue := []reservation.PaymentCore{
+			{PaymentID: "payment_id_1", Status: "pending"},
+			{PaymentID: "payment_id_2", Status: "pending"},
+			{PaymentID: "payment_id_3", Status: "pending"},
+		}
+		expired := []reservation.PaymentCore{{PaymentID: "payment_id_2", Status: "expire"}}
+		data.On("GetOverduePayments", beforeGrace).Return(overdue, nil).Once()
+		// Paid just before the cancellation reached the gateway, the reconciler settles it
+		payments.On("Cancel", "payment_id_1").Return(errors.New("cancel rejected: payment is settlement")).Once()
+		payments.On("Status", "payment_id_1").Return(reservation.PaymentCore{Status: "settlement"}, nil).Once()
+		// Already lapsed at the gateway
+		payments.On("Cancel", "payment_id_2").Return(errors.New("cancel rejected: payment is expire")).Once()
+		payments.On("Status", "payment_id_2").Return(reservation.PaymentCore{Status: "expire"}, nil).Once()
+		// Gateway unreachable, tried again on the next run
+		payments.On("Cancel", "payment_id_3").Return(errors.New("connection refused")).Once()
+		payments.On("Status", "payment_id_3").Return(reservation.PaymentCore{}, errors.New("connection refused")).Once()
+		data.On("ExpirePendingPayments", []string{"payment_id_2"}, mock.AnythingOfType("string")).Return(expired, nil).Once()
+		data.On("GetPaymentReservations", "payment_id_2").Return([]reservation.ReservationCore{}, nil).Once()
+
+		result, err := service.ExpirePendingPayments()
+		assert.Nil(t, err)
+		assert.Equal(t, expired, result)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - expired split payment undoes its shares", func(t *testing.T) {
+		expired := []reservation.PaymentCore{
+			{PaymentID: "SPL-1", PaymentMethod: reservation.PaymentMethodSplit, Status: "expire"},
+			{PaymentID: "charge_3", Purpose: reservation.PaymentForShare, ReferenceID: "SPL-1", Status: "expire"},
+		}
+		shares := []reservation.ShareCore{
+			{ShareID: "SHR-1", PaymentID: "SPL-1", Amount: 68, Charge: reservation.PaymentCore{PaymentID: "charge_1", Status: "success"}},
+			{ShareID: "SHR-2", PaymentID: "SPL-1", Amount: 66, Charge: reservation.PaymentCore{PaymentID: "charge_2", Status: "pending"}},
+			{ShareID: "SHR-3", PaymentID: "SPL-1", Amount: 66, Charge: reservation.PaymentCore{PaymentID: "charge_3", Status: "expire"}},
+		}
+		overdue := []reservation.PaymentCore{
+			{PaymentID: "SPL-1", PaymentMethod: reservation.PaymentMethodSplit, Status: "pending"},
+			{PaymentID: "charge_3", Purpose: reservation.PaymentForShare, ReferenceID: "SPL-1", Status: "pending"},
+		}
+		data.On("GetOverduePayments", beforeGrace).Return(overdue, nil).Once()
+		payments.On("Cancel", "charge_3").Return(nil).Once()
+		data.On("ExpirePendingPayments", []string{"SPL-1", "charge_3"}, mock.AnythingOfType("string")).Return(expired, nil).Once()
+		data.On("GetShares", "SPL-1").Return(shares, nil).Once()
+		payments.On("Cancel", "SHR-2").Return(nil).Once()
+		payments.On("RefundTransaction", "SHR-1", int64(68), mock.AnythingOfType("string")).Return(nil).Once()
+		data.On("InsertRefund", mock.MatchedBy(func(r reservation.RefundCore) bool {
+			return r.PaymentID == "SPL-1" && r.OrderID == "SHR-1" && r.Amount == 68
+		})).Return(reservation.RefundCore{}, nil).Once()
+		data.On("ReservationStatus", reservation.PaymentCore{PaymentID: "charge_2", Status: "cancel"}).Return(reservation.PaymentCore{}, nil).Once()
+		data.On("ReservationStatus", reservation.PaymentCore{PaymentID: "charge_1", Status: "cancel"}).Return(reservation.PaymentCore{}, nil).Once()
+		data.On("GetPaymentReservations", "SPL-1").Return([]reservation.ReservationCore{}, nil).Once()
+
+		result, err := service.ExpirePendingPayments()
+		assert.Nil(t, err)
+		assert.Equal(t, expired, result)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		data.On("GetOverduePayments", beforeGrace).Return(nil, errors.New("database down")).Once()
+
+		result, err := service.ExpirePendingPayments()
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestReconcilePayments(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	email := mocks.NewEmailSender(t)
+	// Stands in for the Midtrans status API
+	gateway := map[string]string{
+		"payment_id_1": `{"status_code":"407","transaction_id":"payment_id_1","order_id":"reservation_id_1","gross_amount":"150.00","payment_type":"bank_transfer","transaction_status":"expire"}`,
+		"payment_id_2": `{"status_code":"201","transaction_id":"payment_id_2","order_id":"reservation_id_2","gross_amount":"150.00","payment_type":"bank_transfer","transaction_status":"pending"}`,
+		"payment_id_3": `{"status_code":"200","transaction_id":"payment_id_3","order_id":"reservation_id_3","gross_amount":"1.00","payment_type":"bank_transfer","transaction_status":"settlement"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/"), "/status")
+		switch body, found := gateway[id]; {
+		case found:
+			w.Write([]byte(body))
+		case id == "payment_id_5":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status_code":"404","status_message":"Transaction doesn't exist."}`))
+		}
+	}))
+	defer server.Close()
+	midtrans := paymentgateway.NewMidtrans("server_key", "merchant_id")
+	midtrans.BaseURL = server.URL
+	service := New(data, midtrans, email)
+	staleBefore := mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().Add(-defaultReconcileAfter)
+		return !before.After(cutoff) && before.After(cutoff.Add(-time.Minute))
+	})
+	pendingAt := func(paymentId string) reservation.PaymentCore {
+		return reservation.PaymentCore{PaymentID: paymentId, Status: "pending", GrandTotal: "150.00"}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		stale := []reservation.PaymentCore{pendingAt("payment_id_1"), pendingAt("payment_id_2"), pendingAt("payment_id_3"), pendingAt("payment_id_4"), pendingAt("payment_id_5")}
+		data.On("GetStalePayments", staleBefore, reconcileBatchSize).Return(stale, nil).Once()
+		// The lost expiry notification is applied as it would have been
+		expired := reservation.PaymentCore{
+			PaymentID:     "payment_id_1",
+			PaymentMethod: "bank_transfer",
+			GrandTotal:    "150.00",
+			Status:        "expire",
+			ReservationID: "reservation_id_1",
+			Reservation:   reservation.ReservationCore{ReservationID: "reservation_id_1"},
+		}
+		data.On("GetPayment", "payment_id_1").Return(pendingAt("payment_id_1"), nil).Once()
+		data.On("ReservationStatus", expired).Return(expired, nil).Once()
+		data.On("GetPaymentReservations", "payment_id_1").Return([]reservation.ReservationCore{}, nil).Once()
+		// The settlement is not for the amount we charged
+		data.On("GetPayment", "payment_id_3").Return(pendingAt("payment_id_3"), nil).Once()
+		data.On("InsertDiscrepancy", mock.Anything).Return(func(d reservation.DiscrepancyCore) (reservation.DiscrepancyCore, error) {
+			return d, nil
+		}).Times(3)
+
+		result, err := service.ReconcilePayments()
+		assert.Nil(t, err)
+		assert.Equal(t, []reservation.DiscrepancyCore{
+			{PaymentID: "payment_id_1", OrderID: "reservation_id_1", LocalStatus: "pending", GatewayStatus: "expire", LocalAmount: "150.00", GatewayAmount: "150.00", Resolved: true, Detail: "payment moved to expire"},
+			{PaymentID: "payment_id_3", OrderID: "reservation_id_3", LocalStatus: "pending", GatewayStatus: "settlement", LocalAmount: "150.00", GatewayAmount: "1.00", Detail: "gross amount does not match the payment"},
+			{PaymentID: "payment_id_4", LocalStatus: "pending", LocalAmount: "150.00", Detail: "payment not found at the gateway"},
+		}, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		data.On("GetStalePayments", staleBefore, reconcileBatchSize).Return(nil, errors.New("database down")).Once()
+
+		result, err := service.ReconcilePayments()
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestPaymentDiscrepancies(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	found := []reservation.DiscrepancyCore{{DiscrepancyID: "DSC-1", PaymentID: "payment_id_3", Detail: "gross amount does not match the payment"}}
+
+	t.Run("success", func(t *testing.T) {
+		data.On("GetUserRole", "admin_id").Return("admin", nil).Once()
+		data.On("GetDiscrepancies", true).Return(found, nil).Once()
+
+		result, err := service.PaymentDiscrepancies("admin_id", true)
+		assert.Nil(t, err)
+		assert.Equal(t, found, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not an admin", func(t *testing.T) {
+		data.On("GetUserRole", "user_id_1").Return("user", nil).Once()
+
+		_, err := service.PaymentDiscrepancies("user_id_1", false)
+		assert.EqualError(t, err, "only admins can view payment discrepancies")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestMakeRecurringReservation(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	venue := reservation.VenueCore{VenueID: "venue_id_1", ServiceTime: "07:00 - 23:00"}
+	request := reservation.ReservationCore{
+		VenueID:      "venue_id_1",
+		CheckInDate:  day.Add(19 * time.Hour),
+		CheckOutDate: day.Add(21 * time.Hour),
+	}
+	rule := reservation.RecurrenceCore{Frequency: reservation.RecurrenceWeekly, Count: 3}
+	occurrenceAt := func(week int) reservation.ReservationCore {
+		occurrence := request
+		occurrence.CheckInDate = request.CheckInDate.AddDate(0, 0, 7*week)
+		occurrence.CheckOutDate = request.CheckOutDate.AddDate(0, 0, 7*week)
+		occurrence.Duration = 2
+		occurrence.Subtotal = 200
+		return occurrence
+	}
+	priced := []reservation.ReservationCore{occurrenceAt(0), occurrenceAt(1), occurrenceAt(2)}
+	pricedPayment := reservation.PaymentCore{
+		GrandTotal: "600",
+		Items:      []reservation.PaymentItemCore{{Kind: "booking", Description: "Venue booking, 3 sessions", Amount: 600}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Times(3)
+		data.On("GetClosures", venue.VenueID, mock.Anything, mock.Anything).Return([]reservation.ClosureCore{}, nil).Times(3)
+		data.On("GetReservationsByTimeSlot", venue.VenueID, mock.Anything, mock.Anything).Return([]reservation.ReservationCore{}, nil).Times(3)
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Times(3)
+		data.On("PriceVenue", venue.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", venue.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		var seriesId string
+		payments.On("Charge", mock.AnythingOfType("string"), pricedPayment).Run(func(args mock.Arguments) {
+			seriesId = args.String(0)
+		}).Return(pendingCharge, nil).Once()
+		// Every occurrence belongs to the series the payment was charged for
+		inSeries := mock.MatchedBy(func(got []reservation.ReservationCore) bool {
+			if len(got) != len(priced) {
+				return false
+			}
+			for i := range got {
+				want := priced[i]
+				want.SeriesID = seriesId
+				if !assert.ObjectsAreEqual(want, got[i]) {
+					return false
+				}
+			}
+			return true
+		})
+		data.On("MakeRecurringReservation", userId, inSeries, charged(pricedPayment)).Return(func(userId string, occurrences []reservation.ReservationCore, p reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, error) {
+			return occurrences, p, nil
+		}).Once()
+
+		result, paymentResult, conflicts, err := service.MakeRecurringReservation(userId, request, rule, reservation.PaymentCore{})
+		assert.Nil(t, err)
+		assert.Empty(t, conflicts)
+		assert.True(t, strings.HasPrefix(seriesId, "SRS-"))
+		assert.Len(t, result, 3)
+		assert.Equal(t, seriesId, result[2].SeriesID)
+		assert.Equal(t, charged(pricedPayment), paymentResult)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - some occurrences are not available", func(t *testing.T) {
+		booked := occurrenceAt(1)
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Times(3)
+		data.On("GetClosures", venue.VenueID, mock.Anything, mock.Anything).Return([]reservation.ClosureCore{}, nil).Times(3)
+		data.On("GetReservationsByTimeSlot", venue.VenueID, booked.CheckInDate, booked.CheckOutDate).Return([]reservation.ReservationCore{{}}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, mock.Anything, mock.Anything).Return([]reservation.ReservationCore{}, nil).Twice()
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Twice()
+
+		result, _, conflicts, err := service.MakeRecurringReservation(userId, request, rule, reservation.PaymentCore{})
+		assert.EqualError(t, err, "some occurrences are not available")
+		assert.Nil(t, result)
+		assert.Equal(t, []reservation.ConflictCore{{
+			CheckInDate:  booked.CheckInDate,
+			CheckOutDate: booked.CheckOutDate,
+			Reason:       "reservation not available",
+		}}, conflicts)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid frequency", func(t *testing.T) {
+		invalid := reservation.RecurrenceCore{Frequency: "daily", Count: 3}
+		_, _, _, err := service.MakeRecurringReservation(userId, request, invalid, reservation.PaymentCore{})
+		assert.EqualError(t, err, "invalid frequency, expected weekly or biweekly")
+	})
+
+	t.Run("error - count and until together", func(t *testing.T) {
+		invalid := rule
+		invalid.Until = day.AddDate(0, 0, 14)
+		_, _, _, err := service.MakeRecurringReservation(userId, request, invalid, reservation.PaymentCore{})
+		assert.EqualError(t, err, "recurrence takes either count or until, not both")
+	})
+}
+
+func TestExpandRecurrence(t *testing.T) {
+	day := time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)
+	request := reservation.ReservationCore{
+		CheckInDate:  day.Add(19 * time.Hour),
+		CheckOutDate: day.Add(21 * time.Hour),
+		HoldID:       "HLD-1",
+	}
+
+	t.Run("biweekly until is inclusive", func(t *testing.T) {
+		rule := reservation.RecurrenceCore{Frequency: reservation.RecurrenceBiweekly, Until: day.AddDate(0, 0, 28)}
+		result, err := expandRecurrence(request, rule)
+		assert.Nil(t, err)
+		assert.Len(t, result, 3)
+		assert.Equal(t, day.AddDate(0, 0, 28).Add(19*time.Hour), result[2].CheckInDate)
+		assert.Equal(t, "HLD-1", result[0].HoldID)
+		assert.Equal(t, "", result[1].HoldID)
+	})
+
+	t.Run("error - too many occurrences", func(t *testing.T) {
+		rule := reservation.RecurrenceCore{Frequency: reservation.RecurrenceWeekly, Until: day.AddDate(2, 0, 0)}
+		_, err := expandRecurrence(request, rule)
+		assert.EqualError(t, err, "recurrence cannot exceed 52 occurrences")
+	})
+
+	t.Run("error - until before check_in_date", func(t *testing.T) {
+		rule := reservation.RecurrenceCore{Frequency: reservation.RecurrenceWeekly, Until: day.AddDate(0, 0, -1)}
+		_, err := expandRecurrence(request, rule)
+		assert.EqualError(t, err, "until cannot be before check_in_date")
+	})
+}
+
+func TestCancelReservation(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	series := []reservation.ReservationCore{
+		{ReservationID: "reservation_id_1", VenueID: "venue_id_1", SeriesID: "SRS-1", PaymentID: "payment_id_1", CheckInDate: time.Now().Add(30 * time.Minute), Subtotal: 200},
+		{ReservationID: "reservation_id_2", VenueID: "venue_id_1", SeriesID: "SRS-1", PaymentID: "payment_id_1", CheckInDate: nextWeek, Subtotal: 200},
+		{ReservationID: "reservation_id_3", VenueID: "venue_id_1", SeriesID: "SRS-1", PaymentID: "payment_id_1", CheckInDate: nextWeek.AddDate(0, 0, 7), Subtotal: 200},
+	}
+	paid := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "success", GrandTotal: "600.00"}
+	policy := []reservation.CancellationTierCore{
+		{HoursBefore: 24, RefundPercent: 100},
+		{HoursBefore: 6, RefundPercent: 50},
+		{HoursBefore: 0, RefundPercent: 0},
+	}
+	reason := "cancelled by customer, refunded under the venue cancellation policy"
+
+	t.Run("success - single occurrence is refunded", func(t *testing.T) {
+		recorded := reservation.RefundCore{RefundID: "RFD-1", PaymentID: "payment_id_1", OrderID: "SRS-1", Amount: 200, Reason: reason}
+		data.On("GetReservation", userId, "reservation_id_2").Return(series[1], paid, nil).Once()
+		data.On("GetSeries", "SRS-1").Return(series, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		payments.On("RefundTransaction", "SRS-1", int64(200), reason).Return(nil).Once()
+		data.On("CancelReservations", "payment_id_1", []string{"reservation_id_2"}, false).Return(nil).Once()
+		data.On("InsertRefund", reservation.RefundCore{PaymentID: "payment_id_1", OrderID: "SRS-1", Amount: 200, Reason: reason}).Return(recorded, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", series[1].CheckInDate, series[1].CheckOutDate).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		result, refunded, err := service.CancelReservation(userId, "reservation_id_2", "")
+		assert.Nil(t, err)
+		assert.Equal(t, []reservation.ReservationCore{series[1]}, result)
+		assert.Equal(t, recorded, refunded)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - partial refund closer to check-in", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_4", VenueID: "venue_id_1", PaymentID: "payment_id_2", CheckInDate: time.Now().Add(12 * time.Hour), Subtotal: 300}
+		payment := reservation.PaymentCore{PaymentID: "payment_id_2", Status: "success", GrandTotal: "300.00"}
+		data.On("GetReservation", userId, "reservation_id_4").Return(single, payment, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		payments.On("RefundTransaction", "reservation_id_4", int64(150), reason).Return(nil).Once()
+		data.On("CancelReservations", "payment_id_2", []string{"reservation_id_4"}, true).Return(nil).Once()
+		data.On("InsertRefund", mock.Anything).Return(reservation.RefundCore{RefundID: "RFD-2", Amount: 150}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_4", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 150.0, refunded.Amount)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - fees and tax are not refunded", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_10", VenueID: "venue_id_1", PaymentID: "payment_id_10", CheckInDate: nextWeek, Subtotal: 300}
+		payment := reservation.PaymentCore{PaymentID: "payment_id_10", Status: "success", GrandTotal: "341.00", ServiceFee: 7}
+		data.On("GetReservation", userId, "reservation_id_10").Return(single, payment, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		payments.On("RefundTransaction", "reservation_id_10", int64(300), reason).Return(nil).Once()
+		data.On("CancelReservations", "payment_id_10", []string{"reservation_id_10"}, true).Return(nil).Once()
+		data.On("InsertRefund", mock.Anything).Return(reservation.RefundCore{RefundID: "RFD-3", Amount: 300}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_10", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 300.0, refunded.Amount)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - booking without a subtotal is refunded from the payment subtotal", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_11", VenueID: "venue_id_1", PaymentID: "payment_id_11", CheckInDate: time.Now().Add(12 * time.Hour)}
+		payment := reservation.PaymentCore{PaymentID: "payment_id_11", Status: "success", GrandTotal: "210.00", ServiceFee: 10}
+		data.On("GetReservation", userId, "reservation_id_11").Return(single, payment, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		payments.On("RefundTransaction", "reservation_id_11", int64(100), reason).Return(nil).Once()
+		data.On("CancelReservations", "payment_id_11", []string{"reservation_id_11"}, true).Return(nil).Once()
+		data.On("InsertRefund", mock.Anything).Return(reservation.RefundCore{RefundID: "RFD-4", Amount: 100}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_11", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 100.0, refunded.Amount)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - credits go back to the balance", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_5", VenueID: "venue_id_1", PaymentID: "CRP-1", CheckInDate: time.Now().Add(12 * time.Hour), Duration: 2, Subtotal: 300}
+		payment := reservation.PaymentCore{PaymentID: "CRP-1", PaymentMethod: reservation.PaymentTypeCredits, Status: "success", GrandTotal: "0.00", Credits: 2}
+		data.On("GetReservation", userId, "reservation_id_5").Return(single, payment, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		data.On("CancelReservations", "CRP-1", []string{"reservation_id_5"}, true).Return(nil).Once()
+		data.On("RefundCredits", userId, "venue_id_1", "CRP-1", 1.0).Return(nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_5", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 0.0, refunded.Amount)
+		assert.Equal(t, 1.0, refunded.Credits)
+		data.AssertExpectations(t)
+		payments.AssertNotCalled(t, "RefundTransaction", "reservation_id_5", mock.Anything, mock.Anything)
+	})
+
+	t.Run("success - series refunds each occurrence by its own tier", func(t *testing.T) {
+		data.On("GetReservation", userId, "reservation_id_2").Return(series[1], paid, nil).Once()
+		data.On("GetSeries", "SRS-1").Return(series, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		payments.On("RefundTransaction", "SRS-1", int64(400), reason).Return(nil).Once()
+		data.On("CancelReservations", "payment_id_1", []string{"reservation_id_1", "reservation_id_2", "reservation_id_3"}, true).Return(nil).Once()
+		data.On("InsertRefund", mock.Anything).Return(reservation.RefundCore{RefundID: "RFD-3", Amount: 400}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Times(3)
+
+		result, refunded, err := service.CancelReservation(userId, "reservation_id_2", reservation.CancelSeries)
+		assert.Nil(t, err)
+		assert.Len(t, result, 3)
+		assert.Equal(t, 400.0, refunded.Amount)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - no refund in the last tier", func(t *testing.T) {
+		data.On("GetReservation", userId, "reservation_id_1").Return(series[0], paid, nil).Once()
+		data.On("GetSeries", "SRS-1").Return(series, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		data.On("CancelReservations", "payment_id_1", []string{"reservation_id_1"}, false).Return(nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_1", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 0.0, refunded.Amount)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - unpaid series cancelled partially", func(t *testing.T) {
+		pending := paid
+		pending.Status = "pending"
+		data.On("GetReservation", userId, "reservation_id_2").Return(series[1], pending, nil).Once()
+		data.On("GetSeries", "SRS-1").Return(series, nil).Once()
+
+		_, _, err := service.CancelReservation(userId, "reservation_id_2", "")
+		assert.EqualError(t, err, "unpaid series can only be cancelled as a whole")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - pending payment voids its charge", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_7", VenueID: "venue_id_1", PaymentID: "payment_id_3", CheckInDate: time.Now().Add(12 * time.Hour), Subtotal: 300}
+		pending := reservation.PaymentCore{PaymentID: "payment_id_3", PaymentMethod: "bca", Status: "pending", GrandTotal: "300.00"}
+		data.On("GetReservation", userId, "reservation_id_7").Return(single, pending, nil).Once()
+		payments.On("Cancel", "reservation_id_7").Return(nil).Once()
+		data.On("CancelReservations", "payment_id_3", []string{"reservation_id_7"}, true).Return(nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_7", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 0.0, refunded.Amount)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - pending charge cannot be voided", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_8", VenueID: "venue_id_1", PaymentID: "payment_id_4", CheckInDate: time.Now().Add(12 * time.Hour), Subtotal: 300}
+		pending := reservation.PaymentCore{PaymentID: "payment_id_4", PaymentMethod: "bca", Status: "pending", GrandTotal: "300.00"}
+		data.On("GetReservation", userId, "reservation_id_8").Return(single, pending, nil).Once()
+		payments.On("Cancel", "reservation_id_8").Return(errors.New("gateway timeout")).Once()
+
+		_, _, err := service.CancelReservation(userId, "reservation_id_8", "")
+		assert.EqualError(t, err, "failed to cancel payment")
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+		data.AssertNotCalled(t, "CancelReservations", "payment_id_4", mock.Anything, mock.Anything)
+	})
+
+	t.Run("success - split payment is refunded to every player", func(t *testing.T) {
+		single := reservation.ReservationCore{ReservationID: "reservation_id_6", VenueID: "venue_id_1", PaymentID: "SPL-1", CheckInDate: time.Now().Add(12 * time.Hour), Subtotal: 200}
+		payment := reservation.PaymentCore{PaymentID: "SPL-1", PaymentMethod: reservation.PaymentMethodSplit, Status: "success", GrandTotal: "200"}
+		shares := []reservation.ShareCore{
+			{ShareID: "SHR-1", PaymentID: "SPL-1", Amount: 68, Charge: reservation.PaymentCore{PaymentID: "charge_1", Status: "success"}},
+			{ShareID: "SHR-2", PaymentID: "SPL-1", Amount: 66, Charge: reservation.PaymentCore{PaymentID: "charge_2", Status: "success"}},
+			{ShareID: "SHR-3", PaymentID: "SPL-1", Amount: 66, Charge: reservation.PaymentCore{PaymentID: "charge_3", Status: "success"}},
+		}
+		data.On("GetReservation", userId, "reservation_id_6").Return(single, payment, nil).Once()
+		data.On("GetShares", "SPL-1").Return(shares, nil).Once()
+		data.On("GetCancellationPolicy", "venue_id_1").Return(policy, nil).Once()
+		for i, amount := range []int64{34, 33, 33} {
+			payments.On("RefundTransaction", shares[i].ShareID, amount, reason).Return(nil).Once()
+			data.On("InsertRefund", reservation.RefundCore{PaymentID: "SPL-1", OrderID: shares[i].ShareID, Amount: float64(amount), Reason: reason}).Return(reservation.RefundCore{}, nil).Once()
+			data.On("ReservationStatus", reservation.PaymentCore{PaymentID: shares[i].Charge.PaymentID, Status: "cancel"}).Return(reservation.PaymentCore{}, nil).Once()
+		}
+		data.On("CancelReservations", "SPL-1", []string{"reservation_id_6"}, true).Return(nil).Once()
+		data.On("GetWaitlist", "venue_id_1", mock.Anything, mock.Anything).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		_, refunded, err := service.CancelReservation(userId, "reservation_id_6", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 100.0, refunded.Amount)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - after check-in", func(t *testing.T) {
+		started := reservation.ReservationCore{ReservationID: "reservation_id_5", VenueID: "venue_id_1", CheckInDate: time.Now().Add(-30 * time.Minute)}
+		data.On("GetReservation", userId, "reservation_id_5").Return(started, paid, nil).Once()
+
+		_, _, err := service.CancelReservation(userId, "reservation_id_5", "")
+		assert.EqualError(t, err, "reservation cannot be cancelled after check-in")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid scope", func(t *testing.T) {
+		_, _, err := service.CancelReservation(userId, "reservation_id_1", "all")
+		assert.EqualError(t, err, "invalid scope, expected occurrence or series")
+	})
+}
+
+func TestRefundPercent(t *testing.T) {
+	policy := []reservation.CancellationTierCore{
+		{HoursBefore: 24, RefundPercent: 100},
+		{HoursBefore: 6, RefundPercent: 50},
+	}
+
+	assert.Equal(t, 100, refundPercent(policy, time.Now().Add(48*time.Hour)))
+	assert.Equal(t, 50, refundPercent(policy, time.Now().Add(12*time.Hour)))
+	assert.Equal(t, 0, refundPercent(policy, time.Now().Add(2*time.Hour)))
+	assert.Equal(t, 100, refundPercent(nil, time.Now().Add(2*time.Hour)))
+	assert.Equal(t, 0, refundPercent(nil, time.Now().Add(30*time.Minute)))
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	venue := reservation.VenueCore{VenueID: "venue_id_1", ServiceTime: "07:00 - 23:00"}
+	request := reservation.WaitlistCore{
+		VenueID:      "venue_id_1",
+		CheckInDate:  day.Add(9 * time.Hour),
+		CheckOutDate: day.Add(11 * time.Hour),
+	}
+	expected := request
+	expected.UserID = userId
+
+	t.Run("success", func(t *testing.T) {
+		created := expected
+		created.WaitlistID = "WTL-1"
+		created.Status = reservation.WaitlistWaiting
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ReservationCore{{}}, nil).Once()
+		data.On("InsertWaitlist", expected).Return(created, nil).Once()
+
+		result, err := service.JoinWaitlist(userId, request)
+		assert.Nil(t, err)
+		assert.Equal(t, created, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - slot is available", func(t *testing.T) {
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+
+		_, err := service.JoinWaitlist(userId, request)
+		assert.EqualError(t, err, "reservation is available, no need to join the waitlist")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - already on the waitlist", func(t *testing.T) {
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Once()
+		data.On("GetClosures", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, request.CheckInDate, request.CheckOutDate).Return([]reservation.ReservationCore{{}}, nil).Once()
+		data.On("InsertWaitlist", expected).Return(reservation.WaitlistCore{}, errors.New("already on the waitlist")).Once()
+
+		_, err := service.JoinWaitlist(userId, request)
+		assert.EqualError(t, err, "already on the waitlist")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - check_out_date is empty", func(t *testing.T) {
+		invalid := request
+		invalid.CheckOutDate = time.Time{}
+		_, err := service.JoinWaitlist(userId, invalid)
+		assert.EqualError(t, err, "check_out_date cannot be empty")
+	})
+}
+
+func TestWaitlistOffer(t *testing.T) {
+	waitlistTemplate = "../../../utils/email/waitlist_template.html"
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(time.Hour)
+	checkOut := checkIn.Add(2 * time.Hour)
+	first := reservation.WaitlistCore{
+		WaitlistID:   "WTL-1",
+		UserID:       "user_id_1",
+		VenueID:      "venue_id_1",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		Status:       reservation.WaitlistWaiting,
+		Fullname:     "First User",
+		Email:        "first@mail.com",
+		VenueName:    "Futsal Arena",
+	}
+	second := first
+	second.WaitlistID = "WTL-2"
+	second.UserID = "user_id_2"
+	second.Email = "second@mail.com"
+	hold := reservation.HoldCore{
+		HoldID:       "HLD-1",
+		VenueID:      "venue_id_1",
+		UserID:       "user_id_1",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		ExpiresAt:    time.Now().Add(defaultOfferTTL),
+	}
+
+	t.Run("success - expired payment offers the slot to the first in line", func(t *testing.T) {
+		expired := []reservation.PaymentCore{{PaymentID: "payment_id_1", Status: "expire"}}
+		offered := first
+		offered.Status = reservation.WaitlistOffered
+		offered.HoldID = hold.HoldID
+		offered.OfferExpiresAt = hold.ExpiresAt
+		data.On("GetOverduePayments", mock.Anything).Return([]reservation.PaymentCore{{PaymentID: "payment_id_1", Status: "pending"}}, nil).Once()
+		payments.On("Cancel", "payment_id_1").Return(nil).Once()
+		data.On("ExpirePendingPayments", []string{"payment_id_1"}, mock.AnythingOfType("string")).Return(expired, nil).Once()
+		data.On("GetPaymentReservations", "payment_id_1").Return([]reservation.ReservationCore{{VenueID: "venue_id_1", CheckInDate: checkIn, CheckOutDate: checkOut}}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", checkIn, checkOut).Return([]reservation.WaitlistCore{first, second}, nil).Once()
+		data.On("GetReservationsByTimeSlot", "venue_id_1", checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Twice()
+		data.On("InsertHold", reservation.HoldCore{VenueID: "venue_id_1", UserID: "user_id_1", CheckInDate: checkIn, CheckOutDate: checkOut}, defaultOfferTTL).Return(hold, nil).Once()
+		data.On("InsertHold", reservation.HoldCore{VenueID: "venue_id_1", UserID: "user_id_2", CheckInDate: checkIn, CheckOutDate: checkOut}, defaultOfferTTL).Return(reservation.HoldCore{}, errors.New("slot is held by another user")).Once()
+		data.On("UpdateWaitlist", offered).Return(nil).Once()
+		email.On("SendEmail", mock.AnythingOfType("string"), mock.MatchedBy(func(content string) bool {
+			return strings.Contains(content, "HLD-1") && strings.Contains(content, "Futsal Arena")
+		}), []string{"first@mail.com"}, []string(nil), []string(nil), []string(nil)).Return(nil).Once()
+
+		_, err := service.ExpirePendingPayments()
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+		email.AssertExpectations(t)
+	})
+
+	t.Run("success - unclaimed offer moves on to the next in line", func(t *testing.T) {
+		lapsed := first
+		lapsed.Status = reservation.WaitlistExpired
+		nextHold := hold
+		nextHold.HoldID = "HLD-2"
+		nextHold.UserID = "user_id_2"
+		offered := second
+		offered.Status = reservation.WaitlistOffered
+		offered.HoldID = nextHold.HoldID
+		offered.OfferExpiresAt = nextHold.ExpiresAt
+		data.On("ExpireWaitlistOffers", mock.AnythingOfType("time.Time")).Return([]reservation.WaitlistCore{lapsed}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", checkIn, checkOut).Return([]reservation.WaitlistCore{second}, nil).Once()
+		data.On("GetReservationsByTimeSlot", "venue_id_1", checkIn, checkOut).Return([]reservation.ReservationCore{}, nil).Once()
+		data.On("InsertHold", reservation.HoldCore{VenueID: "venue_id_1", UserID: "user_id_2", CheckInDate: checkIn, CheckOutDate: checkOut}, defaultOfferTTL).Return(nextHold, nil).Once()
+		data.On("UpdateWaitlist", offered).Return(nil).Once()
+		email.On("SendEmail", mock.AnythingOfType("string"), mock.AnythingOfType("string"), []string{"second@mail.com"}, []string(nil), []string(nil), []string(nil)).Return(nil).Once()
+
+		result, err := service.ExpireWaitlistOffers()
+		assert.Nil(t, err)
+		assert.Equal(t, []reservation.WaitlistCore{lapsed}, result)
+		data.AssertExpectations(t)
+		email.AssertExpectations(t)
+	})
+
+	t.Run("success - slot still partly booked keeps the entry waiting", func(t *testing.T) {
+		data.On("GetPaymentReservations", "payment_id_2").Return([]reservation.ReservationCore{{VenueID: "venue_id_1", CheckInDate: checkIn, CheckOutDate: checkIn.Add(time.Hour)}}, nil).Once()
+		data.On("GetWaitlist", "venue_id_1", checkIn, checkIn.Add(time.Hour)).Return([]reservation.WaitlistCore{first}, nil).Once()
+		data.On("GetReservationsByTimeSlot", "venue_id_1", checkIn, checkOut).Return([]reservation.ReservationCore{{}}, nil).Once()
+
+		request := reservation.PaymentCore{PaymentID: "payment_id_2", Status: "expire", GrandTotal: "100.00"}
+		data.On("GetPayment", "payment_id_2").Return(reservation.PaymentCore{PaymentID: "payment_id_2", Status: "pending", GrandTotal: "100.00"}, nil).Once()
+		data.On("ReservationStatus", request).Return(request, nil).Once()
+
+		_, err := service.ReservationStatus(request)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	entry := reservation.WaitlistCore{
+		WaitlistID:   "WTL-1",
+		UserID:       userId,
+		VenueID:      "venue_id_1",
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 1).Add(time.Hour),
+		Status:       reservation.WaitlistWaiting,
+	}
+
+	t.Run("success - waiting", func(t *testing.T) {
+		cancelled := entry
+		cancelled.Status = reservation.WaitlistCancelled
+		data.On("GetWaitlistEntry", userId, "WTL-1").Return(entry, nil).Once()
+		data.On("UpdateWaitlist", cancelled).Return(nil).Once()
+
+		err := service.LeaveWaitlist(userId, "WTL-1")
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - declined offer is passed on", func(t *testing.T) {
+		offered := entry
+		offered.Status = reservation.WaitlistOffered
+		offered.HoldID = "HLD-1"
+		cancelled := offered
+		cancelled.Status = reservation.WaitlistCancelled
+		data.On("GetWaitlistEntry", userId, "WTL-1").Return(offered, nil).Once()
+		data.On("UpdateWaitlist", cancelled).Return(nil).Once()
+		data.On("DeleteHold", "venue_id_1", "HLD-1").Return(nil).Once()
+		data.On("GetWaitlist", "venue_id_1", entry.CheckInDate, entry.CheckOutDate).Return([]reservation.WaitlistCore{}, nil).Once()
+
+		err := service.LeaveWaitlist(userId, "WTL-1")
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		data.On("GetWaitlistEntry", userId, "WTL-404").Return(reservation.WaitlistCore{}, errors.New("waitlist entry not found")).Once()
+
+		err := service.LeaveWaitlist(userId, "WTL-404")
+		assert.EqualError(t, err, "waitlist entry not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - no longer active", func(t *testing.T) {
+		claimed := entry
+		claimed.Status = reservation.WaitlistClaimed
+		data.On("GetWaitlistEntry", userId, "WTL-1").Return(claimed, nil).Once()
+
+		err := service.LeaveWaitlist(userId, "WTL-1")
+		assert.EqualError(t, err, "waitlist entry is no longer active")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestRescheduleReservation(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	userId := "user_id_1"
+	tomorrow := time.Now().AddDate(0, 0, 2)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
+	venue := reservation.VenueCore{VenueID: "venue_id_1", ServiceTime: "07:00 - 23:00"}
+	target := reservation.ReservationCore{
+		ReservationID: "reservation_id_1",
+		VenueID:       "venue_id_1",
+		PaymentID:     "payment_id_1",
+		CheckInDate:   day.Add(9 * time.Hour),
+		CheckOutDate:  day.Add(11 * time.Hour),
+		Duration:      2,
+		Subtotal:      200,
+	}
+	paid := reservation.PaymentCore{PaymentID: "payment_id_1", Status: "success", GrandTotal: "200.00"}
+	expectSlot := func(checkIn, checkOut time.Time) {
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Twice()
+		data.On("GetClosures", venue.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		// The reservation itself overlaps its new slot
+		data.On("GetReservationsByTimeSlot", venue.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{target}, nil).Once()
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", venue.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", venue.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+		data.On("GetWaitlist", venue.VenueID, target.CheckInDate, target.CheckOutDate).Return([]reservation.WaitlistCore{}, nil).Once()
+	}
+
+	t.Run("success - same price is moved without payment", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(12*time.Hour)
+		moved := target
+		moved.CheckInDate, moved.CheckOutDate = checkIn, checkOut
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, paid, nil).Once()
+		expectSlot(checkIn, checkOut)
+		data.On("RescheduleReservation", moved, reservation.PaymentCore{}).Return(moved, reservation.PaymentCore{}, nil).Once()
+
+		result, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{})
+		assert.Nil(t, err)
+		assert.Equal(t, moved, result.Reservation)
+		assert.Equal(t, 0.0, result.PriceDifference)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - longer slot charges the difference", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(13*time.Hour)
+		moved := target
+		moved.CheckInDate, moved.CheckOutDate = checkIn, checkOut
+		moved.Duration, moved.Subtotal = 3, 300
+		charge := reservation.PaymentCore{
+			PaymentType: "bca",
+			GrandTotal:  "100",
+			Items:       []reservation.PaymentItemCore{{Kind: "booking", Description: "Reschedule price difference", Amount: 100}},
+		}
+		recorded := charged(charge)
+		recorded.Purpose, recorded.ReferenceID = reservation.PaymentForReschedule, "reservation_id_1"
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, paid, nil).Once()
+		expectSlot(checkIn, checkOut)
+		var orderId string
+		payments.On("Charge", mock.AnythingOfType("string"), charge).Run(func(args mock.Arguments) {
+			orderId = args.String(0)
+		}).Return(pendingCharge, nil).Once()
+		data.On("RescheduleReservation", moved, charged(charge)).Return(moved, recorded, nil).Once()
+
+		result, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{PaymentType: "bca"})
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(orderId, "RSC-"))
+		assert.Equal(t, 100.0, result.PriceDifference)
+		assert.Equal(t, recorded, result.Payment)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - shorter slot refunds the difference", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(11*time.Hour)
+		moved := target
+		moved.CheckInDate, moved.CheckOutDate = checkIn, checkOut
+		moved.Duration, moved.Subtotal = 1, 100
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, paid, nil).Once()
+		expectSlot(checkIn, checkOut)
+		data.On("RescheduleReservation", moved, reservation.PaymentCore{}).Return(moved, reservation.PaymentCore{}, nil).Once()
+		payments.On("RefundTransaction", "reservation_id_1", int64(100), "rescheduled by customer").Return(nil).Once()
+		refund := reservation.RefundCore{PaymentID: "payment_id_1", OrderID: "reservation_id_1", Amount: 100, Reason: "rescheduled by customer"}
+		data.On("InsertRefund", refund).Return(refund, nil).Once()
+
+		result, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{})
+		assert.Nil(t, err)
+		assert.Equal(t, -100.0, result.PriceDifference)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - failed refund does not undo the move", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(11*time.Hour)
+		moved := target
+		moved.CheckInDate, moved.CheckOutDate = checkIn, checkOut
+		moved.Duration, moved.Subtotal = 1, 100
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, paid, nil).Once()
+		expectSlot(checkIn, checkOut)
+		data.On("RescheduleReservation", moved, reservation.PaymentCore{}).Return(moved, reservation.PaymentCore{}, nil).Once()
+		payments.On("RefundTransaction", "reservation_id_1", int64(100), "rescheduled by customer").Return(errors.New("gateway timeout")).Once()
+
+		result, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{})
+		assert.Nil(t, err)
+		assert.Equal(t, moved, result.Reservation)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("success - split payment refunds every share", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(11*time.Hour)
+		moved := target
+		moved.CheckInDate, moved.CheckOutDate = checkIn, checkOut
+		moved.Duration, moved.Subtotal = 1, 100
+		split := reservation.PaymentCore{PaymentID: "SPL-1", PaymentMethod: reservation.PaymentMethodSplit, Status: "success", GrandTotal: "200.00"}
+		shares := []reservation.ShareCore{
+			{ShareID: "SHR-1", PaymentID: "SPL-1", Amount: 100, Charge: reservation.PaymentCore{PaymentID: "charge_1", Status: "success"}},
+			{ShareID: "SHR-2", PaymentID: "SPL-1", Amount: 100, Charge: reservation.PaymentCore{PaymentID: "charge_2", Status: "success"}},
+		}
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, split, nil).Once()
+		expectSlot(checkIn, checkOut)
+		data.On("RescheduleReservation", moved, reservation.PaymentCore{}).Return(moved, reservation.PaymentCore{}, nil).Once()
+		data.On("GetShares", "SPL-1").Return(shares, nil).Once()
+		for _, share := range shares {
+			payments.On("RefundTransaction", share.ShareID, int64(50), "rescheduled by customer").Return(nil).Once()
+			data.On("InsertRefund", reservation.RefundCore{PaymentID: "SPL-1", OrderID: share.ShareID, Amount: 50, Reason: "rescheduled by customer"}).Return(reservation.RefundCore{}, nil).Once()
+		}
+
+		result, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{})
+		assert.Nil(t, err)
+		assert.Equal(t, -100.0, result.PriceDifference)
+		data.AssertExpectations(t)
+		payments.AssertExpectations(t)
+	})
+
+	t.Run("error - unpaid reservation cannot change price", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(13*time.Hour)
+		pending := paid
+		pending.Status = "pending"
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, pending, nil).Once()
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Twice()
+		data.On("GetClosures", venue.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{target}, nil).Once()
+		data.On("GetHolds", venue.VenueID).Return([]reservation.HoldCore{}, nil).Once()
+		data.On("PriceVenue", venue.VenueID).Return(100.0, nil).Once()
+		data.On("GetPricingRules", venue.VenueID).Return([]reservation.PricingRuleCore{}, nil).Once()
+
+		_, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{PaymentType: "bca"})
+		assert.EqualError(t, err, "reservation must be paid before its price can change")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - new slot is taken", func(t *testing.T) {
+		checkIn, checkOut := day.Add(10*time.Hour), day.Add(12*time.Hour)
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, paid, nil).Once()
+		data.On("GetVenue", venue.VenueID).Return(venue, nil).Twice()
+		data.On("GetClosures", venue.VenueID, checkIn, checkOut).Return([]reservation.ClosureCore{}, nil).Once()
+		data.On("GetReservationsByTimeSlot", venue.VenueID, checkIn, checkOut).Return([]reservation.ReservationCore{target, {ReservationID: "reservation_id_2"}}, nil).Once()
+
+		_, err := service.RescheduleReservation(userId, "reservation_id_1", checkIn, checkOut, reservation.PaymentCore{})
+		assert.EqualError(t, err, "reservation not available")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - reschedule cutoff has passed", func(t *testing.T) {
+		strict := venue
+		strict.RescheduleCutoff = 72
+		data.On("GetReservation", userId, "reservation_id_1").Return(target, paid, nil).Once()
+		data.On("GetVenue", venue.VenueID).Return(strict, nil).Once()
+
+		_, err := service.RescheduleReservation(userId, "reservation_id_1", day.Add(10*time.Hour), day.Add(12*time.Hour), reservation.PaymentCore{})
+		assert.EqualError(t, err, "reservation cannot be rescheduled less than 72 hour(s) before check-in")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - check_out_date before check_in_date", func(t *testing.T) {
+		_, err := service.RescheduleReservation(userId, "reservation_id_1", day.Add(12*time.Hour), day.Add(10*time.Hour), reservation.PaymentCore{})
+		assert.EqualError(t, err, "check_out_date must be after check_in_date")
+	})
+}
+
+func TestQuoteReservation(t *testing.T) {
+	data := mocks.NewReservationData(t)
+	payments := mocks.NewPaymentProvider(t)
+	email := mocks.NewEmailSender(t)
+	service := New(data, payments, email)
+	venueId := "venue_id_1"
+	friday := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+	rules := []reservation.PricingRuleCore{
+		{Name: "weekend", Weekdays: []int{0, 6}, Price: 200000, Priority: 1},
+		{Name: "weekday evening", Weekdays: []int{1, 2, 3, 4, 5}, StartTime: "18:00", EndTime: "00:00", Price: 150000},
+		{Name: "long booking", MinDuration: 4, Price: 80000, Priority: 2},
+	}
+
+	t.Run("success - split across rule boundaries", func(t *testing.T) {
+		data.On("PriceVenue", venueId).Return(100000.0, nil).Once()
+		data.On("GetPricingRules", venueId).Return(rules, nil).Once()
+
+		result, err := service.QuoteReservation(venueId, friday.Add(17*time.Hour), friday.Add(20*time.Hour))
+		assert.Nil(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, "", result.Items[0].Rule)
+		assert.Equal(t, 100000.0, result.Items[0].Amount)
+		assert.Equal(t, "weekday evening", result.Items[1].Rule)
+		assert.Equal(t, 300000.0, result.Items[1].Amount)
+		assert.Equal(t, 400000.0, result.Total)
+		assert.Equal(t, 3.0, result.Duration)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - overnight into the weekend", func(t *testing.T) {
+		data.On("PriceVenue", venueId).Return(100000.0, nil).Once()
+		data.On("GetPricingRules", venueId).Return(rules, nil).Once()
+
+		result, err := service.QuoteReservation(venueId, friday.Add(23*time.Hour), friday.Add(25*time.Hour))
+		assert.Nil(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, "weekday evening", result.Items[0].Rule)
+		assert.Equal(t, "weekend", result.Items[1].Rule)
+		assert.Equal(t, 350000.0, result.Total)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - minimum duration rule", func(t *testing.T) {
+		data.On("PriceVenue", venueId).Return(100000.0, nil).Once()
+		data.On("GetPricingRules", venueId).Return(rules, nil).Once()
+
+		result, err := service.QuoteReservation(venueId, friday.Add(16*time.Hour), friday.Add(20*time.Hour))
+		assert.Nil(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, "long booking", result.Items[0].Rule)
+		assert.Equal(t, 320000.0, result.Total)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - check out before check in", func(t *testing.T) {
+		_, err := service.QuoteReservation(venueId, friday.Add(20*time.Hour), friday.Add(18*time.Hour))
+		assert.EqualError(t, err, "check_out_date must be after check_in_date")
+	})
+
+	t.Run("error - venue not found", func(t *testing.T) {
+		data.On("PriceVenue", venueId).Return(0.0, errors.New("venue not found")).Once()
+
+		_, err := service.QuoteReservation(venueId, friday.Add(17*time.Hour), friday.Add(20*time.Hour))
+		assert.EqualError(t, err, "venue not found")
+		data.AssertExpectations(t)
+	})
+}
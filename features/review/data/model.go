@@ -37,7 +37,7 @@ type User struct {
 type Venue struct {
 	VenueID     string         `gorm:"primaryKey;type:varchar(45)"`
 	OwnerID     string         `gorm:"type:varchar(45)"`
-	Category    string         `gorm:"type:enum('Basketball','Football','Futsal','Badminton','Swimming');default:'Basketball'"`
+	CategoryID  string         `gorm:"type:varchar(45)"`
 	Name        string         `gorm:"type:varchar(225);not null"`
 	Description string         `gorm:"type:text"`
 	Location    string         `gorm:"type:text"`
@@ -91,7 +91,7 @@ func VenueModelToCore(v Venue) review.VenueCore {
 	return review.VenueCore{
 		VenueID:     v.VenueID,
 		OwnerID:     v.OwnerID,
-		Category:    v.Category,
+		CategoryID:  v.CategoryID,
 		Name:        v.Name,
 		Description: v.Description,
 		Location:    v.Location,
@@ -35,7 +35,7 @@ type UserCore struct {
 type VenueCore struct {
 	VenueID     string
 	OwnerID     string
-	Category    string
+	CategoryID  string
 	Name        string
 	Description string
 	Location    string
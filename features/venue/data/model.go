@@ -2,6 +2,8 @@ package data
 
 import (
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation/data"
@@ -11,23 +13,120 @@ import (
 )
 
 type Venue struct {
-	VenueID       string          `gorm:"primaryKey;type:varchar(45)"`
-	OwnerID       string          `gorm:"type:varchar(45)"`
-	Category      string          `gorm:"type:enum('basketball','football','futsal','badminton');default:'basketball'"`
-	Name          string          `gorm:"type:varchar(225);not null;unique"`
-	Description   string          `gorm:"type:text"`
-	ServiceTime   string          `gorm:"type:varchar(100)"`
-	Location      string          `gorm:"type:text"`
-	Price         float64         `gorm:"type:double"`
-	Longitude     float64         `gorm:"type:double"`
-	Latitude      float64         `gorm:"type:double"`
-	CreatedAt     time.Time       `gorm:"type:datetime"`
-	UpdatedAt     time.Time       `gorm:"type:datetime"`
-	DeletedAt     gorm.DeletedAt  `gorm:"index"`
-	User          User            `gorm:"references:OwnerID;foreignKey:UserID"`
-	VenuePictures []VenuePicture  `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Reservations  []Reservation   `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	Reviews       []review.Review `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	VenueID     string  `gorm:"primaryKey;type:varchar(45)"`
+	OwnerID     string  `gorm:"type:varchar(45)"`
+	CategoryID  string  `gorm:"type:varchar(45);index"`
+	Name        string  `gorm:"type:varchar(225);not null;unique;index:idx_venues_fulltext,class:FULLTEXT"`
+	Description string  `gorm:"type:text;index:idx_venues_fulltext,class:FULLTEXT"`
+	ServiceTime string  `gorm:"type:varchar(100)"`
+	Location    string  `gorm:"type:text;index:idx_venues_fulltext,class:FULLTEXT"`
+	Price       float64 `gorm:"type:double"`
+	Longitude   float64 `gorm:"type:double"`
+	Latitude    float64 `gorm:"type:double"`
+	// Coordinates is generated by MySQL from latitude and longitude so radius and map
+	// searches can go through the spatial index. It is never read or written directly.
+	Coordinates []byte `gorm:"type:point SRID 4326 AS (ST_SRID(POINT(longitude, latitude), 4326)) STORED;not null;index:idx_venues_coordinates,class:SPATIAL;->:false;<-:false"`
+	// RescheduleCutoff is how many hours before check-in a booking can still be moved
+	RescheduleCutoff     uint                 `gorm:"default:0"`
+	CreatedAt            time.Time            `gorm:"type:datetime"`
+	UpdatedAt            time.Time            `gorm:"type:datetime"`
+	DeletedAt            gorm.DeletedAt       `gorm:"index"`
+	User                 User                 `gorm:"references:OwnerID;foreignKey:UserID"`
+	Category             Category             `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	VenuePictures        []VenuePicture       `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Reservations         []Reservation        `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Reviews              []review.Review      `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	OpeningHours         []VenueHour          `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Closures             []VenueClosure       `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CancellationPolicies []CancellationPolicy `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PricingRules         []PricingRule        `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Amenities            []Amenity            `gorm:"many2many:venue_amenities;joinForeignKey:VenueID;joinReferences:AmenityID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// Amenity is an entry of the amenities catalog venues are tagged with through the
+// venue_amenities table.
+type Amenity struct {
+	AmenityID string    `gorm:"primaryKey;type:varchar(45)"`
+	Code      string    `gorm:"type:varchar(30);not null;uniqueIndex"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	Kind      string    `gorm:"type:enum('facility','setting','surface');default:'facility'"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+	UpdatedAt time.Time `gorm:"type:datetime"`
+}
+
+// DefaultAmenities is the catalog created on the first migration.
+var DefaultAmenities = []Amenity{
+	{Code: "parking", Name: "Parking", Kind: venue.AmenityFacility},
+	{Code: "showers", Name: "Showers", Kind: venue.AmenityFacility},
+	{Code: "changing-rooms", Name: "Changing rooms", Kind: venue.AmenityFacility},
+	{Code: "lighting", Name: "Lighting", Kind: venue.AmenityFacility},
+	{Code: "equipment-rental", Name: "Equipment rental", Kind: venue.AmenityFacility},
+	{Code: "indoor", Name: "Indoor", Kind: venue.AmenitySetting},
+	{Code: "outdoor", Name: "Outdoor", Kind: venue.AmenitySetting},
+	{Code: "synthetic-grass", Name: "Synthetic grass", Kind: venue.AmenitySurface},
+	{Code: "natural-grass", Name: "Natural grass", Kind: venue.AmenitySurface},
+	{Code: "wood", Name: "Wooden floor", Kind: venue.AmenitySurface},
+	{Code: "vinyl", Name: "Vinyl", Kind: venue.AmenitySurface},
+	{Code: "concrete", Name: "Concrete", Kind: venue.AmenitySurface},
+}
+
+// Category is the sport a venue is listed under, managed by admins.
+type Category struct {
+	CategoryID string `gorm:"primaryKey;type:varchar(45)"`
+	Name       string `gorm:"type:varchar(30);not null;uniqueIndex"`
+}
+
+// VenueHour is one opening shift of a venue on a weekday (0 = Sunday).
+// A close time not after the open time runs past midnight.
+type VenueHour struct {
+	VenueHourID string    `gorm:"primaryKey;type:varchar(45)"`
+	VenueID     string    `gorm:"type:varchar(45);index"`
+	Weekday     int       `gorm:"type:tinyint"`
+	OpenTime    string    `gorm:"type:varchar(5)"`
+	CloseTime   string    `gorm:"type:varchar(5)"`
+	CreatedAt   time.Time `gorm:"type:datetime"`
+	UpdatedAt   time.Time `gorm:"type:datetime"`
+}
+
+// CancellationPolicy is one refund tier of a venue: bookings cancelled at least HoursBefore
+// hours before check-in get RefundPercent of their price back.
+type CancellationPolicy struct {
+	CancellationPolicyID string    `gorm:"primaryKey;type:varchar(45)"`
+	VenueID              string    `gorm:"type:varchar(45);index"`
+	HoursBefore          int       `gorm:"type:int"`
+	RefundPercent        int       `gorm:"type:tinyint"`
+	CreatedAt            time.Time `gorm:"type:datetime"`
+	UpdatedAt            time.Time `gorm:"type:datetime"`
+}
+
+// PricingRule overrides the hourly price of a venue. Weekdays is a comma separated list
+// (0 = Sunday), empty columns leave that dimension unrestricted.
+type PricingRule struct {
+	PricingRuleID string     `gorm:"primaryKey;type:varchar(45)"`
+	VenueID       string     `gorm:"type:varchar(45);index"`
+	Name          string     `gorm:"type:varchar(100)"`
+	Weekdays      string     `gorm:"type:varchar(20)"`
+	StartTime     string     `gorm:"type:varchar(5)"`
+	EndTime       string     `gorm:"type:varchar(5)"`
+	StartDate     *time.Time `gorm:"type:datetime"`
+	EndDate       *time.Time `gorm:"type:datetime"`
+	MinDuration   float64    `gorm:"type:double"`
+	Price         float64    `gorm:"type:double"`
+	Priority      int        `gorm:"type:int"`
+	CreatedAt     time.Time  `gorm:"type:datetime"`
+	UpdatedAt     time.Time  `gorm:"type:datetime"`
+}
+
+// VenueClosure blocks bookings of a venue between two dates, e.g. maintenance or public holidays.
+type VenueClosure struct {
+	VenueClosureID string         `gorm:"primaryKey;type:varchar(45)"`
+	VenueID        string         `gorm:"type:varchar(45);index"`
+	StartDate      time.Time      `gorm:"type:datetime"`
+	EndDate        time.Time      `gorm:"type:datetime"`
+	Reason         string         `gorm:"type:varchar(225)"`
+	CreatedAt      time.Time      `gorm:"type:datetime"`
+	UpdatedAt      time.Time      `gorm:"type:datetime"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 type User struct {
@@ -87,7 +186,8 @@ type Reservation struct {
 type Venues struct {
 	VenueID       string  `gorm:"primaryKey;type:varchar(45)"`
 	OwnerID       string  `gorm:"type:varchar(45)"`
-	Category      string  `gorm:"type:enum('basketball','football','futsal','badminton');default:'basketball'"`
+	CategoryID    string  `gorm:"type:varchar(45)"`
+	Category      string  `gorm:"type:varchar(30)"`
 	Name          string  `gorm:"type:varchar(225);not null;unique"`
 	Description   string  `gorm:"type:text"`
 	ServiceTime   string  `gorm:"type:varchar(100)"`
@@ -97,6 +197,7 @@ type Venues struct {
 	Latitude      float64 `gorm:"type:double"`
 	Distance      float64 `gorm:"type:double"`
 	TotalReviews  uint
+	TotalBookings uint
 	AverageRating float64         `gorm:"type:double"`
 	VenuePicture  string          `gorm:"type:text"`
 	CreatedAt     time.Time       `gorm:"type:datetime"`
@@ -108,15 +209,41 @@ type Venues struct {
 	Reviews       []review.Review `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
 
+// MapMarker is a venue or a cluster of venues scanned for the map.
+type MapMarker struct {
+	VenueID   string
+	Name      string
+	Category  string
+	Price     float64
+	Latitude  float64
+	Longitude float64
+	Count     int64
+}
+
+func mapMarkerModel(m MapMarker) venue.MapMarkerCore {
+	return venue.MapMarkerCore{
+		VenueID:   m.VenueID,
+		Name:      m.Name,
+		Category:  m.Category,
+		Price:     m.Price,
+		Latitude:  m.Latitude,
+		Longitude: m.Longitude,
+		Count:     m.Count,
+	}
+}
+
 func searchVenueModel(v Venues) venue.VenueCoreRaw {
 	result := venue.VenueCoreRaw{
 		VenueID:       v.VenueID,
+		CategoryID:    v.CategoryID,
 		Category:      v.Category,
 		Name:          v.Name,
 		OwnerID:       v.OwnerID,
 		Location:      v.Location,
 		Distance:      v.Distance,
 		Price:         v.Price,
+		TotalReviews:  v.TotalReviews,
+		TotalBookings: v.TotalBookings,
 		AverageRating: v.AverageRating,
 		VenuePicture:  v.VenuePicture,
 	}
@@ -144,7 +271,8 @@ func searchVenueModels(v Venue) venue.VenueCore {
 
 	result := venue.VenueCore{
 		VenueID:       v.VenueID,
-		Category:      v.Category,
+		CategoryID:    v.CategoryID,
+		Category:      v.Category.Name,
 		Name:          v.Name,
 		OwnerID:       v.OwnerID,
 		Location:      v.Location,
@@ -184,25 +312,39 @@ func selectVenueModels(v Venue) venue.VenueCore {
 		}
 	}
 
+	hours := make([]venue.VenueHourCore, len(v.OpeningHours))
+	for i, h := range v.OpeningHours {
+		hours[i] = venueHourModels(h)
+	}
+
+	amenities := make([]venue.AmenityCore, len(v.Amenities))
+	for i, a := range v.Amenities {
+		amenities[i] = amenityModels(a)
+	}
+
 	result := venue.VenueCore{
-		VenueID:       v.VenueID,
-		OwnerID:       v.OwnerID,
-		Category:      v.Category,
-		Name:          v.Name,
-		Description:   v.Description,
-		Username:      v.User.Fullname,
-		ServiceTime:   v.ServiceTime,
-		Location:      v.Location,
-		Price:         v.Price,
-		Longitude:     v.Longitude,
-		Latitude:      v.Latitude,
-		CreatedAt:     v.CreatedAt,
-		UpdatedAt:     v.UpdatedAt,
-		DeletedAt:     v.DeletedAt.Time,
-		TotalReviews:  uint(len(v.Reviews)),
-		AverageRating: averageRating,
-		VenuePictures: pictures,
-		Reviews:       reviews,
+		VenueID:          v.VenueID,
+		OwnerID:          v.OwnerID,
+		CategoryID:       v.CategoryID,
+		Category:         v.Category.Name,
+		Name:             v.Name,
+		Description:      v.Description,
+		Username:         v.User.Fullname,
+		ServiceTime:      v.ServiceTime,
+		Location:         v.Location,
+		Price:            v.Price,
+		Longitude:        v.Longitude,
+		Latitude:         v.Latitude,
+		RescheduleCutoff: v.RescheduleCutoff,
+		CreatedAt:        v.CreatedAt,
+		UpdatedAt:        v.UpdatedAt,
+		DeletedAt:        v.DeletedAt.Time,
+		TotalReviews:     uint(len(v.Reviews)),
+		AverageRating:    averageRating,
+		VenuePictures:    pictures,
+		Reviews:          reviews,
+		OpeningHours:     hours,
+		Amenities:        amenities,
 	}
 
 	return result
@@ -222,7 +364,8 @@ func Availability(v Venue) venue.VenueCore {
 	result := venue.VenueCore{
 		VenueID:      v.VenueID,
 		OwnerID:      v.OwnerID,
-		Category:     v.Category,
+		CategoryID:   v.CategoryID,
+		Category:     v.Category.Name,
 		Name:         v.Name,
 		Reservations: reservations,
 	}
@@ -232,42 +375,45 @@ func Availability(v Venue) venue.VenueCore {
 // Venue-Model to venue-core
 func venueModels(v Venue) venue.VenueCore {
 	return venue.VenueCore{
-		VenueID:       v.VenueID,
-		OwnerID:       v.OwnerID,
-		Category:      v.Category,
-		Name:          v.Name,
-		Description:   v.Description,
-		ServiceTime:   v.ServiceTime,
-		Location:      v.Location,
-		Price:         v.Price,
-		Longitude:     v.Longitude,
-		Latitude:      v.Latitude,
-		CreatedAt:     v.CreatedAt,
-		UpdatedAt:     v.UpdatedAt,
-		DeletedAt:     v.DeletedAt.Time,
-		VenuePictures: []venue.VenuePictureCore{},
-		Reviews:       []venue.ReviewCore{},
+		VenueID:          v.VenueID,
+		OwnerID:          v.OwnerID,
+		CategoryID:       v.CategoryID,
+		Category:         v.Category.Name,
+		Name:             v.Name,
+		Description:      v.Description,
+		ServiceTime:      v.ServiceTime,
+		Location:         v.Location,
+		Price:            v.Price,
+		Longitude:        v.Longitude,
+		Latitude:         v.Latitude,
+		RescheduleCutoff: v.RescheduleCutoff,
+		CreatedAt:        v.CreatedAt,
+		UpdatedAt:        v.UpdatedAt,
+		DeletedAt:        v.DeletedAt.Time,
+		VenuePictures:    []venue.VenuePictureCore{},
+		Reviews:          []venue.ReviewCore{},
 	}
 }
 
 // Venue-core to venue-model
 func venueEntities(v venue.VenueCore) Venue {
 	return Venue{
-		VenueID:       v.VenueID,
-		OwnerID:       v.OwnerID,
-		Category:      v.Category,
-		Name:          v.Name,
-		Description:   v.Description,
-		ServiceTime:   v.ServiceTime,
-		Location:      v.Location,
-		Price:         v.Price,
-		Longitude:     v.Longitude,
-		Latitude:      v.Latitude,
-		CreatedAt:     v.CreatedAt,
-		UpdatedAt:     v.UpdatedAt,
-		DeletedAt:     gorm.DeletedAt{Time: v.DeletedAt},
-		VenuePictures: []VenuePicture{},
-		Reviews:       []review.Review{},
+		VenueID:          v.VenueID,
+		OwnerID:          v.OwnerID,
+		CategoryID:       v.CategoryID,
+		Name:             v.Name,
+		Description:      v.Description,
+		ServiceTime:      v.ServiceTime,
+		Location:         v.Location,
+		Price:            v.Price,
+		Longitude:        v.Longitude,
+		Latitude:         v.Latitude,
+		RescheduleCutoff: v.RescheduleCutoff,
+		CreatedAt:        v.CreatedAt,
+		UpdatedAt:        v.UpdatedAt,
+		DeletedAt:        gorm.DeletedAt{Time: v.DeletedAt},
+		VenuePictures:    []VenuePicture{},
+		Reviews:          []review.Review{},
 	}
 }
 
@@ -289,3 +435,127 @@ func VenuePictureModelToCore(v VenuePicture) venue.VenuePictureCore {
 		DeletedAt:      v.DeletedAt.Time,
 	}
 }
+
+func amenityModels(a Amenity) venue.AmenityCore {
+	return venue.AmenityCore{
+		AmenityID: a.AmenityID,
+		Code:      a.Code,
+		Name:      a.Name,
+		Kind:      a.Kind,
+	}
+}
+
+func venueHourModels(h VenueHour) venue.VenueHourCore {
+	return venue.VenueHourCore{
+		VenueHourID: h.VenueHourID,
+		VenueID:     h.VenueID,
+		Weekday:     h.Weekday,
+		OpenTime:    h.OpenTime,
+		CloseTime:   h.CloseTime,
+	}
+}
+
+func venueHourEntities(h venue.VenueHourCore) VenueHour {
+	return VenueHour{
+		VenueHourID: h.VenueHourID,
+		VenueID:     h.VenueID,
+		Weekday:     h.Weekday,
+		OpenTime:    h.OpenTime,
+		CloseTime:   h.CloseTime,
+	}
+}
+
+func cancellationPolicyModels(p CancellationPolicy) venue.CancellationTierCore {
+	return venue.CancellationTierCore{
+		CancellationPolicyID: p.CancellationPolicyID,
+		VenueID:              p.VenueID,
+		HoursBefore:          p.HoursBefore,
+		RefundPercent:        p.RefundPercent,
+	}
+}
+
+func cancellationPolicyEntities(t venue.CancellationTierCore) CancellationPolicy {
+	return CancellationPolicy{
+		CancellationPolicyID: t.CancellationPolicyID,
+		VenueID:              t.VenueID,
+		HoursBefore:          t.HoursBefore,
+		RefundPercent:        t.RefundPercent,
+	}
+}
+
+func pricingRuleModels(r PricingRule) venue.PricingRuleCore {
+	weekdays := []int{}
+	for _, day := range strings.Split(r.Weekdays, ",") {
+		if weekday, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+
+	rule := venue.PricingRuleCore{
+		PricingRuleID: r.PricingRuleID,
+		VenueID:       r.VenueID,
+		Name:          r.Name,
+		Weekdays:      weekdays,
+		StartTime:     r.StartTime,
+		EndTime:       r.EndTime,
+		MinDuration:   r.MinDuration,
+		Price:         r.Price,
+		Priority:      r.Priority,
+	}
+	if r.StartDate != nil {
+		rule.StartDate = *r.StartDate
+	}
+	if r.EndDate != nil {
+		rule.EndDate = *r.EndDate
+	}
+
+	return rule
+}
+
+func pricingRuleEntities(r venue.PricingRuleCore) PricingRule {
+	weekdays := make([]string, len(r.Weekdays))
+	for i, weekday := range r.Weekdays {
+		weekdays[i] = strconv.Itoa(weekday)
+	}
+
+	rule := PricingRule{
+		PricingRuleID: r.PricingRuleID,
+		VenueID:       r.VenueID,
+		Name:          r.Name,
+		Weekdays:      strings.Join(weekdays, ","),
+		StartTime:     r.StartTime,
+		EndTime:       r.EndTime,
+		MinDuration:   r.MinDuration,
+		Price:         r.Price,
+		Priority:      r.Priority,
+	}
+	if !r.StartDate.IsZero() {
+		rule.StartDate = &r.StartDate
+	}
+	if !r.EndDate.IsZero() {
+		rule.EndDate = &r.EndDate
+	}
+
+	return rule
+}
+
+func venueClosureModels(c VenueClosure) venue.VenueClosureCore {
+	return venue.VenueClosureCore{
+		VenueClosureID: c.VenueClosureID,
+		VenueID:        c.VenueID,
+		StartDate:      c.StartDate,
+		EndDate:        c.EndDate,
+		Reason:         c.Reason,
+		CreatedAt:      c.CreatedAt,
+	}
+}
+
+func venueClosureEntities(c venue.VenueClosureCore) VenueClosure {
+	return VenueClosure{
+		VenueClosureID: c.VenueClosureID,
+		VenueID:        c.VenueID,
+		StartDate:      c.StartDate,
+		EndDate:        c.EndDate,
+		Reason:         c.Reason,
+	}
+}
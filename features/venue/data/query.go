@@ -4,26 +4,124 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
 	"github.com/playground-pro-project/playground-pro-api/features/venue"
 	"github.com/playground-pro-project/playground-pro-api/utils/cache"
+	"github.com/playground-pro-project/playground-pro-api/utils/geo"
 	"github.com/playground-pro-project/playground-pro-api/utils/helper"
 	"github.com/playground-pro-project/playground-pro-api/utils/pagination"
+	"github.com/playground-pro-project/playground-pro-api/utils/redis"
+	"github.com/playground-pro-project/playground-pro-api/utils/schedule"
 	"gorm.io/gorm"
 )
 
 var log = middlewares.Log()
 
+// suggestIndex is the Redis prefix index of venue names and locations. It backs
+// autocomplete and the vocabulary searches correct typos against.
+const suggestIndex = "venues:suggest"
+
 type venueQuery struct {
-	db *gorm.DB
+	db    *gorm.DB
+	redis *redis.RedisClient
 }
 
 func New(db *gorm.DB) venue.VenueData {
 	return &venueQuery{
-		db: db,
+		db:    db,
+		redis: redis.NewRedisClient(),
+	}
+}
+
+// GetCategory implements venue.VenueData.
+func (vq *venueQuery) GetCategory(category string) (venue.CategoryCore, error) {
+	result := Category{}
+	query := vq.db.Table("categories").
+		Where("category_id = ? OR name = ?", category, strings.ToLower(category)).
+		Limit(1).
+		Find(&result)
+	if query.Error != nil {
+		log.Sugar().Error("error executing category query:", query.Error)
+		return venue.CategoryCore{}, query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("category not found")
+		return venue.CategoryCore{}, errors.New("category not found")
+	}
+
+	return venue.CategoryCore{CategoryID: result.CategoryID, Name: result.Name}, nil
+}
+
+// SelectAmenities implements venue.VenueData.
+func (vq *venueQuery) SelectAmenities() ([]venue.AmenityCore, error) {
+	amenities := []Amenity{}
+	query := vq.db.Order("kind ASC, name ASC").Find(&amenities)
+	if query.Error != nil {
+		log.Sugar().Error("error executing amenities query:", query.Error)
+		return nil, query.Error
+	}
+
+	result := make([]venue.AmenityCore, len(amenities))
+	for i, a := range amenities {
+		result[i] = amenityModels(a)
+	}
+
+	return result, nil
+}
+
+// GetAmenitiesByCode implements venue.VenueData.
+func (vq *venueQuery) GetAmenitiesByCode(codes []string) ([]venue.AmenityCore, error) {
+	amenities := []Amenity{}
+	query := vq.db.Where("code IN ?", codes).Order("kind ASC, name ASC").Find(&amenities)
+	if query.Error != nil {
+		log.Sugar().Error("error executing amenities query:", query.Error)
+		return nil, query.Error
+	}
+
+	result := make([]venue.AmenityCore, len(amenities))
+	for i, a := range amenities {
+		result[i] = amenityModels(a)
 	}
+
+	return result, nil
+}
+
+// replaceAmenities tags a venue with exactly the given amenities, nil amenities leave
+// the venue untouched.
+func replaceAmenities(db *gorm.DB, venueId string, amenities []venue.AmenityCore) error {
+	if amenities == nil {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Exec("DELETE FROM venue_amenities WHERE venue_id = ?", venueId)
+		if query.Error != nil {
+			log.Sugar().Error("error while clearing venue amenities:", query.Error)
+			return errors.New("error while saving venue amenities")
+		}
+		if len(amenities) == 0 {
+			return nil
+		}
+
+		rows := make([]map[string]interface{}, len(amenities))
+		for i, a := range amenities {
+			rows[i] = map[string]interface{}{"venue_id": venueId, "amenity_id": a.AmenityID}
+		}
+		query = tx.Table("venue_amenities").Create(&rows)
+		if query.Error != nil {
+			log.Sugar().Error("error while saving venue amenities:", query.Error)
+			return errors.New("error while saving venue amenities")
+		}
+
+		return nil
+	})
 }
 
 // RegisterVenue implements venue.VenueData.
@@ -46,8 +144,16 @@ func (vq *venueQuery) RegisterVenue(userId string, request venue.VenueCore) (ven
 		return venue.VenueCore{}, errors.New("row affected : 0")
 	}
 
+	if err := replaceAmenities(vq.db, req.VenueID, request.Amenities); err != nil {
+		return venue.VenueCore{}, err
+	}
+
+	vq.indexSuggestion(req.VenueID)
 	log.Sugar().Infof("new venue has been created: %s", req.VenueID)
-	return venueModels(req), nil
+	req.Category.Name = request.Category
+	result := venueModels(req)
+	result.Amenities = request.Amenities
+	return result, nil
 }
 
 func (vq *venueQuery) InsertVenue(userID string, venueReq venue.VenueCore, venueImageReq venue.VenuePictureCore) (venue.VenueCore, error) {
@@ -102,6 +208,12 @@ func (vq *venueQuery) InsertVenue(userID string, venueReq venue.VenueCore, venue
 		return venue.VenueCore{}, errors.New("venue not found. no venue image has been created")
 	}
 
+	// Tag the venue with its amenities
+	if err := replaceAmenities(vq.db, venueID, venueReq.Amenities); err != nil {
+		tx.Rollback()
+		return venue.VenueCore{}, err
+	}
+
 	// Commit the transaction if everything is successful
 	err := tx.Commit().Error
 	if err != nil {
@@ -109,8 +221,12 @@ func (vq *venueQuery) InsertVenue(userID string, venueReq venue.VenueCore, venue
 		return venue.VenueCore{}, errors.New("error to commit transaction")
 	}
 
+	vq.indexSuggestion(req.VenueID)
 	log.Sugar().Infof("new venue has been created: %s", req.VenueID)
-	return venueModels(req), nil
+	req.Category.Name = venueReq.Category
+	result := venueModels(req)
+	result.Amenities = venueReq.Amenities
+	return result, nil
 }
 
 func (vq *venueQuery) InsertVenueImage(req venue.VenuePictureCore) (venue.VenuePictureCore, error) {
@@ -135,87 +251,587 @@ func (vq *venueQuery) InsertVenueImage(req venue.VenuePictureCore) (venue.VenueP
 	return VenuePictureModelToCore(model), nil
 }
 
+// priceBuckets are the boundaries of the price facet, the last bucket is open-ended.
+var priceBuckets = []float64{50000, 100000, 200000}
+
+// venueSearch is a search along with what is resolved once before querying: the boolean
+// full-text query of its keyword and the venues open at its OpenAt time.
+type venueSearch struct {
+	venue.SearchCore
+	against    string
+	openVenues []string
+}
+
+const (
+	filterCategory = "category"
+	filterPrice    = "price"
+	filterOpenAt   = "open_at"
+)
+
 // SearchVenue implements venue.VenueData.
-func (vq *venueQuery) SearchVenues(keyword string, latitude float64, longitude float64, page pagination.Pagination) ([]venue.VenueCoreRaw, int64, int, error) {
-	res := []Venues{}
-	search := "%" + keyword + "%"
+func (vq *venueQuery) SearchVenues(search venue.SearchCore, page pagination.Pagination) (venue.SearchResultCore, error) {
 	expTime := 5 * time.Second
-	cacheKey := fmt.Sprintf("venues:%s:%d", keyword, page.Page)
-	cachedVenues, err := cache.GetCached(context.Background(), cacheKey)
+	cacheKey := searchCacheKey(search, page)
+	cached := venue.SearchResultCore{}
+	found, err := cache.GetCached(context.Background(), cacheKey, &cached)
 	if err != nil {
-		return nil, 0, 0, err
+		return venue.SearchResultCore{}, err
 	}
 
-	if cachedVenues != nil {
-		result, ok := cachedVenues.([]venue.VenueCoreRaw)
-		if !ok {
-			return nil, 0, 0, errors.New("unexpected type for cachedVenues")
+	if found {
+		return cached, nil
+	}
+
+	// Opening hours cannot be matched in SQL, the venues open at that time are
+	// resolved first and filtered by id.
+	filter := venueSearch{SearchCore: search, against: vq.fulltextQuery(search.Keyword)}
+	if !search.OpenAt.IsZero() {
+		filter.openVenues, err = vq.openVenues(filter)
+		if err != nil {
+			return venue.SearchResultCore{}, err
 		}
-		return result, page.TotalRows, page.TotalPages, nil
 	}
 
 	var totalRows int64
-	queryPagination := vq.db.Raw(`
-		SELECT COUNT(*) FROM venues
-		WHERE venues.category LIKE ? 
-			AND venues.location LIKE ? 
-			AND venues.price LIKE ? 
-			AND venues.deleted_at IS NULL;
-		`, search, search, search).
-		Count(&totalRows)
+	queryPagination := vq.searchQuery(filter).Count(&totalRows)
 	if queryPagination.Error != nil {
 		log.Sugar().Error("error executing count query:", queryPagination.Error)
-		return nil, 0, 0, queryPagination.Error
+		return venue.SearchResultCore{}, queryPagination.Error
 	}
 
 	page.TotalRows = totalRows
 	page.TotalPages = pagination.CalculateTotalPages(totalRows, page.GetLimit())
 
-	query := vq.db.Raw(`
-		SELECT venues.*, 
-		    AVG(reviews.rating) AS average_rating, 
-		    COUNT(reviews.review_id) AS total_reviews, 
-		    users.fullname,
-		    6371 * 2 * ASIN(SQRT(
-		        POWER(SIN((RADIANS(? - RADIANS(venues.latitude)) / 2)), 2) +
-		        COS(RADIANS(venues.latitude)) * COS(RADIANS(?)) *
-		        POWER(SIN((RADIANS(? - RADIANS(venues.longitude)) / 2)), 2)
-		    )) AS distance,
-			(SELECT url FROM venue_pictures WHERE venue_pictures.venue_id = venues.venue_id LIMIT 1) AS venue_picture
-		FROM venues
-		LEFT JOIN venue_pictures ON venue_pictures.venue_id = venues.venue_id
-		LEFT JOIN reviews ON reviews.venue_id = venues.venue_id
-		LEFT JOIN users ON users.user_id = venues.owner_id
-		WHERE venues.category LIKE ? 
-			AND venues.location LIKE ? 
-			AND venues.price LIKE ? 
-			AND venues.deleted_at IS NULL
-		GROUP BY venues.venue_id
-		ORDER BY venues.updated_at DESC
-		LIMIT ? OFFSET ?;
-		`, latitude, latitude, longitude, search, search, search, page.GetLimit(), page.GetOffset()).
+	res := []Venues{}
+	query := vq.searchQuery(filter).
+		Order(searchOrder(filter)).
+		Limit(page.GetLimit()).
+		Offset(page.GetOffset()).
 		Scan(&res)
-	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
-		log.Error("list venues not found")
-		return nil, 0, 0, errors.New("venues not found")
-	} else if query.Error != nil {
+	if query.Error != nil {
 		log.Sugar().Error("error executing venues query:", query.Error)
-		return nil, 0, 0, query.Error
-	} else {
-		log.Sugar().Info("Venues data found in the database")
+		return venue.SearchResultCore{}, query.Error
+	}
+
+	facets, err := vq.searchFacets(filter)
+	if err != nil {
+		return venue.SearchResultCore{}, err
 	}
 
-	result := make([]venue.VenueCoreRaw, len(res))
+	result := venue.SearchResultCore{
+		Venues:     make([]venue.VenueCoreRaw, len(res)),
+		TotalRows:  page.TotalRows,
+		TotalPages: page.TotalPages,
+		Facets:     facets,
+	}
 	for i, venue := range res {
-		result[i] = searchVenueModel(venue)
+		result.Venues[i] = searchVenueModel(venue)
 	}
 
 	err = cache.SetCached(context.Background(), cacheKey, result, expTime)
 	if err != nil {
-		return nil, 0, 0, err
+		return venue.SearchResultCore{}, err
+	}
+
+	return result, nil
+}
+
+// searchCacheKey identifies a search by every filter and the page requested.
+func searchCacheKey(search venue.SearchCore, page pagination.Pagination) string {
+	params := url.Values{}
+	params.Set("keyword", search.Keyword)
+	params.Set("category", search.CategoryID)
+	params.Set("amenities", strings.Join(search.Amenities, ","))
+	params.Set("min_price", strconv.FormatFloat(search.MinPrice, 'f', -1, 64))
+	params.Set("max_price", strconv.FormatFloat(search.MaxPrice, 'f', -1, 64))
+	params.Set("min_rating", strconv.FormatFloat(search.MinRating, 'f', -1, 64))
+	params.Set("latitude", strconv.FormatFloat(search.Latitude, 'f', -1, 64))
+	params.Set("longitude", strconv.FormatFloat(search.Longitude, 'f', -1, 64))
+	params.Set("radius", strconv.FormatFloat(search.RadiusKm, 'f', -1, 64))
+	if !search.OpenAt.IsZero() {
+		params.Set("open_at", search.OpenAt.Format(time.RFC3339))
+	}
+	params.Set("sort", search.SortBy)
+	params.Set("order", search.Order)
+	params.Set("page", strconv.Itoa(page.GetPage()))
+	params.Set("limit", strconv.Itoa(page.GetLimit()))
+
+	return "venues:" + params.Encode()
+}
+
+// searchBase selects the live venues along with the relevance, rating, popularity and
+// distance columns the search filters and sorts on. A keyword goes through the full-text
+// index and a radius first narrows the venues to its bounding box through the spatial
+// index.
+func (vq *venueQuery) searchBase(search venueSearch) *gorm.DB {
+	columns := `venues.*, categories.name AS category,
+		COALESCE((SELECT AVG(reviews.rating) FROM reviews WHERE reviews.venue_id = venues.venue_id AND reviews.deleted_at IS NULL), 0) AS average_rating,
+		(SELECT COUNT(*) FROM reviews WHERE reviews.venue_id = venues.venue_id AND reviews.deleted_at IS NULL) AS total_reviews,
+		(SELECT COUNT(*) FROM reservations WHERE reservations.venue_id = venues.venue_id AND reservations.deleted_at IS NULL) AS total_bookings,
+		ST_Distance_Sphere(venues.coordinates, ST_GeomFromText(?, ?, 'axis-order=long-lat')) / 1000 AS distance,
+		(SELECT url FROM venue_pictures WHERE venue_pictures.venue_id = venues.venue_id LIMIT 1) AS venue_picture`
+	args := []interface{}{geo.PointWKT(search.Latitude, search.Longitude), geo.SRID}
+
+	if search.against != "" {
+		columns += ", MATCH(venues.name, venues.description, venues.location) AGAINST (? IN BOOLEAN MODE) AS relevance"
+		args = append(args, search.against)
+	} else {
+		columns += ", 0 AS relevance"
+	}
+
+	query := vq.db.Table("venues").
+		Select(columns, args...).
+		Joins("LEFT JOIN categories ON categories.category_id = venues.category_id").
+		Where("venues.deleted_at IS NULL")
+	if search.against != "" {
+		query = query.Where("MATCH(venues.name, venues.description, venues.location) AGAINST (? IN BOOLEAN MODE)", search.against)
+	}
+	if search.Located && search.RadiusKm > 0 {
+		box := geo.Around(search.Latitude, search.Longitude, search.RadiusKm)
+		query = query.Where("MBRContains(ST_GeomFromText(?, ?, 'axis-order=long-lat'), venues.coordinates)", box.WKT(), geo.SRID)
+	}
+
+	return query
+}
+
+// fulltextQuery turns a keyword into a boolean full-text query matching every term as a
+// prefix, or one of the closest known terms so a typo still finds the venue.
+func (vq *venueQuery) fulltextQuery(keyword string) string {
+	terms := searchTerms(keyword)
+	if len(terms) == 0 {
+		return ""
+	}
+
+	vocabulary, err := vq.suggestVocabulary()
+	if err != nil {
+		log.Sugar().Warnf("searching without typo tolerance: %v", err)
+	}
+
+	return booleanQuery(terms, vocabulary)
+}
+
+// booleanQuery requires every term, each grouped with its corrections as alternatives:
+// "futsl jakarta" becomes "+(futsl* futsal) +(jakarta*)".
+func booleanQuery(terms []string, vocabulary []string) string {
+	groups := make([]string, len(terms))
+	for i, term := range terms {
+		words := append([]string{term + "*"}, corrections(term, vocabulary)...)
+		groups[i] = "+(" + strings.Join(words, " ") + ")"
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// searchQuery applies the search filters, leaving out the ones named by skip so a facet
+// can count across its own options.
+func (vq *venueQuery) searchQuery(search venueSearch, skip ...string) *gorm.DB {
+	skipped := map[string]bool{}
+	for _, filter := range skip {
+		skipped[filter] = true
+	}
+
+	query := vq.db.Table("(?) AS venues", vq.searchBase(search))
+	if search.CategoryID != "" && !skipped[filterCategory] {
+		query = query.Where("venues.category_id = ?", search.CategoryID)
+	}
+	if search.MinPrice > 0 && !skipped[filterPrice] {
+		query = query.Where("venues.price >= ?", search.MinPrice)
+	}
+	if search.MaxPrice > 0 && !skipped[filterPrice] {
+		query = query.Where("venues.price <= ?", search.MaxPrice)
+	}
+	if search.MinRating > 0 {
+		query = query.Where("venues.average_rating >= ?", search.MinRating)
+	}
+	if len(search.Amenities) > 0 {
+		query = query.Where("venues.venue_id IN (?)", vq.db.Table("venue_amenities").
+			Select("venue_amenities.venue_id").
+			Joins("JOIN amenities ON amenities.amenity_id = venue_amenities.amenity_id").
+			Where("amenities.code IN ?", search.Amenities).
+			Group("venue_amenities.venue_id").
+			Having("COUNT(DISTINCT amenities.amenity_id) = ?", len(search.Amenities)))
+	}
+	if search.Located && search.RadiusKm > 0 {
+		query = query.Where("venues.distance <= ?", search.RadiusKm)
+	}
+	if !search.OpenAt.IsZero() && !skipped[filterOpenAt] {
+		query = query.Where("venues.venue_id IN ?", search.openVenues)
+	}
+
+	return query
+}
+
+// searchOrder sorts most relevant, nearest, cheapest, best rated or most booked first,
+// with the order overriding the direction. By default a keyword search ranks by relevance
+// and any other search shows recently updated venues first. Only a located search sorts
+// and filters by distance, the default point is not where the searcher is.
+func searchOrder(search venueSearch) string {
+	column, order := "venues.updated_at", "DESC"
+	if search.against != "" {
+		column = "venues.relevance"
+	}
+	switch search.SortBy {
+	case venue.SortByRelevance:
+		column, order = "venues.relevance", "DESC"
+	case venue.SortByDistance:
+		if search.Located {
+			column, order = "venues.distance", "ASC"
+		}
+	case venue.SortByPrice:
+		column, order = "venues.price", "ASC"
+	case venue.SortByRating:
+		column, order = "venues.average_rating", "DESC"
+	case venue.SortByPopularity:
+		column, order = "venues.total_bookings", "DESC"
+	}
+
+	if search.Order != "" {
+		order = strings.ToUpper(search.Order)
+	}
+
+	return column + " " + order + ", venues.venue_id ASC"
+}
+
+// searchFacets counts the matching venues per category and price bucket.
+func (vq *venueQuery) searchFacets(search venueSearch) (venue.FacetsCore, error) {
+	categories := []venue.FacetCore{}
+	query := vq.searchQuery(search, filterCategory).
+		Select("venues.category_id AS id, venues.category AS value, COUNT(*) AS count").
+		Group("venues.category_id, venues.category").
+		Order("count DESC, value ASC").
+		Scan(&categories)
+	if query.Error != nil {
+		log.Sugar().Error("error executing category facet query:", query.Error)
+		return venue.FacetsCore{}, query.Error
+	}
+
+	bucket := "CASE"
+	for i, bound := range priceBuckets {
+		bucket += fmt.Sprintf(" WHEN venues.price < %.0f THEN %d", bound, i)
+	}
+	bucket += fmt.Sprintf(" ELSE %d END", len(priceBuckets))
+
+	counts := []struct {
+		Bucket int
+		Count  int64
+	}{}
+	query = vq.searchQuery(search, filterPrice).
+		Select(bucket + " AS bucket, COUNT(*) AS count").
+		Group("bucket").
+		Scan(&counts)
+	if query.Error != nil {
+		log.Sugar().Error("error executing price facet query:", query.Error)
+		return venue.FacetsCore{}, query.Error
+	}
+
+	buckets := make([]venue.PriceBucketCore, len(priceBuckets)+1)
+	for i := range buckets {
+		if i > 0 {
+			buckets[i].Min = priceBuckets[i-1]
+		}
+		if i < len(priceBuckets) {
+			buckets[i].Max = priceBuckets[i]
+		}
+	}
+	for _, c := range counts {
+		buckets[c.Bucket].Count = c.Count
+	}
+
+	return venue.FacetsCore{Categories: categories, PriceBuckets: buckets}, nil
+}
+
+// openVenues returns the ids of the venues open and not closed at the OpenAt time of the
+// search. Category and price are left to the caller so the facets can still skip them.
+func (vq *venueQuery) openVenues(search venueSearch) ([]string, error) {
+	candidates := []Venues{}
+	query := vq.searchQuery(search, filterOpenAt, filterCategory, filterPrice).
+		Select("venues.venue_id, venues.service_time").
+		Where("venues.venue_id NOT IN (?)", vq.db.Model(&VenueClosure{}).
+			Select("venue_id").
+			Where("start_date <= ? AND end_date > ?", search.OpenAt, search.OpenAt)).
+		Scan(&candidates)
+	if query.Error != nil {
+		log.Sugar().Error("error executing open venues query:", query.Error)
+		return nil, query.Error
+	}
+
+	if len(candidates) == 0 {
+		return []string{}, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.VenueID
+	}
+
+	hours := []VenueHour{}
+	query = vq.db.Where("venue_id IN ?", ids).Find(&hours)
+	if query.Error != nil {
+		log.Sugar().Error("error executing opening hours query:", query.Error)
+		return nil, query.Error
+	}
+
+	weekly := map[string]schedule.WeeklyHours{}
+	for _, h := range hours {
+		shift, err := schedule.NewShift(h.OpenTime, h.CloseTime)
+		if err != nil {
+			continue
+		}
+		if weekly[h.VenueID] == nil {
+			weekly[h.VenueID] = schedule.WeeklyHours{}
+		}
+		day := time.Weekday(h.Weekday)
+		weekly[h.VenueID][day] = append(weekly[h.VenueID][day], shift)
+	}
+
+	moment := schedule.Interval{Start: search.OpenAt, End: search.OpenAt.Add(time.Minute)}
+	open := []string{}
+	for _, c := range candidates {
+		// Venues without structured opening hours fall back to their service time.
+		hours, ok := weekly[c.VenueID]
+		if !ok {
+			shifts, err := schedule.ParseServiceTime(c.ServiceTime)
+			if err != nil {
+				continue
+			}
+			hours = schedule.Daily(shifts)
+		}
+		if hours.Covers(moment) {
+			open = append(open, c.VenueID)
+		}
+	}
+
+	return open, nil
+}
+
+// GetVenuesInBox implements venue.VenueData.
+func (vq *venueQuery) GetVenuesInBox(box geo.Box, limit int) ([]venue.MapMarkerCore, error) {
+	markers := []MapMarker{}
+	query := vq.db.Table("venues").
+		Select("venues.venue_id, venues.name, categories.name AS category, venues.price, venues.latitude, venues.longitude, 1 AS count").
+		Joins("LEFT JOIN categories ON categories.category_id = venues.category_id").
+		Where("venues.deleted_at IS NULL").
+		Where("MBRContains(ST_GeomFromText(?, ?, 'axis-order=long-lat'), venues.coordinates)", box.WKT(), geo.SRID).
+		Order("venues.updated_at DESC, venues.venue_id ASC").
+		Limit(limit).
+		Scan(&markers)
+	if query.Error != nil {
+		log.Sugar().Error("error executing map venues query:", query.Error)
+		return nil, query.Error
+	}
+
+	result := make([]venue.MapMarkerCore, len(markers))
+	for i, m := range markers {
+		result[i] = mapMarkerModel(m)
+	}
+
+	return result, nil
+}
+
+// ClusterVenuesInBox implements venue.VenueData.
+func (vq *venueQuery) ClusterVenuesInBox(box geo.Box, cellSize float64) ([]venue.MapMarkerCore, error) {
+	markers := []MapMarker{}
+	query := vq.db.Table("venues").
+		Select(`FLOOR(venues.longitude / ?) AS cell_x, FLOOR(venues.latitude / ?) AS cell_y, COUNT(*) AS count,
+			AVG(venues.latitude) AS latitude, AVG(venues.longitude) AS longitude, MIN(venues.venue_id) AS venue_id,
+			MIN(venues.name) AS name, MIN(categories.name) AS category, MIN(venues.price) AS price`, cellSize, cellSize).
+		Joins("LEFT JOIN categories ON categories.category_id = venues.category_id").
+		Where("venues.deleted_at IS NULL").
+		Where("MBRContains(ST_GeomFromText(?, ?, 'axis-order=long-lat'), venues.coordinates)", box.WKT(), geo.SRID).
+		Group("cell_x, cell_y").
+		Order("cell_y ASC, cell_x ASC").
+		Scan(&markers)
+	if query.Error != nil {
+		log.Sugar().Error("error executing map clusters query:", query.Error)
+		return nil, query.Error
+	}
+
+	result := make([]venue.MapMarkerCore, len(markers))
+	for i, m := range markers {
+		// A cluster of one is just that venue.
+		if m.Count > 1 {
+			m.VenueID, m.Name, m.Category, m.Price = "", "", "", 0
+		}
+		result[i] = mapMarkerModel(m)
+	}
+
+	return result, nil
+}
+
+// SuggestVenues implements venue.VenueData.
+func (vq *venueQuery) SuggestVenues(query string, limit int) ([]venue.SuggestionCore, error) {
+	if err := vq.ensureSuggestions(); err != nil {
+		return nil, err
+	}
+
+	labels, err := vq.redis.SearchPrefixes(suggestIndex, searchTerms(query))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]venue.SuggestionCore, 0, len(labels))
+	for venueId, name := range labels {
+		result = append(result, venue.SuggestionCore{VenueID: venueId, Name: name})
+	}
+
+	// Names starting with the query come first, then the shortest and alphabetical.
+	prefix := strings.ToLower(strings.TrimSpace(query))
+	sort.Slice(result, func(i, j int) bool {
+		a, b := strings.ToLower(result[i].Name), strings.ToLower(result[j].Name)
+		if strings.HasPrefix(a, prefix) != strings.HasPrefix(b, prefix) {
+			return strings.HasPrefix(a, prefix)
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	if len(result) > limit {
+		result = result[:limit]
 	}
 
-	return result, page.TotalRows, page.TotalPages, nil
+	return result, nil
+}
+
+// indexSuggestion refreshes a venue in the suggestion index, dropping it once the venue
+// is gone. Failures are only logged, the index is rebuilt whenever Redis loses it.
+func (vq *venueQuery) indexSuggestion(venueId string) {
+	v := Venue{}
+	query := vq.db.Select("venue_id, name, location").Where("venue_id = ?", venueId).Limit(1).Find(&v)
+	if query.Error != nil {
+		log.Sugar().Warnf("failed to load venue %s for suggestions: %v", venueId, query.Error)
+		return
+	}
+
+	if query.RowsAffected == 0 {
+		if err := vq.redis.RemovePrefixes(suggestIndex, venueId); err != nil {
+			log.Sugar().Warnf("failed to remove venue %s from suggestions: %v", venueId, err)
+		}
+		return
+	}
+
+	terms := append(searchTerms(v.Name), searchTerms(v.Location)...)
+	if err := vq.redis.IndexPrefixes(suggestIndex, v.VenueID, v.Name, terms); err != nil {
+		log.Sugar().Warnf("failed to index venue %s for suggestions: %v", venueId, err)
+	}
+}
+
+// ensureSuggestions indexes every live venue when the suggestion index is empty, as on a
+// fresh or flushed Redis.
+func (vq *venueQuery) ensureSuggestions() error {
+	size, err := vq.redis.PrefixIndexSize(suggestIndex)
+	if err != nil || size > 0 {
+		return err
+	}
+
+	venues := []Venue{}
+	query := vq.db.Select("venue_id, name, location").Find(&venues)
+	if query.Error != nil {
+		log.Sugar().Error("error executing suggestion venues query:", query.Error)
+		return query.Error
+	}
+
+	for _, v := range venues {
+		terms := append(searchTerms(v.Name), searchTerms(v.Location)...)
+		if err := vq.redis.IndexPrefixes(suggestIndex, v.VenueID, v.Name, terms); err != nil {
+			return err
+		}
+	}
+
+	log.Sugar().Infof("suggestion index rebuilt with %d venues", len(venues))
+	return nil
+}
+
+func (vq *venueQuery) suggestVocabulary() ([]string, error) {
+	if err := vq.ensureSuggestions(); err != nil {
+		return nil, err
+	}
+
+	return vq.redis.PrefixVocabulary(suggestIndex)
+}
+
+// searchTerms splits text into lowercase words, dropping punctuation and the full-text
+// operators along with it.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// maxCorrections is how many known terms a misspelled term is expanded to.
+const maxCorrections = 3
+
+// corrections returns the known terms closest to term, allowing one edit for terms of
+// four to seven characters and two for longer ones. Known terms are not corrected.
+func corrections(term string, vocabulary []string) []string {
+	allowed := 0
+	switch length := len([]rune(term)); {
+	case length >= 8:
+		allowed = 2
+	case length >= 4:
+		allowed = 1
+	}
+	if allowed == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		term     string
+		distance int
+	}
+	candidates := []candidate{}
+	for _, known := range vocabulary {
+		if known == term {
+			return nil
+		}
+		if distance := editDistance(term, known); distance <= allowed {
+			candidates = append(candidates, candidate{known, distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	if len(candidates) > maxCorrections {
+		candidates = candidates[:maxCorrections]
+	}
+
+	result := make([]string, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.term
+	}
+
+	return result
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
 }
 
 // SelectVenueById implements venue.VenueData.
@@ -230,8 +846,15 @@ func (vq *venueQuery) SelectVenue(venueId string) (venue.VenueCore, error) {
 		Group("venues.venue_id").
 		Order("venues.updated_at DESC").
 		Preload("User").
+		Preload("Category").
 		Preload("VenuePictures").
 		Preload("Reviews").
+		Preload("OpeningHours", func(db *gorm.DB) *gorm.DB {
+			return db.Order("weekday ASC, open_time ASC")
+		}).
+		Preload("Amenities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("kind ASC, name ASC")
+		}).
 		First(&venues)
 	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
 		log.Error("list venues not found")
@@ -266,6 +889,12 @@ func (vq *venueQuery) EditVenue(userId string, venueId string, request venue.Ven
 		log.Sugar().Error("error executing venues query:", query.Error)
 		return errors.New("error executing venues query")
 	}
+
+	if err := replaceAmenities(vq.db, venueId, request.Amenities); err != nil {
+		return err
+	}
+
+	vq.indexSuggestion(venueId)
 	return nil
 }
 
@@ -290,6 +919,7 @@ func (vq *venueQuery) UnregisterVenue(userId string, venueId string) error {
 		return errors.New("error executing query")
 	}
 
+	vq.indexSuggestion(venueId)
 	return nil
 }
 
@@ -297,12 +927,13 @@ func (vq *venueQuery) UnregisterVenue(userId string, venueId string) error {
 func (vq *venueQuery) VenueAvailability(venueId string) (venue.VenueCore, error) {
 	venues := Venue{}
 	query := vq.db.Table("venues").
-		Select("venues.venue_id, venues.owner_id, venues.category, venues.name, reservations.reservation_id, reservations.check_in_date, reservations.check_out_date, users.fullname").
+		Select("venues.venue_id, venues.owner_id, venues.category_id, venues.name, reservations.reservation_id, reservations.check_in_date, reservations.check_out_date, users.fullname").
 		Joins("JOIN reservations ON reservations.venue_id = venues.venue_id").
 		Joins("JOIN users ON users.user_id = reservations.user_id").
 		Where("venues.venue_id = ?", venueId).
 		Where("reservations.check_in_date BETWEEN NOW() AND DATE_ADD(NOW(), INTERVAL 3 DAY)").
 		Group("venues.venue_id, reservations.reservation_id").
+		Preload("Category").
 		Preload("Reservations.User").
 		First(&venues)
 	if query.Error != nil {
@@ -386,6 +1017,7 @@ func (vq *venueQuery) MyVenues(userId string) ([]venue.VenueCore, error) {
 		Group("venues.venue_id").
 		Order("venues.updated_at DESC").
 		Preload("User").
+		Preload("Category").
 		Preload("VenuePictures").
 		Preload("Reviews").
 		Find(&venues)
@@ -407,3 +1039,286 @@ func (vq *venueQuery) MyVenues(userId string) ([]venue.VenueCore, error) {
 
 	return result, nil
 }
+
+// GetOpeningHours implements venue.VenueData.
+func (vq *venueQuery) GetOpeningHours(venueId string) ([]venue.VenueHourCore, error) {
+	hours := []VenueHour{}
+	query := vq.db.Table("venue_hours").
+		Where("venue_id = ?", venueId).
+		Order("weekday ASC, open_time ASC").
+		Find(&hours)
+	if query.Error != nil {
+		log.Sugar().Error("error executing opening hours query:", query.Error)
+		return nil, errors.New("error executing opening hours query")
+	}
+
+	result := make([]venue.VenueHourCore, len(hours))
+	for i, h := range hours {
+		result[i] = venueHourModels(h)
+	}
+
+	return result, nil
+}
+
+// ReplaceOpeningHours implements venue.VenueData.
+func (vq *venueQuery) ReplaceOpeningHours(userId string, venueId string, hours []venue.VenueHourCore) ([]venue.VenueHourCore, error) {
+	tx := vq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return nil, errors.New("internal server error on beginning database transaction")
+	}
+
+	if err := ownedVenue(tx, userId, venueId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Where("venue_id = ?", venueId).Delete(&VenueHour{}).Error; err != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while clearing opening hours:", err)
+		return nil, errors.New("error while clearing opening hours")
+	}
+
+	models := make([]VenueHour, len(hours))
+	for i, h := range hours {
+		h.VenueHourID = helper.GenerateVenueHourID()
+		h.VenueID = venueId
+		models[i] = venueHourEntities(h)
+	}
+
+	if len(models) > 0 {
+		if err := tx.Create(&models).Error; err != nil {
+			tx.Rollback()
+			log.Sugar().Error("error while saving opening hours:", err)
+			return nil, errors.New("error while saving opening hours")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return nil, errors.New("internal server error on committing database transaction")
+	}
+
+	result := make([]venue.VenueHourCore, len(models))
+	for i, h := range models {
+		result[i] = venueHourModels(h)
+	}
+
+	log.Sugar().Infof("opening hours of venue %s have been replaced", venueId)
+	return result, nil
+}
+
+// GetCancellationPolicy implements venue.VenueData.
+func (vq *venueQuery) GetCancellationPolicy(venueId string) ([]venue.CancellationTierCore, error) {
+	tiers := []CancellationPolicy{}
+	query := vq.db.Table("cancellation_policies").
+		Where("venue_id = ?", venueId).
+		Order("hours_before DESC").
+		Find(&tiers)
+	if query.Error != nil {
+		log.Sugar().Error("error executing cancellation policy query:", query.Error)
+		return nil, errors.New("error executing cancellation policy query")
+	}
+
+	result := make([]venue.CancellationTierCore, len(tiers))
+	for i, t := range tiers {
+		result[i] = cancellationPolicyModels(t)
+	}
+
+	return result, nil
+}
+
+// ReplaceCancellationPolicy implements venue.VenueData.
+func (vq *venueQuery) ReplaceCancellationPolicy(userId string, venueId string, tiers []venue.CancellationTierCore) ([]venue.CancellationTierCore, error) {
+	tx := vq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return nil, errors.New("internal server error on beginning database transaction")
+	}
+
+	if err := ownedVenue(tx, userId, venueId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Where("venue_id = ?", venueId).Delete(&CancellationPolicy{}).Error; err != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while clearing cancellation policy:", err)
+		return nil, errors.New("error while clearing cancellation policy")
+	}
+
+	models := make([]CancellationPolicy, len(tiers))
+	for i, t := range tiers {
+		t.CancellationPolicyID = helper.GenerateCancellationPolicyID()
+		t.VenueID = venueId
+		models[i] = cancellationPolicyEntities(t)
+	}
+
+	if len(models) > 0 {
+		if err := tx.Create(&models).Error; err != nil {
+			tx.Rollback()
+			log.Sugar().Error("error while saving cancellation policy:", err)
+			return nil, errors.New("error while saving cancellation policy")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return nil, errors.New("internal server error on committing database transaction")
+	}
+
+	result := make([]venue.CancellationTierCore, len(models))
+	for i, t := range models {
+		result[i] = cancellationPolicyModels(t)
+	}
+
+	log.Sugar().Infof("cancellation policy of venue %s has been replaced", venueId)
+	return result, nil
+}
+
+// GetPricingRules implements venue.VenueData.
+func (vq *venueQuery) GetPricingRules(venueId string) ([]venue.PricingRuleCore, error) {
+	rules := []PricingRule{}
+	query := vq.db.Table("pricing_rules").
+		Where("venue_id = ?", venueId).
+		Order("priority DESC, created_at ASC").
+		Find(&rules)
+	if query.Error != nil {
+		log.Sugar().Error("error executing pricing rules query:", query.Error)
+		return nil, errors.New("error executing pricing rules query")
+	}
+
+	result := make([]venue.PricingRuleCore, len(rules))
+	for i, r := range rules {
+		result[i] = pricingRuleModels(r)
+	}
+
+	return result, nil
+}
+
+// ReplacePricingRules implements venue.VenueData.
+func (vq *venueQuery) ReplacePricingRules(userId string, venueId string, rules []venue.PricingRuleCore) ([]venue.PricingRuleCore, error) {
+	tx := vq.db.Begin()
+	if tx.Error != nil {
+		log.Error("error on beginning database transaction")
+		return nil, errors.New("internal server error on beginning database transaction")
+	}
+
+	if err := ownedVenue(tx, userId, venueId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Where("venue_id = ?", venueId).Delete(&PricingRule{}).Error; err != nil {
+		tx.Rollback()
+		log.Sugar().Error("error while clearing pricing rules:", err)
+		return nil, errors.New("error while clearing pricing rules")
+	}
+
+	models := make([]PricingRule, len(rules))
+	for i, r := range rules {
+		r.PricingRuleID = helper.GeneratePricingRuleID()
+		r.VenueID = venueId
+		models[i] = pricingRuleEntities(r)
+	}
+
+	if len(models) > 0 {
+		if err := tx.Create(&models).Error; err != nil {
+			tx.Rollback()
+			log.Sugar().Error("error while saving pricing rules:", err)
+			return nil, errors.New("error while saving pricing rules")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("error on committing database transaction")
+		return nil, errors.New("internal server error on committing database transaction")
+	}
+
+	result := make([]venue.PricingRuleCore, len(models))
+	for i, r := range models {
+		result[i] = pricingRuleModels(r)
+	}
+
+	log.Sugar().Infof("pricing rules of venue %s have been replaced", venueId)
+	return result, nil
+}
+
+// GetClosures implements venue.VenueData.
+func (vq *venueQuery) GetClosures(venueId string) ([]venue.VenueClosureCore, error) {
+	closures := []VenueClosure{}
+	query := vq.db.Table("venue_closures").
+		Where("venue_id = ? AND end_date > ? AND deleted_at IS NULL", venueId, time.Now()).
+		Order("start_date ASC").
+		Find(&closures)
+	if query.Error != nil {
+		log.Sugar().Error("error executing closures query:", query.Error)
+		return nil, errors.New("error executing closures query")
+	}
+
+	result := make([]venue.VenueClosureCore, len(closures))
+	for i, c := range closures {
+		result[i] = venueClosureModels(c)
+	}
+
+	return result, nil
+}
+
+// InsertClosure implements venue.VenueData.
+func (vq *venueQuery) InsertClosure(userId string, request venue.VenueClosureCore) (venue.VenueClosureCore, error) {
+	if err := ownedVenue(vq.db, userId, request.VenueID); err != nil {
+		return venue.VenueClosureCore{}, err
+	}
+
+	request.VenueClosureID = helper.GenerateClosureID()
+	req := venueClosureEntities(request)
+	query := vq.db.Table("venue_closures").Create(&req)
+	if query.Error != nil {
+		log.Sugar().Error("error while creating closure:", query.Error)
+		return venue.VenueClosureCore{}, errors.New("error while creating closure")
+	}
+
+	log.Sugar().Infof("new closure has been created: %s", req.VenueClosureID)
+	return venueClosureModels(req), nil
+}
+
+// DeleteClosure implements venue.VenueData.
+func (vq *venueQuery) DeleteClosure(userId string, venueId string, closureId string) error {
+	if err := ownedVenue(vq.db, userId, venueId); err != nil {
+		return err
+	}
+
+	query := vq.db.Table("venue_closures").
+		Where("venue_id = ? AND venue_closure_id = ?", venueId, closureId).
+		Delete(&VenueClosure{})
+	if query.Error != nil {
+		log.Sugar().Error("error while deleting closure:", query.Error)
+		return errors.New("error while deleting closure")
+	}
+
+	if query.RowsAffected == 0 {
+		log.Warn("closure record not found")
+		return errors.New("closure record not found")
+	}
+
+	return nil
+}
+
+// ownedVenue makes sure the venue exists and belongs to the user.
+func ownedVenue(db *gorm.DB, userId string, venueId string) error {
+	var count int64
+	query := db.Table("venues").
+		Where("owner_id = ? AND venue_id = ? AND deleted_at IS NULL", userId, venueId).
+		Count(&count)
+	if query.Error != nil {
+		log.Sugar().Error("error executing venues query:", query.Error)
+		return errors.New("error executing venues query")
+	}
+
+	if count == 0 {
+		log.Warn("venue record not found")
+		return errors.New("venue record not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBooleanQuery(t *testing.T) {
+	vocabulary := []string{"futsal", "jakarta", "badminton", "bandung"}
+
+	t.Run("every term is required", func(t *testing.T) {
+		query := booleanQuery(searchTerms("Futsal Jakarta"), vocabulary)
+		assert.Equal(t, "+(futsal*) +(jakarta*)", query)
+	})
+
+	t.Run("corrections are alternatives of their own term", func(t *testing.T) {
+		query := booleanQuery(searchTerms("futsl jakarta"), vocabulary)
+		assert.Equal(t, "+(futsl* futsal) +(jakarta*)", query)
+	})
+
+	t.Run("operators in the keyword are dropped", func(t *testing.T) {
+		query := booleanQuery(searchTerms(`-badminton "bandung"`), vocabulary)
+		assert.Equal(t, "+(badminton*) +(bandung*)", query)
+	})
+
+	t.Run("without a vocabulary", func(t *testing.T) {
+		query := booleanQuery(searchTerms("futsl jakarta"), nil)
+		assert.Equal(t, "+(futsl*) +(jakarta*)", query)
+	})
+}
@@ -4,33 +4,86 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/utils/geo"
 	"github.com/playground-pro-project/playground-pro-api/utils/pagination"
 )
 
+// VenueCore is a venue listed by an owner. Editing a venue with nil Amenities keeps the
+// amenities it has.
 type VenueCore struct {
+	VenueID          string
+	OwnerID          string
+	CategoryID       string
+	Category         string `validate:"required"`
+	Name             string `validate:"required"`
+	Description      string
+	Username         string
+	ServiceTime      string `validate:"required"`
+	Location         string `validate:"required"`
+	Distance         float64
+	Price            float64 `validate:"required"`
+	Longitude        float64
+	Latitude         float64
+	RescheduleCutoff uint
+	TotalRows        int64
+	TotalPages       int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        time.Time
+	TotalReviews     uint
+	AverageRating    float64
+	VenuePictures    []VenuePictureCore
+	Reviews          []ReviewCore
+	Reservations     []ReservationCore
+	OpeningHours     []VenueHourCore
+	Amenities        []AmenityCore
+	User             UserCore
+}
+
+type VenueHourCore struct {
+	VenueHourID string
+	VenueID     string
+	Weekday     int
+	OpenTime    string
+	CloseTime   string
+}
+
+// CancellationTierCore refunds RefundPercent of a booking cancelled at least HoursBefore hours
+// before check-in. The percent is of the RefundBasis, fees and tax are not refunded.
+// RefundBasis is what cancellation refunds are a percent of: the booking subtotal, after any
+// voucher discount and before fees and tax.
+const RefundBasis = "subtotal"
+
+type CancellationTierCore struct {
+	CancellationPolicyID string
+	VenueID              string
+	HoursBefore          int
+	RefundPercent        int
+}
+
+// PricingRuleCore charges Price per hour instead of the venue price for bookings touching its
+// weekdays, time band and date range. Empty fields leave that dimension unrestricted.
+type PricingRuleCore struct {
+	PricingRuleID string
 	VenueID       string
-	OwnerID       string
-	Category      string `validate:"required"`
-	Name          string `validate:"required"`
-	Description   string
-	Username      string
-	ServiceTime   string `validate:"required"`
-	Location      string `validate:"required"`
-	Distance      float64
-	Price         float64 `validate:"required"`
-	Longitude     float64
-	Latitude      float64
-	TotalRows     int64
-	TotalPages    int
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	DeletedAt     time.Time
-	TotalReviews  uint
-	AverageRating float64
-	VenuePictures []VenuePictureCore
-	Reviews       []ReviewCore
-	Reservations  []ReservationCore
-	User          UserCore
+	Name          string
+	Weekdays      []int
+	StartTime     string
+	EndTime       string
+	StartDate     time.Time
+	EndDate       time.Time
+	MinDuration   float64
+	Price         float64
+	Priority      int
+}
+
+type VenueClosureCore struct {
+	VenueClosureID string
+	VenueID        string
+	StartDate      time.Time
+	EndDate        time.Time
+	Reason         string
+	CreatedAt      time.Time
 }
 
 type ReviewCore struct {
@@ -46,6 +99,27 @@ type ReviewCore struct {
 	Venue     VenueCore
 }
 
+// AmenityCore is a facility or attribute of a venue players filter on, identified by its
+// Code. Kind groups the catalog into facilities, indoor or outdoor settings and surfaces.
+type AmenityCore struct {
+	AmenityID string
+	Code      string
+	Name      string
+	Kind      string
+}
+
+const (
+	AmenityFacility = "facility"
+	AmenitySetting  = "setting"
+	AmenitySurface  = "surface"
+)
+
+// CategoryCore is the sport a venue is listed under.
+type CategoryCore struct {
+	CategoryID string
+	Name       string
+}
+
 type UserCore struct {
 	UserID         string
 	Fullname       string
@@ -89,6 +163,7 @@ type ReservationCore struct {
 type VenueCoreRaw struct {
 	VenueID       string
 	OwnerID       string
+	CategoryID    string
 	Category      string `validate:"required"`
 	Name          string `validate:"required"`
 	Description   string
@@ -105,6 +180,7 @@ type VenueCoreRaw struct {
 	UpdatedAt     time.Time
 	DeletedAt     time.Time
 	TotalReviews  uint
+	TotalBookings uint
 	AverageRating float64
 	VenuePicture  string
 	VenuePictures []VenuePictureCore
@@ -113,8 +189,90 @@ type VenueCoreRaw struct {
 	User          UserCore
 }
 
+// SearchCore holds the filters and sort order of a venue search. Zero values leave that
+// filter off, OpenAt keeps only venues open at that moment. Category is a category name
+// or id, resolved to CategoryID before searching. Amenities keeps only venues having
+// every one of those amenity codes. Located tells whether the searcher gave their
+// coordinates, without them distances are measured from a default point and neither a
+// radius nor the distance sort is allowed.
+type SearchCore struct {
+	Keyword    string
+	Category   string
+	CategoryID string
+	Amenities  []string
+	MinPrice   float64
+	MaxPrice   float64
+	MinRating  float64
+	Latitude   float64
+	Longitude  float64
+	Located    bool
+	RadiusKm   float64
+	OpenAt     time.Time
+	SortBy     string
+	Order      string
+}
+
+type SearchResultCore struct {
+	Venues     []VenueCoreRaw
+	TotalRows  int64
+	TotalPages int
+	Facets     FacetsCore
+}
+
+// FacetsCore counts the venues matching a search per category and price bucket. Each
+// facet ignores its own filter so the other options stay visible.
+type FacetsCore struct {
+	Categories   []FacetCore
+	PriceBuckets []PriceBucketCore
+}
+
+// FacetCore counts the venues of one facet option. ID identifies the option when its
+// Value is only a label, as for categories.
+type FacetCore struct {
+	ID    string
+	Value string
+	Count int64
+}
+
+// PriceBucketCore counts venues priced from Min up to but excluding Max. A zero Max
+// leaves the last bucket open-ended.
+type PriceBucketCore struct {
+	Min   float64
+	Max   float64
+	Count int64
+}
+
+// SuggestionCore is a venue offered by autocomplete.
+type SuggestionCore struct {
+	VenueID string
+	Name    string
+}
+
+// MapMarkerCore is a venue on the map, or at low zoom levels a cluster of Count venues
+// centred on its coordinates. Clusters leave the venue fields empty.
+type MapMarkerCore struct {
+	VenueID   string
+	Name      string
+	Category  string
+	Price     float64
+	Latitude  float64
+	Longitude float64
+	Count     int64
+}
+
+const (
+	SortByRelevance  = "relevance"
+	SortByDistance   = "distance"
+	SortByPrice      = "price"
+	SortByRating     = "rating"
+	SortByPopularity = "popularity"
+)
+
 type VenueHandler interface {
 	SearchVenues() echo.HandlerFunc
+	VenueMap() echo.HandlerFunc
+	SuggestVenues() echo.HandlerFunc
+	GetAmenities() echo.HandlerFunc
 	SelectVenue() echo.HandlerFunc
 	EditVenue() echo.HandlerFunc
 	UnregisterVenue() echo.HandlerFunc
@@ -124,10 +282,22 @@ type VenueHandler interface {
 	MyVenues() echo.HandlerFunc
 	CreateVenue() echo.HandlerFunc
 	CreateVenueImage() echo.HandlerFunc
+	GetOpeningHours() echo.HandlerFunc
+	SetOpeningHours() echo.HandlerFunc
+	GetCancellationPolicy() echo.HandlerFunc
+	SetCancellationPolicy() echo.HandlerFunc
+	GetPricingRules() echo.HandlerFunc
+	SetPricingRules() echo.HandlerFunc
+	GetClosures() echo.HandlerFunc
+	CreateClosure() echo.HandlerFunc
+	DeleteClosure() echo.HandlerFunc
 }
 
 type VenueService interface {
-	SearchVenues(keyword string, latitude float64, longitude float64, page pagination.Pagination) ([]VenueCoreRaw, int64, int, error)
+	SearchVenues(search SearchCore, page pagination.Pagination) (SearchResultCore, error)
+	VenueMap(box geo.Box, zoom int) ([]MapMarkerCore, error)
+	SuggestVenues(query string) ([]SuggestionCore, error)
+	GetAmenities() ([]AmenityCore, error)
 	SelectVenue(venueId string) (VenueCore, error)
 	EditVenue(userId string, venueId string, request VenueCore) error
 	UnregisterVenue(userId string, venueId string) error
@@ -138,11 +308,26 @@ type VenueService interface {
 	MyVenues(userId string) ([]VenueCore, error)
 	CreateVenue(userID string, venueReq VenueCore, venueImageReq VenuePictureCore) (VenueCore, error)
 	CreateVenueImage(req VenuePictureCore) (VenuePictureCore, error)
+	GetOpeningHours(venueId string) ([]VenueHourCore, error)
+	SetOpeningHours(userId string, venueId string, hours []VenueHourCore) ([]VenueHourCore, error)
+	GetCancellationPolicy(venueId string) ([]CancellationTierCore, error)
+	SetCancellationPolicy(userId string, venueId string, tiers []CancellationTierCore) ([]CancellationTierCore, error)
+	GetPricingRules(venueId string) ([]PricingRuleCore, error)
+	SetPricingRules(userId string, venueId string, rules []PricingRuleCore) ([]PricingRuleCore, error)
+	GetClosures(venueId string) ([]VenueClosureCore, error)
+	CreateClosure(userId string, request VenueClosureCore) (VenueClosureCore, error)
+	DeleteClosure(userId string, venueId string, closureId string) error
 }
 
 type VenueData interface {
+	GetCategory(category string) (CategoryCore, error)
+	SelectAmenities() ([]AmenityCore, error)
+	GetAmenitiesByCode(codes []string) ([]AmenityCore, error)
 	RegisterVenue(userId string, request VenueCore) (VenueCore, error)
-	SearchVenues(keyword string, latitude float64, longitude float64, page pagination.Pagination) ([]VenueCoreRaw, int64, int, error)
+	SearchVenues(search SearchCore, page pagination.Pagination) (SearchResultCore, error)
+	GetVenuesInBox(box geo.Box, limit int) ([]MapMarkerCore, error)
+	ClusterVenuesInBox(box geo.Box, cellSize float64) ([]MapMarkerCore, error)
+	SuggestVenues(query string, limit int) ([]SuggestionCore, error)
 	SelectVenue(venueId string) (VenueCore, error)
 	EditVenue(userId string, venueId string, request VenueCore) error
 	UnregisterVenue(userId string, venueId string) error
@@ -153,4 +338,13 @@ type VenueData interface {
 	MyVenues(userId string) ([]VenueCore, error)
 	InsertVenue(userID string, venueReq VenueCore, venueImageReq VenuePictureCore) (VenueCore, error)
 	InsertVenueImage(req VenuePictureCore) (VenuePictureCore, error)
+	GetOpeningHours(venueId string) ([]VenueHourCore, error)
+	ReplaceOpeningHours(userId string, venueId string, hours []VenueHourCore) ([]VenueHourCore, error)
+	GetCancellationPolicy(venueId string) ([]CancellationTierCore, error)
+	ReplaceCancellationPolicy(userId string, venueId string, tiers []CancellationTierCore) ([]CancellationTierCore, error)
+	GetPricingRules(venueId string) ([]PricingRuleCore, error)
+	ReplacePricingRules(userId string, venueId string, rules []PricingRuleCore) ([]PricingRuleCore, error)
+	GetClosures(venueId string) ([]VenueClosureCore, error)
+	InsertClosure(userId string, request VenueClosureCore) (VenueClosureCore, error)
+	DeleteClosure(userId string, venueId string, closureId string) error
 }
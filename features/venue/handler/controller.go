@@ -6,11 +6,13 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	echo "github.com/labstack/echo/v4"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
 	"github.com/playground-pro-project/playground-pro-api/features/venue"
 	"github.com/playground-pro-project/playground-pro-api/utils/aws"
+	"github.com/playground-pro-project/playground-pro-api/utils/geo"
 	"github.com/playground-pro-project/playground-pro-api/utils/helper"
 	"github.com/playground-pro-project/playground-pro-api/utils/pagination"
 )
@@ -141,19 +143,60 @@ func (vh *venueHandler) SearchVenues() echo.HandlerFunc {
 			}
 		}
 
-		venues, rows, pages, err := vh.service.SearchVenues(keyword, latitude, longitude, page)
+		search := venue.SearchCore{
+			Keyword:   keyword,
+			Category:  c.QueryParam("category"),
+			Latitude:  latitude,
+			Longitude: longitude,
+			Located:   latitudeStr != "" && longitudeStr != "",
+			Amenities: splitList(c.QueryParams()["amenities"]),
+			SortBy:    c.QueryParam("sort"),
+			Order:     c.QueryParam("order"),
+		}
+		filters := []struct {
+			param string
+			value *float64
+		}{
+			{"min_price", &search.MinPrice},
+			{"max_price", &search.MaxPrice},
+			{"min_rating", &search.MinRating},
+			{"radius", &search.RadiusKm},
+		}
+		for _, f := range filters {
+			if c.QueryParam(f.param) == "" {
+				continue
+			}
+			*f.value, err = strconv.ParseFloat(c.QueryParam(f.param), 64)
+			if err != nil {
+				log.Sugar().Errorf("invalid %s", f.param)
+				return helper.BadRequestError(c, "Invalid "+f.param)
+			}
+		}
+		if openAt := c.QueryParam("open_at"); openAt != "" {
+			search.OpenAt, err = time.Parse("2006-01-02 15:04:05", openAt)
+			if err != nil {
+				log.Error("invalid open_at")
+				return helper.BadRequestError(c, "Invalid open_at, expected YYYY-MM-DD HH:MM:SS")
+			}
+		}
+
+		searchResult, err := vh.service.SearchVenues(search, page)
 		if err != nil {
-			if strings.Contains(err.Error(), "venues not found") {
+			switch {
+			case strings.Contains(err.Error(), "venues not found"):
 				log.Error("venues not found")
 				return helper.NotFoundError(c, "The requested resource was not found")
-			} else {
+			case strings.Contains(err.Error(), "invalid"):
+				log.Error("bad request, invalid search filter")
+				return helper.BadRequestError(c, err.Error())
+			default:
 				log.Error("internal server error")
 				return helper.InternalServerError(c, "Internal server error")
 			}
 		}
 
-		result := make([]SearchVenueResponse, len(venues))
-		for i, venue := range venues {
+		result := make([]SearchVenueResponse, len(searchResult.Venues))
+		for i, venue := range searchResult.Venues {
 			result[i] = SearchVenueRaw(venue)
 		}
 
@@ -161,8 +204,8 @@ func (vh *venueHandler) SearchVenues() echo.HandlerFunc {
 			Limit:      page.Limit,
 			Offset:     page.Offset,
 			Page:       page.Page,
-			TotalRows:  rows,
-			TotalPages: pages,
+			TotalRows:  searchResult.TotalRows,
+			TotalPages: searchResult.TotalPages,
 		}
 
 		if len(result) == 0 {
@@ -170,7 +213,89 @@ func (vh *venueHandler) SearchVenues() echo.HandlerFunc {
 			return helper.NotFoundError(c, "The requested resource was not found")
 		}
 
-		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, pagination))
+		response := helper.ResponseFormat(http.StatusOK, "Successful Operation", result, pagination)
+		response.Facets = searchFacets(searchResult.Facets)
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// VenueMap implements venue.VenueHandler.
+func (vh *venueHandler) VenueMap() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		bounds := strings.Split(c.QueryParam("bbox"), ",")
+		if len(bounds) != 4 {
+			log.Error("invalid bbox")
+			return helper.BadRequestError(c, "Invalid bbox, expected min_lng,min_lat,max_lng,max_lat")
+		}
+
+		values := make([]float64, len(bounds))
+		for i, b := range bounds {
+			value, err := strconv.ParseFloat(strings.TrimSpace(b), 64)
+			if err != nil {
+				log.Error("invalid bbox")
+				return helper.BadRequestError(c, "Invalid bbox, expected min_lng,min_lat,max_lng,max_lat")
+			}
+			values[i] = value
+		}
+		box := geo.Box{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+
+		zoom, err := strconv.Atoi(c.QueryParam("zoom"))
+		if err != nil {
+			log.Error("invalid zoom")
+			return helper.BadRequestError(c, "Invalid zoom")
+		}
+
+		markers, err := vh.service.VenueMap(box, zoom)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				log.Error("bad request, invalid map viewport")
+				return helper.BadRequestError(c, err.Error())
+			}
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]MapMarkerResponse, len(markers))
+		for i, m := range markers {
+			result[i] = mapMarker(m)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// SuggestVenues implements venue.VenueHandler.
+func (vh *venueHandler) SuggestVenues() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		suggestions, err := vh.service.SuggestVenues(c.QueryParam("q"))
+		if err != nil {
+			if strings.Contains(err.Error(), "query cannot be empty") {
+				log.Error("bad request, query cannot be empty")
+				return helper.BadRequestError(c, "Bad request, q cannot be empty")
+			}
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]SuggestionResponse, len(suggestions))
+		for i, s := range suggestions {
+			result[i] = SuggestionResponse{VenueID: s.VenueID, Name: s.Name}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// GetAmenities implements venue.VenueHandler.
+func (vh *venueHandler) GetAmenities() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		amenities, err := vh.service.GetAmenities()
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", Amenities(amenities), nil))
 	}
 }
 
@@ -230,6 +355,9 @@ func (vh *venueHandler) EditVenue() echo.HandlerFunc {
 			case strings.Contains(err.Error(), "no venue has been created"):
 				log.Error("no venue has been created")
 				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, err.Error())
 			default:
 				log.Error("internal server error")
 				return helper.InternalServerError(c, "Internal server error")
@@ -498,3 +626,262 @@ func (vh *venueHandler) MyVenues() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
 	}
 }
+
+// GetOpeningHours implements venue.VenueHandler.
+func (vh *venueHandler) GetOpeningHours() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		venueId := c.Param("venue_id")
+		if venueId == "" {
+			log.Error("empty venue_id parameter")
+			return helper.NotFoundError(c, "The requested resource was not found")
+		}
+
+		hours, err := vh.service.GetOpeningHours(venueId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", OpeningHours(hours), nil))
+	}
+}
+
+// SetOpeningHours implements venue.VenueHandler.
+func (vh *venueHandler) SetOpeningHours() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := SetOpeningHoursRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&request)
+		if errBind != nil {
+			log.Error("error on bind input")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		venueId := c.Param("venue_id")
+		hours, err := vh.service.SetOpeningHours(userId, venueId, OpeningHoursRequestToCore(request))
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "venue record not found"):
+				log.Error("venue record not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "overlap"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Opening hours updated successfully", OpeningHours(hours), nil))
+	}
+}
+
+// GetCancellationPolicy implements venue.VenueHandler.
+func (vh *venueHandler) GetCancellationPolicy() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		venueId := c.Param("venue_id")
+		if venueId == "" {
+			log.Error("empty venue_id parameter")
+			return helper.NotFoundError(c, "The requested resource was not found")
+		}
+
+		tiers, err := vh.service.GetCancellationPolicy(venueId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", CancellationPolicy(tiers), nil))
+	}
+}
+
+// SetCancellationPolicy implements venue.VenueHandler.
+func (vh *venueHandler) SetCancellationPolicy() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := SetCancellationPolicyRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&request)
+		if errBind != nil {
+			log.Error("error on bind input")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		venueId := c.Param("venue_id")
+		tiers, err := vh.service.SetCancellationPolicy(userId, venueId, CancellationPolicyRequestToCore(request))
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "venue record not found"):
+				log.Error("venue record not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Cancellation policy updated successfully", CancellationPolicy(tiers), nil))
+	}
+}
+
+// GetPricingRules implements venue.VenueHandler.
+func (vh *venueHandler) GetPricingRules() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		venueId := c.Param("venue_id")
+		if venueId == "" {
+			log.Error("empty venue_id parameter")
+			return helper.NotFoundError(c, "The requested resource was not found")
+		}
+
+		rules, err := vh.service.GetPricingRules(venueId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", PricingRules(rules), nil))
+	}
+}
+
+// SetPricingRules implements venue.VenueHandler.
+func (vh *venueHandler) SetPricingRules() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := SetPricingRulesRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&request)
+		if errBind != nil {
+			log.Error("error on bind input")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		rules, err := PricingRulesRequestToCore(request)
+		if err != nil {
+			log.Error(err.Error())
+			return helper.BadRequestError(c, "Bad request, "+err.Error())
+		}
+
+		venueId := c.Param("venue_id")
+		rules, err = vh.service.SetPricingRules(userId, venueId, rules)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "venue record not found"):
+				log.Error("venue record not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Pricing rules updated successfully", PricingRules(rules), nil))
+	}
+}
+
+// GetClosures implements venue.VenueHandler.
+func (vh *venueHandler) GetClosures() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		venueId := c.Param("venue_id")
+		if venueId == "" {
+			log.Error("empty venue_id parameter")
+			return helper.NotFoundError(c, "The requested resource was not found")
+		}
+
+		closures, err := vh.service.GetClosures(venueId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]ClosureResponse, len(closures))
+		for i, closure := range closures {
+			result[i] = Closure(closure)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// CreateClosure implements venue.VenueHandler.
+func (vh *venueHandler) CreateClosure() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := CreateClosureRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&request)
+		if errBind != nil {
+			log.Error("error on bind input")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		closureCore, err := ClosureRequestToCore(c.Param("venue_id"), request)
+		if err != nil {
+			log.Error(err.Error())
+			return helper.BadRequestError(c, "Bad request, "+err.Error())
+		}
+
+		closure, err := vh.service.CreateClosure(userId, closureCore)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "venue record not found"):
+				log.Error("venue record not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "date"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully operation", Closure(closure), nil))
+	}
+}
+
+// DeleteClosure implements venue.VenueHandler.
+func (vh *venueHandler) DeleteClosure() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		err := vh.service.DeleteClosure(userId, c.Param("venue_id"), c.Param("closure_id"))
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			}
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully deleted a closure", nil, nil))
+	}
+}
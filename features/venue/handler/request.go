@@ -2,6 +2,8 @@ package handler
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/playground-pro-project/playground-pro-api/features/venue"
 )
@@ -12,23 +14,144 @@ const (
 )
 
 type RegisterVenueRequest struct {
-	Category    string  `json:"category" form:"category"`
+	Category         string   `json:"category" form:"category"`
+	Name             string   `json:"name" form:"name"`
+	Description      string   `json:"description" form:"description"`
+	ServiceTime      string   `json:"service_time" form:"service_time"`
+	Location         string   `json:"location" form:"location"`
+	Price            float64  `json:"price" form:"price"`
+	Longitude        float64  `json:"lon" form:"lon"`
+	Latitude         float64  `json:"lat" form:"lat"`
+	RescheduleCutoff uint     `json:"reschedule_cutoff" form:"reschedule_cutoff"`
+	Amenities        []string `json:"amenities" form:"amenities"`
+}
+
+type EditVenueRequest struct {
+	Category         *string  `json:"category" form:"category"`
+	Name             *string  `json:"name" form:"name"`
+	Description      *string  `json:"description" form:"description"`
+	ServiceTime      *string  `json:"service_time" form:"service_time"`
+	Location         *string  `json:"location" form:"location"`
+	Price            *float64 `json:"price" form:"price"`
+	RescheduleCutoff *uint    `json:"reschedule_cutoff" form:"reschedule_cutoff"`
+	Amenities        []string `json:"amenities" form:"amenities"`
+}
+
+type OpeningHourRequest struct {
+	Weekday   int    `json:"weekday" form:"weekday"`
+	OpenTime  string `json:"open_time" form:"open_time"`
+	CloseTime string `json:"close_time" form:"close_time"`
+}
+
+type SetOpeningHoursRequest struct {
+	Hours []OpeningHourRequest `json:"hours" form:"hours"`
+}
+
+type CancellationTierRequest struct {
+	HoursBefore   int `json:"hours_before" form:"hours_before"`
+	RefundPercent int `json:"refund_percent" form:"refund_percent"`
+}
+
+type SetCancellationPolicyRequest struct {
+	Tiers []CancellationTierRequest `json:"tiers" form:"tiers"`
+}
+
+type PricingRuleRequest struct {
 	Name        string  `json:"name" form:"name"`
-	Description string  `json:"description" form:"description"`
-	ServiceTime string  `json:"service_time" form:"service_time"`
-	Location    string  `json:"location" form:"location"`
+	Weekdays    []int   `json:"weekdays" form:"weekdays"`
+	StartTime   string  `json:"start_time" form:"start_time"`
+	EndTime     string  `json:"end_time" form:"end_time"`
+	StartDate   string  `json:"start_date" form:"start_date"`
+	EndDate     string  `json:"end_date" form:"end_date"`
+	MinDuration float64 `json:"min_duration" form:"min_duration"`
 	Price       float64 `json:"price" form:"price"`
-	Longitude   float64 `json:"lon" form:"lon"`
-	Latitude    float64 `json:"lat" form:"lat"`
+	Priority    int     `json:"priority" form:"priority"`
 }
 
-type EditVenueRequest struct {
-	Category    *string  `json:"category" form:"category"`
-	Name        *string  `json:"name" form:"name"`
-	Description *string  `json:"description" form:"description"`
-	ServiceTime *string  `json:"service_time" form:"service_time"`
-	Location    *string  `json:"location" form:"location"`
-	Price       *float64 `json:"price" form:"price"`
+type SetPricingRulesRequest struct {
+	Rules []PricingRuleRequest `json:"rules" form:"rules"`
+}
+
+type CreateClosureRequest struct {
+	StartDate string `json:"start_date" form:"start_date"`
+	EndDate   string `json:"end_date" form:"end_date"`
+	Reason    string `json:"reason" form:"reason"`
+}
+
+func OpeningHoursRequestToCore(request SetOpeningHoursRequest) []venue.VenueHourCore {
+	hours := make([]venue.VenueHourCore, len(request.Hours))
+	for i, h := range request.Hours {
+		hours[i] = venue.VenueHourCore{
+			Weekday:   h.Weekday,
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		}
+	}
+
+	return hours
+}
+
+func CancellationPolicyRequestToCore(request SetCancellationPolicyRequest) []venue.CancellationTierCore {
+	tiers := make([]venue.CancellationTierCore, len(request.Tiers))
+	for i, t := range request.Tiers {
+		tiers[i] = venue.CancellationTierCore{
+			HoursBefore:   t.HoursBefore,
+			RefundPercent: t.RefundPercent,
+		}
+	}
+
+	return tiers
+}
+
+func PricingRulesRequestToCore(request SetPricingRulesRequest) ([]venue.PricingRuleCore, error) {
+	rules := make([]venue.PricingRuleCore, len(request.Rules))
+	for i, r := range request.Rules {
+		rules[i] = venue.PricingRuleCore{
+			Name:        r.Name,
+			Weekdays:    r.Weekdays,
+			StartTime:   r.StartTime,
+			EndTime:     r.EndTime,
+			MinDuration: r.MinDuration,
+			Price:       r.Price,
+			Priority:    r.Priority,
+		}
+
+		if r.StartDate != "" {
+			startDate, err := time.Parse("2006-01-02", r.StartDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid start_date")
+			}
+			rules[i].StartDate = startDate
+		}
+		if r.EndDate != "" {
+			endDate, err := time.Parse("2006-01-02", r.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid end_date")
+			}
+			rules[i].EndDate = endDate
+		}
+	}
+
+	return rules, nil
+}
+
+func ClosureRequestToCore(venueId string, request CreateClosureRequest) (venue.VenueClosureCore, error) {
+	startDate, err := time.Parse("2006-01-02 15:04:05", request.StartDate)
+	if err != nil {
+		return venue.VenueClosureCore{}, fmt.Errorf("invalid start_date")
+	}
+
+	endDate, err := time.Parse("2006-01-02 15:04:05", request.EndDate)
+	if err != nil {
+		return venue.VenueClosureCore{}, fmt.Errorf("invalid end_date")
+	}
+
+	return venue.VenueClosureCore{
+		VenueID:   venueId,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    request.Reason,
+	}, nil
 }
 
 func RequestToCore(data interface{}) venue.VenueCore {
@@ -43,6 +166,8 @@ func RequestToCore(data interface{}) venue.VenueCore {
 		res.Price = v.Price
 		res.Longitude = v.Longitude
 		res.Latitude = v.Latitude
+		res.RescheduleCutoff = v.RescheduleCutoff
+		res.Amenities = requestAmenities(v.Amenities)
 	case *EditVenueRequest:
 		if v.Category != nil {
 			res.Category = *v.Category
@@ -62,6 +187,10 @@ func RequestToCore(data interface{}) venue.VenueCore {
 		if v.Price != nil {
 			res.Price = *v.Price
 		}
+		if v.RescheduleCutoff != nil {
+			res.RescheduleCutoff = *v.RescheduleCutoff
+		}
+		res.Amenities = requestAmenities(v.Amenities)
 	default:
 		return venue.VenueCore{}
 
@@ -69,6 +198,37 @@ func RequestToCore(data interface{}) venue.VenueCore {
 	return res
 }
 
+// splitList splits comma separated values, so a list can be sent as repeated parameters
+// or as one.
+func splitList(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result
+}
+
+// requestAmenities keeps amenities that were not sent nil, so editing a venue without
+// them leaves its amenities as they are.
+func requestAmenities(codes []string) []venue.AmenityCore {
+	if codes == nil {
+		return nil
+	}
+
+	items := splitList(codes)
+	amenities := make([]venue.AmenityCore, len(items))
+	for i, code := range items {
+		amenities[i] = venue.AmenityCore{Code: code}
+	}
+
+	return amenities
+}
+
 func validateRegisterVenueRequest(request RegisterVenueRequest) error {
 	if request.Category == "" {
 		return fmt.Errorf("category is required")
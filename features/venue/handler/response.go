@@ -14,26 +14,103 @@ type SearchVenueResponse struct {
 	Location      string  `json:"location,omitempty"`
 	Distance      float64 `json:"distance,omitempty"`
 	Price         float64 `json:"price,omitempty"`
+	TotalReviews  uint    `json:"total_reviews,omitempty"`
+	TotalBookings uint    `json:"total_bookings,omitempty"`
 	AverageRating float64 `json:"average_rating,omitempty"`
 	VenuePicture  string  `json:"venue_picture,omitempty"`
 }
 
+type Amenity struct {
+	AmenityID string `json:"amenity_id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+}
+
+type SuggestionResponse struct {
+	VenueID string `json:"venue_id"`
+	Name    string `json:"name"`
+}
+
+type MapMarkerResponse struct {
+	VenueID   string  `json:"venue_id,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Category  string  `json:"category,omitempty"`
+	Price     float64 `json:"price,omitempty"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Count     int64   `json:"count"`
+}
+
+type FacetsResponse struct {
+	Categories   []CategoryFacet `json:"categories"`
+	PriceBuckets []PriceBucket   `json:"price_buckets"`
+}
+
+type CategoryFacet struct {
+	CategoryID string `json:"category_id"`
+	Category   string `json:"category"`
+	Count      int64  `json:"count"`
+}
+
+type PriceBucket struct {
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price,omitempty"`
+	Count    int64   `json:"count"`
+}
+
 type SelectVenueResponse struct {
-	VenueID       string         `json:"venue_id,omitempty"`
-	OwnerID       string         `json:"user_id,omitempty"`
-	Category      string         `json:"category,omitempty"`
-	Name          string         `json:"venue_name,omitempty"`
-	Description   string         `json:"description,omitempty"`
-	Username      string         `json:"username,omitempty"`
-	ServiceTime   string         `json:"service_time,omitempty"`
-	Location      string         `json:"location,omitempty"`
-	Distance      float64        `json:"distance,omitempty"`
-	Price         float64        `json:"price,omitempty"`
-	TotalReviews  uint           `json:"total_reviews,omitempty"`
-	AverageRating float64        `json:"average_rating,omitempty"`
-	VenuePictures []VenuePicture `json:"venue_pictures,omitempty"`
-	Reviews       []Review       `json:"reviews,omitempty"`
-	Reservations  []Reservation  `json:"reservations,omitempty"`
+	VenueID          string         `json:"venue_id,omitempty"`
+	OwnerID          string         `json:"user_id,omitempty"`
+	Category         string         `json:"category,omitempty"`
+	Name             string         `json:"venue_name,omitempty"`
+	Description      string         `json:"description,omitempty"`
+	Username         string         `json:"username,omitempty"`
+	ServiceTime      string         `json:"service_time,omitempty"`
+	Location         string         `json:"location,omitempty"`
+	Distance         float64        `json:"distance,omitempty"`
+	Price            float64        `json:"price,omitempty"`
+	TotalReviews     uint           `json:"total_reviews,omitempty"`
+	AverageRating    float64        `json:"average_rating,omitempty"`
+	RescheduleCutoff uint           `json:"reschedule_cutoff,omitempty"`
+	VenuePictures    []VenuePicture `json:"venue_pictures,omitempty"`
+	Reviews          []Review       `json:"reviews,omitempty"`
+	Reservations     []Reservation  `json:"reservations,omitempty"`
+	OpeningHours     []OpeningHour  `json:"opening_hours,omitempty"`
+	Amenities        []Amenity      `json:"amenities,omitempty"`
+}
+
+type OpeningHour struct {
+	VenueHourID string `json:"venue_hour_id,omitempty"`
+	Weekday     int    `json:"weekday"`
+	OpenTime    string `json:"open_time"`
+	CloseTime   string `json:"close_time"`
+}
+
+type CancellationTier struct {
+	HoursBefore   int    `json:"hours_before"`
+	RefundPercent int    `json:"refund_percent"`
+	RefundBasis   string `json:"refund_basis"`
+}
+
+type PricingRule struct {
+	PricingRuleID string  `json:"pricing_rule_id,omitempty"`
+	Name          string  `json:"name"`
+	Weekdays      []int   `json:"weekdays,omitempty"`
+	StartTime     string  `json:"start_time,omitempty"`
+	EndTime       string  `json:"end_time,omitempty"`
+	StartDate     string  `json:"start_date,omitempty"`
+	EndDate       string  `json:"end_date,omitempty"`
+	MinDuration   float64 `json:"min_duration,omitempty"`
+	Price         float64 `json:"price"`
+	Priority      int     `json:"priority"`
+}
+
+type ClosureResponse struct {
+	VenueClosureID string           `json:"closure_id"`
+	StartDate      helper.LocalTime `json:"start_date"`
+	EndDate        helper.LocalTime `json:"end_date"`
+	Reason         string           `json:"reason,omitempty"`
 }
 
 type RegistVenueResp struct {
@@ -79,6 +156,8 @@ func SearchVenueRaw(v venue.VenueCoreRaw) SearchVenueResponse {
 		Location:      v.Location,
 		Distance:      helper.TwoDecimals(v.Distance),
 		Price:         v.Price,
+		TotalReviews:  v.TotalReviews,
+		TotalBookings: v.TotalBookings,
 		AverageRating: helper.TwoDecimals(v.AverageRating),
 		VenuePicture:  v.VenuePicture,
 	}
@@ -87,6 +166,33 @@ func SearchVenueRaw(v venue.VenueCoreRaw) SearchVenueResponse {
 
 }
 
+func mapMarker(m venue.MapMarkerCore) MapMarkerResponse {
+	return MapMarkerResponse{
+		VenueID:   m.VenueID,
+		Name:      m.Name,
+		Category:  m.Category,
+		Price:     m.Price,
+		Latitude:  m.Latitude,
+		Longitude: m.Longitude,
+		Count:     m.Count,
+	}
+}
+
+func searchFacets(f venue.FacetsCore) FacetsResponse {
+	response := FacetsResponse{
+		Categories:   make([]CategoryFacet, len(f.Categories)),
+		PriceBuckets: make([]PriceBucket, len(f.PriceBuckets)),
+	}
+	for i, c := range f.Categories {
+		response.Categories[i] = CategoryFacet{CategoryID: c.ID, Category: c.Value, Count: c.Count}
+	}
+	for i, b := range f.PriceBuckets {
+		response.PriceBuckets[i] = PriceBucket{MinPrice: b.Min, MaxPrice: b.Max, Count: b.Count}
+	}
+
+	return response
+}
+
 func RegistVenueResponse(v venue.VenueCore) RegistVenueResp {
 	return RegistVenueResp{
 		Longitude: v.Longitude,
@@ -138,25 +244,42 @@ func SelectVenue(v venue.VenueCore) SelectVenueResponse {
 	}
 
 	response := SelectVenueResponse{
-		VenueID:       v.VenueID,
-		OwnerID:       v.OwnerID,
-		Category:      v.Category,
-		Name:          v.Name,
-		Username:      v.Username,
-		Description:   v.Description,
-		ServiceTime:   v.ServiceTime,
-		Location:      v.Location,
-		Distance:      v.Distance,
-		Price:         v.Price,
-		TotalReviews:  v.TotalReviews,
-		AverageRating: v.AverageRating,
-		VenuePictures: pictures,
-		Reviews:       reviews,
+		VenueID:          v.VenueID,
+		OwnerID:          v.OwnerID,
+		Category:         v.Category,
+		Name:             v.Name,
+		Username:         v.Username,
+		Description:      v.Description,
+		ServiceTime:      v.ServiceTime,
+		Location:         v.Location,
+		Distance:         v.Distance,
+		Price:            v.Price,
+		TotalReviews:     v.TotalReviews,
+		AverageRating:    v.AverageRating,
+		RescheduleCutoff: v.RescheduleCutoff,
+		VenuePictures:    pictures,
+		Reviews:          reviews,
+		OpeningHours:     OpeningHours(v.OpeningHours),
+		Amenities:        Amenities(v.Amenities),
 	}
 
 	return response
 }
 
+func Amenities(amenities []venue.AmenityCore) []Amenity {
+	result := make([]Amenity, len(amenities))
+	for i, a := range amenities {
+		result[i] = Amenity{
+			AmenityID: a.AmenityID,
+			Code:      a.Code,
+			Name:      a.Name,
+			Kind:      a.Kind,
+		}
+	}
+
+	return result
+}
+
 func Availability(a venue.VenueCore) SelectVenueResponse {
 	reservations := make([]Reservation, len(a.Reservations))
 	for i, r := range a.Reservations {
@@ -177,3 +300,63 @@ func Availability(a venue.VenueCore) SelectVenueResponse {
 	}
 	return response
 }
+
+func OpeningHours(hours []venue.VenueHourCore) []OpeningHour {
+	result := make([]OpeningHour, len(hours))
+	for i, h := range hours {
+		result[i] = OpeningHour{
+			VenueHourID: h.VenueHourID,
+			Weekday:     h.Weekday,
+			OpenTime:    h.OpenTime,
+			CloseTime:   h.CloseTime,
+		}
+	}
+
+	return result
+}
+
+func CancellationPolicy(tiers []venue.CancellationTierCore) []CancellationTier {
+	result := make([]CancellationTier, len(tiers))
+	for i, t := range tiers {
+		result[i] = CancellationTier{
+			HoursBefore:   t.HoursBefore,
+			RefundPercent: t.RefundPercent,
+			RefundBasis:   venue.RefundBasis,
+		}
+	}
+
+	return result
+}
+
+func PricingRules(rules []venue.PricingRuleCore) []PricingRule {
+	result := make([]PricingRule, len(rules))
+	for i, r := range rules {
+		result[i] = PricingRule{
+			PricingRuleID: r.PricingRuleID,
+			Name:          r.Name,
+			Weekdays:      r.Weekdays,
+			StartTime:     r.StartTime,
+			EndTime:       r.EndTime,
+			MinDuration:   r.MinDuration,
+			Price:         r.Price,
+			Priority:      r.Priority,
+		}
+		if !r.StartDate.IsZero() {
+			result[i].StartDate = r.StartDate.Format("2006-01-02")
+		}
+		if !r.EndDate.IsZero() {
+			result[i].EndDate = r.EndDate.Format("2006-01-02")
+		}
+	}
+
+	return result
+}
+
+func Closure(c venue.VenueClosureCore) ClosureResponse {
+	return ClosureResponse{
+		VenueClosureID: c.VenueClosureID,
+		StartDate:      helper.LocalTime(c.StartDate),
+		EndDate:        helper.LocalTime(c.EndDate),
+		Reason:         c.Reason,
+	}
+}
@@ -2,16 +2,33 @@ package service
 
 import (
 	"errors"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
 	"github.com/playground-pro-project/playground-pro-api/features/venue"
+	"github.com/playground-pro-project/playground-pro-api/utils/geo"
 	"github.com/playground-pro-project/playground-pro-api/utils/pagination"
+	"github.com/playground-pro-project/playground-pro-api/utils/schedule"
 )
 
 var log = middlewares.Log()
 
+const (
+	// maxMapZoom is the deepest zoom level of the map tiles.
+	maxMapZoom = 22
+	// clusterMaxZoom is the zoom level from which the map shows venues instead of clusters.
+	clusterMaxZoom = 14
+	// clusterCellsPerTile is how many cluster cells span the width of a map tile.
+	clusterCellsPerTile = 4
+	// maxMapMarkers caps the venues returned for a single viewport.
+	maxMapMarkers = 500
+	// maxSuggestions caps the venues offered by autocomplete.
+	maxSuggestions = 10
+)
+
 type venueService struct {
 	query    venue.VenueData
 	validate *validator.Validate
@@ -50,6 +67,25 @@ func (vs *venueService) CreateVenue(userID string, venueReq venue.VenueCore, ven
 		return venue.VenueCore{}, errors.New("price cannot be empty")
 	}
 
+	if !geo.ValidCoordinates(venueReq.Latitude, venueReq.Longitude) {
+		log.Warn("invalid coordinates")
+		return venue.VenueCore{}, errors.New("invalid coordinates, expected latitude -90 to 90 and longitude -180 to 180")
+	}
+
+	category, err := vs.category(venueReq.Category)
+	if err != nil {
+		return venue.VenueCore{}, err
+	}
+	venueReq.CategoryID, venueReq.Category = category.CategoryID, category.Name
+
+	if venueReq.Amenities != nil {
+		amenities, err := vs.amenities(amenityCodes(venueReq.Amenities))
+		if err != nil {
+			return venue.VenueCore{}, err
+		}
+		venueReq.Amenities = amenities
+	}
+
 	result, err := vs.query.InsertVenue(userID, venueReq, venueImageReq)
 	if err != nil {
 		message := ""
@@ -67,19 +103,195 @@ func (vs *venueService) CreateVenue(userID string, venueReq venue.VenueCore, ven
 }
 
 // SearchVenue implements venue.VenueService.
-func (vs *venueService) SearchVenues(keyword string, latitude float64, longitude float64, page pagination.Pagination) ([]venue.VenueCoreRaw, int64, int, error) {
-	venues, rows, pages, err := vs.query.SearchVenues(keyword, latitude, longitude, page)
+func (vs *venueService) SearchVenues(search venue.SearchCore, page pagination.Pagination) (venue.SearchResultCore, error) {
+	if search.Category != "" {
+		category, err := vs.category(search.Category)
+		if err != nil {
+			return venue.SearchResultCore{}, err
+		}
+		search.CategoryID = category.CategoryID
+	}
+
+	if search.MinPrice < 0 || search.MaxPrice < 0 {
+		log.Warn("invalid price range")
+		return venue.SearchResultCore{}, errors.New("invalid price range, prices cannot be negative")
+	}
+
+	if search.MaxPrice > 0 && search.MinPrice > search.MaxPrice {
+		log.Warn("invalid price range")
+		return venue.SearchResultCore{}, errors.New("invalid price range, min_price is greater than max_price")
+	}
+
+	if search.MinRating < 0 || search.MinRating > 5 {
+		log.Warn("invalid min_rating")
+		return venue.SearchResultCore{}, errors.New("invalid min_rating, expected 0 to 5")
+	}
+
+	if !geo.ValidCoordinates(search.Latitude, search.Longitude) {
+		log.Warn("invalid coordinates")
+		return venue.SearchResultCore{}, errors.New("invalid coordinates, expected latitude -90 to 90 and longitude -180 to 180")
+	}
+
+	if search.RadiusKm < 0 {
+		log.Warn("invalid radius")
+		return venue.SearchResultCore{}, errors.New("invalid radius, cannot be negative")
+	}
+
+	if (search.RadiusKm > 0 || search.SortBy == venue.SortByDistance) && !search.Located {
+		log.Warn("invalid location")
+		return venue.SearchResultCore{}, errors.New("invalid location, radius and distance sort need both latitude and longitude")
+	}
+
+	switch search.SortBy {
+	case "", venue.SortByRelevance, venue.SortByDistance, venue.SortByPrice, venue.SortByRating, venue.SortByPopularity:
+	default:
+		log.Warn("invalid sort")
+		return venue.SearchResultCore{}, errors.New("invalid sort, expected relevance, distance, price, rating or popularity")
+	}
+
+	search.Order = strings.ToLower(search.Order)
+	if search.Order != "" && search.Order != "asc" && search.Order != "desc" {
+		log.Warn("invalid order")
+		return venue.SearchResultCore{}, errors.New("invalid order, expected asc or desc")
+	}
+
+	if len(search.Amenities) > 0 {
+		amenities, err := vs.amenities(search.Amenities)
+		if err != nil {
+			return venue.SearchResultCore{}, err
+		}
+		search.Amenities = amenityCodes(amenities)
+		sort.Strings(search.Amenities)
+	}
+
+	result, err := vs.query.SearchVenues(search, page)
 	if err != nil {
 		if strings.Contains(err.Error(), "venues not found") {
 			log.Error("list venues record not found")
-			return []venue.VenueCoreRaw{}, 0, 0, errors.New("venues not found")
+			return venue.SearchResultCore{}, errors.New("venues not found")
 		} else {
 			log.Error("internal server error")
-			return []venue.VenueCoreRaw{}, 0, 0, err
+			return venue.SearchResultCore{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// VenueMap implements venue.VenueService.
+func (vs *venueService) VenueMap(box geo.Box, zoom int) ([]venue.MapMarkerCore, error) {
+	if zoom < 0 || zoom > maxMapZoom {
+		log.Warn("invalid zoom")
+		return nil, errors.New("invalid zoom, expected 0 to 22")
+	}
+
+	if !geo.ValidCoordinates(box.MinLat, box.MinLng) || !geo.ValidCoordinates(box.MaxLat, box.MaxLng) ||
+		box.MinLat >= box.MaxLat || box.MinLng >= box.MaxLng {
+		log.Warn("invalid bbox")
+		return nil, errors.New("invalid bbox, expected min_lng,min_lat,max_lng,max_lat within the globe")
+	}
+
+	var markers []venue.MapMarkerCore
+	var err error
+	if zoom < clusterMaxZoom {
+		markers, err = vs.query.ClusterVenuesInBox(box, geo.CellSize(zoom, clusterCellsPerTile))
+	} else {
+		markers, err = vs.query.GetVenuesInBox(box, maxMapMarkers)
+	}
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return markers, nil
+}
+
+// SuggestVenues implements venue.VenueService.
+func (vs *venueService) SuggestVenues(query string) ([]venue.SuggestionCore, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		log.Warn("query cannot be empty")
+		return nil, errors.New("query cannot be empty")
+	}
+
+	suggestions, err := vs.query.SuggestVenues(query, maxSuggestions)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return suggestions, nil
+}
+
+// GetAmenities implements venue.VenueService.
+func (vs *venueService) GetAmenities() ([]venue.AmenityCore, error) {
+	amenities, err := vs.query.SelectAmenities()
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return amenities, nil
+}
+
+// amenities resolves amenity codes to the catalog, rejecting codes it does not have.
+func (vs *venueService) amenities(codes []string) ([]venue.AmenityCore, error) {
+	wanted := []string{}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code != "" && !seen[code] {
+			seen[code] = true
+			wanted = append(wanted, code)
+		}
+	}
+	if len(wanted) == 0 {
+		return []venue.AmenityCore{}, nil
+	}
+	sort.Strings(wanted)
+
+	amenities, err := vs.query.GetAmenitiesByCode(wanted)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	found := map[string]bool{}
+	for _, a := range amenities {
+		found[a.Code] = true
+	}
+	for _, code := range wanted {
+		if !found[code] {
+			log.Sugar().Warnf("invalid amenity %s", code)
+			return nil, errors.New("invalid amenity, no such amenity " + code)
 		}
 	}
 
-	return venues, rows, pages, nil
+	return amenities, nil
+}
+
+func amenityCodes(amenities []venue.AmenityCore) []string {
+	codes := make([]string, len(amenities))
+	for i, a := range amenities {
+		codes[i] = a.Code
+	}
+
+	return codes
+}
+
+// category resolves a category name or id to the category venues reference.
+func (vs *venueService) category(category string) (venue.CategoryCore, error) {
+	result, err := vs.query.GetCategory(category)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("invalid category")
+			return venue.CategoryCore{}, errors.New("invalid category, no such category")
+		}
+		log.Error("internal server error")
+		return venue.CategoryCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
 }
 
 // SelectVenue implements venue.VenueService.
@@ -99,6 +311,27 @@ func (vs *venueService) SelectVenue(venueId string) (venue.VenueCore, error) {
 
 // EditVenue implements venue.VenueService.
 func (vs *venueService) EditVenue(userId string, venueId string, request venue.VenueCore) error {
+	if !geo.ValidCoordinates(request.Latitude, request.Longitude) {
+		log.Warn("invalid coordinates")
+		return errors.New("invalid coordinates, expected latitude -90 to 90 and longitude -180 to 180")
+	}
+
+	if request.Category != "" {
+		category, err := vs.category(request.Category)
+		if err != nil {
+			return err
+		}
+		request.CategoryID = category.CategoryID
+	}
+
+	if request.Amenities != nil {
+		amenities, err := vs.amenities(amenityCodes(request.Amenities))
+		if err != nil {
+			return err
+		}
+		request.Amenities = amenities
+	}
+
 	err := vs.query.EditVenue(userId, venueId, request)
 	if err != nil {
 		if strings.Contains(err.Error(), "venue profile record not found") {
@@ -215,3 +448,226 @@ func (vs *venueService) MyVenues(userId string) ([]venue.VenueCore, error) {
 
 	return venues, nil
 }
+
+// GetOpeningHours implements venue.VenueService.
+func (vs *venueService) GetOpeningHours(venueId string) ([]venue.VenueHourCore, error) {
+	hours, err := vs.query.GetOpeningHours(venueId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return hours, nil
+}
+
+// SetOpeningHours implements venue.VenueService.
+func (vs *venueService) SetOpeningHours(userId string, venueId string, hours []venue.VenueHourCore) ([]venue.VenueHourCore, error) {
+	reference := time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC) // a Sunday
+	intervals := make([]schedule.Interval, len(hours))
+	for i, h := range hours {
+		if h.Weekday < int(time.Sunday) || h.Weekday > int(time.Saturday) {
+			log.Warn("invalid weekday")
+			return nil, errors.New("invalid weekday, expected 0 (sunday) to 6 (saturday)")
+		}
+
+		shift, err := schedule.NewShift(h.OpenTime, h.CloseTime)
+		if err != nil {
+			log.Warn("invalid opening hours format")
+			return nil, errors.New("invalid opening hours format, expected HH:MM")
+		}
+
+		hours[i].OpenTime = schedule.FormatClock(shift.Open)
+		hours[i].CloseTime = schedule.FormatClock(shift.Close)
+		intervals[i] = shift.On(reference.AddDate(0, 0, h.Weekday))
+	}
+
+	for i := range intervals {
+		for j := i + 1; j < len(intervals); j++ {
+			// Overnight shifts on saturday wrap into the next sunday.
+			wrapped := schedule.Interval{Start: intervals[j].Start.AddDate(0, 0, 7), End: intervals[j].End.AddDate(0, 0, 7)}
+			wrappedBack := schedule.Interval{Start: intervals[j].Start.AddDate(0, 0, -7), End: intervals[j].End.AddDate(0, 0, -7)}
+			if intervals[i].Overlaps(intervals[j]) || intervals[i].Overlaps(wrapped) || intervals[i].Overlaps(wrappedBack) {
+				log.Warn("opening hours overlap")
+				return nil, errors.New("opening hours overlap")
+			}
+		}
+	}
+
+	result, err := vs.query.ReplaceOpeningHours(userId, venueId, hours)
+	if err != nil {
+		if strings.Contains(err.Error(), "venue record not found") {
+			log.Error("venue record not found")
+			return nil, errors.New("venue record not found")
+		}
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// GetCancellationPolicy implements venue.VenueService.
+func (vs *venueService) GetCancellationPolicy(venueId string) ([]venue.CancellationTierCore, error) {
+	tiers, err := vs.query.GetCancellationPolicy(venueId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return tiers, nil
+}
+
+// SetCancellationPolicy implements venue.VenueService.
+func (vs *venueService) SetCancellationPolicy(userId string, venueId string, tiers []venue.CancellationTierCore) ([]venue.CancellationTierCore, error) {
+	for _, t := range tiers {
+		if t.HoursBefore < 0 {
+			log.Warn("invalid hours_before")
+			return nil, errors.New("invalid hours_before, cannot be negative")
+		}
+		if t.RefundPercent < 0 || t.RefundPercent > 100 {
+			log.Warn("invalid refund_percent")
+			return nil, errors.New("invalid refund_percent, expected 0 to 100")
+		}
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].HoursBefore > tiers[j].HoursBefore
+	})
+	for i := 1; i < len(tiers); i++ {
+		if tiers[i].HoursBefore == tiers[i-1].HoursBefore {
+			log.Warn("duplicate hours_before")
+			return nil, errors.New("invalid policy, duplicate hours_before")
+		}
+		// Cancelling later must never pay back more
+		if tiers[i].RefundPercent > tiers[i-1].RefundPercent {
+			log.Warn("refund_percent increases closer to check-in")
+			return nil, errors.New("invalid policy, refund_percent cannot increase closer to check-in")
+		}
+	}
+
+	result, err := vs.query.ReplaceCancellationPolicy(userId, venueId, tiers)
+	if err != nil {
+		if strings.Contains(err.Error(), "venue record not found") {
+			log.Error("venue record not found")
+			return nil, errors.New("venue record not found")
+		}
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// GetPricingRules implements venue.VenueService.
+func (vs *venueService) GetPricingRules(venueId string) ([]venue.PricingRuleCore, error) {
+	rules, err := vs.query.GetPricingRules(venueId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return rules, nil
+}
+
+// SetPricingRules implements venue.VenueService.
+func (vs *venueService) SetPricingRules(userId string, venueId string, rules []venue.PricingRuleCore) ([]venue.PricingRuleCore, error) {
+	for i, r := range rules {
+		var message string
+		switch {
+		case strings.TrimSpace(r.Name) == "":
+			message = "invalid name, cannot be empty"
+		case r.Price < 0:
+			message = "invalid price, cannot be negative"
+		case r.MinDuration < 0:
+			message = "invalid min_duration, cannot be negative"
+		case !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.EndDate.Before(r.StartDate):
+			message = "invalid date range, end_date is before start_date"
+		case (r.StartTime == "") != (r.EndTime == ""):
+			message = "invalid time band, expected both start_time and end_time"
+		}
+		for _, weekday := range r.Weekdays {
+			if weekday < int(time.Sunday) || weekday > int(time.Saturday) {
+				message = "invalid weekday, expected 0 (sunday) to 6 (saturday)"
+			}
+		}
+		if message != "" {
+			log.Warn(message)
+			return nil, errors.New(message)
+		}
+
+		if r.StartTime != "" {
+			band, err := schedule.NewShift(r.StartTime, r.EndTime)
+			if err != nil {
+				log.Warn("invalid time band format")
+				return nil, errors.New("invalid time band format, expected HH:MM")
+			}
+			rules[i].StartTime = schedule.FormatClock(band.Open)
+			rules[i].EndTime = schedule.FormatClock(band.Close)
+		}
+	}
+
+	result, err := vs.query.ReplacePricingRules(userId, venueId, rules)
+	if err != nil {
+		if strings.Contains(err.Error(), "venue record not found") {
+			log.Error("venue record not found")
+			return nil, errors.New("venue record not found")
+		}
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// GetClosures implements venue.VenueService.
+func (vs *venueService) GetClosures(venueId string) ([]venue.VenueClosureCore, error) {
+	closures, err := vs.query.GetClosures(venueId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return closures, nil
+}
+
+// CreateClosure implements venue.VenueService.
+func (vs *venueService) CreateClosure(userId string, request venue.VenueClosureCore) (venue.VenueClosureCore, error) {
+	switch {
+	case request.StartDate.IsZero() || request.EndDate.IsZero():
+		log.Warn("closure dates cannot be empty")
+		return venue.VenueClosureCore{}, errors.New("start_date and end_date cannot be empty")
+	case !request.EndDate.After(request.StartDate):
+		log.Warn("closure ends before it starts")
+		return venue.VenueClosureCore{}, errors.New("end_date must be after start_date")
+	case request.EndDate.Before(time.Now()):
+		log.Warn("closure is already over")
+		return venue.VenueClosureCore{}, errors.New("end_date cannot be in the past")
+	}
+
+	result, err := vs.query.InsertClosure(userId, request)
+	if err != nil {
+		if strings.Contains(err.Error(), "venue record not found") {
+			log.Error("venue record not found")
+			return venue.VenueClosureCore{}, errors.New("venue record not found")
+		}
+		log.Error("internal server error")
+		return venue.VenueClosureCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// DeleteClosure implements venue.VenueService.
+func (vs *venueService) DeleteClosure(userId string, venueId string, closureId string) error {
+	err := vs.query.DeleteClosure(userId, venueId, closureId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Error(err.Error())
+			return err
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
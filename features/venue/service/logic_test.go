@@ -7,6 +7,7 @@ import (
 
 	"github.com/playground-pro-project/playground-pro-api/features/venue"
 	"github.com/playground-pro-project/playground-pro-api/mocks"
+	"github.com/playground-pro-project/playground-pro-api/utils/geo"
 	"github.com/playground-pro-project/playground-pro-api/utils/pagination"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,6 +47,8 @@ func TestCreateVenue(t *testing.T) {
 		Price:       9.99,
 	}
 
+	category := venue.CategoryCore{CategoryID: "category_id_1", Name: "category_1"}
+
 	t.Run("category cannot be empty", func(t *testing.T) {
 		expectedErrorMessage := "category cannot be empty"
 		requestVenue.Category = ""
@@ -95,9 +98,21 @@ func TestCreateVenue(t *testing.T) {
 		data.AssertExpectations(t)
 	})
 
+	t.Run("invalid category", func(t *testing.T) {
+		requestVenue.Price = 9.99
+		data.On("GetCategory", "category_1").Return(venue.CategoryCore{}, errors.New("category not found")).Once()
+		_, err := service.CreateVenue(userID, requestVenue, requestVenuePictures)
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid category")
+		data.AssertExpectations(t)
+	})
+
 	t.Run("success create a venue", func(t *testing.T) {
 		requestVenue.Price = 9.99
-		data.On("InsertVenue", userID, requestVenue, requestVenuePictures).Return(expectedResult, nil).Once()
+		created := requestVenue
+		created.CategoryID = category.CategoryID
+		data.On("GetCategory", "category_1").Return(category, nil).Once()
+		data.On("InsertVenue", userID, created, requestVenuePictures).Return(expectedResult, nil).Once()
 		result, err := service.CreateVenue(userID, requestVenue, requestVenuePictures)
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
@@ -107,7 +122,10 @@ func TestCreateVenue(t *testing.T) {
 	t.Run("error insert data, duplicated", func(t *testing.T) {
 		expectedErrorMessage := "error insert data, duplicated"
 		requestVenue.Price = 9.99
-		data.On("InsertVenue", userID, requestVenue, requestVenuePictures).Return(venue.VenueCore{}, errors.New(expectedErrorMessage)).Once()
+		created := requestVenue
+		created.CategoryID = category.CategoryID
+		data.On("GetCategory", "category_1").Return(category, nil).Once()
+		data.On("InsertVenue", userID, created, requestVenuePictures).Return(venue.VenueCore{}, errors.New(expectedErrorMessage)).Once()
 		_, err := service.CreateVenue(userID, requestVenue, requestVenuePictures)
 		assert.NotNil(t, err)
 		assert.EqualError(t, err, expectedErrorMessage, "Error message mismatch")
@@ -117,20 +135,57 @@ func TestCreateVenue(t *testing.T) {
 	t.Run("internal server error", func(t *testing.T) {
 		expectedErrorMessage := "internal server error"
 		requestVenue.Price = 9.99
-		data.On("InsertVenue", userID, requestVenue, requestVenuePictures).Return(venue.VenueCore{}, errors.New(expectedErrorMessage)).Once()
+		created := requestVenue
+		created.CategoryID = category.CategoryID
+		data.On("GetCategory", "category_1").Return(category, nil).Once()
+		data.On("InsertVenue", userID, created, requestVenuePictures).Return(venue.VenueCore{}, errors.New(expectedErrorMessage)).Once()
 		_, err := service.CreateVenue(userID, requestVenue, requestVenuePictures)
 		assert.NotNil(t, err)
 		assert.EqualError(t, err, expectedErrorMessage, "Error message mismatch")
 		data.AssertExpectations(t)
 	})
+
+	t.Run("success create a venue with amenities", func(t *testing.T) {
+		withAmenities := requestVenue
+		withAmenities.Amenities = []venue.AmenityCore{{Code: "Showers"}, {Code: "parking"}, {Code: "showers"}}
+		amenities := []venue.AmenityCore{
+			{AmenityID: "amenity_id_1", Code: "parking", Name: "Parking", Kind: venue.AmenityFacility},
+			{AmenityID: "amenity_id_2", Code: "showers", Name: "Showers", Kind: venue.AmenityFacility},
+		}
+		created := withAmenities
+		created.CategoryID = category.CategoryID
+		created.Amenities = amenities
+		data.On("GetCategory", "category_1").Return(category, nil).Once()
+		data.On("GetAmenitiesByCode", []string{"parking", "showers"}).Return(amenities, nil).Once()
+		data.On("InsertVenue", userID, created, requestVenuePictures).Return(created, nil).Once()
+		result, err := service.CreateVenue(userID, withAmenities, requestVenuePictures)
+		assert.Nil(t, err)
+		assert.Equal(t, amenities, result.Amenities)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid amenity", func(t *testing.T) {
+		withAmenities := requestVenue
+		withAmenities.Amenities = []venue.AmenityCore{{Code: "parking"}, {Code: "sauna"}}
+		data.On("GetCategory", "category_1").Return(category, nil).Once()
+		data.On("GetAmenitiesByCode", []string{"parking", "sauna"}).
+			Return([]venue.AmenityCore{{AmenityID: "amenity_id_1", Code: "parking"}}, nil).Once()
+		_, err := service.CreateVenue(userID, withAmenities, requestVenuePictures)
+		assert.NotNil(t, err)
+		assert.EqualError(t, err, "invalid amenity, no such amenity sauna")
+		data.AssertExpectations(t)
+	})
 }
 
 func TestSearchVenues(t *testing.T) {
 	data := mocks.NewVenueData(t)
 	service := New(data)
-	keyword := "keyword"
-	latitude := 123.456
-	longitude := 789.012
+	search := venue.SearchCore{
+		Keyword:   "keyword",
+		Latitude:  -8.6870282,
+		Longitude: 115.201581,
+		Located:   true,
+	}
 	page := pagination.Pagination{
 		Sort:       "",
 		TotalRows:  10,
@@ -167,36 +222,118 @@ func TestSearchVenues(t *testing.T) {
 			},
 		}
 
-		data.On("SearchVenues", keyword, latitude, longitude, page).Return(mockVenues, int64(10), 1, nil).Once()
-		result, rows, pages, err := service.SearchVenues(keyword, latitude, longitude, page)
+		mockResult := venue.SearchResultCore{
+			Venues:     mockVenues,
+			TotalRows:  10,
+			TotalPages: 1,
+			Facets: venue.FacetsCore{
+				Categories:   []venue.FacetCore{{Value: "football", Count: 1}},
+				PriceBuckets: []venue.PriceBucketCore{{Min: 0, Max: 50000, Count: 1}},
+			},
+		}
+
+		data.On("SearchVenues", search, page).Return(mockResult, nil).Once()
+		result, err := service.SearchVenues(search, page)
 		assert.Nil(t, err)
-		assert.Equal(t, mockVenues, result)
-		assert.Equal(t, int64(10), rows)
-		assert.Equal(t, 1, pages)
+		assert.Equal(t, mockVenues, result.Venues)
+		assert.Equal(t, int64(10), result.TotalRows)
+		assert.Equal(t, 1, result.TotalPages)
+		assert.Equal(t, mockResult.Facets, result.Facets)
 		data.AssertExpectations(t)
 	})
 
 	t.Run("venues not found", func(t *testing.T) {
 		mockError := errors.New("venues not found")
-		data.On("SearchVenues", keyword, latitude, longitude, page).Return([]venue.VenueCoreRaw{}, int64(0), 0, mockError).Once()
-		result, rows, pages, err := service.SearchVenues(keyword, latitude, longitude, page)
+		data.On("SearchVenues", search, page).Return(venue.SearchResultCore{}, mockError).Once()
+		result, err := service.SearchVenues(search, page)
 		assert.NotNil(t, err)
 		assert.ErrorContains(t, err, "venues not found")
-		assert.Equal(t, []venue.VenueCoreRaw{}, result)
-		assert.Equal(t, int64(0), rows)
-		assert.Equal(t, 0, pages)
+		assert.Empty(t, result.Venues)
+		assert.Equal(t, int64(0), result.TotalRows)
+		assert.Equal(t, 0, result.TotalPages)
 		data.AssertExpectations(t)
 	})
 
 	t.Run("query error", func(t *testing.T) {
 		mockError := errors.New("internal server error")
-		data.On("SearchVenues", keyword, latitude, longitude, page).Return([]venue.VenueCoreRaw{}, int64(0), 0, mockError).Once()
-		result, rows, pages, err := service.SearchVenues(keyword, latitude, longitude, page)
+		data.On("SearchVenues", search, page).Return(venue.SearchResultCore{}, mockError).Once()
+		result, err := service.SearchVenues(search, page)
 		assert.NotNil(t, err)
 		assert.ErrorContains(t, err, "internal server error")
-		assert.Equal(t, []venue.VenueCoreRaw{}, result)
-		assert.Equal(t, int64(0), rows)
-		assert.Equal(t, 0, pages)
+		assert.Empty(t, result.Venues)
+		assert.Equal(t, int64(0), result.TotalRows)
+		assert.Equal(t, 0, result.TotalPages)
+		data.AssertExpectations(t)
+	})
+	t.Run("filters and sort", func(t *testing.T) {
+		filtered := search
+		filtered.Category = "futsal"
+		filtered.MinPrice = 50000
+		filtered.MaxPrice = 100000
+		filtered.MinRating = 4
+		filtered.RadiusKm = 10
+		filtered.OpenAt = time.Date(2023, time.August, 5, 19, 0, 0, 0, time.UTC)
+		filtered.SortBy = venue.SortByRelevance
+		filtered.Order = "ASC"
+
+		expected := filtered
+		expected.CategoryID = "category_id_2"
+		expected.Order = "asc"
+		data.On("GetCategory", "futsal").Return(venue.CategoryCore{CategoryID: "category_id_2", Name: "futsal"}, nil).Once()
+		data.On("SearchVenues", expected, page).Return(venue.SearchResultCore{TotalRows: 0}, nil).Once()
+		_, err := service.SearchVenues(filtered, page)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("amenities", func(t *testing.T) {
+		filtered := search
+		filtered.Amenities = []string{"Indoor", "lighting", "indoor"}
+		amenities := []venue.AmenityCore{
+			{AmenityID: "amenity_id_3", Code: "lighting", Kind: venue.AmenityFacility},
+			{AmenityID: "amenity_id_4", Code: "indoor", Kind: venue.AmenitySetting},
+		}
+
+		expected := filtered
+		expected.Amenities = []string{"indoor", "lighting"}
+		data.On("GetAmenitiesByCode", []string{"indoor", "lighting"}).Return(amenities, nil).Once()
+		data.On("SearchVenues", expected, page).Return(venue.SearchResultCore{TotalRows: 0}, nil).Once()
+		_, err := service.SearchVenues(filtered, page)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		data.On("GetCategory", "tennis").Return(venue.CategoryCore{}, errors.New("category not found")).Once()
+		data.On("GetAmenitiesByCode", []string{"sauna"}).Return([]venue.AmenityCore{}, nil).Once()
+		cases := []struct {
+			name    string
+			mutate  func(s *venue.SearchCore)
+			message string
+		}{
+			{"category", func(s *venue.SearchCore) { s.Category = "tennis" }, "invalid category"},
+			{"negative price", func(s *venue.SearchCore) { s.MinPrice = -1 }, "invalid price range"},
+			{"min above max", func(s *venue.SearchCore) { s.MinPrice = 200000; s.MaxPrice = 100000 }, "min_price is greater than max_price"},
+			{"rating", func(s *venue.SearchCore) { s.MinRating = 6 }, "invalid min_rating"},
+			{"radius", func(s *venue.SearchCore) { s.RadiusKm = -5 }, "invalid radius"},
+			{"coordinates", func(s *venue.SearchCore) { s.Longitude = 190 }, "invalid coordinates"},
+			{"radius without location", func(s *venue.SearchCore) { s.Located = false; s.RadiusKm = 5 }, "invalid location"},
+			{"distance sort without location", func(s *venue.SearchCore) { s.Located = false; s.SortBy = venue.SortByDistance }, "invalid location"},
+			{"sort", func(s *venue.SearchCore) { s.SortBy = "name" }, "invalid sort"},
+			{"order", func(s *venue.SearchCore) { s.Order = "up" }, "invalid order"},
+			{"amenity", func(s *venue.SearchCore) { s.Amenities = []string{"sauna"} }, "invalid amenity"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				invalid := search
+				tc.mutate(&invalid)
+				_, err := service.SearchVenues(invalid, page)
+				assert.NotNil(t, err)
+				assert.ErrorContains(t, err, tc.message)
+			})
+		}
 		data.AssertExpectations(t)
 	})
 }
@@ -285,6 +422,182 @@ func TestEditVenue(t *testing.T) {
 		assert.ErrorContains(t, err, "internal server error")
 		data.AssertExpectations(t)
 	})
+
+	t.Run("invalid coordinates", func(t *testing.T) {
+		invalid := requestVenue
+		invalid.Latitude = 95
+		err := service.EditVenue(userID, venueID, invalid)
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid coordinates")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("change category", func(t *testing.T) {
+		changed := requestVenue
+		changed.Category = "Futsal"
+		expected := changed
+		expected.CategoryID = "category_id_2"
+		data.On("GetCategory", "Futsal").Return(venue.CategoryCore{CategoryID: "category_id_2", Name: "futsal"}, nil).Once()
+		data.On("EditVenue", userID, venueID, expected).Return(nil).Once()
+		err := service.EditVenue(userID, venueID, changed)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid category", func(t *testing.T) {
+		changed := requestVenue
+		changed.Category = "tennis"
+		data.On("GetCategory", "tennis").Return(venue.CategoryCore{}, errors.New("category not found")).Once()
+		err := service.EditVenue(userID, venueID, changed)
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid category")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("replace amenities", func(t *testing.T) {
+		changed := requestVenue
+		changed.Amenities = []venue.AmenityCore{{Code: "outdoor"}}
+		amenities := []venue.AmenityCore{{AmenityID: "amenity_id_5", Code: "outdoor", Name: "Outdoor", Kind: venue.AmenitySetting}}
+		expected := changed
+		expected.Amenities = amenities
+		data.On("GetAmenitiesByCode", []string{"outdoor"}).Return(amenities, nil).Once()
+		data.On("EditVenue", userID, venueID, expected).Return(nil).Once()
+		err := service.EditVenue(userID, venueID, changed)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("clear amenities", func(t *testing.T) {
+		changed := requestVenue
+		changed.Amenities = []venue.AmenityCore{}
+		data.On("EditVenue", userID, venueID, changed).Return(nil).Once()
+		err := service.EditVenue(userID, venueID, changed)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("amenities query error", func(t *testing.T) {
+		changed := requestVenue
+		changed.Amenities = []venue.AmenityCore{{Code: "parking"}}
+		data.On("GetAmenitiesByCode", []string{"parking"}).Return(nil, errors.New("connection refused")).Once()
+		err := service.EditVenue(userID, venueID, changed)
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestGetAmenities(t *testing.T) {
+	data := mocks.NewVenueData(t)
+	service := New(data)
+
+	t.Run("success", func(t *testing.T) {
+		amenities := []venue.AmenityCore{
+			{AmenityID: "amenity_id_1", Code: "parking", Name: "Parking", Kind: venue.AmenityFacility},
+			{AmenityID: "amenity_id_4", Code: "indoor", Name: "Indoor", Kind: venue.AmenitySetting},
+		}
+		data.On("SelectAmenities").Return(amenities, nil).Once()
+		result, err := service.GetAmenities()
+		assert.Nil(t, err)
+		assert.Equal(t, amenities, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		data.On("SelectAmenities").Return(nil, errors.New("connection refused")).Once()
+		_, err := service.GetAmenities()
+		assert.EqualError(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestSuggestVenues(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		suggestions := []venue.SuggestionCore{
+			{VenueID: "venue_id_1", Name: "GOR Senayan"},
+		}
+		data.On("SuggestVenues", "gor sen", maxSuggestions).Return(suggestions, nil).Once()
+
+		result, err := service.SuggestVenues("  gor sen ")
+		assert.Nil(t, err)
+		assert.Equal(t, suggestions, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		_, err := service.SuggestVenues(" ")
+		assert.ErrorContains(t, err, "query cannot be empty")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("index error", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		data.On("SuggestVenues", "gor", maxSuggestions).Return(nil, errors.New("redis down")).Once()
+
+		_, err := service.SuggestVenues("gor")
+		assert.ErrorContains(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestVenueMap(t *testing.T) {
+	box := geo.Box{MinLat: -8.8, MinLng: 115.1, MaxLat: -8.6, MaxLng: 115.3}
+
+	t.Run("venues at high zoom", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		markers := []venue.MapMarkerCore{
+			{VenueID: "venue_id_1", Name: "Venue 1", Latitude: -8.7, Longitude: 115.2, Count: 1},
+		}
+		data.On("GetVenuesInBox", box, maxMapMarkers).Return(markers, nil).Once()
+
+		result, err := service.VenueMap(box, clusterMaxZoom)
+		assert.Nil(t, err)
+		assert.Equal(t, markers, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("clusters at low zoom", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		clusters := []venue.MapMarkerCore{
+			{Latitude: -8.7, Longitude: 115.2, Count: 12},
+		}
+		data.On("ClusterVenuesInBox", box, geo.CellSize(10, clusterCellsPerTile)).Return(clusters, nil).Once()
+
+		result, err := service.VenueMap(box, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, clusters, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid viewport", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		_, err := service.VenueMap(box, 23)
+		assert.ErrorContains(t, err, "invalid zoom")
+
+		_, err = service.VenueMap(geo.Box{MinLat: -8.6, MinLng: 115.1, MaxLat: -8.8, MaxLng: 115.3}, 12)
+		assert.ErrorContains(t, err, "invalid bbox")
+
+		_, err = service.VenueMap(geo.Box{MinLat: -91, MinLng: 115.1, MaxLat: -8.8, MaxLng: 115.3}, 12)
+		assert.ErrorContains(t, err, "invalid bbox")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		data := mocks.NewVenueData(t)
+		service := New(data)
+		data.On("GetVenuesInBox", box, maxMapMarkers).Return(nil, errors.New("database error")).Once()
+
+		_, err := service.VenueMap(box, 16)
+		assert.ErrorContains(t, err, "internal server error")
+		data.AssertExpectations(t)
+	})
 }
 
 func TestUnregisterVenue(t *testing.T) {
@@ -555,3 +868,225 @@ func TestMyVenues(t *testing.T) {
 	})
 
 }
+
+func TestSetOpeningHours(t *testing.T) {
+	data := mocks.NewVenueData(t)
+	service := New(data)
+	userID := "user_id_1"
+	venueID := "venue_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		hours := []venue.VenueHourCore{
+			{Weekday: 1, OpenTime: "6:00", CloseTime: "12.00"},
+			{Weekday: 1, OpenTime: "15:00", CloseTime: "02:00"},
+			{Weekday: 2, OpenTime: "08:00", CloseTime: "24:00"},
+		}
+		expected := []venue.VenueHourCore{
+			{Weekday: 1, OpenTime: "06:00", CloseTime: "12:00"},
+			{Weekday: 1, OpenTime: "15:00", CloseTime: "02:00"},
+			{Weekday: 2, OpenTime: "08:00", CloseTime: "00:00"},
+		}
+		data.On("ReplaceOpeningHours", userID, venueID, expected).Return(expected, nil).Once()
+
+		result, err := service.SetOpeningHours(userID, venueID, hours)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid weekday", func(t *testing.T) {
+		hours := []venue.VenueHourCore{{Weekday: 7, OpenTime: "08:00", CloseTime: "22:00"}}
+		_, err := service.SetOpeningHours(userID, venueID, hours)
+		assert.EqualError(t, err, "invalid weekday, expected 0 (sunday) to 6 (saturday)")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		hours := []venue.VenueHourCore{{Weekday: 1, OpenTime: "8am", CloseTime: "22:00"}}
+		_, err := service.SetOpeningHours(userID, venueID, hours)
+		assert.EqualError(t, err, "invalid opening hours format, expected HH:MM")
+	})
+
+	t.Run("overlapping overnight shift", func(t *testing.T) {
+		hours := []venue.VenueHourCore{
+			{Weekday: 6, OpenTime: "20:00", CloseTime: "03:00"},
+			{Weekday: 0, OpenTime: "02:00", CloseTime: "10:00"},
+		}
+		_, err := service.SetOpeningHours(userID, venueID, hours)
+		assert.EqualError(t, err, "opening hours overlap")
+	})
+
+	t.Run("venue record not found", func(t *testing.T) {
+		hours := []venue.VenueHourCore{{Weekday: 1, OpenTime: "08:00", CloseTime: "22:00"}}
+		data.On("ReplaceOpeningHours", userID, venueID, hours).Return(nil, errors.New("venue record not found")).Once()
+		_, err := service.SetOpeningHours(userID, venueID, hours)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestSetCancellationPolicy(t *testing.T) {
+	data := mocks.NewVenueData(t)
+	service := New(data)
+	userID := "user_id_1"
+	venueID := "venue_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		tiers := []venue.CancellationTierCore{
+			{HoursBefore: 6, RefundPercent: 50},
+			{HoursBefore: 24, RefundPercent: 100},
+			{HoursBefore: 0, RefundPercent: 0},
+		}
+		expected := []venue.CancellationTierCore{
+			{HoursBefore: 24, RefundPercent: 100},
+			{HoursBefore: 6, RefundPercent: 50},
+			{HoursBefore: 0, RefundPercent: 0},
+		}
+		data.On("ReplaceCancellationPolicy", userID, venueID, expected).Return(expected, nil).Once()
+
+		result, err := service.SetCancellationPolicy(userID, venueID, tiers)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid refund percent", func(t *testing.T) {
+		tiers := []venue.CancellationTierCore{{HoursBefore: 24, RefundPercent: 120}}
+		_, err := service.SetCancellationPolicy(userID, venueID, tiers)
+		assert.EqualError(t, err, "invalid refund_percent, expected 0 to 100")
+	})
+
+	t.Run("negative hours", func(t *testing.T) {
+		tiers := []venue.CancellationTierCore{{HoursBefore: -1, RefundPercent: 50}}
+		_, err := service.SetCancellationPolicy(userID, venueID, tiers)
+		assert.EqualError(t, err, "invalid hours_before, cannot be negative")
+	})
+
+	t.Run("duplicate hours", func(t *testing.T) {
+		tiers := []venue.CancellationTierCore{
+			{HoursBefore: 24, RefundPercent: 100},
+			{HoursBefore: 24, RefundPercent: 50},
+		}
+		_, err := service.SetCancellationPolicy(userID, venueID, tiers)
+		assert.EqualError(t, err, "invalid policy, duplicate hours_before")
+	})
+
+	t.Run("refund increases closer to check-in", func(t *testing.T) {
+		tiers := []venue.CancellationTierCore{
+			{HoursBefore: 24, RefundPercent: 50},
+			{HoursBefore: 6, RefundPercent: 100},
+		}
+		_, err := service.SetCancellationPolicy(userID, venueID, tiers)
+		assert.EqualError(t, err, "invalid policy, refund_percent cannot increase closer to check-in")
+	})
+
+	t.Run("venue record not found", func(t *testing.T) {
+		tiers := []venue.CancellationTierCore{{HoursBefore: 24, RefundPercent: 100}}
+		data.On("ReplaceCancellationPolicy", userID, venueID, tiers).Return(nil, errors.New("venue record not found")).Once()
+		_, err := service.SetCancellationPolicy(userID, venueID, tiers)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestSetPricingRules(t *testing.T) {
+	data := mocks.NewVenueData(t)
+	service := New(data)
+	userID := "user_id_1"
+	venueID := "venue_id_1"
+
+	t.Run("success", func(t *testing.T) {
+		rules := []venue.PricingRuleCore{
+			{Name: "weekday evening", Weekdays: []int{1, 2, 3, 4, 5}, StartTime: "18.00", EndTime: "24:00", Price: 150000},
+			{Name: "weekend", Weekdays: []int{0, 6}, Price: 200000, Priority: 1},
+		}
+		expected := []venue.PricingRuleCore{
+			{Name: "weekday evening", Weekdays: []int{1, 2, 3, 4, 5}, StartTime: "18:00", EndTime: "00:00", Price: 150000},
+			{Name: "weekend", Weekdays: []int{0, 6}, Price: 200000, Priority: 1},
+		}
+		data.On("ReplacePricingRules", userID, venueID, expected).Return(expected, nil).Once()
+
+		result, err := service.SetPricingRules(userID, venueID, rules)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("invalid weekday", func(t *testing.T) {
+		rules := []venue.PricingRuleCore{{Name: "weekend", Weekdays: []int{7}, Price: 200000}}
+		_, err := service.SetPricingRules(userID, venueID, rules)
+		assert.EqualError(t, err, "invalid weekday, expected 0 (sunday) to 6 (saturday)")
+	})
+
+	t.Run("incomplete time band", func(t *testing.T) {
+		rules := []venue.PricingRuleCore{{Name: "evening", StartTime: "18:00", Price: 150000}}
+		_, err := service.SetPricingRules(userID, venueID, rules)
+		assert.EqualError(t, err, "invalid time band, expected both start_time and end_time")
+	})
+
+	t.Run("invalid date range", func(t *testing.T) {
+		start := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+		rules := []venue.PricingRuleCore{{Name: "holiday", StartDate: start, EndDate: start.AddDate(0, 0, -1), Price: 250000}}
+		_, err := service.SetPricingRules(userID, venueID, rules)
+		assert.EqualError(t, err, "invalid date range, end_date is before start_date")
+	})
+
+	t.Run("negative price", func(t *testing.T) {
+		rules := []venue.PricingRuleCore{{Name: "weekend", Price: -1}}
+		_, err := service.SetPricingRules(userID, venueID, rules)
+		assert.EqualError(t, err, "invalid price, cannot be negative")
+	})
+
+	t.Run("venue record not found", func(t *testing.T) {
+		rules := []venue.PricingRuleCore{{Name: "weekend", Weekdays: []int{0, 6}, Price: 200000}}
+		data.On("ReplacePricingRules", userID, venueID, rules).Return(nil, errors.New("venue record not found")).Once()
+		_, err := service.SetPricingRules(userID, venueID, rules)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestCreateClosure(t *testing.T) {
+	data := mocks.NewVenueData(t)
+	service := New(data)
+	userID := "user_id_1"
+	start := time.Now().AddDate(0, 0, 7)
+	request := venue.VenueClosureCore{
+		VenueID:   "venue_id_1",
+		StartDate: start,
+		EndDate:   start.Add(24 * time.Hour),
+		Reason:    "maintenance",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		expected := request
+		expected.VenueClosureID = "CLS-1"
+		data.On("InsertClosure", userID, request).Return(expected, nil).Once()
+
+		result, err := service.CreateClosure(userID, request)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("end date before start date", func(t *testing.T) {
+		invalid := request
+		invalid.EndDate = start.Add(-time.Hour)
+		_, err := service.CreateClosure(userID, invalid)
+		assert.EqualError(t, err, "end_date must be after start_date")
+	})
+
+	t.Run("closure in the past", func(t *testing.T) {
+		invalid := request
+		invalid.StartDate = time.Now().AddDate(0, 0, -2)
+		invalid.EndDate = time.Now().AddDate(0, 0, -1)
+		_, err := service.CreateClosure(userID, invalid)
+		assert.EqualError(t, err, "end_date cannot be in the past")
+	})
+
+	t.Run("venue record not found", func(t *testing.T) {
+		data.On("InsertClosure", userID, request).Return(venue.VenueClosureCore{}, errors.New("venue record not found")).Once()
+		_, err := service.CreateClosure(userID, request)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+	"gorm.io/gorm"
+)
+
+type Voucher struct {
+	VoucherID     string         `gorm:"primaryKey;type:varchar(45)"`
+	Code          string         `gorm:"type:varchar(32);not null;uniqueIndex"`
+	CreatedBy     string         `gorm:"type:varchar(45);index"`
+	DiscountType  string         `gorm:"type:enum('percentage','fixed');default:'percentage'"`
+	DiscountValue float64        `gorm:"type:double"`
+	MaxDiscount   float64        `gorm:"type:double"`
+	MinSpend      float64        `gorm:"type:double"`
+	UsageLimit    int            `gorm:"type:int"`
+	PerUserLimit  int            `gorm:"type:int"`
+	ValidFrom     *time.Time     `gorm:"type:datetime"`
+	ValidUntil    *time.Time     `gorm:"type:datetime"`
+	VenueID       string         `gorm:"type:varchar(45);index"`
+	CategoryID    *string        `gorm:"type:varchar(45);index"`
+	CreatedAt     time.Time      `gorm:"type:datetime"`
+	UpdatedAt     time.Time      `gorm:"type:datetime"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	Category      *Category      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
+
+// Category is the sport a voucher can be scoped to, managed by admins.
+type Category struct {
+	CategoryID string `gorm:"primaryKey;type:varchar(45)"`
+	Name       string `gorm:"type:varchar(30);not null;uniqueIndex"`
+}
+
+// VoucherRedemption is one use of a voucher, recorded together with the payment it discounted
+type VoucherRedemption struct {
+	RedemptionID string    `gorm:"primaryKey;type:varchar(45)"`
+	VoucherID    string    `gorm:"type:varchar(45);index"`
+	UserID       string    `gorm:"type:varchar(45);index"`
+	PaymentID    string    `gorm:"type:varchar(45);index"`
+	Amount       float64   `gorm:"type:double"`
+	CreatedAt    time.Time `gorm:"type:datetime"`
+}
+
+// Struct helper to read a voucher together with its category name and how often it was
+// redeemed
+type VoucherUsage struct {
+	Voucher
+	CategoryName string
+	UsageCount   int
+}
+
+func voucherModels(v Voucher) voucher.VoucherCore {
+	result := voucher.VoucherCore{
+		VoucherID:     v.VoucherID,
+		Code:          v.Code,
+		CreatedBy:     v.CreatedBy,
+		DiscountType:  v.DiscountType,
+		DiscountValue: v.DiscountValue,
+		MaxDiscount:   v.MaxDiscount,
+		MinSpend:      v.MinSpend,
+		UsageLimit:    v.UsageLimit,
+		PerUserLimit:  v.PerUserLimit,
+		VenueID:       v.VenueID,
+		CreatedAt:     v.CreatedAt,
+	}
+	if v.CategoryID != nil {
+		result.CategoryID = *v.CategoryID
+	}
+	if v.Category != nil {
+		result.Category = v.Category.Name
+	}
+	if v.ValidFrom != nil {
+		result.ValidFrom = *v.ValidFrom
+	}
+	if v.ValidUntil != nil {
+		result.ValidUntil = *v.ValidUntil
+	}
+
+	return result
+}
+
+func voucherEntities(v voucher.VoucherCore) Voucher {
+	result := Voucher{
+		VoucherID:     v.VoucherID,
+		Code:          v.Code,
+		CreatedBy:     v.CreatedBy,
+		DiscountType:  v.DiscountType,
+		DiscountValue: v.DiscountValue,
+		MaxDiscount:   v.MaxDiscount,
+		MinSpend:      v.MinSpend,
+		UsageLimit:    v.UsageLimit,
+		PerUserLimit:  v.PerUserLimit,
+		VenueID:       v.VenueID,
+	}
+	if v.CategoryID != "" {
+		result.CategoryID = &v.CategoryID
+	}
+	if !v.ValidFrom.IsZero() {
+		result.ValidFrom = &v.ValidFrom
+	}
+	if !v.ValidUntil.IsZero() {
+		result.ValidUntil = &v.ValidUntil
+	}
+
+	return result
+}
+
+func modelToVoucherCore(usages []VoucherUsage) []voucher.VoucherCore {
+	result := make([]voucher.VoucherCore, len(usages))
+	for i, u := range usages {
+		result[i] = voucherModels(u.Voucher)
+		result[i].Category = u.CategoryName
+		result[i].UsageCount = u.UsageCount
+	}
+
+	return result
+}
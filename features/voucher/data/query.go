@@ -0,0 +1,142 @@
+package data
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+	"gorm.io/gorm"
+)
+
+var log = middlewares.Log()
+
+type voucherQuery struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) voucher.VoucherData {
+	return &voucherQuery{
+		db: db,
+	}
+}
+
+// GetUserRole implements voucher.VoucherData.
+func (vq *voucherQuery) GetUserRole(userId string) (string, error) {
+	var role string
+	query := vq.db.Table("users").
+		Select("role").
+		Where("user_id = ? AND deleted_at IS NULL", userId).
+		Scan(&role)
+	if query.Error != nil {
+		log.Sugar().Error("error executing user query:", query.Error)
+		return "", query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("user not found")
+		return "", errors.New("user not found")
+	}
+
+	return role, nil
+}
+
+// GetVenueOwner implements voucher.VoucherData.
+func (vq *voucherQuery) GetVenueOwner(venueId string) (string, error) {
+	var ownerId string
+	query := vq.db.Table("venues").
+		Select("owner_id").
+		Where("venue_id = ? AND deleted_at IS NULL", venueId).
+		Scan(&ownerId)
+	if query.Error != nil {
+		log.Sugar().Error("error executing venue query:", query.Error)
+		return "", query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("venue record not found")
+		return "", errors.New("venue record not found")
+	}
+
+	return ownerId, nil
+}
+
+// GetCategory implements voucher.VoucherData.
+func (vq *voucherQuery) GetCategory(category string) (voucher.CategoryCore, error) {
+	result := Category{}
+	query := vq.db.Table("categories").
+		Where("category_id = ? OR name = ?", category, strings.ToLower(category)).
+		Limit(1).
+		Find(&result)
+	if query.Error != nil {
+		log.Sugar().Error("error executing category query:", query.Error)
+		return voucher.CategoryCore{}, query.Error
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("category not found")
+		return voucher.CategoryCore{}, errors.New("category not found")
+	}
+
+	return voucher.CategoryCore{CategoryID: result.CategoryID, Name: result.Name}, nil
+}
+
+// InsertVoucher implements voucher.VoucherData.
+func (vq *voucherQuery) InsertVoucher(request voucher.VoucherCore) (voucher.VoucherCore, error) {
+	var count int64
+	query := vq.db.Unscoped().Model(&Voucher{}).Where("code = ?", request.Code).Count(&count)
+	if query.Error != nil {
+		log.Sugar().Error("error executing voucher query:", query.Error)
+		return voucher.VoucherCore{}, errors.New("internal server error while checking voucher code")
+	}
+	if count > 0 {
+		log.Warn("voucher code already exists")
+		return voucher.VoucherCore{}, errors.New("voucher code already exists")
+	}
+
+	request.VoucherID = helper.GenerateVoucherID()
+	req := voucherEntities(request)
+	query = vq.db.Create(&req)
+	if query.Error != nil {
+		log.Sugar().Error("error while creating voucher:", query.Error)
+		return voucher.VoucherCore{}, errors.New("internal server error while creating voucher")
+	}
+
+	log.Sugar().Infof("new voucher has been created: %s", req.VoucherID)
+	result := voucherModels(req)
+	result.Category = request.Category
+	return result, nil
+}
+
+// MyVouchers implements voucher.VoucherData.
+func (vq *voucherQuery) MyVouchers(userId string) ([]voucher.VoucherCore, error) {
+	usages := []VoucherUsage{}
+	query := vq.db.Table("vouchers").
+		Select("vouchers.*, categories.name AS category_name, COUNT(payments.payment_id) AS usage_count").
+		Joins("LEFT JOIN categories ON categories.category_id = vouchers.category_id").
+		Joins("LEFT JOIN voucher_redemptions ON voucher_redemptions.voucher_id = vouchers.voucher_id").
+		Joins("LEFT JOIN payments ON payments.payment_id = voucher_redemptions.payment_id AND payments.status NOT IN ?", []string{"cancel", "expire"}).
+		Where("vouchers.created_by = ? AND vouchers.deleted_at IS NULL", userId).
+		Group("vouchers.voucher_id, categories.name").
+		Order("vouchers.created_at DESC").
+		Find(&usages)
+	if query.Error != nil {
+		log.Sugar().Error("error executing vouchers query:", query.Error)
+		return nil, query.Error
+	}
+
+	return modelToVoucherCore(usages), nil
+}
+
+// DeleteVoucher implements voucher.VoucherData.
+func (vq *voucherQuery) DeleteVoucher(userId string, voucherId string) error {
+	query := vq.db.Where("voucher_id = ? AND created_by = ?", voucherId, userId).Delete(&Voucher{})
+	if query.Error != nil {
+		log.Sugar().Error("error while deleting voucher:", query.Error)
+		return errors.New("internal server error while deleting voucher")
+	}
+	if query.RowsAffected == 0 {
+		log.Warn("voucher not found")
+		return errors.New("voucher not found")
+	}
+
+	return nil
+}
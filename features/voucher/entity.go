@@ -0,0 +1,60 @@
+package voucher
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// VoucherCore is a promo code redeemable at checkout. Zero limits, dates and scopes leave
+// that restriction off.
+type VoucherCore struct {
+	VoucherID     string
+	Code          string
+	CreatedBy     string
+	DiscountType  string
+	DiscountValue float64
+	MaxDiscount   float64
+	MinSpend      float64
+	UsageLimit    int
+	PerUserLimit  int
+	ValidFrom     time.Time
+	ValidUntil    time.Time
+	VenueID       string
+	CategoryID    string
+	Category      string
+	UsageCount    int
+	CreatedAt     time.Time
+}
+
+// CategoryCore is the category a voucher can be scoped to.
+type CategoryCore struct {
+	CategoryID string
+	Name       string
+}
+
+type VoucherHandler interface {
+	CreateVoucher() echo.HandlerFunc
+	MyVouchers() echo.HandlerFunc
+	DeleteVoucher() echo.HandlerFunc
+}
+
+type VoucherService interface {
+	CreateVoucher(userId string, request VoucherCore) (VoucherCore, error)
+	MyVouchers(userId string) ([]VoucherCore, error)
+	DeleteVoucher(userId string, voucherId string) error
+}
+
+type VoucherData interface {
+	GetUserRole(userId string) (string, error)
+	GetVenueOwner(venueId string) (string, error)
+	GetCategory(category string) (CategoryCore, error)
+	InsertVoucher(request VoucherCore) (VoucherCore, error)
+	MyVouchers(userId string) ([]VoucherCore, error)
+	DeleteVoucher(userId string, voucherId string) error
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+var log = middlewares.Log()
+
+type voucherHandler struct {
+	service voucher.VoucherService
+}
+
+func New(vs voucher.VoucherService) voucher.VoucherHandler {
+	return &voucherHandler{
+		service: vs,
+	}
+}
+
+// CreateVoucher implements voucher.VoucherHandler.
+func (vh *voucherHandler) CreateVoucher() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := createVoucherRequest{}
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		errBind := c.Bind(&req)
+		if errBind != nil {
+			log.Error("error on bind request")
+			return helper.BadRequestError(c, "Bad request")
+		}
+
+		request, err := requestVoucher(req)
+		if err != nil {
+			log.Error(err.Error())
+			return helper.BadRequestError(c, "Bad request, "+err.Error())
+		}
+
+		result, err := vh.service.CreateVoucher(userId, request)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "only admins and venue owners"):
+				log.Error(err.Error())
+				return helper.UnauthorizedError(c, "Only admins and venue owners can create vouchers")
+			case strings.Contains(err.Error(), "not found"):
+				log.Error(err.Error())
+				return helper.NotFoundError(c, "The requested resource was not found")
+			case strings.Contains(err.Error(), "invalid"),
+				strings.Contains(err.Error(), "empty"),
+				strings.Contains(err.Error(), "already exists"):
+				log.Error("bad request, " + err.Error())
+				return helper.BadRequestError(c, "Bad request, "+err.Error())
+			default:
+				log.Error("internal server error")
+				return helper.InternalServerError(c, "Internal server error")
+			}
+		}
+
+		return c.JSON(http.StatusCreated, helper.ResponseFormat(http.StatusCreated, "Successfully created voucher", voucherResp(result), nil))
+	}
+}
+
+// MyVouchers implements voucher.VoucherHandler.
+func (vh *voucherHandler) MyVouchers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		vouchers, err := vh.service.MyVouchers(userId)
+		if err != nil {
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		result := make([]voucherResponse, len(vouchers))
+		for i, v := range vouchers {
+			result[i] = voucherResp(v)
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successful Operation", result, nil))
+	}
+}
+
+// DeleteVoucher implements voucher.VoucherHandler.
+func (vh *voucherHandler) DeleteVoucher() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userId, errToken := middlewares.ExtractToken(c)
+		if errToken != nil {
+			log.Error("missing or malformed JWT")
+			return helper.UnauthorizedError(c, "Missing or malformed JWT")
+		}
+
+		err := vh.service.DeleteVoucher(userId, c.Param("voucher_id"))
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				log.Error("voucher not found")
+				return helper.NotFoundError(c, "The requested resource was not found")
+			}
+			log.Error("internal server error")
+			return helper.InternalServerError(c, "Internal server error")
+		}
+
+		return c.JSON(http.StatusOK, helper.ResponseFormat(http.StatusOK, "Successfully deleted voucher", nil, nil))
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+)
+
+type createVoucherRequest struct {
+	Code          string  `json:"code" form:"code"`
+	DiscountType  string  `json:"discount_type" form:"discount_type"`
+	DiscountValue float64 `json:"discount_value" form:"discount_value"`
+	MaxDiscount   float64 `json:"max_discount" form:"max_discount"`
+	MinSpend      float64 `json:"min_spend" form:"min_spend"`
+	UsageLimit    int     `json:"usage_limit" form:"usage_limit"`
+	PerUserLimit  int     `json:"per_user_limit" form:"per_user_limit"`
+	ValidFrom     string  `json:"valid_from" form:"valid_from"`
+	ValidUntil    string  `json:"valid_until" form:"valid_until"`
+	VenueID       string  `json:"venue_id" form:"venue_id"`
+	Category      string  `json:"category" form:"category"`
+}
+
+func requestVoucher(r createVoucherRequest) (voucher.VoucherCore, error) {
+	result := voucher.VoucherCore{
+		Code:          r.Code,
+		DiscountType:  r.DiscountType,
+		DiscountValue: r.DiscountValue,
+		MaxDiscount:   r.MaxDiscount,
+		MinSpend:      r.MinSpend,
+		UsageLimit:    r.UsageLimit,
+		PerUserLimit:  r.PerUserLimit,
+		VenueID:       r.VenueID,
+		Category:      r.Category,
+	}
+
+	if r.ValidFrom != "" {
+		validFrom, err := time.Parse("2006-01-02 15:04:05", r.ValidFrom)
+		if err != nil {
+			return voucher.VoucherCore{}, fmt.Errorf("invalid valid_from")
+		}
+		result.ValidFrom = validFrom
+	}
+	if r.ValidUntil != "" {
+		validUntil, err := time.Parse("2006-01-02 15:04:05", r.ValidUntil)
+		if err != nil {
+			return voucher.VoucherCore{}, fmt.Errorf("invalid valid_until")
+		}
+		result.ValidUntil = validUntil
+	}
+
+	return result, nil
+}
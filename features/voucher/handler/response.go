@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+	"github.com/playground-pro-project/playground-pro-api/utils/helper"
+)
+
+type voucherResponse struct {
+	VoucherID     string           `json:"voucher_id"`
+	Code          string           `json:"code"`
+	DiscountType  string           `json:"discount_type"`
+	DiscountValue float64          `json:"discount_value"`
+	MaxDiscount   float64          `json:"max_discount,omitempty"`
+	MinSpend      float64          `json:"min_spend,omitempty"`
+	UsageLimit    int              `json:"usage_limit,omitempty"`
+	PerUserLimit  int              `json:"per_user_limit,omitempty"`
+	UsageCount    int              `json:"usage_count"`
+	ValidFrom     helper.LocalTime `json:"valid_from"`
+	ValidUntil    helper.LocalTime `json:"valid_until"`
+	VenueID       string           `json:"venue_id,omitempty"`
+	CategoryID    string           `json:"category_id,omitempty"`
+	Category      string           `json:"category,omitempty"`
+}
+
+func voucherResp(v voucher.VoucherCore) voucherResponse {
+	return voucherResponse{
+		VoucherID:     v.VoucherID,
+		Code:          v.Code,
+		DiscountType:  v.DiscountType,
+		DiscountValue: v.DiscountValue,
+		MaxDiscount:   v.MaxDiscount,
+		MinSpend:      v.MinSpend,
+		UsageLimit:    v.UsageLimit,
+		PerUserLimit:  v.PerUserLimit,
+		UsageCount:    v.UsageCount,
+		ValidFrom:     helper.LocalTime(v.ValidFrom),
+		ValidUntil:    helper.LocalTime(v.ValidUntil),
+		VenueID:       v.VenueID,
+		CategoryID:    v.CategoryID,
+		Category:      v.Category,
+	}
+}
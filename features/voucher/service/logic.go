@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+)
+
+var log = middlewares.Log()
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type voucherService struct {
+	query voucher.VoucherData
+}
+
+func New(vd voucher.VoucherData) voucher.VoucherService {
+	return &voucherService{
+		query: vd,
+	}
+}
+
+// CreateVoucher implements voucher.VoucherService.
+func (vs *voucherService) CreateVoucher(userId string, request voucher.VoucherCore) (voucher.VoucherCore, error) {
+	request.Code = strings.ToUpper(strings.TrimSpace(request.Code))
+	request.Category = strings.TrimSpace(request.Category)
+
+	// TODO 1 : Validate the voucher terms
+	var message string
+	switch {
+	case request.Code == "":
+		message = "code cannot be empty"
+	case !codePattern.MatchString(request.Code):
+		message = "invalid code, expected 3 to 32 letters, digits, dashes or underscores"
+	case request.DiscountType != voucher.DiscountPercentage && request.DiscountType != voucher.DiscountFixed:
+		message = "invalid discount_type, expected percentage or fixed"
+	case request.DiscountValue <= 0:
+		message = "invalid discount_value, must be greater than 0"
+	case request.DiscountType == voucher.DiscountPercentage && request.DiscountValue > 100:
+		message = "invalid discount_value, percentage cannot exceed 100"
+	case request.MaxDiscount < 0 || request.MinSpend < 0:
+		message = "invalid max_discount or min_spend, cannot be negative"
+	case request.UsageLimit < 0 || request.PerUserLimit < 0:
+		message = "invalid usage_limit or per_user_limit, cannot be negative"
+	case !request.ValidFrom.IsZero() && !request.ValidUntil.IsZero() && !request.ValidUntil.After(request.ValidFrom):
+		message = "invalid validity window, valid_until must be after valid_from"
+	}
+	if message != "" {
+		log.Warn(message)
+		return voucher.VoucherCore{}, errors.New(message)
+	}
+
+	// TODO 2 : Admins may scope freely, owners only to their own venues
+	role, err := vs.query.GetUserRole(userId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return voucher.VoucherCore{}, errors.New("user not found")
+		}
+		log.Error("internal server error")
+		return voucher.VoucherCore{}, errors.New("internal server error")
+	}
+
+	switch role {
+	case "admin":
+		if request.VenueID != "" {
+			if _, err := vs.query.GetVenueOwner(request.VenueID); err != nil {
+				return voucher.VoucherCore{}, venueError(err)
+			}
+		}
+	case "owner":
+		if request.VenueID == "" {
+			log.Warn("owner voucher without venue_id")
+			return voucher.VoucherCore{}, errors.New("venue_id cannot be empty for owner vouchers")
+		}
+		ownerId, err := vs.query.GetVenueOwner(request.VenueID)
+		if err != nil {
+			return voucher.VoucherCore{}, venueError(err)
+		}
+		if ownerId != userId {
+			log.Warn("owner voucher for someone else's venue")
+			return voucher.VoucherCore{}, errors.New("venue record not found")
+		}
+	default:
+		log.Warn("user is not allowed to create vouchers")
+		return voucher.VoucherCore{}, errors.New("only admins and venue owners can create vouchers")
+	}
+
+	// TODO 3 : A category scope must name a category venues are listed under
+	if request.Category != "" {
+		category, err := vs.query.GetCategory(request.Category)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				log.Warn("invalid category")
+				return voucher.VoucherCore{}, errors.New("invalid category, no such category")
+			}
+			log.Error("internal server error")
+			return voucher.VoucherCore{}, errors.New("internal server error")
+		}
+		request.CategoryID, request.Category = category.CategoryID, category.Name
+	}
+
+	// TODO 4 : Save the voucher
+	request.CreatedBy = userId
+	result, err := vs.query.InsertVoucher(request)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return voucher.VoucherCore{}, errors.New("voucher code already exists")
+		}
+		log.Error("internal server error")
+		return voucher.VoucherCore{}, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// MyVouchers implements voucher.VoucherService.
+func (vs *voucherService) MyVouchers(userId string) ([]voucher.VoucherCore, error) {
+	vouchers, err := vs.query.MyVouchers(userId)
+	if err != nil {
+		log.Error("internal server error")
+		return nil, errors.New("internal server error")
+	}
+
+	return vouchers, nil
+}
+
+// DeleteVoucher implements voucher.VoucherService.
+func (vs *voucherService) DeleteVoucher(userId string, voucherId string) error {
+	err := vs.query.DeleteVoucher(userId, voucherId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("voucher not found")
+		}
+		log.Error("internal server error")
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+func venueError(err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return errors.New("venue record not found")
+	}
+	log.Error("internal server error")
+	return errors.New("internal server error")
+}
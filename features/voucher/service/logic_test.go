@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/features/voucher"
+	"github.com/playground-pro-project/playground-pro-api/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateVoucher(t *testing.T) {
+	data := mocks.NewVoucherData(t)
+	service := New(data)
+	adminId := "admin_id_1"
+	ownerId := "owner_id_1"
+	request := voucher.VoucherCore{
+		Code:          " weekend25 ",
+		DiscountType:  voucher.DiscountPercentage,
+		DiscountValue: 25,
+		MaxDiscount:   50000,
+		UsageLimit:    100,
+		PerUserLimit:  1,
+		ValidFrom:     time.Now(),
+		ValidUntil:    time.Now().AddDate(0, 1, 0),
+		Category:      "Futsal",
+	}
+	futsal := voucher.CategoryCore{CategoryID: "CTG-1", Name: "futsal"}
+
+	t.Run("success - admin voucher", func(t *testing.T) {
+		expected := request
+		expected.Code = "WEEKEND25"
+		expected.CategoryID = "CTG-1"
+		expected.Category = "futsal"
+		expected.CreatedBy = adminId
+		created := expected
+		created.VoucherID = "VCR-1"
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("GetCategory", "Futsal").Return(futsal, nil).Once()
+		data.On("InsertVoucher", expected).Return(created, nil).Once()
+
+		result, err := service.CreateVoucher(adminId, request)
+		assert.Nil(t, err)
+		assert.Equal(t, created, result)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("success - owner voucher for own venue", func(t *testing.T) {
+		ownerRequest := voucher.VoucherCore{Code: "HEMAT10", DiscountType: voucher.DiscountFixed, DiscountValue: 10000, VenueID: "venue_id_1"}
+		data.On("GetUserRole", ownerId).Return("owner", nil).Once()
+		data.On("GetVenueOwner", "venue_id_1").Return(ownerId, nil).Once()
+		expected := ownerRequest
+		expected.CreatedBy = ownerId
+		data.On("InsertVoucher", expected).Return(expected, nil).Once()
+
+		_, err := service.CreateVoucher(ownerId, ownerRequest)
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - owner voucher for another venue", func(t *testing.T) {
+		ownerRequest := voucher.VoucherCore{Code: "HEMAT10", DiscountType: voucher.DiscountFixed, DiscountValue: 10000, VenueID: "venue_id_2"}
+		data.On("GetUserRole", ownerId).Return("owner", nil).Once()
+		data.On("GetVenueOwner", "venue_id_2").Return("owner_id_2", nil).Once()
+
+		_, err := service.CreateVoucher(ownerId, ownerRequest)
+		assert.EqualError(t, err, "venue record not found")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - owner voucher without venue", func(t *testing.T) {
+		ownerRequest := voucher.VoucherCore{Code: "HEMAT10", DiscountType: voucher.DiscountFixed, DiscountValue: 10000}
+		data.On("GetUserRole", ownerId).Return("owner", nil).Once()
+
+		_, err := service.CreateVoucher(ownerId, ownerRequest)
+		assert.EqualError(t, err, "venue_id cannot be empty for owner vouchers")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - regular user", func(t *testing.T) {
+		data.On("GetUserRole", "user_id_1").Return("user", nil).Once()
+
+		_, err := service.CreateVoucher("user_id_1", request)
+		assert.EqualError(t, err, "only admins and venue owners can create vouchers")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - percentage above 100", func(t *testing.T) {
+		invalid := request
+		invalid.DiscountValue = 120
+		_, err := service.CreateVoucher(adminId, invalid)
+		assert.EqualError(t, err, "invalid discount_value, percentage cannot exceed 100")
+	})
+
+	t.Run("error - invalid validity window", func(t *testing.T) {
+		invalid := request
+		invalid.ValidUntil = invalid.ValidFrom.Add(-time.Hour)
+		_, err := service.CreateVoucher(adminId, invalid)
+		assert.EqualError(t, err, "invalid validity window, valid_until must be after valid_from")
+	})
+
+	t.Run("error - invalid discount type", func(t *testing.T) {
+		invalid := request
+		invalid.DiscountType = "bogo"
+		_, err := service.CreateVoucher(adminId, invalid)
+		assert.EqualError(t, err, "invalid discount_type, expected percentage or fixed")
+	})
+
+	t.Run("error - unknown category", func(t *testing.T) {
+		invalid := request
+		invalid.Category = "tennis"
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("GetCategory", "tennis").Return(voucher.CategoryCore{}, errors.New("category not found")).Once()
+
+		_, err := service.CreateVoucher(adminId, invalid)
+		assert.EqualError(t, err, "invalid category, no such category")
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - duplicate code", func(t *testing.T) {
+		data.On("GetUserRole", adminId).Return("admin", nil).Once()
+		data.On("GetCategory", "Futsal").Return(futsal, nil).Once()
+		data.On("InsertVoucher", mock.Anything).Return(voucher.VoucherCore{}, errors.New("voucher code already exists")).Once()
+
+		_, err := service.CreateVoucher(adminId, request)
+		assert.EqualError(t, err, "voucher code already exists")
+		data.AssertExpectations(t)
+	})
+}
+
+func TestDeleteVoucher(t *testing.T) {
+	data := mocks.NewVoucherData(t)
+	service := New(data)
+
+	t.Run("success", func(t *testing.T) {
+		data.On("DeleteVoucher", "admin_id_1", "VCR-1").Return(nil).Once()
+		err := service.DeleteVoucher("admin_id_1", "VCR-1")
+		assert.Nil(t, err)
+		data.AssertExpectations(t)
+	})
+
+	t.Run("error - voucher not found", func(t *testing.T) {
+		data.On("DeleteVoucher", "admin_id_1", "VCR-2").Return(errors.New("voucher not found")).Once()
+		err := service.DeleteVoucher("admin_id_1", "VCR-2")
+		assert.EqualError(t, err, "voucher not found")
+		data.AssertExpectations(t)
+	})
+}
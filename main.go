@@ -5,6 +5,7 @@ import (
 	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/app/database"
 	"github.com/playground-pro-project/playground-pro-api/app/router"
+	"github.com/playground-pro-project/playground-pro-api/app/scheduler"
 )
 
 func main() {
@@ -12,5 +13,8 @@ func main() {
 	cfg := config.InitConfig()
 	db := database.InitDatabase(cfg)
 	router.InitRouter(db, e)
+	jobs := scheduler.InitScheduler(db)
+	jobs.Start()
+	defer jobs.Stop()
 	e.Logger.Fatal(e.Start(":8080"))
 }
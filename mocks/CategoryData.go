@@ -0,0 +1,139 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	category "github.com/playground-pro-project/playground-pro-api/features/category"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CategoryData is an autogenerated mock type for the CategoryData type
+type CategoryData struct {
+	mock.Mock
+}
+
+// DeleteCategory provides a mock function with given fields: categoryId
+func (_m *CategoryData) DeleteCategory(categoryId string) error {
+	ret := _m.Called(categoryId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(categoryId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserRole provides a mock function with given fields: userId
+func (_m *CategoryData) GetUserRole(userId string) (string, error) {
+	ret := _m.Called(userId)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertCategory provides a mock function with given fields: request
+func (_m *CategoryData) InsertCategory(request category.CategoryCore) (category.CategoryCore, error) {
+	ret := _m.Called(request)
+
+	var r0 category.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(category.CategoryCore) (category.CategoryCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(category.CategoryCore) category.CategoryCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(category.CategoryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(category.CategoryCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectCategories provides a mock function with given fields:
+func (_m *CategoryData) SelectCategories() ([]category.CategoryCore, error) {
+	ret := _m.Called()
+
+	var r0 []category.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]category.CategoryCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []category.CategoryCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]category.CategoryCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCategory provides a mock function with given fields: categoryId, request
+func (_m *CategoryData) UpdateCategory(categoryId string, request category.CategoryCore) (category.CategoryCore, error) {
+	ret := _m.Called(categoryId, request)
+
+	var r0 category.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, category.CategoryCore) (category.CategoryCore, error)); ok {
+		return rf(categoryId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, category.CategoryCore) category.CategoryCore); ok {
+		r0 = rf(categoryId, request)
+	} else {
+		r0 = ret.Get(0).(category.CategoryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, category.CategoryCore) error); ok {
+		r1 = rf(categoryId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCategoryData creates a new instance of CategoryData. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCategoryData(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CategoryData {
+	mock := &CategoryData{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
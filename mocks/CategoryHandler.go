@@ -0,0 +1,91 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CategoryHandler is an autogenerated mock type for the CategoryHandler type
+type CategoryHandler struct {
+	mock.Mock
+}
+
+// CreateCategory provides a mock function with given fields:
+func (_m *CategoryHandler) CreateCategory() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// DeleteCategory provides a mock function with given fields:
+func (_m *CategoryHandler) DeleteCategory() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// GetCategories provides a mock function with given fields:
+func (_m *CategoryHandler) GetCategories() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields:
+func (_m *CategoryHandler) UpdateCategory() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// NewCategoryHandler creates a new instance of CategoryHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCategoryHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CategoryHandler {
+	mock := &CategoryHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
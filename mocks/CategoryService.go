@@ -0,0 +1,115 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	category "github.com/playground-pro-project/playground-pro-api/features/category"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CategoryService is an autogenerated mock type for the CategoryService type
+type CategoryService struct {
+	mock.Mock
+}
+
+// CreateCategory provides a mock function with given fields: userId, request
+func (_m *CategoryService) CreateCategory(userId string, request category.CategoryCore) (category.CategoryCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 category.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, category.CategoryCore) (category.CategoryCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, category.CategoryCore) category.CategoryCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(category.CategoryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, category.CategoryCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCategory provides a mock function with given fields: userId, categoryId
+func (_m *CategoryService) DeleteCategory(userId string, categoryId string) error {
+	ret := _m.Called(userId, categoryId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, categoryId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCategories provides a mock function with given fields:
+func (_m *CategoryService) GetCategories() ([]category.CategoryCore, error) {
+	ret := _m.Called()
+
+	var r0 []category.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]category.CategoryCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []category.CategoryCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]category.CategoryCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCategory provides a mock function with given fields: userId, categoryId, request
+func (_m *CategoryService) UpdateCategory(userId string, categoryId string, request category.CategoryCore) (category.CategoryCore, error) {
+	ret := _m.Called(userId, categoryId, request)
+
+	var r0 category.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, category.CategoryCore) (category.CategoryCore, error)); ok {
+		return rf(userId, categoryId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, category.CategoryCore) category.CategoryCore); ok {
+		r0 = rf(userId, categoryId, request)
+	} else {
+		r0 = ret.Get(0).(category.CategoryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, category.CategoryCore) error); ok {
+		r1 = rf(userId, categoryId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCategoryService creates a new instance of CategoryService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCategoryService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CategoryService {
+	mock := &CategoryService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
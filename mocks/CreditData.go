@@ -0,0 +1,215 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	credit "github.com/playground-pro-project/playground-pro-api/features/credit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CreditData is an autogenerated mock type for the CreditData type
+type CreditData struct {
+	mock.Mock
+}
+
+// DeletePackage provides a mock function with given fields: venueId, packageId
+func (_m *CreditData) DeletePackage(venueId string, packageId string) error {
+	ret := _m.Called(venueId, packageId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(venueId, packageId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPackage provides a mock function with given fields: packageId
+func (_m *CreditData) GetPackage(packageId string) (credit.PackageCore, error) {
+	ret := _m.Called(packageId)
+
+	var r0 credit.PackageCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (credit.PackageCore, error)); ok {
+		return rf(packageId)
+	}
+	if rf, ok := ret.Get(0).(func(string) credit.PackageCore); ok {
+		r0 = rf(packageId)
+	} else {
+		r0 = ret.Get(0).(credit.PackageCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(packageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPackages provides a mock function with given fields: venueId
+func (_m *CreditData) GetPackages(venueId string) ([]credit.PackageCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []credit.PackageCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]credit.PackageCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []credit.PackageCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]credit.PackageCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVenueOwner provides a mock function with given fields: venueId
+func (_m *CreditData) GetVenueOwner(venueId string) (string, error) {
+	ret := _m.Called(venueId)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(venueId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPackage provides a mock function with given fields: request
+func (_m *CreditData) InsertPackage(request credit.PackageCore) (credit.PackageCore, error) {
+	ret := _m.Called(request)
+
+	var r0 credit.PackageCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(credit.PackageCore) (credit.PackageCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(credit.PackageCore) credit.PackageCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(credit.PackageCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(credit.PackageCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPurchase provides a mock function with given fields: request
+func (_m *CreditData) InsertPurchase(request credit.PurchaseCore) (credit.PurchaseCore, error) {
+	ret := _m.Called(request)
+
+	var r0 credit.PurchaseCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(credit.PurchaseCore) (credit.PurchaseCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(credit.PurchaseCore) credit.PurchaseCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(credit.PurchaseCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(credit.PurchaseCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyCreditHistory provides a mock function with given fields: userId
+func (_m *CreditData) MyCreditHistory(userId string) ([]credit.EntryCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []credit.EntryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]credit.EntryCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []credit.EntryCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]credit.EntryCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyCredits provides a mock function with given fields: userId
+func (_m *CreditData) MyCredits(userId string) ([]credit.BalanceCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []credit.BalanceCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]credit.BalanceCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []credit.BalanceCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]credit.BalanceCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCreditData creates a new instance of CreditData. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCreditData(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CreditData {
+	mock := &CreditData{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,167 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	credit "github.com/playground-pro-project/playground-pro-api/features/credit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CreditService is an autogenerated mock type for the CreditService type
+type CreditService struct {
+	mock.Mock
+}
+
+// BuyPackage provides a mock function with given fields: userId, packageId, paymentType
+func (_m *CreditService) BuyPackage(userId string, packageId string, paymentType string) (credit.PurchaseCore, error) {
+	ret := _m.Called(userId, packageId, paymentType)
+
+	var r0 credit.PurchaseCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (credit.PurchaseCore, error)); ok {
+		return rf(userId, packageId, paymentType)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) credit.PurchaseCore); ok {
+		r0 = rf(userId, packageId, paymentType)
+	} else {
+		r0 = ret.Get(0).(credit.PurchaseCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userId, packageId, paymentType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePackage provides a mock function with given fields: userId, request
+func (_m *CreditService) CreatePackage(userId string, request credit.PackageCore) (credit.PackageCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 credit.PackageCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, credit.PackageCore) (credit.PackageCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, credit.PackageCore) credit.PackageCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(credit.PackageCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, credit.PackageCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePackage provides a mock function with given fields: userId, venueId, packageId
+func (_m *CreditService) DeletePackage(userId string, venueId string, packageId string) error {
+	ret := _m.Called(userId, venueId, packageId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userId, venueId, packageId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPackages provides a mock function with given fields: venueId
+func (_m *CreditService) GetPackages(venueId string) ([]credit.PackageCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []credit.PackageCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]credit.PackageCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []credit.PackageCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]credit.PackageCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyCreditHistory provides a mock function with given fields: userId
+func (_m *CreditService) MyCreditHistory(userId string) ([]credit.EntryCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []credit.EntryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]credit.EntryCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []credit.EntryCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]credit.EntryCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyCredits provides a mock function with given fields: userId
+func (_m *CreditService) MyCredits(userId string) ([]credit.BalanceCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []credit.BalanceCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]credit.BalanceCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []credit.BalanceCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]credit.BalanceCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCreditService creates a new instance of CreditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCreditService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CreditService {
+	mock := &CreditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
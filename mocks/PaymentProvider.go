@@ -0,0 +1,128 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation"
+)
+
+// PaymentProvider is an autogenerated mock type for the PaymentProvider type
+type PaymentProvider struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: orderID
+func (_m *PaymentProvider) Cancel(orderID string) error {
+	ret := _m.Called(orderID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(orderID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Charge provides a mock function with given fields: orderID, request
+func (_m *PaymentProvider) Charge(orderID string, request reservation.PaymentCore) (reservation.PaymentCore, error) {
+	ret := _m.Called(orderID, request)
+
+	var r0 reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, reservation.PaymentCore) (reservation.PaymentCore, error)); ok {
+		return rf(orderID, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, reservation.PaymentCore) reservation.PaymentCore); ok {
+		r0 = rf(orderID, request)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, reservation.PaymentCore) error); ok {
+		r1 = rf(orderID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseWebhook provides a mock function with given fields: body
+func (_m *PaymentProvider) ParseWebhook(body []byte) (reservation.PaymentCore, error) {
+	ret := _m.Called(body)
+
+	var r0 reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]byte) (reservation.PaymentCore, error)); ok {
+		return rf(body)
+	}
+	if rf, ok := ret.Get(0).(func([]byte) reservation.PaymentCore); ok {
+		r0 = rf(body)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefundTransaction provides a mock function with given fields: invoice, amount, reason
+func (_m *PaymentProvider) RefundTransaction(invoice string, amount int64, reason string) error {
+	ret := _m.Called(invoice, amount, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64, string) error); ok {
+		r0 = rf(invoice, amount, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Status provides a mock function with given fields: orderID
+func (_m *PaymentProvider) Status(orderID string) (reservation.PaymentCore, error) {
+	ret := _m.Called(orderID)
+
+	var r0 reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (reservation.PaymentCore, error)); ok {
+		return rf(orderID)
+	}
+	if rf, ok := ret.Get(0).(func(string) reservation.PaymentCore); ok {
+		r0 = rf(orderID)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPaymentProvider creates a new instance of PaymentProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentProvider {
+	mock := &PaymentProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,249 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	payout "github.com/playground-pro-project/playground-pro-api/features/payout"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayoutData is an autogenerated mock type for the PayoutData type
+type PayoutData struct {
+	mock.Mock
+}
+
+// CreateBatch provides a mock function with given fields:
+func (_m *PayoutData) CreateBatch() (payout.BatchCore, error) {
+	ret := _m.Called()
+
+	var r0 payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (payout.BatchCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() payout.BatchCore); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(payout.BatchCore)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: ownerId
+func (_m *PayoutData) GetAccount(ownerId string) (payout.AccountCore, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 payout.AccountCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (payout.AccountCore, error)); ok {
+		return rf(ownerId)
+	}
+	if rf, ok := ret.Get(0).(func(string) payout.AccountCore); ok {
+		r0 = rf(ownerId)
+	} else {
+		r0 = ret.Get(0).(payout.AccountCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatch provides a mock function with given fields: batchId
+func (_m *PayoutData) GetBatch(batchId string) (payout.BatchCore, error) {
+	ret := _m.Called(batchId)
+
+	var r0 payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (payout.BatchCore, error)); ok {
+		return rf(batchId)
+	}
+	if rf, ok := ret.Get(0).(func(string) payout.BatchCore); ok {
+		r0 = rf(batchId)
+	} else {
+		r0 = ret.Get(0).(payout.BatchCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(batchId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatches provides a mock function with given fields:
+func (_m *PayoutData) GetBatches() ([]payout.BatchCore, error) {
+	ret := _m.Called()
+
+	var r0 []payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]payout.BatchCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []payout.BatchCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payout.BatchCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOwnerEntries provides a mock function with given fields: ownerId
+func (_m *PayoutData) GetOwnerEntries(ownerId string) ([]payout.EntryCore, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 []payout.EntryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]payout.EntryCore, error)); ok {
+		return rf(ownerId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []payout.EntryCore); ok {
+		r0 = rf(ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payout.EntryCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOwnerPayouts provides a mock function with given fields: ownerId
+func (_m *PayoutData) GetOwnerPayouts(ownerId string) ([]payout.LineCore, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 []payout.LineCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]payout.LineCore, error)); ok {
+		return rf(ownerId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []payout.LineCore); ok {
+		r0 = rf(ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payout.LineCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRole provides a mock function with given fields: userId
+func (_m *PayoutData) GetUserRole(userId string) (string, error) {
+	ret := _m.Called(userId)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkBatchPaid provides a mock function with given fields: batchId, paidBy, reference
+func (_m *PayoutData) MarkBatchPaid(batchId string, paidBy string, reference string) (payout.BatchCore, error) {
+	ret := _m.Called(batchId, paidBy, reference)
+
+	var r0 payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (payout.BatchCore, error)); ok {
+		return rf(batchId, paidBy, reference)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) payout.BatchCore); ok {
+		r0 = rf(batchId, paidBy, reference)
+	} else {
+		r0 = ret.Get(0).(payout.BatchCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(batchId, paidBy, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveAccount provides a mock function with given fields: request
+func (_m *PayoutData) SaveAccount(request payout.AccountCore) (payout.AccountCore, error) {
+	ret := _m.Called(request)
+
+	var r0 payout.AccountCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(payout.AccountCore) (payout.AccountCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(payout.AccountCore) payout.AccountCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(payout.AccountCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(payout.AccountCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayoutData creates a new instance of PayoutData. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayoutData(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayoutData {
+	mock := &PayoutData{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,175 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	payout "github.com/playground-pro-project/playground-pro-api/features/payout"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayoutService is an autogenerated mock type for the PayoutService type
+type PayoutService struct {
+	mock.Mock
+}
+
+// CreateBatch provides a mock function with given fields: userId
+func (_m *PayoutService) CreateBatch(userId string) (payout.BatchCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (payout.BatchCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) payout.BatchCore); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(payout.BatchCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportBatch provides a mock function with given fields: userId, batchId
+func (_m *PayoutService) ExportBatch(userId string, batchId string) ([]byte, error) {
+	ret := _m.Called(userId, batchId)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]byte, error)); ok {
+		return rf(userId, batchId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []byte); ok {
+		r0 = rf(userId, batchId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, batchId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatches provides a mock function with given fields: userId
+func (_m *PayoutService) GetBatches(userId string) ([]payout.BatchCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]payout.BatchCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []payout.BatchCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payout.BatchCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkBatchPaid provides a mock function with given fields: userId, batchId, reference
+func (_m *PayoutService) MarkBatchPaid(userId string, batchId string, reference string) (payout.BatchCore, error) {
+	ret := _m.Called(userId, batchId, reference)
+
+	var r0 payout.BatchCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (payout.BatchCore, error)); ok {
+		return rf(userId, batchId, reference)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) payout.BatchCore); ok {
+		r0 = rf(userId, batchId, reference)
+	} else {
+		r0 = ret.Get(0).(payout.BatchCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userId, batchId, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyPayouts provides a mock function with given fields: userId
+func (_m *PayoutService) MyPayouts(userId string) (payout.SummaryCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 payout.SummaryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (payout.SummaryCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) payout.SummaryCore); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(payout.SummaryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPayoutAccount provides a mock function with given fields: userId, request
+func (_m *PayoutService) SetPayoutAccount(userId string, request payout.AccountCore) (payout.AccountCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 payout.AccountCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, payout.AccountCore) (payout.AccountCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, payout.AccountCore) payout.AccountCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(payout.AccountCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, payout.AccountCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayoutService creates a new instance of PayoutService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayoutService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayoutService {
+	mock := &PayoutService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,21 +14,692 @@ type ReservationData struct {
 	mock.Mock
 }
 
+// CancelReservations provides a mock function with given fields: paymentId, reservationIds, cancelPayment
+func (_m *ReservationData) CancelReservations(paymentId string, reservationIds []string, cancelPayment bool) error {
+	ret := _m.Called(paymentId, reservationIds, cancelPayment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string, bool) error); ok {
+		r0 = rf(paymentId, reservationIds, cancelPayment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CheckAvailability provides a mock function with given fields: venueId
 func (_m *ReservationData) CheckAvailability(venueId string) ([]reservation.AvailabilityCore, error) {
 	ret := _m.Called(venueId)
 
-	var r0 []reservation.AvailabilityCore
+	var r0 []reservation.AvailabilityCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.AvailabilityCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.AvailabilityCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.AvailabilityCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckAvailabilityByTimeWindow provides a mock function with given fields: venueId, start, end
+func (_m *ReservationData) CheckAvailabilityByTimeWindow(venueId string, start time.Time, end time.Time) ([]reservation.AvailabilityCore, error) {
+	ret := _m.Called(venueId, start, end)
+
+	var r0 []reservation.AvailabilityCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]reservation.AvailabilityCore, error)); ok {
+		return rf(venueId, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []reservation.AvailabilityCore); ok {
+		r0 = rf(venueId, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.AvailabilityCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(venueId, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimWaitlistOffer provides a mock function with given fields: userId, holdId
+func (_m *ReservationData) ClaimWaitlistOffer(userId string, holdId string) error {
+	ret := _m.Called(userId, holdId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, holdId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmSplitPayment provides a mock function with given fields: paymentId
+func (_m *ReservationData) ConfirmSplitPayment(paymentId string) (reservation.PaymentCore, bool, error) {
+	ret := _m.Called(paymentId)
+
+	var r0 reservation.PaymentCore
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (reservation.PaymentCore, bool, error)); ok {
+		return rf(paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string) reservation.PaymentCore); ok {
+		r0 = rf(paymentId)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(paymentId)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(paymentId)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteHold provides a mock function with given fields: venueId, holdId
+func (_m *ReservationData) DeleteHold(venueId string, holdId string) error {
+	ret := _m.Called(venueId, holdId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(venueId, holdId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetailTransaction provides a mock function with given fields: userId, paymentId
+func (_m *ReservationData) DetailTransaction(userId string, paymentId string) (reservation.PaymentCore, error) {
+	ret := _m.Called(userId, paymentId)
+
+	var r0 reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (reservation.PaymentCore, error)); ok {
+		return rf(userId, paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) reservation.PaymentCore); ok {
+		r0 = rf(userId, paymentId)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpirePendingPayments provides a mock function with given fields: paymentIds, reason
+func (_m *ReservationData) ExpirePendingPayments(paymentIds []string, reason string) ([]reservation.PaymentCore, error) {
+	ret := _m.Called(paymentIds, reason)
+
+	var r0 []reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string, string) ([]reservation.PaymentCore, error)); ok {
+		return rf(paymentIds, reason)
+	}
+	if rf, ok := ret.Get(0).(func([]string, string) []reservation.PaymentCore); ok {
+		r0 = rf(paymentIds, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.PaymentCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string, string) error); ok {
+		r1 = rf(paymentIds, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireWaitlistOffers provides a mock function with given fields: before
+func (_m *ReservationData) ExpireWaitlistOffers(before time.Time) ([]reservation.WaitlistCore, error) {
+	ret := _m.Called(before)
+
+	var r0 []reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]reservation.WaitlistCore, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []reservation.WaitlistCore); ok {
+		r0 = rf(before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.WaitlistCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FailSplitPayment provides a mock function with given fields: paymentId, reason
+func (_m *ReservationData) FailSplitPayment(paymentId string, reason string) (reservation.PaymentCore, []reservation.ShareCore, error) {
+	ret := _m.Called(paymentId, reason)
+
+	var r0 reservation.PaymentCore
+	var r1 []reservation.ShareCore
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string) (reservation.PaymentCore, []reservation.ShareCore, error)); ok {
+		return rf(paymentId, reason)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) reservation.PaymentCore); ok {
+		r0 = rf(paymentId, reason)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) []reservation.ShareCore); ok {
+		r1 = rf(paymentId, reason)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]reservation.ShareCore)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string) error); ok {
+		r2 = rf(paymentId, reason)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCancellationPolicy provides a mock function with given fields: venueId
+func (_m *ReservationData) GetCancellationPolicy(venueId string) ([]reservation.CancellationTierCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []reservation.CancellationTierCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.CancellationTierCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.CancellationTierCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.CancellationTierCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClosures provides a mock function with given fields: venueId, start, end
+func (_m *ReservationData) GetClosures(venueId string, start time.Time, end time.Time) ([]reservation.ClosureCore, error) {
+	ret := _m.Called(venueId, start, end)
+
+	var r0 []reservation.ClosureCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]reservation.ClosureCore, error)); ok {
+		return rf(venueId, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []reservation.ClosureCore); ok {
+		r0 = rf(venueId, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ClosureCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(venueId, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDiscrepancies provides a mock function with given fields: unresolvedOnly
+func (_m *ReservationData) GetDiscrepancies(unresolvedOnly bool) ([]reservation.DiscrepancyCore, error) {
+	ret := _m.Called(unresolvedOnly)
+
+	var r0 []reservation.DiscrepancyCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool) ([]reservation.DiscrepancyCore, error)); ok {
+		return rf(unresolvedOnly)
+	}
+	if rf, ok := ret.Get(0).(func(bool) []reservation.DiscrepancyCore); ok {
+		r0 = rf(unresolvedOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.DiscrepancyCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(unresolvedOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHolds provides a mock function with given fields: venueId
+func (_m *ReservationData) GetHolds(venueId string) ([]reservation.HoldCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []reservation.HoldCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.HoldCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.HoldCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.HoldCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOverduePayments provides a mock function with given fields: before
+func (_m *ReservationData) GetOverduePayments(before time.Time) ([]reservation.PaymentCore, error) {
+	ret := _m.Called(before)
+
+	var r0 []reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]reservation.PaymentCore, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []reservation.PaymentCore); ok {
+		r0 = rf(before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.PaymentCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayment provides a mock function with given fields: paymentId
+func (_m *ReservationData) GetPayment(paymentId string) (reservation.PaymentCore, error) {
+	ret := _m.Called(paymentId)
+
+	var r0 reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (reservation.PaymentCore, error)); ok {
+		return rf(paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string) reservation.PaymentCore); ok {
+		r0 = rf(paymentId)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentCustomer provides a mock function with given fields: paymentId
+func (_m *ReservationData) GetPaymentCustomer(paymentId string) (reservation.CustomerCore, error) {
+	ret := _m.Called(paymentId)
+
+	var r0 reservation.CustomerCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (reservation.CustomerCore, error)); ok {
+		return rf(paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string) reservation.CustomerCore); ok {
+		r0 = rf(paymentId)
+	} else {
+		r0 = ret.Get(0).(reservation.CustomerCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentReservations provides a mock function with given fields: paymentId
+func (_m *ReservationData) GetPaymentReservations(paymentId string) ([]reservation.ReservationCore, error) {
+	ret := _m.Called(paymentId)
+
+	var r0 []reservation.ReservationCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.ReservationCore, error)); ok {
+		return rf(paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.ReservationCore); ok {
+		r0 = rf(paymentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ReservationCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPricingRules provides a mock function with given fields: venueId
+func (_m *ReservationData) GetPricingRules(venueId string) ([]reservation.PricingRuleCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []reservation.PricingRuleCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.PricingRuleCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.PricingRuleCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.PricingRuleCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReservation provides a mock function with given fields: userId, reservationId
+func (_m *ReservationData) GetReservation(userId string, reservationId string) (reservation.ReservationCore, reservation.PaymentCore, error) {
+	ret := _m.Called(userId, reservationId)
+
+	var r0 reservation.ReservationCore
+	var r1 reservation.PaymentCore
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string) (reservation.ReservationCore, reservation.PaymentCore, error)); ok {
+		return rf(userId, reservationId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) reservation.ReservationCore); ok {
+		r0 = rf(userId, reservationId)
+	} else {
+		r0 = ret.Get(0).(reservation.ReservationCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) reservation.PaymentCore); ok {
+		r1 = rf(userId, reservationId)
+	} else {
+		r1 = ret.Get(1).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string) error); ok {
+		r2 = rf(userId, reservationId)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReservationsByTimeSlot provides a mock function with given fields: venueID, checkInDate, checkOutDate
+func (_m *ReservationData) GetReservationsByTimeSlot(venueID string, checkInDate time.Time, checkOutDate time.Time) ([]reservation.ReservationCore, error) {
+	ret := _m.Called(venueID, checkInDate, checkOutDate)
+
+	var r0 []reservation.ReservationCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]reservation.ReservationCore, error)); ok {
+		return rf(venueID, checkInDate, checkOutDate)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []reservation.ReservationCore); ok {
+		r0 = rf(venueID, checkInDate, checkOutDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ReservationCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(venueID, checkInDate, checkOutDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeries provides a mock function with given fields: seriesId
+func (_m *ReservationData) GetSeries(seriesId string) ([]reservation.ReservationCore, error) {
+	ret := _m.Called(seriesId)
+
+	var r0 []reservation.ReservationCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.ReservationCore, error)); ok {
+		return rf(seriesId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.ReservationCore); ok {
+		r0 = rf(seriesId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ReservationCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(seriesId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShares provides a mock function with given fields: paymentId
+func (_m *ReservationData) GetShares(paymentId string) ([]reservation.ShareCore, error) {
+	ret := _m.Called(paymentId)
+
+	var r0 []reservation.ShareCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.ShareCore, error)); ok {
+		return rf(paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.ShareCore); ok {
+		r0 = rf(paymentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ShareCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStalePayments provides a mock function with given fields: before, limit
+func (_m *ReservationData) GetStalePayments(before time.Time, limit int) ([]reservation.PaymentCore, error) {
+	ret := _m.Called(before, limit)
+
+	var r0 []reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]reservation.PaymentCore, error)); ok {
+		return rf(before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []reservation.PaymentCore); ok {
+		r0 = rf(before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.PaymentCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByContact provides a mock function with given fields: email, phone
+func (_m *ReservationData) GetUserByContact(email string, phone string) (reservation.CustomerCore, error) {
+	ret := _m.Called(email, phone)
+
+	var r0 reservation.CustomerCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (reservation.CustomerCore, error)); ok {
+		return rf(email, phone)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) reservation.CustomerCore); ok {
+		r0 = rf(email, phone)
+	} else {
+		r0 = ret.Get(0).(reservation.CustomerCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(email, phone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRole provides a mock function with given fields: userId
+func (_m *ReservationData) GetUserRole(userId string) (string, error) {
+	ret := _m.Called(userId)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserShares provides a mock function with given fields: userId
+func (_m *ReservationData) GetUserShares(userId string) ([]reservation.ShareCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []reservation.ShareCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.ShareCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.ShareCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ShareCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVenue provides a mock function with given fields: venueId
+func (_m *ReservationData) GetVenue(venueId string) (reservation.VenueCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 reservation.VenueCore
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]reservation.AvailabilityCore, error)); ok {
+	if rf, ok := ret.Get(0).(func(string) (reservation.VenueCore, error)); ok {
 		return rf(venueId)
 	}
-	if rf, ok := ret.Get(0).(func(string) []reservation.AvailabilityCore); ok {
+	if rf, ok := ret.Get(0).(func(string) reservation.VenueCore); ok {
 		r0 = rf(venueId)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]reservation.AvailabilityCore)
-		}
+		r0 = ret.Get(0).(reservation.VenueCore)
 	}
 
 	if rf, ok := ret.Get(1).(func(string) error); ok {
@@ -40,23 +711,23 @@ func (_m *ReservationData) CheckAvailability(venueId string) ([]reservation.Avai
 	return r0, r1
 }
 
-// DetailTransaction provides a mock function with given fields: userId, paymentId
-func (_m *ReservationData) DetailTransaction(userId string, paymentId string) (reservation.PaymentCore, error) {
-	ret := _m.Called(userId, paymentId)
+// GetVoucher provides a mock function with given fields: code
+func (_m *ReservationData) GetVoucher(code string) (reservation.VoucherCore, error) {
+	ret := _m.Called(code)
 
-	var r0 reservation.PaymentCore
+	var r0 reservation.VoucherCore
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (reservation.PaymentCore, error)); ok {
-		return rf(userId, paymentId)
+	if rf, ok := ret.Get(0).(func(string) (reservation.VoucherCore, error)); ok {
+		return rf(code)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) reservation.PaymentCore); ok {
-		r0 = rf(userId, paymentId)
+	if rf, ok := ret.Get(0).(func(string) reservation.VoucherCore); ok {
+		r0 = rf(code)
 	} else {
-		r0 = ret.Get(0).(reservation.PaymentCore)
+		r0 = ret.Get(0).(reservation.VoucherCore)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(userId, paymentId)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -64,25 +735,145 @@ func (_m *ReservationData) DetailTransaction(userId string, paymentId string) (r
 	return r0, r1
 }
 
-// GetReservationsByTimeSlot provides a mock function with given fields: venueID, checkInDate, checkOutDate
-func (_m *ReservationData) GetReservationsByTimeSlot(venueID string, checkInDate time.Time, checkOutDate time.Time) ([]reservation.ReservationCore, error) {
-	ret := _m.Called(venueID, checkInDate, checkOutDate)
+// GetWaitlist provides a mock function with given fields: venueId, start, end
+func (_m *ReservationData) GetWaitlist(venueId string, start time.Time, end time.Time) ([]reservation.WaitlistCore, error) {
+	ret := _m.Called(venueId, start, end)
 
-	var r0 []reservation.ReservationCore
+	var r0 []reservation.WaitlistCore
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]reservation.ReservationCore, error)); ok {
-		return rf(venueID, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]reservation.WaitlistCore, error)); ok {
+		return rf(venueId, start, end)
 	}
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []reservation.ReservationCore); ok {
-		r0 = rf(venueID, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []reservation.WaitlistCore); ok {
+		r0 = rf(venueId, start, end)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]reservation.ReservationCore)
+			r0 = ret.Get(0).([]reservation.WaitlistCore)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
-		r1 = rf(venueID, checkInDate, checkOutDate)
+		r1 = rf(venueId, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWaitlistEntry provides a mock function with given fields: userId, waitlistId
+func (_m *ReservationData) GetWaitlistEntry(userId string, waitlistId string) (reservation.WaitlistCore, error) {
+	ret := _m.Called(userId, waitlistId)
+
+	var r0 reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (reservation.WaitlistCore, error)); ok {
+		return rf(userId, waitlistId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) reservation.WaitlistCore); ok {
+		r0 = rf(userId, waitlistId)
+	} else {
+		r0 = ret.Get(0).(reservation.WaitlistCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, waitlistId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertDiscrepancy provides a mock function with given fields: request
+func (_m *ReservationData) InsertDiscrepancy(request reservation.DiscrepancyCore) (reservation.DiscrepancyCore, error) {
+	ret := _m.Called(request)
+
+	var r0 reservation.DiscrepancyCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(reservation.DiscrepancyCore) (reservation.DiscrepancyCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(reservation.DiscrepancyCore) reservation.DiscrepancyCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(reservation.DiscrepancyCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(reservation.DiscrepancyCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertHold provides a mock function with given fields: request, ttl
+func (_m *ReservationData) InsertHold(request reservation.HoldCore, ttl time.Duration) (reservation.HoldCore, error) {
+	ret := _m.Called(request, ttl)
+
+	var r0 reservation.HoldCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(reservation.HoldCore, time.Duration) (reservation.HoldCore, error)); ok {
+		return rf(request, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(reservation.HoldCore, time.Duration) reservation.HoldCore); ok {
+		r0 = rf(request, ttl)
+	} else {
+		r0 = ret.Get(0).(reservation.HoldCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(reservation.HoldCore, time.Duration) error); ok {
+		r1 = rf(request, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertRefund provides a mock function with given fields: request
+func (_m *ReservationData) InsertRefund(request reservation.RefundCore) (reservation.RefundCore, error) {
+	ret := _m.Called(request)
+
+	var r0 reservation.RefundCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(reservation.RefundCore) (reservation.RefundCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(reservation.RefundCore) reservation.RefundCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(reservation.RefundCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(reservation.RefundCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertWaitlist provides a mock function with given fields: request
+func (_m *ReservationData) InsertWaitlist(request reservation.WaitlistCore) (reservation.WaitlistCore, error) {
+	ret := _m.Called(request)
+
+	var r0 reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(reservation.WaitlistCore) (reservation.WaitlistCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(reservation.WaitlistCore) reservation.WaitlistCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(reservation.WaitlistCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(reservation.WaitlistCore) error); ok {
+		r1 = rf(request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -90,6 +881,39 @@ func (_m *ReservationData) GetReservationsByTimeSlot(venueID string, checkInDate
 	return r0, r1
 }
 
+// MakeRecurringReservation provides a mock function with given fields: userId, occurrences, p
+func (_m *ReservationData) MakeRecurringReservation(userId string, occurrences []reservation.ReservationCore, p reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, error) {
+	ret := _m.Called(userId, occurrences, p)
+
+	var r0 []reservation.ReservationCore
+	var r1 reservation.PaymentCore
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, []reservation.ReservationCore, reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, error)); ok {
+		return rf(userId, occurrences, p)
+	}
+	if rf, ok := ret.Get(0).(func(string, []reservation.ReservationCore, reservation.PaymentCore) []reservation.ReservationCore); ok {
+		r0 = rf(userId, occurrences, p)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ReservationCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []reservation.ReservationCore, reservation.PaymentCore) reservation.PaymentCore); ok {
+		r1 = rf(userId, occurrences, p)
+	} else {
+		r1 = ret.Get(1).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, []reservation.ReservationCore, reservation.PaymentCore) error); ok {
+		r2 = rf(userId, occurrences, p)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // MakeReservation provides a mock function with given fields: userId, r, p
 func (_m *ReservationData) MakeReservation(userId string, r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
 	ret := _m.Called(userId, r, p)
@@ -147,25 +971,51 @@ func (_m *ReservationData) MyReservation(userId string) ([]reservation.MyReserva
 	return r0, r1
 }
 
-// MyVenueCharts provides a mock function with given fields: userId, keyword, checkInDate, checkOutDate
-func (_m *ReservationData) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time) ([]reservation.MyReservationCore, error) {
-	ret := _m.Called(userId, keyword, checkInDate, checkOutDate)
+// MyVenueCharts provides a mock function with given fields: userId, keyword, checkInDate, checkOutDate, groupBy
+func (_m *ReservationData) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time, groupBy string) ([]reservation.MyReservationCore, error) {
+	ret := _m.Called(userId, keyword, checkInDate, checkOutDate, groupBy)
 
 	var r0 []reservation.MyReservationCore
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time) ([]reservation.MyReservationCore, error)); ok {
-		return rf(userId, keyword, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time, string) ([]reservation.MyReservationCore, error)); ok {
+		return rf(userId, keyword, checkInDate, checkOutDate, groupBy)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time) []reservation.MyReservationCore); ok {
-		r0 = rf(userId, keyword, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time, string) []reservation.MyReservationCore); ok {
+		r0 = rf(userId, keyword, checkInDate, checkOutDate, groupBy)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]reservation.MyReservationCore)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, time.Time, time.Time) error); ok {
-		r1 = rf(userId, keyword, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(1).(func(string, string, time.Time, time.Time, string) error); ok {
+		r1 = rf(userId, keyword, checkInDate, checkOutDate, groupBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyWaitlist provides a mock function with given fields: userId
+func (_m *ReservationData) MyWaitlist(userId string) ([]reservation.WaitlistCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.WaitlistCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.WaitlistCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.WaitlistCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -197,28 +1047,49 @@ func (_m *ReservationData) PriceVenue(venueID string) (float64, error) {
 	return r0, r1
 }
 
-// ReservationCheckOutDate provides a mock function with given fields: reservation_id
-func (_m *ReservationData) ReservationCheckOutDate(reservation_id string) (time.Time, error) {
-	ret := _m.Called(reservation_id)
+// RefundCredits provides a mock function with given fields: userId, venueId, paymentId, hours
+func (_m *ReservationData) RefundCredits(userId string, venueId string, paymentId string, hours float64) error {
+	ret := _m.Called(userId, venueId, paymentId, hours)
 
-	var r0 time.Time
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (time.Time, error)); ok {
-		return rf(reservation_id)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, float64) error); ok {
+		r0 = rf(userId, venueId, paymentId, hours)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RescheduleReservation provides a mock function with given fields: r, p
+func (_m *ReservationData) RescheduleReservation(r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
+	ret := _m.Called(r, p)
+
+	var r0 reservation.ReservationCore
+	var r1 reservation.PaymentCore
+	var r2 error
+	if rf, ok := ret.Get(0).(func(reservation.ReservationCore, reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error)); ok {
+		return rf(r, p)
 	}
-	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
-		r0 = rf(reservation_id)
+	if rf, ok := ret.Get(0).(func(reservation.ReservationCore, reservation.PaymentCore) reservation.ReservationCore); ok {
+		r0 = rf(r, p)
 	} else {
-		r0 = ret.Get(0).(time.Time)
+		r0 = ret.Get(0).(reservation.ReservationCore)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(reservation_id)
+	if rf, ok := ret.Get(1).(func(reservation.ReservationCore, reservation.PaymentCore) reservation.PaymentCore); ok {
+		r1 = rf(r, p)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(reservation.PaymentCore)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(reservation.ReservationCore, reservation.PaymentCore) error); ok {
+		r2 = rf(r, p)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // ReservationStatus provides a mock function with given fields: request
@@ -245,6 +1116,20 @@ func (_m *ReservationData) ReservationStatus(request reservation.PaymentCore) (r
 	return r0, r1
 }
 
+// UpdateWaitlist provides a mock function with given fields: request
+func (_m *ReservationData) UpdateWaitlist(request reservation.WaitlistCore) error {
+	ret := _m.Called(request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(reservation.WaitlistCore) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewReservationData creates a new instance of ReservationData. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReservationData(t interface {
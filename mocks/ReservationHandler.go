@@ -12,6 +12,38 @@ type ReservationHandler struct {
 	mock.Mock
 }
 
+// AvailabilitySlots provides a mock function with given fields:
+func (_m *ReservationHandler) AvailabilitySlots() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// CancelReservation provides a mock function with given fields:
+func (_m *ReservationHandler) CancelReservation() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // CheckAvailability provides a mock function with given fields:
 func (_m *ReservationHandler) CheckAvailability() echo.HandlerFunc {
 	ret := _m.Called()
@@ -28,6 +60,22 @@ func (_m *ReservationHandler) CheckAvailability() echo.HandlerFunc {
 	return r0
 }
 
+// CreateHold provides a mock function with given fields:
+func (_m *ReservationHandler) CreateHold() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // DetailTransaction provides a mock function with given fields:
 func (_m *ReservationHandler) DetailTransaction() echo.HandlerFunc {
 	ret := _m.Called()
@@ -44,6 +92,70 @@ func (_m *ReservationHandler) DetailTransaction() echo.HandlerFunc {
 	return r0
 }
 
+// DownloadInvoice provides a mock function with given fields:
+func (_m *ReservationHandler) DownloadInvoice() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// JoinWaitlist provides a mock function with given fields:
+func (_m *ReservationHandler) JoinWaitlist() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// LeaveWaitlist provides a mock function with given fields:
+func (_m *ReservationHandler) LeaveWaitlist() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// MakeRecurringReservation provides a mock function with given fields:
+func (_m *ReservationHandler) MakeRecurringReservation() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // MakeReservation provides a mock function with given fields:
 func (_m *ReservationHandler) MakeReservation() echo.HandlerFunc {
 	ret := _m.Called()
@@ -76,6 +188,22 @@ func (_m *ReservationHandler) MyReservation() echo.HandlerFunc {
 	return r0
 }
 
+// MyShares provides a mock function with given fields:
+func (_m *ReservationHandler) MyShares() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // MyVenueCharts provides a mock function with given fields:
 func (_m *ReservationHandler) MyVenueCharts() echo.HandlerFunc {
 	ret := _m.Called()
@@ -92,6 +220,70 @@ func (_m *ReservationHandler) MyVenueCharts() echo.HandlerFunc {
 	return r0
 }
 
+// MyWaitlist provides a mock function with given fields:
+func (_m *ReservationHandler) MyWaitlist() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// PaymentDiscrepancies provides a mock function with given fields:
+func (_m *ReservationHandler) PaymentDiscrepancies() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// QuoteReservation provides a mock function with given fields:
+func (_m *ReservationHandler) QuoteReservation() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// RescheduleReservation provides a mock function with given fields:
+func (_m *ReservationHandler) RescheduleReservation() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // ReservationStatus provides a mock function with given fields:
 func (_m *ReservationHandler) ReservationStatus() echo.HandlerFunc {
 	ret := _m.Called()
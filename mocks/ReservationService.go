@@ -14,6 +14,65 @@ type ReservationService struct {
 	mock.Mock
 }
 
+// AvailabilitySlots provides a mock function with given fields: venueId, startDate, endDate, slotLength
+func (_m *ReservationService) AvailabilitySlots(venueId string, startDate time.Time, endDate time.Time, slotLength time.Duration) ([]reservation.DaySlotsCore, error) {
+	ret := _m.Called(venueId, startDate, endDate, slotLength)
+
+	var r0 []reservation.DaySlotsCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time, time.Duration) ([]reservation.DaySlotsCore, error)); ok {
+		return rf(venueId, startDate, endDate, slotLength)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time, time.Duration) []reservation.DaySlotsCore); ok {
+		r0 = rf(venueId, startDate, endDate, slotLength)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.DaySlotsCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time, time.Duration) error); ok {
+		r1 = rf(venueId, startDate, endDate, slotLength)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelReservation provides a mock function with given fields: userId, reservationId, scope
+func (_m *ReservationService) CancelReservation(userId string, reservationId string, scope string) ([]reservation.ReservationCore, reservation.RefundCore, error) {
+	ret := _m.Called(userId, reservationId, scope)
+
+	var r0 []reservation.ReservationCore
+	var r1 reservation.RefundCore
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string, string) ([]reservation.ReservationCore, reservation.RefundCore, error)); ok {
+		return rf(userId, reservationId, scope)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) []reservation.ReservationCore); ok {
+		r0 = rf(userId, reservationId, scope)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ReservationCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) reservation.RefundCore); ok {
+		r1 = rf(userId, reservationId, scope)
+	} else {
+		r1 = ret.Get(1).(reservation.RefundCore)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string, string) error); ok {
+		r2 = rf(userId, reservationId, scope)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // CheckAvailability provides a mock function with given fields: venueId
 func (_m *ReservationService) CheckAvailability(venueId string) ([]reservation.AvailabilityCore, error) {
 	ret := _m.Called(venueId)
@@ -40,6 +99,30 @@ func (_m *ReservationService) CheckAvailability(venueId string) ([]reservation.A
 	return r0, r1
 }
 
+// CreateHold provides a mock function with given fields: userId, request
+func (_m *ReservationService) CreateHold(userId string, request reservation.HoldCore) (reservation.HoldCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 reservation.HoldCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, reservation.HoldCore) (reservation.HoldCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, reservation.HoldCore) reservation.HoldCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(reservation.HoldCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, reservation.HoldCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DetailTransaction provides a mock function with given fields: userId, paymentId
 func (_m *ReservationService) DetailTransaction(userId string, paymentId string) (reservation.PaymentCore, error) {
 	ret := _m.Called(userId, paymentId)
@@ -64,6 +147,164 @@ func (_m *ReservationService) DetailTransaction(userId string, paymentId string)
 	return r0, r1
 }
 
+// ExpirePendingPayments provides a mock function with given fields:
+func (_m *ReservationService) ExpirePendingPayments() ([]reservation.PaymentCore, error) {
+	ret := _m.Called()
+
+	var r0 []reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]reservation.PaymentCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []reservation.PaymentCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.PaymentCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireWaitlistOffers provides a mock function with given fields:
+func (_m *ReservationService) ExpireWaitlistOffers() ([]reservation.WaitlistCore, error) {
+	ret := _m.Called()
+
+	var r0 []reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]reservation.WaitlistCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []reservation.WaitlistCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.WaitlistCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InvoicePDF provides a mock function with given fields: userId, paymentId
+func (_m *ReservationService) InvoicePDF(userId string, paymentId string) ([]byte, error) {
+	ret := _m.Called(userId, paymentId)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]byte, error)); ok {
+		return rf(userId, paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []byte); ok {
+		r0 = rf(userId, paymentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JoinWaitlist provides a mock function with given fields: userId, request
+func (_m *ReservationService) JoinWaitlist(userId string, request reservation.WaitlistCore) (reservation.WaitlistCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, reservation.WaitlistCore) (reservation.WaitlistCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, reservation.WaitlistCore) reservation.WaitlistCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(reservation.WaitlistCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, reservation.WaitlistCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LeaveWaitlist provides a mock function with given fields: userId, waitlistId
+func (_m *ReservationService) LeaveWaitlist(userId string, waitlistId string) error {
+	ret := _m.Called(userId, waitlistId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, waitlistId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MakeRecurringReservation provides a mock function with given fields: userId, r, rule, p
+func (_m *ReservationService) MakeRecurringReservation(userId string, r reservation.ReservationCore, rule reservation.RecurrenceCore, p reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, []reservation.ConflictCore, error) {
+	ret := _m.Called(userId, r, rule, p)
+
+	var r0 []reservation.ReservationCore
+	var r1 reservation.PaymentCore
+	var r2 []reservation.ConflictCore
+	var r3 error
+	if rf, ok := ret.Get(0).(func(string, reservation.ReservationCore, reservation.RecurrenceCore, reservation.PaymentCore) ([]reservation.ReservationCore, reservation.PaymentCore, []reservation.ConflictCore, error)); ok {
+		return rf(userId, r, rule, p)
+	}
+	if rf, ok := ret.Get(0).(func(string, reservation.ReservationCore, reservation.RecurrenceCore, reservation.PaymentCore) []reservation.ReservationCore); ok {
+		r0 = rf(userId, r, rule, p)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ReservationCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, reservation.ReservationCore, reservation.RecurrenceCore, reservation.PaymentCore) reservation.PaymentCore); ok {
+		r1 = rf(userId, r, rule, p)
+	} else {
+		r1 = ret.Get(1).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, reservation.ReservationCore, reservation.RecurrenceCore, reservation.PaymentCore) []reservation.ConflictCore); ok {
+		r2 = rf(userId, r, rule, p)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]reservation.ConflictCore)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(string, reservation.ReservationCore, reservation.RecurrenceCore, reservation.PaymentCore) error); ok {
+		r3 = rf(userId, r, rule, p)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
 // MakeReservation provides a mock function with given fields: userId, r, p
 func (_m *ReservationService) MakeReservation(userId string, r reservation.ReservationCore, p reservation.PaymentCore) (reservation.ReservationCore, reservation.PaymentCore, error) {
 	ret := _m.Called(userId, r, p)
@@ -121,25 +362,201 @@ func (_m *ReservationService) MyReservation(userId string) ([]reservation.MyRese
 	return r0, r1
 }
 
-// MyVenueCharts provides a mock function with given fields: userId, keyword, checkInDate, checkOutDate
-func (_m *ReservationService) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time) ([]reservation.MyReservationCore, error) {
-	ret := _m.Called(userId, keyword, checkInDate, checkOutDate)
+// MyShares provides a mock function with given fields: userId
+func (_m *ReservationService) MyShares(userId string) ([]reservation.ShareCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []reservation.ShareCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.ShareCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.ShareCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.ShareCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyVenueCharts provides a mock function with given fields: userId, keyword, checkInDate, checkOutDate, groupBy
+func (_m *ReservationService) MyVenueCharts(userId string, keyword string, checkInDate time.Time, checkOutDate time.Time, groupBy string) ([]reservation.MyReservationCore, error) {
+	ret := _m.Called(userId, keyword, checkInDate, checkOutDate, groupBy)
 
 	var r0 []reservation.MyReservationCore
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time) ([]reservation.MyReservationCore, error)); ok {
-		return rf(userId, keyword, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time, string) ([]reservation.MyReservationCore, error)); ok {
+		return rf(userId, keyword, checkInDate, checkOutDate, groupBy)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time) []reservation.MyReservationCore); ok {
-		r0 = rf(userId, keyword, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time, string) []reservation.MyReservationCore); ok {
+		r0 = rf(userId, keyword, checkInDate, checkOutDate, groupBy)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]reservation.MyReservationCore)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, time.Time, time.Time) error); ok {
-		r1 = rf(userId, keyword, checkInDate, checkOutDate)
+	if rf, ok := ret.Get(1).(func(string, string, time.Time, time.Time, string) error); ok {
+		r1 = rf(userId, keyword, checkInDate, checkOutDate, groupBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyWaitlist provides a mock function with given fields: userId
+func (_m *ReservationService) MyWaitlist(userId string) ([]reservation.WaitlistCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []reservation.WaitlistCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]reservation.WaitlistCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []reservation.WaitlistCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.WaitlistCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PaymentDiscrepancies provides a mock function with given fields: userId, unresolvedOnly
+func (_m *ReservationService) PaymentDiscrepancies(userId string, unresolvedOnly bool) ([]reservation.DiscrepancyCore, error) {
+	ret := _m.Called(userId, unresolvedOnly)
+
+	var r0 []reservation.DiscrepancyCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, bool) ([]reservation.DiscrepancyCore, error)); ok {
+		return rf(userId, unresolvedOnly)
+	}
+	if rf, ok := ret.Get(0).(func(string, bool) []reservation.DiscrepancyCore); ok {
+		r0 = rf(userId, unresolvedOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.DiscrepancyCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(userId, unresolvedOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PaymentNotification provides a mock function with given fields: body
+func (_m *ReservationService) PaymentNotification(body []byte) (reservation.PaymentCore, error) {
+	ret := _m.Called(body)
+
+	var r0 reservation.PaymentCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]byte) (reservation.PaymentCore, error)); ok {
+		return rf(body)
+	}
+	if rf, ok := ret.Get(0).(func([]byte) reservation.PaymentCore); ok {
+		r0 = rf(body)
+	} else {
+		r0 = ret.Get(0).(reservation.PaymentCore)
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QuoteReservation provides a mock function with given fields: venueId, checkInDate, checkOutDate
+func (_m *ReservationService) QuoteReservation(venueId string, checkInDate time.Time, checkOutDate time.Time) (reservation.QuoteCore, error) {
+	ret := _m.Called(venueId, checkInDate, checkOutDate)
+
+	var r0 reservation.QuoteCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) (reservation.QuoteCore, error)); ok {
+		return rf(venueId, checkInDate, checkOutDate)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) reservation.QuoteCore); ok {
+		r0 = rf(venueId, checkInDate, checkOutDate)
+	} else {
+		r0 = ret.Get(0).(reservation.QuoteCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(venueId, checkInDate, checkOutDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconcilePayments provides a mock function with given fields:
+func (_m *ReservationService) ReconcilePayments() ([]reservation.DiscrepancyCore, error) {
+	ret := _m.Called()
+
+	var r0 []reservation.DiscrepancyCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]reservation.DiscrepancyCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []reservation.DiscrepancyCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservation.DiscrepancyCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RescheduleReservation provides a mock function with given fields: userId, reservationId, checkInDate, checkOutDate, p
+func (_m *ReservationService) RescheduleReservation(userId string, reservationId string, checkInDate time.Time, checkOutDate time.Time, p reservation.PaymentCore) (reservation.RescheduleCore, error) {
+	ret := _m.Called(userId, reservationId, checkInDate, checkOutDate, p)
+
+	var r0 reservation.RescheduleCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time, reservation.PaymentCore) (reservation.RescheduleCore, error)); ok {
+		return rf(userId, reservationId, checkInDate, checkOutDate, p)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time, reservation.PaymentCore) reservation.RescheduleCore); ok {
+		r0 = rf(userId, reservationId, checkInDate, checkOutDate, p)
+	} else {
+		r0 = ret.Get(0).(reservation.RescheduleCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Time, time.Time, reservation.PaymentCore) error); ok {
+		r1 = rf(userId, reservationId, checkInDate, checkOutDate, p)
 	} else {
 		r1 = ret.Error(1)
 	}
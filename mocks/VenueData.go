@@ -4,6 +4,7 @@ package mocks
 
 import (
 	venue "github.com/playground-pro-project/playground-pro-api/features/venue"
+	geo "github.com/playground-pro-project/playground-pro-api/utils/geo"
 	pagination "github.com/playground-pro-project/playground-pro-api/utils/pagination"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,6 +14,46 @@ type VenueData struct {
 	mock.Mock
 }
 
+// ClusterVenuesInBox provides a mock function with given fields: box, cellSize
+func (_m *VenueData) ClusterVenuesInBox(box geo.Box, cellSize float64) ([]venue.MapMarkerCore, error) {
+	ret := _m.Called(box, cellSize)
+
+	var r0 []venue.MapMarkerCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(geo.Box, float64) ([]venue.MapMarkerCore, error)); ok {
+		return rf(box, cellSize)
+	}
+	if rf, ok := ret.Get(0).(func(geo.Box, float64) []venue.MapMarkerCore); ok {
+		r0 = rf(box, cellSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.MapMarkerCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(geo.Box, float64) error); ok {
+		r1 = rf(box, cellSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteClosure provides a mock function with given fields: userId, venueId, closureId
+func (_m *VenueData) DeleteClosure(userId string, venueId string, closureId string) error {
+	ret := _m.Called(userId, venueId, closureId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userId, venueId, closureId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteVenueImage provides a mock function with given fields: venueImageID
 func (_m *VenueData) DeleteVenueImage(venueImageID string) error {
 	ret := _m.Called(venueImageID)
@@ -67,6 +108,160 @@ func (_m *VenueData) GetAllVenueImage(venueID string) ([]venue.VenuePictureCore,
 	return r0, r1
 }
 
+// GetAmenitiesByCode provides a mock function with given fields: codes
+func (_m *VenueData) GetAmenitiesByCode(codes []string) ([]venue.AmenityCore, error) {
+	ret := _m.Called(codes)
+
+	var r0 []venue.AmenityCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) ([]venue.AmenityCore, error)); ok {
+		return rf(codes)
+	}
+	if rf, ok := ret.Get(0).(func([]string) []venue.AmenityCore); ok {
+		r0 = rf(codes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.AmenityCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(codes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCancellationPolicy provides a mock function with given fields: venueId
+func (_m *VenueData) GetCancellationPolicy(venueId string) ([]venue.CancellationTierCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.CancellationTierCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.CancellationTierCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.CancellationTierCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.CancellationTierCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategory provides a mock function with given fields: category
+func (_m *VenueData) GetCategory(category string) (venue.CategoryCore, error) {
+	ret := _m.Called(category)
+
+	var r0 venue.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (venue.CategoryCore, error)); ok {
+		return rf(category)
+	}
+	if rf, ok := ret.Get(0).(func(string) venue.CategoryCore); ok {
+		r0 = rf(category)
+	} else {
+		r0 = ret.Get(0).(venue.CategoryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(category)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClosures provides a mock function with given fields: venueId
+func (_m *VenueData) GetClosures(venueId string) ([]venue.VenueClosureCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.VenueClosureCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.VenueClosureCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.VenueClosureCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.VenueClosureCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpeningHours provides a mock function with given fields: venueId
+func (_m *VenueData) GetOpeningHours(venueId string) ([]venue.VenueHourCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.VenueHourCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.VenueHourCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.VenueHourCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.VenueHourCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPricingRules provides a mock function with given fields: venueId
+func (_m *VenueData) GetPricingRules(venueId string) ([]venue.PricingRuleCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.PricingRuleCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.PricingRuleCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.PricingRuleCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.PricingRuleCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVenueImageByID provides a mock function with given fields: venueID, venueImageID
 func (_m *VenueData) GetVenueImageByID(venueID string, venueImageID string) (venue.VenuePictureCore, error) {
 	ret := _m.Called(venueID, venueImageID)
@@ -91,6 +286,56 @@ func (_m *VenueData) GetVenueImageByID(venueID string, venueImageID string) (ven
 	return r0, r1
 }
 
+// GetVenuesInBox provides a mock function with given fields: box, limit
+func (_m *VenueData) GetVenuesInBox(box geo.Box, limit int) ([]venue.MapMarkerCore, error) {
+	ret := _m.Called(box, limit)
+
+	var r0 []venue.MapMarkerCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(geo.Box, int) ([]venue.MapMarkerCore, error)); ok {
+		return rf(box, limit)
+	}
+	if rf, ok := ret.Get(0).(func(geo.Box, int) []venue.MapMarkerCore); ok {
+		r0 = rf(box, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.MapMarkerCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(geo.Box, int) error); ok {
+		r1 = rf(box, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertClosure provides a mock function with given fields: userId, request
+func (_m *VenueData) InsertClosure(userId string, request venue.VenueClosureCore) (venue.VenueClosureCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 venue.VenueClosureCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, venue.VenueClosureCore) (venue.VenueClosureCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, venue.VenueClosureCore) venue.VenueClosureCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(venue.VenueClosureCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, venue.VenueClosureCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InsertVenue provides a mock function with given fields: userID, venueReq, venueImageReq
 func (_m *VenueData) InsertVenue(userID string, venueReq venue.VenueCore, venueImageReq venue.VenuePictureCore) (venue.VenueCore, error) {
 	ret := _m.Called(userID, venueReq, venueImageReq)
@@ -189,44 +434,132 @@ func (_m *VenueData) RegisterVenue(userId string, request venue.VenueCore) (venu
 	return r0, r1
 }
 
-// SearchVenues provides a mock function with given fields: keyword, latitude, longitude, page
-func (_m *VenueData) SearchVenues(keyword string, latitude float64, longitude float64, page pagination.Pagination) ([]venue.VenueCoreRaw, int64, int, error) {
-	ret := _m.Called(keyword, latitude, longitude, page)
+// ReplaceCancellationPolicy provides a mock function with given fields: userId, venueId, tiers
+func (_m *VenueData) ReplaceCancellationPolicy(userId string, venueId string, tiers []venue.CancellationTierCore) ([]venue.CancellationTierCore, error) {
+	ret := _m.Called(userId, venueId, tiers)
 
-	var r0 []venue.VenueCoreRaw
-	var r1 int64
-	var r2 int
-	var r3 error
-	if rf, ok := ret.Get(0).(func(string, float64, float64, pagination.Pagination) ([]venue.VenueCoreRaw, int64, int, error)); ok {
-		return rf(keyword, latitude, longitude, page)
+	var r0 []venue.CancellationTierCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, []venue.CancellationTierCore) ([]venue.CancellationTierCore, error)); ok {
+		return rf(userId, venueId, tiers)
 	}
-	if rf, ok := ret.Get(0).(func(string, float64, float64, pagination.Pagination) []venue.VenueCoreRaw); ok {
-		r0 = rf(keyword, latitude, longitude, page)
+	if rf, ok := ret.Get(0).(func(string, string, []venue.CancellationTierCore) []venue.CancellationTierCore); ok {
+		r0 = rf(userId, venueId, tiers)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]venue.VenueCoreRaw)
+			r0 = ret.Get(0).([]venue.CancellationTierCore)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, float64, float64, pagination.Pagination) int64); ok {
-		r1 = rf(keyword, latitude, longitude, page)
+	if rf, ok := ret.Get(1).(func(string, string, []venue.CancellationTierCore) error); ok {
+		r1 = rf(userId, venueId, tiers)
 	} else {
-		r1 = ret.Get(1).(int64)
+		r1 = ret.Error(1)
 	}
 
-	if rf, ok := ret.Get(2).(func(string, float64, float64, pagination.Pagination) int); ok {
-		r2 = rf(keyword, latitude, longitude, page)
+	return r0, r1
+}
+
+// ReplaceOpeningHours provides a mock function with given fields: userId, venueId, hours
+func (_m *VenueData) ReplaceOpeningHours(userId string, venueId string, hours []venue.VenueHourCore) ([]venue.VenueHourCore, error) {
+	ret := _m.Called(userId, venueId, hours)
+
+	var r0 []venue.VenueHourCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, []venue.VenueHourCore) ([]venue.VenueHourCore, error)); ok {
+		return rf(userId, venueId, hours)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, []venue.VenueHourCore) []venue.VenueHourCore); ok {
+		r0 = rf(userId, venueId, hours)
 	} else {
-		r2 = ret.Get(2).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.VenueHourCore)
+		}
 	}
 
-	if rf, ok := ret.Get(3).(func(string, float64, float64, pagination.Pagination) error); ok {
-		r3 = rf(keyword, latitude, longitude, page)
+	if rf, ok := ret.Get(1).(func(string, string, []venue.VenueHourCore) error); ok {
+		r1 = rf(userId, venueId, hours)
 	} else {
-		r3 = ret.Error(3)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2, r3
+	return r0, r1
+}
+
+// ReplacePricingRules provides a mock function with given fields: userId, venueId, rules
+func (_m *VenueData) ReplacePricingRules(userId string, venueId string, rules []venue.PricingRuleCore) ([]venue.PricingRuleCore, error) {
+	ret := _m.Called(userId, venueId, rules)
+
+	var r0 []venue.PricingRuleCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, []venue.PricingRuleCore) ([]venue.PricingRuleCore, error)); ok {
+		return rf(userId, venueId, rules)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, []venue.PricingRuleCore) []venue.PricingRuleCore); ok {
+		r0 = rf(userId, venueId, rules)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.PricingRuleCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, []venue.PricingRuleCore) error); ok {
+		r1 = rf(userId, venueId, rules)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchVenues provides a mock function with given fields: search, page
+func (_m *VenueData) SearchVenues(search venue.SearchCore, page pagination.Pagination) (venue.SearchResultCore, error) {
+	ret := _m.Called(search, page)
+
+	var r0 venue.SearchResultCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(venue.SearchCore, pagination.Pagination) (venue.SearchResultCore, error)); ok {
+		return rf(search, page)
+	}
+	if rf, ok := ret.Get(0).(func(venue.SearchCore, pagination.Pagination) venue.SearchResultCore); ok {
+		r0 = rf(search, page)
+	} else {
+		r0 = ret.Get(0).(venue.SearchResultCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(venue.SearchCore, pagination.Pagination) error); ok {
+		r1 = rf(search, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectAmenities provides a mock function with given fields:
+func (_m *VenueData) SelectAmenities() ([]venue.AmenityCore, error) {
+	ret := _m.Called()
+
+	var r0 []venue.AmenityCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]venue.AmenityCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []venue.AmenityCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.AmenityCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // SelectVenue provides a mock function with given fields: venueId
@@ -253,6 +586,32 @@ func (_m *VenueData) SelectVenue(venueId string) (venue.VenueCore, error) {
 	return r0, r1
 }
 
+// SuggestVenues provides a mock function with given fields: query, limit
+func (_m *VenueData) SuggestVenues(query string, limit int) ([]venue.SuggestionCore, error) {
+	ret := _m.Called(query, limit)
+
+	var r0 []venue.SuggestionCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]venue.SuggestionCore, error)); ok {
+		return rf(query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []venue.SuggestionCore); ok {
+		r0 = rf(query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.SuggestionCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnregisterVenue provides a mock function with given fields: userId, venueId
 func (_m *VenueData) UnregisterVenue(userId string, venueId string) error {
 	ret := _m.Called(userId, venueId)
@@ -12,6 +12,22 @@ type VenueHandler struct {
 	mock.Mock
 }
 
+// CreateClosure provides a mock function with given fields:
+func (_m *VenueHandler) CreateClosure() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // CreateVenue provides a mock function with given fields:
 func (_m *VenueHandler) CreateVenue() echo.HandlerFunc {
 	ret := _m.Called()
@@ -44,6 +60,22 @@ func (_m *VenueHandler) CreateVenueImage() echo.HandlerFunc {
 	return r0
 }
 
+// DeleteClosure provides a mock function with given fields:
+func (_m *VenueHandler) DeleteClosure() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // DeleteVenueImage provides a mock function with given fields:
 func (_m *VenueHandler) DeleteVenueImage() echo.HandlerFunc {
 	ret := _m.Called()
@@ -92,6 +124,86 @@ func (_m *VenueHandler) GetAllVenueImage() echo.HandlerFunc {
 	return r0
 }
 
+// GetAmenities provides a mock function with given fields:
+func (_m *VenueHandler) GetAmenities() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// GetCancellationPolicy provides a mock function with given fields:
+func (_m *VenueHandler) GetCancellationPolicy() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// GetClosures provides a mock function with given fields:
+func (_m *VenueHandler) GetClosures() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// GetOpeningHours provides a mock function with given fields:
+func (_m *VenueHandler) GetOpeningHours() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// GetPricingRules provides a mock function with given fields:
+func (_m *VenueHandler) GetPricingRules() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // MyVenues provides a mock function with given fields:
 func (_m *VenueHandler) MyVenues() echo.HandlerFunc {
 	ret := _m.Called()
@@ -140,6 +252,70 @@ func (_m *VenueHandler) SelectVenue() echo.HandlerFunc {
 	return r0
 }
 
+// SetCancellationPolicy provides a mock function with given fields:
+func (_m *VenueHandler) SetCancellationPolicy() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// SetOpeningHours provides a mock function with given fields:
+func (_m *VenueHandler) SetOpeningHours() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// SetPricingRules provides a mock function with given fields:
+func (_m *VenueHandler) SetPricingRules() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// SuggestVenues provides a mock function with given fields:
+func (_m *VenueHandler) SuggestVenues() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // UnregisterVenue provides a mock function with given fields:
 func (_m *VenueHandler) UnregisterVenue() echo.HandlerFunc {
 	ret := _m.Called()
@@ -172,6 +348,22 @@ func (_m *VenueHandler) VenueAvailability() echo.HandlerFunc {
 	return r0
 }
 
+// VenueMap provides a mock function with given fields:
+func (_m *VenueHandler) VenueMap() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // NewVenueHandler creates a new instance of VenueHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewVenueHandler(t interface {
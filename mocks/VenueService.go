@@ -4,6 +4,7 @@ package mocks
 
 import (
 	venue "github.com/playground-pro-project/playground-pro-api/features/venue"
+	geo "github.com/playground-pro-project/playground-pro-api/utils/geo"
 	pagination "github.com/playground-pro-project/playground-pro-api/utils/pagination"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,6 +14,30 @@ type VenueService struct {
 	mock.Mock
 }
 
+// CreateClosure provides a mock function with given fields: userId, request
+func (_m *VenueService) CreateClosure(userId string, request venue.VenueClosureCore) (venue.VenueClosureCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 venue.VenueClosureCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, venue.VenueClosureCore) (venue.VenueClosureCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, venue.VenueClosureCore) venue.VenueClosureCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(venue.VenueClosureCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, venue.VenueClosureCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateVenue provides a mock function with given fields: userID, venueReq, venueImageReq
 func (_m *VenueService) CreateVenue(userID string, venueReq venue.VenueCore, venueImageReq venue.VenuePictureCore) (venue.VenueCore, error) {
 	ret := _m.Called(userID, venueReq, venueImageReq)
@@ -61,6 +86,20 @@ func (_m *VenueService) CreateVenueImage(req venue.VenuePictureCore) (venue.Venu
 	return r0, r1
 }
 
+// DeleteClosure provides a mock function with given fields: userId, venueId, closureId
+func (_m *VenueService) DeleteClosure(userId string, venueId string, closureId string) error {
+	ret := _m.Called(userId, venueId, closureId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userId, venueId, closureId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteVenueImage provides a mock function with given fields: venueImageID
 func (_m *VenueService) DeleteVenueImage(venueImageID string) error {
 	ret := _m.Called(venueImageID)
@@ -115,6 +154,136 @@ func (_m *VenueService) GetAllVenueImage(venueID string) ([]venue.VenuePictureCo
 	return r0, r1
 }
 
+// GetAmenities provides a mock function with given fields:
+func (_m *VenueService) GetAmenities() ([]venue.AmenityCore, error) {
+	ret := _m.Called()
+
+	var r0 []venue.AmenityCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]venue.AmenityCore, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []venue.AmenityCore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.AmenityCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCancellationPolicy provides a mock function with given fields: venueId
+func (_m *VenueService) GetCancellationPolicy(venueId string) ([]venue.CancellationTierCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.CancellationTierCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.CancellationTierCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.CancellationTierCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.CancellationTierCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClosures provides a mock function with given fields: venueId
+func (_m *VenueService) GetClosures(venueId string) ([]venue.VenueClosureCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.VenueClosureCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.VenueClosureCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.VenueClosureCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.VenueClosureCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpeningHours provides a mock function with given fields: venueId
+func (_m *VenueService) GetOpeningHours(venueId string) ([]venue.VenueHourCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.VenueHourCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.VenueHourCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.VenueHourCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.VenueHourCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPricingRules provides a mock function with given fields: venueId
+func (_m *VenueService) GetPricingRules(venueId string) ([]venue.PricingRuleCore, error) {
+	ret := _m.Called(venueId)
+
+	var r0 []venue.PricingRuleCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.PricingRuleCore, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.PricingRuleCore); ok {
+		r0 = rf(venueId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.PricingRuleCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVenueImageByID provides a mock function with given fields: venueID, venueImageID
 func (_m *VenueService) GetVenueImageByID(venueID string, venueImageID string) (venue.VenuePictureCore, error) {
 	ret := _m.Called(venueID, venueImageID)
@@ -165,44 +334,28 @@ func (_m *VenueService) MyVenues(userId string) ([]venue.VenueCore, error) {
 	return r0, r1
 }
 
-// SearchVenues provides a mock function with given fields: keyword, latitude, longitude, page
-func (_m *VenueService) SearchVenues(keyword string, latitude float64, longitude float64, page pagination.Pagination) ([]venue.VenueCoreRaw, int64, int, error) {
-	ret := _m.Called(keyword, latitude, longitude, page)
+// SearchVenues provides a mock function with given fields: search, page
+func (_m *VenueService) SearchVenues(search venue.SearchCore, page pagination.Pagination) (venue.SearchResultCore, error) {
+	ret := _m.Called(search, page)
 
-	var r0 []venue.VenueCoreRaw
-	var r1 int64
-	var r2 int
-	var r3 error
-	if rf, ok := ret.Get(0).(func(string, float64, float64, pagination.Pagination) ([]venue.VenueCoreRaw, int64, int, error)); ok {
-		return rf(keyword, latitude, longitude, page)
-	}
-	if rf, ok := ret.Get(0).(func(string, float64, float64, pagination.Pagination) []venue.VenueCoreRaw); ok {
-		r0 = rf(keyword, latitude, longitude, page)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]venue.VenueCoreRaw)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(string, float64, float64, pagination.Pagination) int64); ok {
-		r1 = rf(keyword, latitude, longitude, page)
-	} else {
-		r1 = ret.Get(1).(int64)
+	var r0 venue.SearchResultCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(venue.SearchCore, pagination.Pagination) (venue.SearchResultCore, error)); ok {
+		return rf(search, page)
 	}
-
-	if rf, ok := ret.Get(2).(func(string, float64, float64, pagination.Pagination) int); ok {
-		r2 = rf(keyword, latitude, longitude, page)
+	if rf, ok := ret.Get(0).(func(venue.SearchCore, pagination.Pagination) venue.SearchResultCore); ok {
+		r0 = rf(search, page)
 	} else {
-		r2 = ret.Get(2).(int)
+		r0 = ret.Get(0).(venue.SearchResultCore)
 	}
 
-	if rf, ok := ret.Get(3).(func(string, float64, float64, pagination.Pagination) error); ok {
-		r3 = rf(keyword, latitude, longitude, page)
+	if rf, ok := ret.Get(1).(func(venue.SearchCore, pagination.Pagination) error); ok {
+		r1 = rf(search, page)
 	} else {
-		r3 = ret.Error(3)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2, r3
+	return r0, r1
 }
 
 // SelectVenue provides a mock function with given fields: venueId
@@ -229,6 +382,110 @@ func (_m *VenueService) SelectVenue(venueId string) (venue.VenueCore, error) {
 	return r0, r1
 }
 
+// SetCancellationPolicy provides a mock function with given fields: userId, venueId, tiers
+func (_m *VenueService) SetCancellationPolicy(userId string, venueId string, tiers []venue.CancellationTierCore) ([]venue.CancellationTierCore, error) {
+	ret := _m.Called(userId, venueId, tiers)
+
+	var r0 []venue.CancellationTierCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, []venue.CancellationTierCore) ([]venue.CancellationTierCore, error)); ok {
+		return rf(userId, venueId, tiers)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, []venue.CancellationTierCore) []venue.CancellationTierCore); ok {
+		r0 = rf(userId, venueId, tiers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.CancellationTierCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, []venue.CancellationTierCore) error); ok {
+		r1 = rf(userId, venueId, tiers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetOpeningHours provides a mock function with given fields: userId, venueId, hours
+func (_m *VenueService) SetOpeningHours(userId string, venueId string, hours []venue.VenueHourCore) ([]venue.VenueHourCore, error) {
+	ret := _m.Called(userId, venueId, hours)
+
+	var r0 []venue.VenueHourCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, []venue.VenueHourCore) ([]venue.VenueHourCore, error)); ok {
+		return rf(userId, venueId, hours)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, []venue.VenueHourCore) []venue.VenueHourCore); ok {
+		r0 = rf(userId, venueId, hours)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.VenueHourCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, []venue.VenueHourCore) error); ok {
+		r1 = rf(userId, venueId, hours)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPricingRules provides a mock function with given fields: userId, venueId, rules
+func (_m *VenueService) SetPricingRules(userId string, venueId string, rules []venue.PricingRuleCore) ([]venue.PricingRuleCore, error) {
+	ret := _m.Called(userId, venueId, rules)
+
+	var r0 []venue.PricingRuleCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, []venue.PricingRuleCore) ([]venue.PricingRuleCore, error)); ok {
+		return rf(userId, venueId, rules)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, []venue.PricingRuleCore) []venue.PricingRuleCore); ok {
+		r0 = rf(userId, venueId, rules)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.PricingRuleCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, []venue.PricingRuleCore) error); ok {
+		r1 = rf(userId, venueId, rules)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestVenues provides a mock function with given fields: query
+func (_m *VenueService) SuggestVenues(query string) ([]venue.SuggestionCore, error) {
+	ret := _m.Called(query)
+
+	var r0 []venue.SuggestionCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]venue.SuggestionCore, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(string) []venue.SuggestionCore); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.SuggestionCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnregisterVenue provides a mock function with given fields: userId, venueId
 func (_m *VenueService) UnregisterVenue(userId string, venueId string) error {
 	ret := _m.Called(userId, venueId)
@@ -267,6 +524,32 @@ func (_m *VenueService) VenueAvailability(venueId string) (venue.VenueCore, erro
 	return r0, r1
 }
 
+// VenueMap provides a mock function with given fields: box, zoom
+func (_m *VenueService) VenueMap(box geo.Box, zoom int) ([]venue.MapMarkerCore, error) {
+	ret := _m.Called(box, zoom)
+
+	var r0 []venue.MapMarkerCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(geo.Box, int) ([]venue.MapMarkerCore, error)); ok {
+		return rf(box, zoom)
+	}
+	if rf, ok := ret.Get(0).(func(geo.Box, int) []venue.MapMarkerCore); ok {
+		r0 = rf(box, zoom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]venue.MapMarkerCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(geo.Box, int) error); ok {
+		r1 = rf(box, zoom)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewVenueService creates a new instance of VenueService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewVenueService(t interface {
@@ -0,0 +1,163 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	voucher "github.com/playground-pro-project/playground-pro-api/features/voucher"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VoucherData is an autogenerated mock type for the VoucherData type
+type VoucherData struct {
+	mock.Mock
+}
+
+// DeleteVoucher provides a mock function with given fields: userId, voucherId
+func (_m *VoucherData) DeleteVoucher(userId string, voucherId string) error {
+	ret := _m.Called(userId, voucherId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, voucherId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCategory provides a mock function with given fields: category
+func (_m *VoucherData) GetCategory(category string) (voucher.CategoryCore, error) {
+	ret := _m.Called(category)
+
+	var r0 voucher.CategoryCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (voucher.CategoryCore, error)); ok {
+		return rf(category)
+	}
+	if rf, ok := ret.Get(0).(func(string) voucher.CategoryCore); ok {
+		r0 = rf(category)
+	} else {
+		r0 = ret.Get(0).(voucher.CategoryCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(category)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRole provides a mock function with given fields: userId
+func (_m *VoucherData) GetUserRole(userId string) (string, error) {
+	ret := _m.Called(userId)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVenueOwner provides a mock function with given fields: venueId
+func (_m *VoucherData) GetVenueOwner(venueId string) (string, error) {
+	ret := _m.Called(venueId)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(venueId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(venueId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(venueId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertVoucher provides a mock function with given fields: request
+func (_m *VoucherData) InsertVoucher(request voucher.VoucherCore) (voucher.VoucherCore, error) {
+	ret := _m.Called(request)
+
+	var r0 voucher.VoucherCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(voucher.VoucherCore) (voucher.VoucherCore, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(voucher.VoucherCore) voucher.VoucherCore); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(voucher.VoucherCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(voucher.VoucherCore) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyVouchers provides a mock function with given fields: userId
+func (_m *VoucherData) MyVouchers(userId string) ([]voucher.VoucherCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []voucher.VoucherCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]voucher.VoucherCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []voucher.VoucherCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]voucher.VoucherCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewVoucherData creates a new instance of VoucherData. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVoucherData(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VoucherData {
+	mock := &VoucherData{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,75 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VoucherHandler is an autogenerated mock type for the VoucherHandler type
+type VoucherHandler struct {
+	mock.Mock
+}
+
+// CreateVoucher provides a mock function with given fields:
+func (_m *VoucherHandler) CreateVoucher() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// DeleteVoucher provides a mock function with given fields:
+func (_m *VoucherHandler) DeleteVoucher() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// MyVouchers provides a mock function with given fields:
+func (_m *VoucherHandler) MyVouchers() echo.HandlerFunc {
+	ret := _m.Called()
+
+	var r0 echo.HandlerFunc
+	if rf, ok := ret.Get(0).(func() echo.HandlerFunc); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(echo.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
+// NewVoucherHandler creates a new instance of VoucherHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVoucherHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VoucherHandler {
+	mock := &VoucherHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
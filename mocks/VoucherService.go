@@ -0,0 +1,91 @@
+// Code generated by mockery v2.30.16. DO NOT EDIT.
+
+package mocks
+
+import (
+	voucher "github.com/playground-pro-project/playground-pro-api/features/voucher"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VoucherService is an autogenerated mock type for the VoucherService type
+type VoucherService struct {
+	mock.Mock
+}
+
+// CreateVoucher provides a mock function with given fields: userId, request
+func (_m *VoucherService) CreateVoucher(userId string, request voucher.VoucherCore) (voucher.VoucherCore, error) {
+	ret := _m.Called(userId, request)
+
+	var r0 voucher.VoucherCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, voucher.VoucherCore) (voucher.VoucherCore, error)); ok {
+		return rf(userId, request)
+	}
+	if rf, ok := ret.Get(0).(func(string, voucher.VoucherCore) voucher.VoucherCore); ok {
+		r0 = rf(userId, request)
+	} else {
+		r0 = ret.Get(0).(voucher.VoucherCore)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, voucher.VoucherCore) error); ok {
+		r1 = rf(userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteVoucher provides a mock function with given fields: userId, voucherId
+func (_m *VoucherService) DeleteVoucher(userId string, voucherId string) error {
+	ret := _m.Called(userId, voucherId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, voucherId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MyVouchers provides a mock function with given fields: userId
+func (_m *VoucherService) MyVouchers(userId string) ([]voucher.VoucherCore, error) {
+	ret := _m.Called(userId)
+
+	var r0 []voucher.VoucherCore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]voucher.VoucherCore, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []voucher.VoucherCore); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]voucher.VoucherCore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewVoucherService creates a new instance of VoucherService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVoucherService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VoucherService {
+	mock := &VoucherService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
-	"github.com/playground-pro-project/playground-pro-api/features/venue"
 )
 
 var (
@@ -46,36 +45,36 @@ func RedisClient(ctx context.Context) (*redis.Client, error) {
 	return redisClient, nil
 }
 
-// GetCached retrieves data from Redis cache based on the cacheKey.
-func GetCached(ctx context.Context, cacheKey string) (interface{}, error) {
+// GetCached retrieves data from Redis cache based on the cacheKey and unmarshals it into dest.
+// It reports whether the key was found.
+func GetCached(ctx context.Context, cacheKey string, dest interface{}) (bool, error) {
 	if redisClient == nil {
 		var err error
 		redisClient, err = InitRedis(ctx)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 	}
 
 	cachedResult, err := redisClient.Get(ctx, cacheKey).Result()
 	if err != nil && err != redis.Nil {
 		log.Sugar().Error("error while retrieving data from Redis cache:", err)
-		return nil, err
+		return false, err
 	} else if cachedResult != "" {
-		var result []venue.VenueCoreRaw
-		err = json.Unmarshal([]byte(cachedResult), &result)
+		err = json.Unmarshal([]byte(cachedResult), dest)
 		if err != nil {
 			log.Sugar().Error("error while unmarshaling cached result:", err)
-			return nil, err
+			return false, err
 		} else {
-			log.Sugar().Info("venue data found in Redis cache")
-			return result, nil
+			log.Sugar().Info("data found in Redis cache")
+			return true, nil
 		}
 	}
 
-	return nil, nil
+	return false, nil
 }
 
-// SetCached sets the provided venues data into Redis cache.
+// SetCached sets the provided data into Redis cache.
 func SetCached(ctx context.Context, cacheKey string, value interface{}, expTime time.Duration) error {
 	if redisClient == nil {
 		var err error
@@ -0,0 +1,60 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// SRID is the spatial reference of venue coordinates, WGS 84 latitude and longitude.
+const SRID = 4326
+
+const kmPerDegree = 111.32
+
+// Box is a latitude and longitude bounding box.
+type Box struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// ValidCoordinates reports whether a latitude and longitude lie on the globe.
+func ValidCoordinates(lat float64, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+// Around returns the box enclosing the circle of radiusKm around a point, clamped to
+// the valid coordinate range. It over-covers the circle so it can narrow a search
+// through the spatial index before the exact distance is checked.
+func Around(lat float64, lng float64, radiusKm float64) Box {
+	latDelta := radiusKm / kmPerDegree
+	lngDelta := 180.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 0 {
+		lngDelta = math.Min(180, radiusKm/(kmPerDegree*cos))
+	}
+
+	return Box{
+		MinLat: math.Max(-90, lat-latDelta),
+		MinLng: math.Max(-180, lng-lngDelta),
+		MaxLat: math.Min(90, lat+latDelta),
+		MaxLng: math.Min(180, lng+lngDelta),
+	}
+}
+
+// WKT formats the box as a polygon in longitude-latitude order, to be parsed with
+// ST_GeomFromText(wkt, SRID, 'axis-order=long-lat').
+func (b Box) WKT() string {
+	return fmt.Sprintf("POLYGON((%[1]f %[2]f, %[3]f %[2]f, %[3]f %[4]f, %[1]f %[4]f, %[1]f %[2]f))",
+		b.MinLng, b.MinLat, b.MaxLng, b.MaxLat)
+}
+
+// PointWKT formats a point in longitude-latitude order.
+func PointWKT(lat float64, lng float64) string {
+	return fmt.Sprintf("POINT(%f %f)", lng, lat)
+}
+
+// CellSize is the width in degrees of the grid cells venues are clustered into at a
+// map zoom level, cellsPerTile cells across each map tile.
+func CellSize(zoom int, cellsPerTile int) float64 {
+	return 360 / math.Pow(2, float64(zoom)) / float64(cellsPerTile)
+}
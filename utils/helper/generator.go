@@ -38,6 +38,102 @@ func GenerateImageID() string {
 	return "IMG-" + generateRandomID()
 }
 
+func GenerateVenueHourID() string {
+	return "HRS-" + generateRandomID()
+}
+
+func GenerateClosureID() string {
+	return "CLS-" + generateRandomID()
+}
+
+func GenerateCancellationPolicyID() string {
+	return "CPL-" + generateRandomID()
+}
+
+func GeneratePricingRuleID() string {
+	return "PRC-" + generateRandomID()
+}
+
+func GenerateHoldID() string {
+	return "HLD-" + generateRandomID()
+}
+
+func GenerateSeriesID() string {
+	return "SRS-" + generateRandomID()
+}
+
+func GenerateWaitlistID() string {
+	return "WTL-" + generateRandomID()
+}
+
+func GenerateRescheduleID() string {
+	return "RSC-" + generateRandomID()
+}
+
+func GenerateRefundID() string {
+	return "RFD-" + generateRandomID()
+}
+
+func GenerateVoucherID() string {
+	return "VCR-" + generateRandomID()
+}
+
+func GenerateRedemptionID() string {
+	return "RDM-" + generateRandomID()
+}
+
+func GeneratePackageID() string {
+	return "PKG-" + generateRandomID()
+}
+
+func GeneratePurchaseID() string {
+	return "PUR-" + generateRandomID()
+}
+
+func GenerateCreditEntryID() string {
+	return "CRE-" + generateRandomID()
+}
+
+func GenerateCreditPaymentID() string {
+	return "CRP-" + generateRandomID()
+}
+
+func GeneratePaymentItemID() string {
+	return "PIT-" + generateRandomID()
+}
+
+func GenerateDiscrepancyID() string {
+	return "DSC-" + generateRandomID()
+}
+
+func GenerateLedgerEntryID() string {
+	return "LDG-" + generateRandomID()
+}
+
+func GeneratePayoutBatchID() string {
+	return "PYB-" + generateRandomID()
+}
+
+func GeneratePayoutLineID() string {
+	return "PYL-" + generateRandomID()
+}
+
+func GenerateSplitPaymentID() string {
+	return "SPL-" + generateRandomID()
+}
+
+func GenerateShareID() string {
+	return "SHR-" + generateRandomID()
+}
+
+func GenerateCategoryID() string {
+	return "CTG-" + generateRandomID()
+}
+
+func GenerateAmenityID() string {
+	return "AMN-" + generateRandomID()
+}
+
 func GenerateReservationID() string {
 	return uuid.New().String()
 }
@@ -10,4 +10,4 @@ func HashPassword(password string) (string, error) {
 func MatchPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
-}
\ No newline at end of file
+}
@@ -12,6 +12,7 @@ type RequestResponse struct {
 	Error      string      `json:"error,omitempty"`
 	Data       interface{} `json:"data,omitempty"`
 	Pagination interface{} `json:"pagination,omitempty"`
+	Facets     interface{} `json:"facets,omitempty"`
 }
 
 func ResponseFormat(code int, message string, data interface{}, pagination interface{}) RequestResponse {
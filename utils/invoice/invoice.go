@@ -0,0 +1,94 @@
+package invoice
+
+import "math"
+
+const (
+	KindBooking     = "booking"
+	KindDiscount    = "discount"
+	KindPlatformFee = "platform_fee"
+	KindBookingFee  = "booking_fee"
+	KindTax         = "tax"
+)
+
+// Rates are the fees and tax charged on top of a booking.
+type Rates struct {
+	// PlatformFeePercent is taken from the booking after any discount
+	PlatformFeePercent float64
+	// BookingFee is a fixed amount charged once per booking
+	BookingFee int64
+	// TaxPercent applies to the booking and the fees together
+	TaxPercent float64
+}
+
+// Item is a line of an invoice in whole rupiah, discounts are negative.
+type Item struct {
+	Kind        string
+	Description string
+	Amount      int64
+}
+
+// Invoice adds its items up by kind. Subtotal is the booking before the discount.
+type Invoice struct {
+	Items    []Item
+	Subtotal int64
+	Discount int64
+	Fees     int64
+	Tax      int64
+	Total    int64
+}
+
+// Build itemizes a booking worth subtotal, less discount, with the fees and tax of rates on top.
+// Each item is rounded to whole rupiah on its own so the items always add up to the total.
+func Build(rates Rates, subtotal float64, discount float64, description string) Invoice {
+	booking := Rupiah(subtotal)
+	off := Rupiah(discount)
+	if off > booking {
+		off = booking
+	}
+	net := booking - off
+
+	items := []Item{{Kind: KindBooking, Description: description, Amount: booking}}
+	if off > 0 {
+		items = append(items, Item{Kind: KindDiscount, Description: "Voucher discount", Amount: -off})
+	}
+
+	platformFee := Rupiah(float64(net) * rates.PlatformFeePercent / 100)
+	if platformFee > 0 {
+		items = append(items, Item{Kind: KindPlatformFee, Description: "Platform fee", Amount: platformFee})
+	}
+	if rates.BookingFee > 0 {
+		items = append(items, Item{Kind: KindBookingFee, Description: "Booking fee", Amount: rates.BookingFee})
+	}
+
+	tax := Rupiah(float64(net+platformFee+rates.BookingFee) * rates.TaxPercent / 100)
+	if tax > 0 {
+		items = append(items, Item{Kind: KindTax, Description: "VAT", Amount: tax})
+	}
+
+	return Summarize(items)
+}
+
+// Summarize adds up stored items into an invoice.
+func Summarize(items []Item) Invoice {
+	result := Invoice{Items: items}
+	for _, item := range items {
+		switch item.Kind {
+		case KindBooking:
+			result.Subtotal += item.Amount
+		case KindDiscount:
+			result.Discount -= item.Amount
+		case KindPlatformFee, KindBookingFee:
+			result.Fees += item.Amount
+		case KindTax:
+			result.Tax += item.Amount
+		}
+		result.Total += item.Amount
+	}
+
+	return result
+}
+
+// Rupiah rounds an amount to whole rupiah, half away from zero.
+func Rupiah(amount float64) int64 {
+	return int64(math.Round(amount))
+}
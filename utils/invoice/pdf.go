@@ -0,0 +1,177 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Receipt is what a PDF receipt shows about a payment.
+type Receipt struct {
+	Number        string
+	IssuedAt      time.Time
+	Customer      string
+	Status        string
+	PaymentMethod string
+	PaymentType   string
+	VenueName     string
+	VenueLocation string
+	Sessions      []Session
+	Invoice       Invoice
+}
+
+// Session is one booked slot of a receipt.
+type Session struct {
+	CheckIn  time.Time
+	CheckOut time.Time
+	Hours    float64
+}
+
+const (
+	brandName    = "Playground Pro"
+	dateLayout   = "02 Jan 2006 15:04"
+	pageWidth    = 190.0
+	amountWidth  = 45.0
+	lineHeight   = 7.0
+	headerHeight = 10.0
+)
+
+// RenderPDF lays out the receipt as a single A4 PDF document.
+func RenderPDF(r Receipt) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Receipt "+r.Number, true)
+	pdf.SetAuthor(brandName, true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	// Brand header
+	pdf.SetFillColor(22, 101, 52)
+	pdf.Rect(0, 0, 210, 28, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.SetXY(10, 9)
+	pdf.CellFormat(pageWidth/2, headerHeight, brandName, "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(pageWidth/2, headerHeight, "RECEIPT", "", 1, "R", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetY(36)
+
+	pdf.SetFont("Helvetica", "", 10)
+	field := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, lineHeight, label, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(pageWidth-40, lineHeight, tr(value), "", 1, "L", false, 0, "")
+	}
+	field("Receipt No.", r.Number)
+	field("Issued", r.IssuedAt.Format(dateLayout))
+	if r.Customer != "" {
+		field("Billed to", r.Customer)
+	}
+	field("Payment method", paymentMethod(r.PaymentMethod, r.PaymentType))
+	field("Status", strings.ToUpper(r.Status))
+	pdf.Ln(4)
+
+	section := func(title string) {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(pageWidth, headerHeight, title, "B", 1, "L", false, 0, "")
+		pdf.Ln(2)
+	}
+
+	section("Venue")
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(pageWidth, lineHeight, tr(r.VenueName), "", 1, "L", false, 0, "")
+	if r.VenueLocation != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(pageWidth, lineHeight, tr(r.VenueLocation), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	section("Reservation")
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(240, 240, 240)
+	pdf.CellFormat(75, lineHeight, "Check in", "", 0, "L", true, 0, "")
+	pdf.CellFormat(75, lineHeight, "Check out", "", 0, "L", true, 0, "")
+	pdf.CellFormat(pageWidth-150, lineHeight, "Hours", "", 1, "R", true, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, s := range r.Sessions {
+		pdf.CellFormat(75, lineHeight, s.CheckIn.Format(dateLayout), "", 0, "L", false, 0, "")
+		pdf.CellFormat(75, lineHeight, s.CheckOut.Format(dateLayout), "", 0, "L", false, 0, "")
+		pdf.CellFormat(pageWidth-150, lineHeight, fmt.Sprintf("%g", s.Hours), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	section("Details")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, item := range r.Invoice.Items {
+		pdf.CellFormat(pageWidth-amountWidth, lineHeight, tr(item.Description), "", 0, "L", false, 0, "")
+		pdf.CellFormat(amountWidth, lineHeight, FormatRupiah(item.Amount), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	total := func(label string, amount int64) {
+		pdf.CellFormat(pageWidth-amountWidth, lineHeight, label, "", 0, "R", false, 0, "")
+		pdf.CellFormat(amountWidth, lineHeight, FormatRupiah(amount), "", 1, "R", false, 0, "")
+	}
+	total("Subtotal", r.Invoice.Subtotal)
+	if r.Invoice.Discount > 0 {
+		total("Discount", -r.Invoice.Discount)
+	}
+	if r.Invoice.Fees > 0 {
+		total("Fees", r.Invoice.Fees)
+	}
+	if r.Invoice.Tax > 0 {
+		total("Tax", r.Invoice.Tax)
+	}
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(pageWidth-amountWidth, headerHeight, "Total", "T", 0, "R", false, 0, "")
+	pdf.CellFormat(amountWidth, headerHeight, FormatRupiah(r.Invoice.Total), "T", 1, "R", false, 0, "")
+
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "I", 9)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.MultiCell(pageWidth, 5, "This receipt was issued electronically by "+brandName+" and is valid without a signature.", "", "C", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FormatRupiah writes an amount the Indonesian way, e.g. Rp 1.250.000 or -Rp 50.000.
+func FormatRupiah(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%d", amount)
+	var grouped strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(d)
+	}
+
+	return sign + "Rp " + grouped.String()
+}
+
+// paymentMethod reads e.g. BCA (bank transfer) from a Midtrans payment method and the payment type picked.
+func paymentMethod(method, paymentType string) string {
+	method = strings.ReplaceAll(method, "_", " ")
+	switch {
+	case paymentType == "":
+		return method
+	case method == "" || strings.EqualFold(method, paymentType):
+		return strings.ToUpper(paymentType)
+	default:
+		return strings.ToUpper(paymentType) + " (" + method + ")"
+	}
+}
@@ -0,0 +1,322 @@
+package paymentgateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation"
+)
+
+// Fake is an offline stand-in for Midtrans, for tests and local development. It hands out
+// made up VA numbers, bill keys, store codes and QR strings, and keeps charges pending until
+// Settle or Expire is called on them or they run past their expiry. Nothing leaves the process.
+// Its notifications are signed the way Midtrans signs them, with a key made up when the fake is
+// built, so only those written by Notify are taken by ParseWebhook.
+type Fake struct {
+	// Now tells the time, tests can move it forward to expire charges
+	Now func() time.Time
+	// Expiry is how long a charge stays payable
+	Expiry time.Duration
+
+	key      string
+	mu       sync.Mutex
+	seq      int
+	payments map[string]*fakePayment
+}
+
+type fakePayment struct {
+	payment  reservation.PaymentCore
+	amount   int64
+	refunded int64
+}
+
+func NewFake() *Fake {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("fake payment provider has no signing key: %v", err))
+	}
+
+	return &Fake{
+		Now:      time.Now,
+		Expiry:   defaultChargeExpiry,
+		key:      hex.EncodeToString(key),
+		payments: map[string]*fakePayment{},
+	}
+}
+
+// Charge implements PaymentProvider.
+func (f *Fake) Charge(orderID string, request reservation.PaymentCore) (reservation.PaymentCore, error) {
+	if orderID == "" {
+		return reservation.PaymentCore{}, errors.New("invalid reservationID")
+	}
+
+	grandTotal, err := strconv.ParseFloat(request.GrandTotal, 64)
+	if err != nil {
+		return reservation.PaymentCore{}, err
+	}
+
+	method, ok := paymentTypeMap[request.PaymentType]
+	if !ok {
+		return reservation.PaymentCore{}, errors.New("invalid payment_type")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, found := f.payments[orderID]; found {
+		return reservation.PaymentCore{}, errors.New("charge rejected: order_id has already been utilized")
+	}
+
+	f.seq++
+	now := f.Now()
+	p := &fakePayment{
+		amount: int64(grandTotal),
+		payment: reservation.PaymentCore{
+			PaymentID:     "fake-" + uuid.New().String(),
+			PaymentType:   request.PaymentType,
+			GrandTotal:    fmt.Sprintf("%d.00", int64(grandTotal)),
+			Status:        "pending",
+			ExpiredAt:     now.Add(f.Expiry),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			ReservationID: orderID,
+			Reservation: reservation.ReservationCore{
+				ReservationID: orderID,
+			},
+		},
+	}
+
+	switch m := method.(type) {
+	case *BankPayment:
+		if m.Bank == mandiri {
+			p.payment.PaymentMethod = "echannel"
+			p.payment.PaymentCode = fmt.Sprintf("BillCode:70012-BillKey:%012d", f.seq)
+		} else {
+			p.payment.PaymentMethod = "bank_transfer"
+			p.payment.PaymentCode = fmt.Sprintf("8808%011d", f.seq)
+		}
+	case *ConvStorePayment:
+		p.payment.PaymentMethod = "cstore"
+		p.payment.PaymentCode = fmt.Sprintf("FAKE%08d", f.seq)
+	case *EWalletPayment:
+		p.payment.PaymentMethod = string(m.EWallet)
+		p.payment.PaymentCode = fakeQRString(orderID, p.amount)
+	}
+
+	f.payments[orderID] = p
+	return p.payment, nil
+}
+
+// RefundTransaction implements Refund, only settled charges can be refunded and never by more
+// than was paid.
+func (f *Fake) RefundTransaction(invoice string, amount int64, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, err := f.find(invoice)
+	if err != nil {
+		return err
+	}
+
+	if p.payment.Status != "settlement" && p.payment.Status != "partial_refund" {
+		return fmt.Errorf("refund rejected: payment is %s", p.payment.Status)
+	}
+	if amount <= 0 || p.refunded+amount > p.amount {
+		return errors.New("refund rejected: invalid refund amount")
+	}
+
+	p.refunded += amount
+	if p.refunded == p.amount {
+		f.update(p, "refund")
+	} else {
+		f.update(p, "partial_refund")
+	}
+
+	return nil
+}
+
+// Cancel implements PaymentProvider.
+func (f *Fake) Cancel(orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, err := f.find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if p.payment.Status != "pending" {
+		return fmt.Errorf("cancel rejected: payment is %s", p.payment.Status)
+	}
+
+	f.update(p, "cancel")
+	return nil
+}
+
+// Status implements PaymentProvider.
+func (f *Fake) Status(orderID string) (reservation.PaymentCore, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, err := f.find(orderID)
+	if err != nil {
+		return reservation.PaymentCore{}, err
+	}
+
+	return p.payment, nil
+}
+
+// ParseWebhook implements PaymentProvider, notifications must carry the signature Notify puts
+// on them.
+func (f *Fake) ParseWebhook(body []byte) (reservation.PaymentCore, error) {
+	notification := Notification{}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return reservation.PaymentCore{}, errors.New("invalid notification")
+	}
+
+	if !validSignatureKey(notification, f.key) {
+		return reservation.PaymentCore{}, errors.New("invalid signature key")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.find(notification.OrderID); err != nil {
+		return reservation.PaymentCore{}, err
+	}
+
+	return notification.PaymentCore(), nil
+}
+
+// Settle pays the pending charge of an order, as the customer would.
+func (f *Fake) Settle(orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, err := f.find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if p.payment.Status != "pending" {
+		return fmt.Errorf("payment is %s", p.payment.Status)
+	}
+
+	f.update(p, "settlement")
+	return nil
+}
+
+// Expire lets the pending charge of an order lapse without waiting for its expiry.
+func (f *Fake) Expire(orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, err := f.find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if p.payment.Status != "pending" {
+		return fmt.Errorf("payment is %s", p.payment.Status)
+	}
+
+	f.update(p, "expire")
+	return nil
+}
+
+// Notify writes the signed notification Midtrans would send about the current state of the
+// payment of an order, to be posted to our webhook.
+func (f *Fake) Notify(orderID string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, err := f.find(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	notification := Notification{
+		TransactionTime:   p.payment.UpdatedAt.In(midtransLocation).Format("2006-01-02 15:04:05"),
+		TransactionStatus: p.payment.Status,
+		TransactionID:     p.payment.PaymentID,
+		StatusCode:        "200",
+		PaymentType:       p.payment.PaymentMethod,
+		OrderID:           orderID,
+		GrossAmount:       p.payment.GrandTotal,
+		Currency:          "IDR",
+	}
+	notification.SignatureKey = hex.EncodeToString(signatureKey(notification, f.key))
+
+	return json.Marshal(notification)
+}
+
+// find looks up the charge of an order, or the charge with that payment ID, expiring it first
+// when it is overdue. The caller holds f.mu.
+func (f *Fake) find(orderID string) (*fakePayment, error) {
+	p, found := f.payments[orderID]
+	if !found {
+		for _, payment := range f.payments {
+			if payment.payment.PaymentID == orderID {
+				p, found = payment, true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("payment not found")
+	}
+
+	if p.payment.Status == "pending" && !f.Now().Before(p.payment.ExpiredAt) {
+		f.update(p, "expire")
+	}
+
+	return p, nil
+}
+
+func (f *Fake) update(p *fakePayment, status string) {
+	p.payment.Status = status
+	p.payment.UpdatedAt = f.Now()
+}
+
+// fakeQRString builds a QRIS style EMV payload for the amount, which scans but pays nobody.
+func fakeQRString(orderID string, amount int64) string {
+	tlv := func(tag string, value string) string {
+		return fmt.Sprintf("%s%02d%s", tag, len(value), value)
+	}
+
+	payload := tlv("00", "01") +
+		tlv("01", "12") +
+		tlv("26", tlv("00", "ID.PLAYGROUNDPRO.FAKE")+tlv("01", orderID)) +
+		tlv("52", "7941") +
+		tlv("53", "360") +
+		tlv("54", strconv.FormatInt(amount, 10)) +
+		tlv("58", "ID") +
+		tlv("59", "PLAYGROUND PRO") +
+		tlv("60", "JAKARTA") +
+		"6304"
+
+	return payload + fmt.Sprintf("%04X", crc16CCITT([]byte(payload)))
+}
+
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
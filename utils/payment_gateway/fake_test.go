@@ -0,0 +1,44 @@
+package paymentgateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeParseWebhook(t *testing.T) {
+	fake := NewFake()
+	_, err := fake.Charge("reservation_id_1", reservation.PaymentCore{PaymentType: "bca", GrandTotal: "300000"})
+	assert.Nil(t, err)
+	assert.Nil(t, fake.Settle("reservation_id_1"))
+
+	t.Run("notification written by Notify", func(t *testing.T) {
+		body, err := fake.Notify("reservation_id_1")
+		assert.Nil(t, err)
+
+		payment, err := fake.ParseWebhook(body)
+		assert.Nil(t, err)
+		assert.Equal(t, "reservation_id_1", payment.Reservation.ReservationID)
+		assert.Equal(t, "settlement", payment.Status)
+	})
+
+	t.Run("unsigned notification", func(t *testing.T) {
+		body, _ := json.Marshal(Notification{OrderID: "reservation_id_1", TransactionStatus: "settlement", StatusCode: "200", GrossAmount: "300000.00"})
+
+		_, err := fake.ParseWebhook(body)
+		assert.EqualError(t, err, "invalid signature key")
+	})
+
+	t.Run("notification signed by another fake", func(t *testing.T) {
+		other := NewFake()
+		_, err := other.Charge("reservation_id_1", reservation.PaymentCore{PaymentType: "bca", GrandTotal: "300000"})
+		assert.Nil(t, err)
+		body, err := other.Notify("reservation_id_1")
+		assert.Nil(t, err)
+
+		_, err = fake.ParseWebhook(body)
+		assert.EqualError(t, err, "invalid signature key")
+	})
+}
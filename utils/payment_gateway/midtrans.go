@@ -2,16 +2,20 @@ package paymentgateway
 
 import (
 	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
-	"github.com/playground-pro-project/playground-pro-api/app/config"
 	"github.com/playground-pro-project/playground-pro-api/app/middlewares"
 	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation"
 )
@@ -19,112 +23,214 @@ import (
 var log = middlewares.Log()
 
 type PaymetGateway struct {
-	Request *coreapi.ChargeReq
+	Request  *coreapi.ChargeReq
+	provider *Midtrans
 }
 
 type PaymentMethod interface {
 	Charge(*PaymetGateway) (*ChargeResponse, error)
 }
 
-type MyRefund struct{}
-
-type Refund interface {
-	RefundTransaction(invoice string, amount int64, reason string) error
+var paymentTypeMap = map[string]PaymentMethod{
+	"bri":       &BankPayment{Bank: bri},
+	"bca":       &BankPayment{Bank: bca},
+	"bni":       &BankPayment{Bank: bni},
+	"mandiri":   &BankPayment{Bank: mandiri},
+	"permata":   &BankPayment{Bank: permata},
+	"indomaret": &ConvStorePayment{Store: indomaret},
+	"alfamart":  &ConvStorePayment{Store: alfamart},
+	"gopay":     &EWalletPayment{EWallet: gopay},
+	"shopeepay": &EWalletPayment{EWallet: shopeepay},
+	"qris":      &EWalletPayment{EWallet: qris},
 }
 
-func (r *MyRefund) RefundTransaction(invoice string, amount int64, reason string) error {
-	client := coreapi.Client{}
-	client.New(config.MIDTRANS_SERVERKEY, midtrans.Sandbox)
-
-	suffix := uuid.New().String()
-	refundKey := config.MIDTRANS_MERCHANT_ID + suffix
+// Midtrans takes payments through the Midtrans Core API.
+type Midtrans struct {
+	ServerKey  string
+	MerchantID string
+	// BaseURL is the Core API to talk to, the sandbox unless set otherwise
+	BaseURL string
+	client  coreapi.Client
+}
 
-	refundRequest := &coreapi.RefundReq{
-		RefundKey: refundKey,
-		Amount:    amount,
-		Reason:    reason,
+func NewMidtrans(serverKey string, merchantID string) *Midtrans {
+	m := &Midtrans{
+		ServerKey:  serverKey,
+		MerchantID: merchantID,
+		BaseURL:    midtrans.Sandbox.BaseUrl(),
 	}
-
-	_, err := client.RefundTransaction(invoice, refundRequest)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	m.client.New(serverKey, midtrans.Sandbox)
+	return m
 }
 
-func ChargeMidtrans(reservationID string, request reservation.PaymentCore) (*ChargeResponse, error) {
-	client := coreapi.Client{}
-	client.New(config.MIDTRANS_SERVERKEY, midtrans.Sandbox)
-
-	if reservationID == "" {
+// Charge implements PaymentProvider.
+func (m *Midtrans) Charge(orderID string, request reservation.PaymentCore) (reservation.PaymentCore, error) {
+	if orderID == "" {
 		log.Error("error reservationID")
-		return nil, errors.New("invalid reservationID")
+		return reservation.PaymentCore{}, errors.New("invalid reservationID")
 	}
 
 	grandTotal, err := strconv.ParseFloat(request.GrandTotal, 64)
 	if err != nil {
 		log.Error("error parsing grand_total")
-		return nil, err
+		return reservation.PaymentCore{}, err
 	}
 
 	pg := PaymetGateway{
 		Request: &coreapi.ChargeReq{
 			TransactionDetails: midtrans.TransactionDetails{
-				OrderID:  reservationID,
+				OrderID:  orderID,
 				GrossAmt: int64(grandTotal),
 			},
 		},
-	}
-
-	paymentTypeMap := map[string]PaymentMethod{
-		"bri":       &BankPayment{Bank: bri},
-		"bca":       &BankPayment{Bank: bca},
-		"bni":       &BankPayment{Bank: bni},
-		"mandiri":   &BankPayment{Bank: mandiri},
-		"permata":   &BankPayment{Bank: permata},
-		"indomaret": &ConvStorePayment{Store: indomaret},
-		"alfamart":  &ConvStorePayment{Store: alfamart},
-		"gopay":     &EWalletPayment{EWallet: gopay},
-		"shopeepay": &EWalletPayment{EWallet: shopeepay},
-		"qris":      &EWalletPayment{EWallet: qris},
+		provider: m,
 	}
 
 	paymentMethod, ok := paymentTypeMap[request.PaymentType]
 	if !ok {
-		return nil, errors.New("invalid payment_type")
+		return reservation.PaymentCore{}, errors.New("invalid payment_type")
 	}
 
-	return paymentMethod.Charge(&pg)
+	res, err := paymentMethod.Charge(&pg)
+	if err != nil {
+		return reservation.PaymentCore{}, err
+	}
+
+	return PaymentCoreFromChargeResponse(res), nil
 }
 
 func (pg *PaymetGateway) CustomCharge(request *coreapi.ChargeReq) (*ChargeResponse, error) {
-	client := coreapi.Client{}
-	client.New(config.MIDTRANS_SERVERKEY, midtrans.Sandbox)
-
 	result := ChargeResponse{}
 	jsonRequest, _ := json.Marshal(request)
-	err := client.HttpClient.Call(http.MethodPost, fmt.Sprintf("%s/v2/charge", client.Env.BaseUrl()), &client.ServerKey, client.Options, bytes.NewBuffer(jsonRequest), &result)
-	if err != nil {
+	if err := pg.provider.call(http.MethodPost, "/v2/charge", bytes.NewBuffer(jsonRequest), &result); err != nil {
 		return nil, err
 	}
 
+	// Midtrans answers rejected charges with HTTP 200 and the actual status in the body
+	if result.StatusCode != "200" && result.StatusCode != "201" {
+		log.Sugar().Errorf("midtrans rejected the charge of order %s: %s %s", request.TransactionDetails.OrderID, result.StatusCode, result.StatusMessage)
+		return nil, fmt.Errorf("charge rejected: %s", result.StatusMessage)
+	}
+
 	switch result.PaymentType {
 	case "bank_transfer", "echannel":
 		if result.PermataVaNumber != "" {
 			result.PaymentCode = result.PermataVaNumber
 		} else if result.BillerCode != "" || result.BillKey != "" {
 			result.PaymentCode = fmt.Sprintf("BillCode:%s-BillKey:%s", result.BillerCode, result.BillKey)
-		} else {
+		} else if len(result.VaNumbers) > 0 {
 			result.PaymentCode = result.VaNumbers[0].VANumber
 		}
 	case "gopay", "shopeepay", "qris":
-		result.PaymentCode = result.Actions[0].URL
+		if len(result.Actions) > 0 {
+			result.PaymentCode = result.Actions[0].URL
+		}
 	}
 
 	return &result, nil
 }
 
+// RefundTransaction implements Refund.
+func (m *Midtrans) RefundTransaction(invoice string, amount int64, reason string) error {
+	suffix := uuid.New().String()
+	refundKey := m.MerchantID + suffix
+
+	refundRequest := &coreapi.RefundReq{
+		RefundKey: refundKey,
+		Amount:    amount,
+		Reason:    reason,
+	}
+
+	jsonRequest, _ := json.Marshal(refundRequest)
+	result := coreapi.RefundResponse{}
+	if err := m.call(http.MethodPost, "/v2/"+invoice+"/refund", bytes.NewBuffer(jsonRequest), &result); err != nil {
+		return err
+	}
+
+	if result.StatusCode != "200" {
+		return fmt.Errorf("refund rejected: %s", result.StatusMessage)
+	}
+
+	return nil
+}
+
+// Cancel implements PaymentProvider.
+func (m *Midtrans) Cancel(orderID string) error {
+	result := coreapi.CancelResponse{}
+	if err := m.call(http.MethodPost, "/v2/"+orderID+"/cancel", nil, &result); err != nil {
+		return err
+	}
+
+	if result.StatusCode != "200" {
+		return fmt.Errorf("cancel rejected: %s", result.StatusMessage)
+	}
+
+	return nil
+}
+
+// Status implements PaymentProvider, Midtrans looks transactions up by order or transaction ID.
+func (m *Midtrans) Status(orderID string) (reservation.PaymentCore, error) {
+	result := coreapi.TransactionStatusResponse{}
+	if err := m.call(http.MethodGet, "/v2/"+orderID+"/status", nil, &result); err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return reservation.PaymentCore{}, errors.New("payment not found")
+		}
+		return reservation.PaymentCore{}, err
+	}
+
+	return reservation.PaymentCore{
+		PaymentID:     result.TransactionID,
+		PaymentMethod: result.PaymentType,
+		GrandTotal:    result.GrossAmount,
+		Status:        result.TransactionStatus,
+		ReservationID: result.OrderID,
+		Reservation: reservation.ReservationCore{
+			ReservationID: result.OrderID,
+		},
+	}, nil
+}
+
+// ParseWebhook implements PaymentProvider, notifications must carry the signature Midtrans
+// computes with our server key.
+func (m *Midtrans) ParseWebhook(body []byte) (reservation.PaymentCore, error) {
+	notification := Notification{}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		log.Sugar().Errorf("error on decoding notification: %v", err)
+		return reservation.PaymentCore{}, errors.New("invalid notification")
+	}
+
+	if !validSignatureKey(notification, m.ServerKey) {
+		log.Error("invalid signature key")
+		return reservation.PaymentCore{}, errors.New("invalid signature key")
+	}
+
+	return notification.PaymentCore(), nil
+}
+
+// signatureKey is the signature Midtrans puts on a notification, the SHA512 of its order,
+// status code and amount followed by the key.
+func signatureKey(n Notification, key string) []byte {
+	hash := sha512.Sum512([]byte(n.OrderID + n.StatusCode + n.GrossAmount + key))
+	return hash[:]
+}
+
+// validSignatureKey compares in constant time, the signature being all that authenticates a
+// notification.
+func validSignatureKey(n Notification, key string) bool {
+	signature, err := hex.DecodeString(n.SignatureKey)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(signatureKey(n, key), signature) == 1
+}
+
+// call sends a request to the Core API and decodes its answer into result. Midtrans errors
+// carry the status code of the answer.
+func (m *Midtrans) call(method string, path string, body io.Reader, result interface{}) *midtrans.Error {
+	return m.client.HttpClient.Call(method, m.BaseURL+path, &m.ServerKey, m.client.Options, body, result)
+}
+
 func IsRefundable(paymentMethod string) bool {
 	refundableMethods := []string{"bank_transfer", "cstore", "echannel"}
 	for _, method := range refundableMethods {
@@ -134,3 +240,21 @@ func IsRefundable(paymentMethod string) bool {
 	}
 	return false
 }
+
+// Payment response Midtrans to payment-core
+func PaymentCoreFromChargeResponse(res *ChargeResponse) reservation.PaymentCore {
+	return reservation.PaymentCore{
+		PaymentID:     res.TransactionID,
+		PaymentMethod: res.PaymentType,
+		PaymentType:   GetBankType(res),
+		PaymentCode:   GetPaymentCode(res),
+		GrandTotal:    res.GrossAmount,
+		ServiceFee:    0,
+		Status:        res.TransactionStatus,
+		ExpiredAt:     GetExpiryTime(res),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		ReservationID: res.OrderID,
+		Reservation:   reservation.ReservationCore{},
+	}
+}
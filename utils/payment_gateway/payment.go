@@ -55,6 +55,26 @@ type ChargeResponse struct {
 	DeletedAt              time.Time          `json:"deleted_at"`
 }
 
+// Midtrans reports its timestamps in Western Indonesia Time.
+var midtransLocation = time.FixedZone("WIB", 7*60*60)
+
+// defaultChargeExpiry is how long Midtrans keeps a charge payable when it
+// does not say otherwise.
+const defaultChargeExpiry = 24 * time.Hour
+
+// GetExpiryTime returns when the charge stops being payable.
+func GetExpiryTime(res *ChargeResponse) time.Time {
+	if expire, err := time.ParseInLocation("2006-01-02 15:04:05", res.Expire, midtransLocation); err == nil {
+		return expire
+	}
+
+	if transactionTime, err := time.ParseInLocation("2006-01-02 15:04:05", res.TransactionTime, midtransLocation); err == nil {
+		return transactionTime.Add(defaultChargeExpiry)
+	}
+
+	return time.Now().Add(defaultChargeExpiry)
+}
+
 func GetPaymentCode(res *ChargeResponse) string {
 	if len(res.VaNumbers) > 0 {
 		return res.VaNumbers[0].VANumber
@@ -0,0 +1,87 @@
+package paymentgateway
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/playground-pro-project/playground-pro-api/app/config"
+	reservation "github.com/playground-pro-project/playground-pro-api/features/reservation"
+)
+
+type Refund interface {
+	RefundTransaction(invoice string, amount int64, reason string) error
+}
+
+// PaymentProvider takes payments for orders through a payment gateway. Payments are keyed by
+// the order they pay for, a reservation, a recurring series or a package purchase.
+type PaymentProvider interface {
+	Refund
+	// Charge asks the customer to pay for the order, the payment is pending until they do
+	Charge(orderID string, request reservation.PaymentCore) (reservation.PaymentCore, error)
+	// Cancel voids the pending payment of an order
+	Cancel(orderID string) error
+	// Status looks up the payment of an order at the gateway, by the order or the payment ID
+	Status(orderID string) (reservation.PaymentCore, error)
+	// ParseWebhook reads a payment notification the gateway sent us
+	ParseWebhook(body []byte) (reservation.PaymentCore, error)
+}
+
+const (
+	ProviderMidtrans = "midtrans"
+	ProviderFake     = "fake"
+)
+
+var (
+	fakeOnce     sync.Once
+	fakeProvider *Fake
+)
+
+// NewProvider returns the gateway set by PAYMENT_PROVIDER, Midtrans unless it says fake.
+// The fake keeps its payments in memory, so every caller shares the same one.
+func NewProvider() PaymentProvider {
+	if strings.EqualFold(config.PAYMENT_PROVIDER, ProviderFake) {
+		fakeOnce.Do(func() {
+			log.Warn("payments go through the offline fake provider, nobody is actually charged")
+			fakeProvider = NewFake()
+		})
+		return fakeProvider
+	}
+
+	return NewMidtrans(config.MIDTRANS_SERVERKEY, config.MIDTRANS_MERCHANT_ID)
+}
+
+// Notification is the payment notification Midtrans posts to us, the fake sends the same.
+type Notification struct {
+	TransactionTime     string `json:"transaction_time"`
+	TransactionStatus   string `json:"transaction_status"`
+	TransactionID       string `json:"transaction_id"`
+	StatusMessage       string `json:"status_message"`
+	StatusCode          string `json:"status_code"`
+	SignatureKey        string `json:"signature_key"` // Hash SHA512
+	PaymentType         string `json:"payment_type"`
+	OrderID             string `json:"order_id"`
+	MerchantID          string `json:"merchant_id"`
+	MaskedCard          string `json:"masked_card"`
+	GrossAmount         string `json:"gross_amount"`
+	FraudStatus         string `json:"fraud_status"`
+	ECI                 string `json:"eci"`
+	Currency            string `json:"currency"`
+	ChannelResponseMsg  string `json:"channel_response_message"`
+	ChannelResponseCode string `json:"channel_response_code"`
+	CardType            string `json:"card_type"`
+	Bank                string `json:"bank"`
+	ApprovalCode        string `json:"approval_code"`
+}
+
+// PaymentCore reads the payment status update a notification carries.
+func (n Notification) PaymentCore() reservation.PaymentCore {
+	return reservation.PaymentCore{
+		PaymentID: n.TransactionID,
+		Reservation: reservation.ReservationCore{
+			ReservationID: n.OrderID,
+		},
+		PaymentMethod: n.PaymentType,
+		GrandTotal:    n.GrossAmount,
+		Status:        n.TransactionStatus,
+	}
+}
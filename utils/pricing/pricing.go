@@ -0,0 +1,159 @@
+package pricing
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/playground-pro-project/playground-pro-api/utils/schedule"
+)
+
+// Rule overrides the base hourly rate of a venue for part of the week.
+type Rule struct {
+	Name string
+	// Weekdays the rule applies on, empty means every day. A band running past
+	// midnight belongs to the weekday it starts on.
+	Weekdays []time.Weekday
+	// Band limits the rule to a time of day, nil means the whole day.
+	Band *schedule.Shift
+	// From and Until bound the dates the rule applies on, both inclusive. A zero
+	// value leaves that side open.
+	From  time.Time
+	Until time.Time
+	// MinDuration only applies the rule to bookings at least this long.
+	MinDuration time.Duration
+	Rate        float64
+	// Priority decides between overlapping rules, the highest wins.
+	Priority int
+}
+
+// Item is a stretch of a booking charged at a single rate.
+type Item struct {
+	schedule.Interval
+	// Rule is the name of the rule behind the rate, empty for the base rate.
+	Rule   string
+	Rate   float64
+	Hours  float64
+	Amount float64
+}
+
+// Quote prices a booking by splitting it wherever the applicable rule changes.
+// Stretches no rule covers are charged at the base rate.
+func Quote(base float64, rules []Rule, booking schedule.Interval) []Item {
+	eligible := []Rule{}
+	for _, r := range rules {
+		if booking.End.Sub(booking.Start) >= r.MinDuration {
+			eligible = append(eligible, r)
+		}
+	}
+
+	boundaries := []time.Time{booking.Start, booking.End}
+	for day := schedule.StartOfDay(booking.Start).AddDate(0, 0, -1); day.Before(booking.End); day = day.AddDate(0, 0, 1) {
+		for _, r := range eligible {
+			band := r.bandOn(day)
+			for _, t := range []time.Time{band.Start, band.End} {
+				if t.After(booking.Start) && t.Before(booking.End) {
+					boundaries = append(boundaries, t)
+				}
+			}
+		}
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	items := []Item{}
+	for i := 1; i < len(boundaries); i++ {
+		if !boundaries[i].After(boundaries[i-1]) {
+			continue
+		}
+
+		item := Item{Interval: schedule.Interval{Start: boundaries[i-1], End: boundaries[i]}, Rate: base}
+		if r, ok := match(eligible, item.Start); ok {
+			item.Rule = r.Name
+			item.Rate = r.Rate
+		}
+
+		last := len(items) - 1
+		if last >= 0 && items[last].Rule == item.Rule && items[last].Rate == item.Rate {
+			items[last].End = item.End
+			continue
+		}
+		items = append(items, item)
+	}
+
+	for i := range items {
+		items[i].Hours = items[i].End.Sub(items[i].Start).Hours()
+		items[i].Amount = round(items[i].Hours * items[i].Rate)
+	}
+
+	return items
+}
+
+// Total sums the amounts of the items.
+func Total(items []Item) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Amount
+	}
+
+	return round(total)
+}
+
+// match picks the highest priority rule in effect at t, the first one listed on a tie.
+func match(rules []Rule, t time.Time) (Rule, bool) {
+	best, found := Rule{}, false
+	for _, r := range rules {
+		if r.covers(t) && (!found || r.Priority > best.Priority) {
+			best, found = r, true
+		}
+	}
+
+	return best, found
+}
+
+// covers reports whether the rule is in effect at t, including a band started the day before.
+func (r Rule) covers(t time.Time) bool {
+	today := schedule.StartOfDay(t)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		band := r.bandOn(day)
+		if r.appliesOn(day) && !t.Before(band.Start) && t.Before(band.End) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) appliesOn(day time.Time) bool {
+	if !r.From.IsZero() && day.Before(schedule.StartOfDay(r.From)) {
+		return false
+	}
+	if !r.Until.IsZero() && day.After(schedule.StartOfDay(r.Until)) {
+		return false
+	}
+	if len(r.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range r.Weekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) bandOn(day time.Time) schedule.Interval {
+	if r.Band == nil {
+		midnight := schedule.StartOfDay(day)
+		return schedule.Interval{Start: midnight, End: midnight.AddDate(0, 0, 1)}
+	}
+
+	return r.Band.On(day)
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
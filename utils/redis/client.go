@@ -12,6 +12,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxTxRetries bounds optimistic transaction retries when a watched key changes.
+const maxTxRetries = 10
+
 type RedisClient struct {
 	client *redis.Client
 	ctx    context.Context
@@ -60,3 +63,131 @@ func (r *RedisClient) GetOTP(key string) (string, error) {
 
 	return val, nil
 }
+
+// SetIndexed stores value under key for ttl and records key in the sorted set index,
+// scored by its expiry. check receives the live values of the index and may veto the
+// write; the read and the write happen atomically with respect to other writers.
+func (r *RedisClient) SetIndexed(index string, key string, value string, ttl time.Duration, check func(values []string) error) error {
+	txf := func(tx *redis.Tx) error {
+		values, err := r.liveValues(tx, index)
+		if err != nil {
+			return err
+		}
+
+		if err := check(values); err != nil {
+			return err
+		}
+
+		expiry := time.Now().Add(ttl)
+		_, err = tx.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZRemRangeByScore(r.ctx, index, "-inf", fmt.Sprint(time.Now().Unix()))
+			pipe.Set(r.ctx, key, value, ttl)
+			pipe.ZAdd(r.ctx, index, redis.Z{Score: float64(expiry.Unix()), Member: key})
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxTxRetries; i++ {
+		err := r.client.Watch(r.ctx, txf, index)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("failed to set indexed key in Redis: too much contention on %s", index)
+}
+
+// GetIndexed returns the values recorded in index that have not expired yet.
+func (r *RedisClient) GetIndexed(index string) ([]string, error) {
+	values, err := r.liveValues(r.client, index)
+	if err != nil {
+		r.log.Error("Failed to get indexed keys from Redis", zap.Error(err))
+		return nil, fmt.Errorf("failed to get indexed keys from Redis: %w", err)
+	}
+
+	return values, nil
+}
+
+// DeleteIndexed removes key and its entry in index.
+func (r *RedisClient) DeleteIndexed(index string, key string) error {
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, key)
+		pipe.ZRem(r.ctx, index, key)
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to delete indexed key from Redis", zap.Error(err))
+		return fmt.Errorf("failed to delete indexed key from Redis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisClient) liveValues(cmd redis.Cmdable, index string) ([]string, error) {
+	keys, err := cmd.ZRangeByScore(r.ctx, index, &redis.ZRangeBy{
+		Min: fmt.Sprint(time.Now().Unix()),
+		Max: "+inf",
+	}).Result()
+	if err != nil || len(keys) == 0 {
+		return []string{}, err
+	}
+
+	raw, err := cmd.MGet(r.ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+
+	return values, nil
+}
+
+// SetIfAbsent stores value under key only when the key does not exist yet.
+func (r *RedisClient) SetIfAbsent(key string, value string, expiration time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(r.ctx, key, value, expiration).Result()
+	if err != nil {
+		r.log.Error("Failed to set key in Redis", zap.Error(err))
+		return false, fmt.Errorf("failed to set key in Redis: %w", err)
+	}
+
+	return ok, nil
+}
+
+func (r *RedisClient) Set(key string, value string, expiration time.Duration) error {
+	err := r.client.Set(r.ctx, key, value, expiration).Err()
+	if err != nil {
+		r.log.Error("Failed to set key in Redis", zap.Error(err))
+		return fmt.Errorf("failed to set key in Redis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisClient) Get(key string) (string, error) {
+	val, err := r.client.Get(r.ctx, key).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("key not found: %s", key)
+	} else if err != nil {
+		r.log.Error("Failed to get key from Redis", zap.Error(err))
+		return "", fmt.Errorf("failed to get key from Redis: %w", err)
+	}
+
+	return val, nil
+}
+
+func (r *RedisClient) Delete(key string) error {
+	err := r.client.Del(r.ctx, key).Err()
+	if err != nil {
+		r.log.Error("Failed to delete key from Redis", zap.Error(err))
+		return fmt.Errorf("failed to delete key from Redis: %w", err)
+	}
+
+	return nil
+}
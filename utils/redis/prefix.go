@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// maxPrefixLength bounds the prefixes recorded for a term, longer queries are matched on
+// their first maxPrefixLength characters.
+const maxPrefixLength = 20
+
+// A prefix index keeps, under the name index:
+//   - index:prefix:<prefix>, the members having a term starting with prefix
+//   - index:member:<member>, the prefixes a member is recorded under
+//   - index:terms:<member>, the terms of a member
+//   - index:labels, the display label of every member
+//   - index:vocabulary, every term scored by how many members use it
+func prefixKey(index string, prefix string) string {
+	return index + ":prefix:" + prefix
+}
+
+func prefixMemberKey(index string, member string) string {
+	return index + ":member:" + member
+}
+
+func prefixTermsKey(index string, member string) string {
+	return index + ":terms:" + member
+}
+
+// Prefix truncates a term to the longest prefix recorded in a prefix index.
+func Prefix(term string) string {
+	runes := []rune(term)
+	if len(runes) > maxPrefixLength {
+		return string(runes[:maxPrefixLength])
+	}
+
+	return term
+}
+
+// IndexPrefixes records member under every prefix of terms in the prefix index, replacing
+// whatever it was recorded under before. label is what a search returns for the member.
+func (r *RedisClient) IndexPrefixes(index string, member string, label string, terms []string) error {
+	if err := r.RemovePrefixes(index, member); err != nil {
+		return err
+	}
+
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		for _, term := range terms {
+			runes := []rune(Prefix(term))
+			for i := 1; i <= len(runes); i++ {
+				prefix := string(runes[:i])
+				pipe.SAdd(r.ctx, prefixKey(index, prefix), member)
+				pipe.SAdd(r.ctx, prefixMemberKey(index, member), prefix)
+			}
+			pipe.SAdd(r.ctx, prefixTermsKey(index, member), term)
+		}
+		for _, term := range uniqueTerms(terms) {
+			pipe.ZIncrBy(r.ctx, index+":vocabulary", 1, term)
+		}
+		pipe.HSet(r.ctx, index+":labels", member, label)
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to index prefixes in Redis", zap.Error(err))
+		return fmt.Errorf("failed to index prefixes in Redis: %w", err)
+	}
+
+	return nil
+}
+
+// RemovePrefixes drops member from the prefix index.
+func (r *RedisClient) RemovePrefixes(index string, member string) error {
+	prefixes, err := r.client.SMembers(r.ctx, prefixMemberKey(index, member)).Result()
+	if err != nil {
+		r.log.Error("Failed to get prefixes from Redis", zap.Error(err))
+		return fmt.Errorf("failed to get prefixes from Redis: %w", err)
+	}
+
+	terms, err := r.client.SMembers(r.ctx, prefixTermsKey(index, member)).Result()
+	if err != nil {
+		r.log.Error("Failed to get terms from Redis", zap.Error(err))
+		return fmt.Errorf("failed to get terms from Redis: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		for _, prefix := range prefixes {
+			pipe.SRem(r.ctx, prefixKey(index, prefix), member)
+		}
+		for _, term := range terms {
+			pipe.ZIncrBy(r.ctx, index+":vocabulary", -1, term)
+		}
+		pipe.ZRemRangeByScore(r.ctx, index+":vocabulary", "-inf", "0")
+		pipe.Del(r.ctx, prefixMemberKey(index, member), prefixTermsKey(index, member))
+		pipe.HDel(r.ctx, index+":labels", member)
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to remove prefixes from Redis", zap.Error(err))
+		return fmt.Errorf("failed to remove prefixes from Redis: %w", err)
+	}
+
+	return nil
+}
+
+// SearchPrefixes returns the labels, keyed by member, of the members recorded under every
+// one of prefixes.
+func (r *RedisClient) SearchPrefixes(index string, prefixes []string) (map[string]string, error) {
+	if len(prefixes) == 0 {
+		return map[string]string{}, nil
+	}
+
+	keys := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		keys[i] = prefixKey(index, Prefix(prefix))
+	}
+
+	members, err := r.client.SInter(r.ctx, keys...).Result()
+	if err != nil {
+		r.log.Error("Failed to search prefixes in Redis", zap.Error(err))
+		return nil, fmt.Errorf("failed to search prefixes in Redis: %w", err)
+	}
+
+	labels := map[string]string{}
+	if len(members) == 0 {
+		return labels, nil
+	}
+
+	values, err := r.client.HMGet(r.ctx, index+":labels", members...).Result()
+	if err != nil {
+		r.log.Error("Failed to get labels from Redis", zap.Error(err))
+		return nil, fmt.Errorf("failed to get labels from Redis: %w", err)
+	}
+
+	for i, v := range values {
+		if label, ok := v.(string); ok {
+			labels[members[i]] = label
+		}
+	}
+
+	return labels, nil
+}
+
+// PrefixVocabulary returns every term recorded in the prefix index.
+func (r *RedisClient) PrefixVocabulary(index string) ([]string, error) {
+	terms, err := r.client.ZRange(r.ctx, index+":vocabulary", 0, -1).Result()
+	if err != nil {
+		r.log.Error("Failed to get vocabulary from Redis", zap.Error(err))
+		return nil, fmt.Errorf("failed to get vocabulary from Redis: %w", err)
+	}
+
+	return terms, nil
+}
+
+// PrefixIndexSize returns how many members the prefix index holds.
+func (r *RedisClient) PrefixIndexSize(index string) (int64, error) {
+	size, err := r.client.HLen(r.ctx, index+":labels").Result()
+	if err != nil {
+		r.log.Error("Failed to get prefix index size from Redis", zap.Error(err))
+		return 0, fmt.Errorf("failed to get prefix index size from Redis: %w", err)
+	}
+
+	return size, nil
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+
+	return unique
+}
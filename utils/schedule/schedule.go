@@ -0,0 +1,207 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+// Shift is an opening range within a day, expressed in minutes since midnight.
+// A shift whose Close is not after its Open runs past midnight.
+type Shift struct {
+	Open  int
+	Close int
+}
+
+// WeeklyHours maps a weekday to the shifts opening on that day.
+type WeeklyHours map[time.Weekday][]Shift
+
+// Interval is a concrete half-open time range [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewShift builds a shift from "HH:MM" opening and closing clocks.
+func NewShift(openTime string, closeTime string) (Shift, error) {
+	open, err := ParseClock(openTime)
+	if err != nil {
+		return Shift{}, err
+	}
+
+	closeAt, err := ParseClock(closeTime)
+	if err != nil {
+		return Shift{}, err
+	}
+
+	return Shift{Open: open % minutesPerDay, Close: closeAt % minutesPerDay}, nil
+}
+
+// Overnight reports whether the shift closes on the following day.
+func (s Shift) Overnight() bool {
+	return s.Close <= s.Open
+}
+
+// On returns the concrete interval of the shift when it opens on day.
+func (s Shift) On(day time.Time) Interval {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	closeAt := s.Close
+	if s.Overnight() {
+		closeAt += minutesPerDay
+	}
+
+	return Interval{
+		Start: midnight.Add(time.Duration(s.Open) * time.Minute),
+		End:   midnight.Add(time.Duration(closeAt) * time.Minute),
+	}
+}
+
+// String formats the shift the same way venues store their service time.
+func (s Shift) String() string {
+	return FormatClock(s.Open) + " - " + FormatClock(s.Close)
+}
+
+// Overlaps reports whether both intervals share at least one instant.
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+// Contains reports whether o lies entirely within i.
+func (i Interval) Contains(o Interval) bool {
+	return !o.Start.Before(i.Start) && !o.End.After(i.End)
+}
+
+// Split divides the interval into consecutive slots of the given length.
+// A trailing remainder shorter than length is dropped.
+func (i Interval) Split(length time.Duration) []Interval {
+	slots := []Interval{}
+	if length <= 0 {
+		return slots
+	}
+
+	for start := i.Start; !start.Add(length).After(i.End); start = start.Add(length) {
+		slots = append(slots, Interval{Start: start, End: start.Add(length)})
+	}
+
+	return slots
+}
+
+// Daily applies the same shifts to every day of the week.
+func Daily(shifts []Shift) WeeklyHours {
+	hours := WeeklyHours{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		hours[day] = shifts
+	}
+
+	return hours
+}
+
+// OpenIntervals returns the opening intervals touching [from, to), with
+// adjacent or overlapping shifts merged so a booking may span them.
+func (w WeeklyHours) OpenIntervals(from time.Time, to time.Time) []Interval {
+	window := Interval{Start: from, End: to}
+	intervals := []Interval{}
+	// Start a day early so overnight shifts of the previous day are included.
+	for day := StartOfDay(from).AddDate(0, 0, -1); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, shift := range w[day.Weekday()] {
+			open := shift.On(day)
+			if open.Overlaps(window) {
+				intervals = append(intervals, open)
+			}
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	merged := []Interval{}
+	for _, interval := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && !interval.Start.After(merged[last].End) {
+			if interval.End.After(merged[last].End) {
+				merged[last].End = interval.End
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+
+	return merged
+}
+
+// Covers reports whether the venue stays open for the whole interval.
+func (w WeeklyHours) Covers(i Interval) bool {
+	for _, open := range w.OpenIntervals(i.Start, i.End) {
+		if open.Contains(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseServiceTime parses a venue service time such as "07:45 - 23:00".
+// Split shifts are separated by commas or semicolons, e.g.
+// "06:00 - 12:00, 15:00 - 02:00".
+func ParseServiceTime(serviceTime string) ([]Shift, error) {
+	normalized := strings.NewReplacer(";", ",", "–", "-", "—", "-", " to ", "-").Replace(serviceTime)
+	shifts := []Shift{}
+	for _, part := range strings.Split(normalized, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.Split(part, "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid service time %q", part)
+		}
+
+		shift, err := NewShift(bounds[0], bounds[1])
+		if err != nil {
+			return nil, err
+		}
+
+		shifts = append(shifts, shift)
+	}
+
+	if len(shifts) == 0 {
+		return nil, errors.New("service time cannot be empty")
+	}
+
+	return shifts, nil
+}
+
+// ParseClock converts "HH:MM" (or "HH.MM") into minutes since midnight.
+// "24:00" is accepted as the end of the day.
+func ParseClock(clock string) (int, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(clock), ".", ":"), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid clock %q", clock)
+	}
+
+	hour, errHour := strconv.Atoi(parts[0])
+	minute, errMinute := strconv.Atoi(parts[1])
+	if errHour != nil || errMinute != nil || hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid clock %q", clock)
+	}
+
+	return hour*60 + minute, nil
+}
+
+// FormatClock converts minutes since midnight into "HH:MM".
+func FormatClock(minutes int) string {
+	minutes = ((minutes % minutesPerDay) + minutesPerDay) % minutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// StartOfDay truncates t to midnight in its own location.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}